@@ -0,0 +1,581 @@
+// Command server runs the audit-log HTTP service.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/smtp"
+	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"syscall"
+	"time"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/archive"
+	"github.com/naveenkumar2412/audit-log-service/internal/auth"
+	"github.com/naveenkumar2412/audit-log-service/internal/cache"
+	"github.com/naveenkumar2412/audit-log-service/internal/config"
+	auditcrypto "github.com/naveenkumar2412/audit-log-service/internal/crypto"
+	"github.com/naveenkumar2412/audit-log-service/internal/dedup"
+	"github.com/naveenkumar2412/audit-log-service/internal/filesink"
+	"github.com/naveenkumar2412/audit-log-service/internal/graphqlapi"
+	"github.com/naveenkumar2412/audit-log-service/internal/grpcapi"
+	"github.com/naveenkumar2412/audit-log-service/internal/grpcapi/auditpb"
+	"github.com/naveenkumar2412/audit-log-service/internal/health"
+	"github.com/naveenkumar2412/audit-log-service/internal/httpapi"
+	"github.com/naveenkumar2412/audit-log-service/internal/kafka"
+	"github.com/naveenkumar2412/audit-log-service/internal/metrics"
+	"github.com/naveenkumar2412/audit-log-service/internal/migrate"
+	natspublish "github.com/naveenkumar2412/audit-log-service/internal/nats"
+	"github.com/naveenkumar2412/audit-log-service/internal/notify"
+	"github.com/naveenkumar2412/audit-log-service/internal/queue"
+	"github.com/naveenkumar2412/audit-log-service/internal/resilience"
+	"github.com/naveenkumar2412/audit-log-service/internal/service"
+	"github.com/naveenkumar2412/audit-log-service/internal/store/postgres"
+	"github.com/naveenkumar2412/audit-log-service/internal/stream"
+	"github.com/naveenkumar2412/audit-log-service/internal/throttle"
+	"github.com/naveenkumar2412/audit-log-service/internal/tracing"
+)
+
+// @title Audit Log Service API
+// @version 1.0
+// @description Multi-tenant audit logging API: create, list, and query
+// @description immutable audit records, plus supporting operations
+// @description (tags, metadata patches, stats, hash-chain verification).
+// @BasePath /api/v1
+// @schemes http https
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "--migrate" {
+		if err := runMigrate(); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// runMigrate applies pending migrations (see internal/migrate) and
+// exits, instead of starting the server. It reads the same
+// DATABASE_URL-driven config as run() so `server --migrate` and a
+// normal deploy always target the same database.
+func runMigrate() error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if err := migrate.Up(cfg.DatabaseURL); err != nil {
+		return err
+	}
+	log.Print("migrate: database is up to date")
+	return nil
+}
+
+func run() error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	logLevel := new(slog.LevelVar)
+	logLevel.Set(slogLevelFromName(cfg.Logging.Level))
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: logLevel})))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	shutdownTracing, err := tracing.Init(ctx, cfg.Tracing)
+	if err != nil {
+		return err
+	}
+	defer shutdownTracing(ctx)
+
+	if cfg.DB.AutoMigrate {
+		log.Print("postgres: applying pending migrations")
+		if err := migrate.Up(cfg.DatabaseURL); err != nil {
+			return err
+		}
+	}
+
+	poolConfig, err := pgxpool.ParseConfig(cfg.DatabaseURL)
+	if err != nil {
+		return err
+	}
+	poolConfig.MinConns = cfg.DB.MinConns
+	poolConfig.MaxConns = cfg.DB.MaxConns
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	ready := &health.Readiness{}
+	if cfg.DB.EagerWarmup {
+		log.Printf("postgres: warming up %d connections before reporting ready", cfg.DB.MinConns)
+		if err := postgres.Warmup(ctx, pool, cfg.DB.MinConns); err != nil {
+			return err
+		}
+	}
+	ready.SetReady()
+
+	dbChecker := health.NewDBChecker(pool, cfg.DB.HealthCheckTimeout)
+	if cfg.DB.RejectWhenUnhealthy {
+		go dbChecker.Run(ctx, cfg.DB.HealthCheckInterval)
+	}
+
+	if cfg.Archival.Enabled {
+		archivalWorker := postgres.NewArchivalWorker(postgres.NewAuditLogRepo(pool).WithQueryTimeout(cfg.DB.QueryTimeout), cfg.Archival.After, cfg.Archival.StatusMetaKey, cfg.Archival.StatusMetaValue)
+		go archivalWorker.Run(ctx, cfg.Archival.CheckInterval)
+	}
+
+	retentionWorker := postgres.NewRetentionWorker(postgres.NewAuditLogRepo(pool).WithQueryTimeout(cfg.DB.QueryTimeout), cfg.Retention)
+	if cfg.Archive.Enabled {
+		awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(cfg.Archive.Region))
+		if err != nil {
+			return fmt.Errorf("load AWS config for archival: %w", err)
+		}
+		s3Archiver := archive.NewS3Archiver(s3.NewFromConfig(awsCfg), cfg.Archive.Bucket, cfg.Archive.Prefix)
+		retentionWorker.WithArchiver(s3Archiver)
+	}
+	go retentionWorker.Run(ctx)
+
+	repo := postgres.NewAuditLogRepo(pool).WithSequenceEnabled(cfg.Sequence.Enabled).WithQueryTimeout(cfg.DB.QueryTimeout)
+	if cfg.Encryption.Enabled {
+		keys := make(map[string][]byte, len(cfg.Encryption.Keys))
+		for id, encoded := range cfg.Encryption.Keys {
+			key, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				return fmt.Errorf("decode encryption key %q: %w", id, err)
+			}
+			keys[id] = key
+		}
+		enc, err := auditcrypto.NewAESGCMEncryptor(cfg.Encryption.ActiveKeyID, keys)
+		if err != nil {
+			return fmt.Errorf("init encryptor: %w", err)
+		}
+		repo = repo.WithEncryptor(enc)
+	}
+
+	redisRetry := resilience.RetryOptions{
+		MaxRetries:      cfg.Redis.MaxRetries,
+		MinRetryBackoff: cfg.Redis.MinRetryBackoff,
+		MaxRetryBackoff: cfg.Redis.MaxRetryBackoff,
+	}
+
+	webhookRepo := postgres.NewWebhookRepo(pool)
+	webhookSvc := service.NewWebhookService(webhookRepo)
+	webhookHandler := httpapi.NewWebhookHandler(webhookSvc).WithAuthPolicies(cfg.Auth.RoutePolicies)
+
+	batchingWebhookSender := notify.NewBatchingWebhookSender(notify.NewWebhookSender(cfg.Notify.WebhookMaxDataBytes, cfg.Notify.PublicBaseURL).WithRetry(cfg.Notify.MaxRetries, cfg.Notify.RetryBackoff).WithSigningSecret(cfg.Notify.WebhookSigningSecret))
+	go batchingWebhookSender.Run(ctx, cfg.Notify.BatchFlushCheckInterval)
+	slackSender := notify.NewSlackSender(cfg.Notify.SlackMaxMessageLength, cfg.Notify.PublicBaseURL).WithRetry(cfg.Notify.MaxRetries, cfg.Notify.RetryBackoff)
+	teamsSender := notify.NewTeamsSender()
+
+	failedNotificationRepo := postgres.NewFailedNotificationRepo(pool)
+	notificationSvc := service.NewNotificationService(failedNotificationRepo)
+	notificationHandler := httpapi.NewNotificationHandler(notificationSvc)
+
+	dispatcher := notify.NewDispatcher(
+		webhookRepo,
+		batchingWebhookSender,
+		slackSender,
+		teamsSender,
+	).WithMaxChannelsPerEvent(cfg.Notify.MaxChannelsPerEvent).
+		WithDeadLetter(failedNotificationRepo, cfg.Notify.DeadLetterBackoff)
+	if cfg.Throttle.Enabled {
+		throttleBreaker := resilience.NewBreaker("notify_throttle", cfg.Redis.CircuitBreakerThreshold, cfg.Redis.CircuitBreakerCooldown)
+		dispatcher = dispatcher.WithThrottle(throttle.NewRedisThrottle(cfg.Redis.Addr, redisRetry, throttleBreaker), cfg.Throttle.DefaultWindow, cfg.Throttle.Windows)
+	}
+
+	retryWorker := notify.NewNotificationRetryWorker(failedNotificationRepo, repo, batchingWebhookSender, slackSender, teamsSender).
+		WithBatchSize(cfg.Notify.DeadLetterBatchSize).
+		WithRetryBackoff(cfg.Notify.DeadLetterBackoff)
+
+	if cfg.Email.SMTPAddr != "" {
+		emailTemplates := make(map[string]notify.EmailTemplate, len(cfg.Email.Templates))
+		for event, tmpl := range cfg.Email.Templates {
+			emailTemplates[event] = notify.EmailTemplate{Subject: tmpl.Subject, Text: tmpl.Text, HTML: tmpl.HTML}
+		}
+		emailSender := notify.NewSMTPEmailSender(cfg.Email.SMTPAddr, cfg.Email.From).WithTemplates(emailTemplates)
+		if cfg.Email.SMTPUsername != "" {
+			emailSender = emailSender.WithAuth(smtp.PlainAuth("", cfg.Email.SMTPUsername, cfg.Email.SMTPPassword, strings.Split(cfg.Email.SMTPAddr, ":")[0]))
+		}
+		dispatcher = dispatcher.WithEmailSender(emailSender)
+		retryWorker = retryWorker.WithEmailSender(emailSender)
+	}
+
+	go retryWorker.Run(ctx, cfg.Notify.DeadLetterRetryInterval)
+
+	notificationPool := service.NewNotificationPool(dispatcher, cfg.Notification.Workers, cfg.Notification.QueueSize, cfg.Notification.SubmitTimeout)
+	go notificationPool.Run(ctx)
+
+	streamHub := stream.NewHub()
+
+	svc := service.NewAuditService(repo, cfg).WithNotifier(service.Notifiers(notificationPool, streamHub))
+	if cfg.Dedup.Enabled {
+		dedupBreaker := resilience.NewBreaker("dedup", cfg.Redis.CircuitBreakerThreshold, cfg.Redis.CircuitBreakerCooldown)
+		svc = svc.WithDeduper(dedup.NewRedisStore(cfg.Redis.Addr, redisRetry, dedupBreaker))
+	}
+	if cfg.Cache.Enabled {
+		cacheBreaker := resilience.NewBreaker("audit_log_cache", cfg.Redis.CircuitBreakerThreshold, cfg.Redis.CircuitBreakerCooldown)
+		svc = svc.WithCache(cache.NewRedisCache(cfg.Redis.Addr, redisRetry, cacheBreaker), cfg.Cache.TTL)
+	}
+	if cfg.FileSink.Enabled {
+		sink := filesink.New(cfg.FileSink.Path, cfg.FileSink.MaxSizeMB, cfg.FileSink.MaxBackups, cfg.FileSink.MaxAgeDays, cfg.FileSink.Compress)
+		defer sink.Close()
+		svc = svc.WithFileSink(sink, cfg.FileSink.Mode == "primary")
+	}
+	if cfg.Kafka.Enabled {
+		publisher := kafka.New(cfg.Kafka.Brokers, cfg.Kafka.Topic)
+		defer publisher.Close()
+		svc = svc.WithKafkaPublisher(publisher)
+	}
+	if cfg.NATS.Enabled {
+		publisher, err := natspublish.New(cfg.NATS.Servers, cfg.NATS.Stream, cfg.NATS.AckTimeout)
+		if err != nil {
+			return fmt.Errorf("nats: %w", err)
+		}
+		defer publisher.Close()
+		svc = svc.WithNATSPublisher(publisher)
+	}
+	handler := httpapi.NewHandler(svc).
+		WithMetricsCacheTTL(cfg.Stats.MetricsCacheTTL).
+		WithAuthPolicies(cfg.Auth.RoutePolicies).
+		WithClientIPConfig(cfg.ClientIP)
+	streamHandler := httpapi.NewStreamHandler(streamHub)
+	docsHandler := httpapi.NewDocsHandler(cfg.Docs.Enabled)
+
+	graphqlHandler, err := graphqlapi.NewHandler(svc)
+	if err != nil {
+		return fmt.Errorf("graphqlapi: %w", err)
+	}
+	graphqlHandler = graphqlHandler.WithAuthPolicies(cfg.Auth.RoutePolicies)
+
+	batchSvc := service.NewBatchIngestService(repo)
+	batchHandler := httpapi.NewBatchHandler(batchSvc)
+
+	asyncSvc := service.NewAsyncWriteService(repo, cfg.AsyncWrite.BatchSize, cfg.AsyncWrite.FlushInterval).
+		WithRetry(cfg.AsyncWrite.MaxAttempts, cfg.AsyncWrite.RetryBackoff).
+		WithStatusTransitions(cfg.AsyncWrite.StatusTransitions)
+	if cfg.AsyncWrite.PersistentQueueEnabled {
+		queueBreaker := resilience.NewBreaker("async_write_queue", cfg.Redis.CircuitBreakerThreshold, cfg.Redis.CircuitBreakerCooldown)
+		asyncSvc = asyncSvc.WithPersistentQueue(queue.NewRedisWAL(cfg.Redis.Addr, redisRetry, queueBreaker))
+		if err := asyncSvc.Recover(ctx); err != nil {
+			return err
+		}
+	}
+	go asyncSvc.Run(ctx)
+	asyncHandler := httpapi.NewAsyncHandler(asyncSvc)
+	asyncQueueChecker := health.NewAsyncQueueChecker(asyncSvc, cfg.AsyncWrite.DegradedQueueDepth)
+
+	// redisInUse mirrors the feature flags above: Redis is only a real
+	// dependency of this deployment if something actually reaches it, so
+	// deployments that use none of these features never dial Redis just
+	// to health-check it.
+	redisInUse := cfg.Cache.Enabled || cfg.Dedup.Enabled || cfg.Throttle.Enabled || cfg.AsyncWrite.PersistentQueueEnabled
+	var redisChecker *health.RedisChecker
+	if redisInUse {
+		redisClient := redis.NewClient(&redis.Options{Addr: cfg.Redis.Addr})
+		redisChecker = health.NewRedisChecker(health.PingFunc(func(ctx context.Context) error {
+			return redisClient.Ping(ctx).Err()
+		}), cfg.Redis.HealthCheckTimeout)
+		go redisChecker.Run(ctx, cfg.Redis.HealthCheckInterval)
+	}
+	checkHandler := health.NewCheckHandler().AddCheck("database", dbChecker, true)
+	// redisChecker is a typed nil when Redis isn't in use; only add it when
+	// it is non-nil rather than passing the typed pointer, since a typed nil
+	// assigned to the Checker interface wouldn't trip AddCheck's nil-guard.
+	if redisChecker != nil {
+		checkHandler.AddCheck("redis", redisChecker, cfg.Redis.RequiredForReadiness)
+	}
+
+	adaptiveLevel := httpapi.NewAdaptiveLevel(cfg.AdaptiveLog)
+	go adaptiveLevel.Run(ctx, cfg.AdaptiveLog.Window)
+
+	go reloadOnSIGHUP(ctx, cfg, logLevel, adaptiveLevel, dispatcher)
+
+	r := chi.NewRouter()
+	r.Use(httpapi.MaxBodyBytes(cfg.RequestLimits))
+	r.Use(httpapi.PerfLogMiddleware)
+	r.Use(tracing.Middleware)
+	r.Use(metrics.Middleware)
+	r.Get("/healthz/ready", ready.Handler())
+	r.Get("/healthz/async", asyncQueueChecker.Handler())
+	r.Get("/healthz/checks", checkHandler.Handler())
+	r.Handle("/metrics", metrics.Handler())
+	go reportDBPoolStats(ctx, pool)
+	if cfg.DB.RejectWhenUnhealthy {
+		r.Use(httpapi.RejectUnhealthyDB(dbChecker))
+	}
+	authN := authResolvers(ctx, cfg.Auth)
+	r.Use(httpapi.TenantFromHeader(cfg.TenantHeader))
+	r.Use(auth.OptionalAuth(authN...))
+	r.Use(httpapi.RequestLogging(adaptiveLevel))
+	r.Use(httpapi.AccessAudit(cfg.AccessAudit))
+	handler.Routes(r)
+	webhookHandler.Routes(r)
+	notificationHandler.Routes(r)
+	batchHandler.Routes(r)
+	asyncHandler.Routes(r)
+	streamHandler.Routes(r)
+	graphqlHandler.Routes(r)
+	docsHandler.Routes(r)
+
+	listener, err := net.Listen(cfg.ListenNetwork, cfg.ListenAddr)
+	if err != nil {
+		return fmt.Errorf("listen on %s %s: %w", cfg.ListenNetwork, cfg.ListenAddr, err)
+	}
+
+	if cfg.AdminListenAddr != "" {
+		adminListener, err := net.Listen(cfg.ListenNetwork, cfg.AdminListenAddr)
+		if err != nil {
+			return fmt.Errorf("listen on admin address %s %s: %w", cfg.ListenNetwork, cfg.AdminListenAddr, err)
+		}
+		admin := chi.NewRouter()
+		admin.Get("/healthz/ready", ready.Handler())
+		admin.Get("/healthz/async", asyncQueueChecker.Handler())
+		go func() {
+			log.Printf("audit-log-service admin listener on %s (%s)", cfg.AdminListenAddr, cfg.ListenNetwork)
+			if err := http.Serve(adminListener, admin); err != nil {
+				log.Printf("admin listener stopped: %v", err)
+			}
+		}()
+	}
+
+	var grpcServer *grpc.Server
+	if cfg.GRPCListenAddr != "" {
+		grpcListener, err := net.Listen(cfg.ListenNetwork, cfg.GRPCListenAddr)
+		if err != nil {
+			return fmt.Errorf("listen on grpc address %s %s: %w", cfg.ListenNetwork, cfg.GRPCListenAddr, err)
+		}
+		grpcServer = grpc.NewServer(grpc.UnaryInterceptor(grpcapi.AuthUnaryInterceptor(authN...)))
+		auditpb.RegisterAuditServiceServer(grpcServer, grpcapi.NewServer(svc))
+		go func() {
+			log.Printf("audit-log-service grpc listener on %s (%s)", cfg.GRPCListenAddr, cfg.ListenNetwork)
+			if err := grpcServer.Serve(grpcListener); err != nil {
+				log.Printf("grpc listener stopped: %v", err)
+			}
+		}()
+	}
+
+	// BaseContext ties every request's context to ctx, so cancel() (called
+	// below as the first step of shutdown) unblocks any handler still
+	// waiting on r.Context().Done() — notably StreamHandler's long-lived
+	// SSE connections — instead of leaving them open until server.Shutdown
+	// times out.
+	server := &http.Server{Handler: r, BaseContext: func(net.Listener) context.Context { return ctx }}
+	serveErr := make(chan error, 1)
+	if cfg.Auth.MTLS.Enabled {
+		tlsConfig, err := mtlsServerConfig(cfg.Auth.MTLS)
+		if err != nil {
+			return err
+		}
+		server.TLSConfig = tlsConfig
+		log.Printf("audit-log-service listening on %s (%s, mTLS enabled)", cfg.ListenAddr, cfg.ListenNetwork)
+		go func() { serveErr <- server.ServeTLS(listener, cfg.Auth.MTLS.CertFile, cfg.Auth.MTLS.KeyFile) }()
+	} else {
+		log.Printf("audit-log-service listening on %s (%s)", cfg.ListenAddr, cfg.ListenNetwork)
+		go func() { serveErr <- server.Serve(listener) }()
+	}
+
+	shutdownSignal := make(chan os.Signal, 1)
+	signal.Notify(shutdownSignal, syscall.SIGTERM, syscall.SIGINT)
+	defer signal.Stop(shutdownSignal)
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-shutdownSignal:
+		log.Print("audit-log-service: shutdown signal received, draining in-flight work")
+	}
+
+	// Stop accepting new work everywhere at once: every background
+	// Run(ctx) loop in this file (adaptiveLevel, retentionWorker,
+	// notificationPool, reloadOnSIGHUP, ...) already exits on ctx.Done.
+	cancel()
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer shutdownCancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("audit-log-service: http server shutdown: %v", err)
+	}
+	if grpcServer != nil {
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-shutdownCtx.Done():
+			grpcServer.Stop()
+		}
+	}
+	if err := notificationPool.Shutdown(shutdownCtx); err != nil {
+		log.Printf("audit-log-service: notification pool did not finish draining before shutdown timeout: %v", err)
+	}
+	return nil
+}
+
+// shutdownTimeout bounds how long a SIGTERM/SIGINT shutdown waits for the
+// HTTP server to finish in-flight requests and for notificationPool to
+// finish draining already-accepted notifications before giving up.
+const shutdownTimeout = 30 * time.Second
+
+// authResolvers builds the credential resolvers for the configured
+// credential types. Wrapped in auth.OptionalAuth for the REST/GraphQL
+// listener and in grpcapi.AuthUnaryInterceptor for the gRPC listener, so
+// every transport authenticates identically. Handlers that require an
+// authenticated caller check auth.FromContext themselves; resolving only
+// attaches identity when present.
+func authResolvers(ctx context.Context, cfg config.AuthConfig) []auth.Resolver {
+	var resolvers []auth.Resolver
+
+	if len(cfg.APIKeys) > 0 {
+		keys := make(map[string]auth.Principal, len(cfg.APIKeys))
+		for key, principal := range cfg.APIKeys {
+			keys[key] = auth.Principal{ID: principal, AllowedTenants: cfg.APIKeyTenants[key], Scopes: cfg.APIKeyScopes[key]}
+		}
+		resolvers = append(resolvers, auth.APIKeyResolver{Keys: keys})
+	}
+	if cfg.JWTSecret != "" || cfg.JWKSURL != "" {
+		jwtResolver := auth.JWTResolver{Secret: []byte(cfg.JWTSecret), Issuer: cfg.JWTIssuer, Audience: cfg.JWTAudience}
+		if cfg.JWKSURL != "" {
+			keys := auth.NewJWKSKeySource(cfg.JWKSURL, http.DefaultClient)
+			go keys.Run(ctx, cfg.JWKSRefreshInterval)
+			jwtResolver.Keys = keys
+		}
+		resolvers = append(resolvers, jwtResolver)
+	}
+	if cfg.MTLS.Enabled {
+		principals := make(map[string]auth.Principal, len(cfg.MTLS.Principals))
+		for cn, principal := range cfg.MTLS.Principals {
+			principals[cn] = auth.Principal{ID: principal, Scopes: cfg.MTLS.Scopes[cn]}
+		}
+		resolvers = append(resolvers, auth.MTLSResolver{Principals: principals})
+	}
+
+	return resolvers
+}
+
+// dbPoolStatsInterval is how often reportDBPoolStats samples the pgx
+// pool's connection usage into metrics.DBPoolInUseConnections.
+const dbPoolStatsInterval = 15 * time.Second
+
+// reportDBPoolStats periodically publishes pool's in-use connection count
+// to metrics.DBPoolInUseConnections, since pgxpool.Pool exposes it only
+// via an on-demand Stat() call rather than its own metrics hook.
+func reportDBPoolStats(ctx context.Context, pool *pgxpool.Pool) {
+	ticker := time.NewTicker(dbPoolStatsInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			metrics.DBPoolInUseConnections.Set(float64(pool.Stat().AcquiredConns()))
+		}
+	}
+}
+
+// slogLevelFromName maps config.LoggingConfig.Level's allowed values to
+// their slog.Level, mirroring config.logLevelAllowlist. Falls back to
+// info for anything unrecognized, matching config.Load's own default.
+func slogLevelFromName(name string) slog.Level {
+	switch name {
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// reloadOnSIGHUP re-loads Config on every SIGHUP and pushes the subset of
+// it that is safe to change on a running process into the already-built
+// components that hold it: the base log level, AdaptiveLevel's config,
+// and the notification dispatcher's throttle windows. Everything else
+// (listen addresses, the database URL, TLS material, ...) requires a
+// restart and is deliberately left untouched — a reload never applies
+// them, and logs a warning only when a listen address or the database URL
+// changed.
+func reloadOnSIGHUP(ctx context.Context, cfg config.Config, logLevel *slog.LevelVar, adaptiveLevel *httpapi.AdaptiveLevel, dispatcher *notify.Dispatcher) {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	defer signal.Stop(sighup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sighup:
+			next, err := config.Load()
+			if err != nil {
+				slog.Error("config: reload failed, keeping previous config", "error", err)
+				continue
+			}
+
+			var changed []string
+			if next.Logging.Level != cfg.Logging.Level {
+				changed = append(changed, fmt.Sprintf("logging.level: %q -> %q", cfg.Logging.Level, next.Logging.Level))
+				logLevel.Set(slogLevelFromName(next.Logging.Level))
+			}
+			if next.AdaptiveLog != cfg.AdaptiveLog {
+				changed = append(changed, fmt.Sprintf("adaptive_log: %+v -> %+v", cfg.AdaptiveLog, next.AdaptiveLog))
+				adaptiveLevel.SetConfig(next.AdaptiveLog)
+			}
+			if next.Throttle.DefaultWindow != cfg.Throttle.DefaultWindow || !reflect.DeepEqual(next.Throttle.Windows, cfg.Throttle.Windows) {
+				changed = append(changed, fmt.Sprintf("throttle.windows: %+v/%v -> %+v/%v", cfg.Throttle.DefaultWindow, cfg.Throttle.Windows, next.Throttle.DefaultWindow, next.Throttle.Windows))
+				dispatcher.SetThrottleWindows(next.Throttle.DefaultWindow, next.Throttle.Windows)
+			}
+			if next.ListenAddr != cfg.ListenAddr || next.ListenNetwork != cfg.ListenNetwork || next.AdminListenAddr != cfg.AdminListenAddr || next.GRPCListenAddr != cfg.GRPCListenAddr || next.DatabaseURL != cfg.DatabaseURL {
+				slog.Warn("config: reload saw a change to a setting that requires a restart to take effect, ignoring it")
+			}
+
+			if len(changed) == 0 {
+				slog.Info("config: reload saw no reloadable changes")
+			} else {
+				slog.Info("config: reloaded", "changes", changed)
+			}
+			cfg = next
+		}
+	}
+}
+
+func mtlsServerConfig(cfg config.MTLSConfig) (*tls.Config, error) {
+	caPEM, err := os.ReadFile(cfg.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("mtls: read client CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("mtls: no certificates found in %s", cfg.ClientCAFile)
+	}
+
+	return &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  pool,
+	}, nil
+}
@@ -0,0 +1,76 @@
+// Package archive offloads audit logs to external storage before
+// RetentionWorker deletes them from Postgres, instead of deleting them
+// outright (see config.ArchiveConfig and config.RetentionConfig).
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+)
+
+// Archiver durably offloads a batch of old audit logs, all belonging to
+// tenantID, before the retention worker removes them from the hot table.
+type Archiver interface {
+	// Archive uploads logs as a single compressed NDJSON batch and
+	// verifies the upload succeeded before returning, so the caller only
+	// deletes the rows once they're confirmed durable elsewhere. Returns
+	// the object's location (e.g. an s3:// URI) for logging.
+	Archive(ctx context.Context, tenantID string, logs []domain.AuditLog) (string, error)
+}
+
+// exportRecord is the archive's per-record JSON shape. It deliberately
+// mirrors httpapi's createAuditLogRequest fields (tenant_id, actor, event,
+// resource, resource_id, data, meta, tags, timestamp) so each line of a
+// decompressed archive is a valid entry for /api/v1/audit/batch, which
+// takes the entries as one JSON array. Server-assigned fields
+// (ID, Sequence, Hash, PrevHash, AuthType) are intentionally dropped:
+// re-importing always produces fresh values for those, the same as any
+// other create.
+type exportRecord struct {
+	TenantID   string         `json:"tenant_id"`
+	Actor      string         `json:"actor"`
+	Event      string         `json:"event"`
+	Resource   string         `json:"resource"`
+	ResourceID string         `json:"resource_id"`
+	Data       map[string]any `json:"data,omitempty"`
+	Meta       map[string]any `json:"meta,omitempty"`
+	Tags       []string       `json:"tags,omitempty"`
+	Timestamp  *time.Time     `json:"timestamp,omitempty"`
+}
+
+// serializeNDJSONGzip renders logs as gzip-compressed, newline-delimited
+// JSON, one exportRecord per line.
+func serializeNDJSONGzip(logs []domain.AuditLog) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	for _, log := range logs {
+		createdAt := log.CreatedAt
+		body, err := json.Marshal(exportRecord{
+			TenantID:   log.TenantID,
+			Actor:      log.Actor,
+			Event:      log.Event,
+			Resource:   log.Resource,
+			ResourceID: log.ResourceID,
+			Data:       log.Data,
+			Meta:       log.Meta,
+			Tags:       log.Tags,
+			Timestamp:  &createdAt,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("archive: marshal record %s: %w", log.ID, err)
+		}
+		if _, err := gz.Write(append(body, '\n')); err != nil {
+			return nil, fmt.Errorf("archive: write record %s: %w", log.ID, err)
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("archive: finalize gzip stream: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,58 @@
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+)
+
+func TestSerializeNDJSONGzip_ProducesOneReimportableLinePerRecord(t *testing.T) {
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	logs := []domain.AuditLog{
+		{ID: uuid.New(), TenantID: "tenant-a", Actor: "alice", Event: "user.login", Resource: "session", ResourceID: "1", CreatedAt: createdAt},
+		{ID: uuid.New(), TenantID: "tenant-a", Actor: "bob", Event: "user.logout", Resource: "session", ResourceID: "2", CreatedAt: createdAt},
+	}
+
+	compressed, err := serializeNDJSONGzip(logs)
+	if err != nil {
+		t.Fatalf("serializeNDJSONGzip: %v", err)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		t.Fatalf("gzip.NewReader: %v", err)
+	}
+	defer gz.Close()
+
+	var got []exportRecord
+	scanner := bufio.NewScanner(gz)
+	for scanner.Scan() {
+		var rec exportRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			t.Fatalf("unmarshal line %q: %v", scanner.Text(), err)
+		}
+		got = append(got, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+
+	if len(got) != len(logs) {
+		t.Fatalf("got %d lines, want %d", len(got), len(logs))
+	}
+	for i, log := range logs {
+		if got[i].TenantID != log.TenantID || got[i].Actor != log.Actor || got[i].Event != log.Event {
+			t.Errorf("line %d: got %+v, want fields from %+v", i, got[i], log)
+		}
+		if got[i].Timestamp == nil || !got[i].Timestamp.Equal(log.CreatedAt) {
+			t.Errorf("line %d: got timestamp %v, want %v", i, got[i].Timestamp, log.CreatedAt)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/google/uuid"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+)
+
+// S3Archiver implements Archiver on top of Amazon S3 (or an
+// S3-compatible store pointed to via the client's configured endpoint).
+type S3Archiver struct {
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+// NewS3Archiver returns an Archiver uploading to bucket under prefix,
+// using client for the actual transfer.
+func NewS3Archiver(client *s3.Client, bucket, prefix string) *S3Archiver {
+	return &S3Archiver{client: client, bucket: bucket, prefix: prefix}
+}
+
+// Archive implements Archiver.
+func (a *S3Archiver) Archive(ctx context.Context, tenantID string, logs []domain.AuditLog) (string, error) {
+	body, err := serializeNDJSONGzip(logs)
+	if err != nil {
+		return "", err
+	}
+
+	key := objectKey(a.prefix, tenantID, time.Now())
+
+	if _, err := a.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:          aws.String(a.bucket),
+		Key:             aws.String(key),
+		Body:            bytes.NewReader(body),
+		ContentType:     aws.String("application/x-ndjson"),
+		ContentEncoding: aws.String("gzip"),
+	}); err != nil {
+		return "", fmt.Errorf("archive: upload to s3://%s/%s: %w", a.bucket, key, err)
+	}
+
+	// HeadObject confirms the object actually landed before the caller
+	// deletes the rows it represents, rather than trusting PutObject's
+	// success response alone.
+	if _, err := a.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(a.bucket),
+		Key:    aws.String(key),
+	}); err != nil {
+		return "", fmt.Errorf("archive: verify upload to s3://%s/%s: %w", a.bucket, key, err)
+	}
+
+	return fmt.Sprintf("s3://%s/%s", a.bucket, key), nil
+}
+
+// objectKey lays out archives as <prefix>/<tenantID>/<date>/<uuid>.ndjson.gz
+// so a tenant's records stay browsable by date directly in the bucket.
+func objectKey(prefix, tenantID string, at time.Time) string {
+	return path.Join(prefix, tenantID, at.UTC().Format("2006-01-02"), uuid.NewString()+".ndjson.gz")
+}
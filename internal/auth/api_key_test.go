@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIKeyResolver_MatchesPlaintextKey(t *testing.T) {
+	resolver := APIKeyResolver{Keys: map[string]Principal{"secret": {ID: "svc-a"}}}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-API-Key", "secret")
+
+	principal, ok, err := resolver.Resolve(req)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if !ok || principal.ID != "svc-a" {
+		t.Errorf("got (%+v, %v), want svc-a", principal, ok)
+	}
+}
+
+func TestAPIKeyResolver_MatchesHashedKey(t *testing.T) {
+	resolver := APIKeyResolver{Keys: map[string]Principal{HashAPIKey("secret"): {ID: "svc-a"}}}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-API-Key", "secret")
+
+	principal, ok, err := resolver.Resolve(req)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if !ok || principal.ID != "svc-a" {
+		t.Errorf("got (%+v, %v), want svc-a", principal, ok)
+	}
+}
+
+func TestAPIKeyResolver_RejectsWrongKeyAgainstHashedEntry(t *testing.T) {
+	resolver := APIKeyResolver{Keys: map[string]Principal{HashAPIKey("secret"): {ID: "svc-a"}}}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-API-Key", "wrong")
+
+	_, ok, err := resolver.Resolve(req)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if ok {
+		t.Error("expected the wrong key to be rejected")
+	}
+}
+
+func TestRedactAPIKey_KeepsOnlyAShortPrefix(t *testing.T) {
+	got := redactAPIKey("sk_live_abcdefgh")
+	if got != "sk_l************" {
+		t.Errorf("redactAPIKey = %q, want a 4-char prefix with the rest masked", got)
+	}
+	if got := redactAPIKey("ab"); got != "**" {
+		t.Errorf("redactAPIKey(short) = %q, want fully masked", got)
+	}
+}
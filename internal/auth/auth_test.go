@@ -0,0 +1,312 @@
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func tlsConnectionStateWithCN(cn string) *tls.ConnectionState {
+	return &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{
+			{Subject: pkix.Name{CommonName: cn}},
+		},
+	}
+}
+
+func TestOptionalAuth_APIKeyMatch(t *testing.T) {
+	resolver := APIKeyResolver{Keys: map[string]Principal{"secret": {ID: "svc-a"}}}
+
+	var gotPrincipal Principal
+	var gotOK bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ac, ok := FromContext(r.Context())
+		gotOK = ok
+		gotPrincipal = ac.Principal
+	})
+
+	handler := OptionalAuth(resolver)(next)
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-API-Key", "secret")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if !gotOK {
+		t.Fatal("expected auth context to be set")
+	}
+	if gotPrincipal.ID != "svc-a" {
+		t.Errorf("got principal %q, want svc-a", gotPrincipal.ID)
+	}
+}
+
+func TestOptionalAuth_NoCredentialPassesThrough(t *testing.T) {
+	resolver := APIKeyResolver{Keys: map[string]Principal{"secret": {ID: "svc-a"}}}
+
+	called := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		if _, ok := FromContext(r.Context()); ok {
+			t.Error("expected no auth context")
+		}
+	})
+
+	handler := OptionalAuth(resolver)(next)
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if !called {
+		t.Fatal("expected next handler to be called")
+	}
+}
+
+func TestRequireAuth_RejectsUnauthenticated(t *testing.T) {
+	resolver := APIKeyResolver{Keys: map[string]Principal{"secret": {ID: "svc-a"}}}
+	handler := RequireAuth(resolver)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("next handler should not be called")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("got status %d, want 401", rec.Code)
+	}
+}
+
+func TestMTLSResolver_MapsCommonName(t *testing.T) {
+	resolver := MTLSResolver{Principals: map[string]Principal{"svc-a.internal": {ID: "svc-a"}}}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.TLS = tlsConnectionStateWithCN("svc-a.internal")
+
+	principal, ok, err := resolver.Resolve(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ok || principal.ID != "svc-a" {
+		t.Errorf("got principal=%+v ok=%v, want svc-a/true", principal, ok)
+	}
+}
+
+func TestRequire_RejectsMissingAuthWhenRequired(t *testing.T) {
+	handler := Require(RoutePolicy{RequireAuth: true})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("expected handler not to be called")
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/", nil))
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401", rec.Code)
+	}
+}
+
+func TestRequire_AllowsUnauthenticatedWhenNotRequired(t *testing.T) {
+	called := false
+	handler := Require(RoutePolicy{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if !called {
+		t.Fatal("expected handler to be called")
+	}
+}
+
+func TestRequire_RejectsDisallowedCredentialType(t *testing.T) {
+	handler := Require(RoutePolicy{AllowedTypes: []Type{TypeJWT}})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("expected handler not to be called")
+	}))
+
+	req := httptest.NewRequest(http.MethodDelete, "/", nil)
+	ctx := WithContext(req.Context(), Context{Principal: Principal{ID: "svc-a"}, Type: TypeAPIKey})
+	req = req.WithContext(ctx)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want 403", rec.Code)
+	}
+}
+
+func TestRequire_RejectsMissingScope(t *testing.T) {
+	handler := Require(RoutePolicy{RequiredScopes: []string{"audit:delete"}})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("expected handler not to be called")
+	}))
+
+	req := httptest.NewRequest(http.MethodDelete, "/", nil)
+	ctx := WithContext(req.Context(), Context{Principal: Principal{ID: "svc-a", Scopes: []string{"audit:read"}}, Type: TypeJWT})
+	req = req.WithContext(ctx)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want 403", rec.Code)
+	}
+}
+
+func TestTenantAllowed_UnauthenticatedIsUnrestricted(t *testing.T) {
+	if !TenantAllowed(context.Background(), "tenant-a") {
+		t.Error("expected unauthenticated caller to be unrestricted")
+	}
+}
+
+func TestTenantAllowed_EmptyAllowedTenantsIsUnrestricted(t *testing.T) {
+	ctx := WithContext(context.Background(), Context{Principal: Principal{ID: "svc-a"}, Type: TypeAPIKey})
+	if !TenantAllowed(ctx, "tenant-a") {
+		t.Error("expected principal with no AllowedTenants to be unrestricted")
+	}
+}
+
+func TestTenantAllowed_AllowsListedTenant(t *testing.T) {
+	ctx := WithContext(context.Background(), Context{
+		Principal: Principal{ID: "svc-a", AllowedTenants: []string{"tenant-a", "tenant-b"}},
+		Type:      TypeAPIKey,
+	})
+	if !TenantAllowed(ctx, "tenant-b") {
+		t.Error("expected tenant-b to be allowed")
+	}
+}
+
+func TestTenantAllowed_RejectsUnlistedTenant(t *testing.T) {
+	ctx := WithContext(context.Background(), Context{
+		Principal: Principal{ID: "svc-a", AllowedTenants: []string{"tenant-a"}},
+		Type:      TypeAPIKey,
+	})
+	if TenantAllowed(ctx, "tenant-b") {
+		t.Error("expected tenant-b to be rejected")
+	}
+}
+
+func TestEffectiveTenant_ReturnsSoleAllowedTenant(t *testing.T) {
+	ctx := WithContext(context.Background(), Context{
+		Principal: Principal{ID: "user-1", AllowedTenants: []string{"tenant-a"}},
+		Type:      TypeJWT,
+	})
+	tenantID, ok := EffectiveTenant(ctx)
+	if !ok || tenantID != "tenant-a" {
+		t.Errorf("EffectiveTenant = %q, %v, want tenant-a, true", tenantID, ok)
+	}
+}
+
+func TestEffectiveTenant_FalseForUnrestrictedPrincipal(t *testing.T) {
+	ctx := WithContext(context.Background(), Context{Principal: Principal{ID: "svc-a"}, Type: TypeAPIKey})
+	if _, ok := EffectiveTenant(ctx); ok {
+		t.Error("expected an unrestricted principal to have no single effective tenant")
+	}
+}
+
+func TestEffectiveTenant_FalseForMultiTenantPrincipal(t *testing.T) {
+	ctx := WithContext(context.Background(), Context{
+		Principal: Principal{ID: "svc-a", AllowedTenants: []string{"tenant-a", "tenant-b"}},
+		Type:      TypeAPIKey,
+	})
+	if _, ok := EffectiveTenant(ctx); ok {
+		t.Error("expected a principal restricted to multiple tenants to have no single effective tenant")
+	}
+}
+
+func TestEffectiveTenant_FalseForUnauthenticated(t *testing.T) {
+	if _, ok := EffectiveTenant(context.Background()); ok {
+		t.Error("expected an unauthenticated caller to have no effective tenant")
+	}
+}
+
+func TestRequireScope_ReadOnlyAPIKeyRejectedOnDelete(t *testing.T) {
+	resolver := APIKeyResolver{Keys: map[string]Principal{
+		"readonly-key": {ID: "svc-readonly", Scopes: []string{"audit:read"}},
+	}}
+	handler := OptionalAuth(resolver)(RequireScope("audit:delete")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("expected handler not to be called for a read-only key")
+	})))
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/audit/123", nil)
+	req.Header.Set("X-API-Key", "readonly-key")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want 403", rec.Code)
+	}
+}
+
+func TestRequireScope_DeleteScopedAPIKeyAllowedOnDelete(t *testing.T) {
+	resolver := APIKeyResolver{Keys: map[string]Principal{
+		"admin-key": {ID: "svc-admin", Scopes: []string{"audit:read", "audit:write", "audit:delete"}},
+	}}
+	called := false
+	handler := OptionalAuth(resolver)(RequireScope("audit:delete")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})))
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/audit/123", nil)
+	req.Header.Set("X-API-Key", "admin-key")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if !called {
+		t.Fatal("expected handler to be called for a key with the audit:delete scope")
+	}
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", rec.Code)
+	}
+}
+
+func TestRequireScope_ReadOnlyAPIKeyAllowedOnRead(t *testing.T) {
+	resolver := APIKeyResolver{Keys: map[string]Principal{
+		"readonly-key": {ID: "svc-readonly", Scopes: []string{"audit:read"}},
+	}}
+	called := false
+	handler := OptionalAuth(resolver)(RequireScope("audit:read")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/audit/123", nil)
+	req.Header.Set("X-API-Key", "readonly-key")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if !called {
+		t.Fatal("expected handler to be called for a read scope on a read route")
+	}
+}
+
+func TestRequireScope_RejectsUnauthenticatedRequest(t *testing.T) {
+	handler := RequireScope("audit:delete")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("expected handler not to be called")
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/v1/audit/123", nil))
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401", rec.Code)
+	}
+}
+
+func TestRequire_AllowsMatchingTypeAndScope(t *testing.T) {
+	called := false
+	handler := Require(RoutePolicy{AllowedTypes: []Type{TypeJWT}, RequiredScopes: []string{"audit:delete"}})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	req := httptest.NewRequest(http.MethodDelete, "/", nil)
+	ctx := WithContext(req.Context(), Context{Principal: Principal{ID: "svc-a", Scopes: []string{"audit:delete"}}, Type: TypeJWT})
+	req = req.WithContext(ctx)
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if !called {
+		t.Fatal("expected handler to be called")
+	}
+}
@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// jwk is the subset of a JSON Web Key's fields JWKSKeySource needs to
+// reconstruct an RSA public key (RFC 7517). Only RSA keys are supported,
+// matching RS256 being the only asymmetric algorithm JWTResolver accepts.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type jwksDocument struct {
+	Keys []jwk `json:"keys"`
+}
+
+// JWKSKeySource fetches and caches the RSA public keys published at a
+// JWKS endpoint (typically an OIDC provider's jwks_uri), keyed by "kid"
+// so JWTResolver can pick the right key per token without knowing the
+// provider's rotation schedule. Call Run in its own goroutine to keep
+// the cache fresh; KeyForKID is also safe to call before the first
+// refresh completes, performing one synchronously.
+type JWKSKeySource struct {
+	url    string
+	client *http.Client
+
+	mu   sync.RWMutex
+	keys map[string]*rsa.PublicKey
+}
+
+// NewJWKSKeySource returns a key source fetching from url with client.
+// The cache starts empty; the first KeyForKID or Run tick populates it.
+func NewJWKSKeySource(url string, client *http.Client) *JWKSKeySource {
+	return &JWKSKeySource{url: url, client: client}
+}
+
+// KeyForKID returns the cached RSA public key for kid, fetching the JWKS
+// document first if the cache is still empty.
+func (s *JWKSKeySource) KeyForKID(kid string) (*rsa.PublicKey, error) {
+	s.mu.RLock()
+	key, ok := s.keys[kid]
+	empty := s.keys == nil
+	s.mu.RUnlock()
+	if ok {
+		return key, nil
+	}
+	if empty {
+		if err := s.refresh(); err != nil {
+			return nil, err
+		}
+		s.mu.RLock()
+		key, ok = s.keys[kid]
+		s.mu.RUnlock()
+		if ok {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("auth: no JWKS key found for kid %q", kid)
+}
+
+// Run refreshes the cached keys every interval until ctx is canceled,
+// logging failures rather than stopping, so a transient outage at the
+// JWKS endpoint doesn't permanently break RS256 verification.
+func (s *JWKSKeySource) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.refresh(); err != nil {
+				log.Printf("%v", err)
+			}
+		}
+	}
+}
+
+func (s *JWKSKeySource) refresh() error {
+	resp, err := s.client.Get(s.url)
+	if err != nil {
+		return fmt.Errorf("auth: fetch JWKS from %s: %w", s.url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("auth: fetch JWKS from %s: status %d", s.url, resp.StatusCode)
+	}
+
+	var doc jwksDocument
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return fmt.Errorf("auth: decode JWKS from %s: %w", s.url, err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(doc.Keys))
+	for _, k := range doc.Keys {
+		if k.Kty != "RSA" || k.Kid == "" {
+			continue
+		}
+		pub, err := k.rsaPublicKey()
+		if err != nil {
+			return fmt.Errorf("auth: parse JWKS key %q: %w", k.Kid, err)
+		}
+		keys[k.Kid] = pub
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.mu.Unlock()
+	return nil
+}
+
+func (k jwk) rsaPublicKey() (*rsa.PublicKey, error) {
+	nBytes, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("decode modulus: %w", err)
+	}
+	eBytes, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("decode exponent: %w", err)
+	}
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(nBytes),
+		E: int(new(big.Int).SetBytes(eBytes).Int64()),
+	}, nil
+}
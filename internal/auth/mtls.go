@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+)
+
+// MTLSResolver authenticates requests on connections where the TLS
+// handshake already verified a client certificate (the server must be
+// configured with tls.RequireAndVerifyClientCert for this to be
+// meaningful — this resolver only maps an already-verified cert to a
+// principal, it does not itself verify the chain).
+type MTLSResolver struct {
+	// Principals maps a verified certificate's Subject Common Name (or,
+	// when the CN has no entry, one of its DNS SANs) to the
+	// principal/scopes it authenticates as. Certs with neither present
+	// here are rejected even though the chain verified, since an unmapped
+	// cert is very likely a config gap rather than an intentional grant.
+	Principals map[string]Principal
+}
+
+func (MTLSResolver) Type() Type { return TypeMTLS }
+
+func (r MTLSResolver) Resolve(req *http.Request) (Principal, bool, error) {
+	if req.TLS == nil || len(req.TLS.PeerCertificates) == 0 {
+		return Principal{}, false, nil
+	}
+	cert := req.TLS.PeerCertificates[0]
+
+	cn := cert.Subject.CommonName
+	if principal, ok := r.Principals[cn]; ok {
+		return principal, true, nil
+	}
+
+	for _, san := range cert.DNSNames {
+		if principal, ok := r.Principals[san]; ok {
+			return principal, true, nil
+		}
+	}
+
+	return Principal{}, false, errUnmappedClientCert
+}
+
+var errUnmappedClientCert = errors.New("auth: client certificate has no mapped principal")
@@ -0,0 +1,90 @@
+// Package cache implements a Redis-backed read-through cache (see
+// service.AuditService's Cache), used to relieve Postgres of repeat
+// GetByID lookups for hot records.
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/resilience"
+)
+
+// RedisCache stores values in Redis under a caller-supplied key, with a
+// caller-supplied TTL. Caching is an optimization, not a correctness
+// requirement, so it fails open: once breaker reports the circuit open,
+// Get behaves as a cache miss and Set/Delete are no-ops, rather than
+// blocking a read or write on a down dependency.
+type RedisCache struct {
+	client  *redis.Client
+	breaker *resilience.Breaker
+}
+
+// NewRedisCache returns a cache backed by a Redis client connected to
+// addr, retrying per retry and tripping breaker on repeated failures (see
+// resilience.Breaker). breaker may be nil to disable tripping.
+func NewRedisCache(addr string, retry resilience.RetryOptions, breaker *resilience.Breaker) *RedisCache {
+	return &RedisCache{
+		client: redis.NewClient(&redis.Options{
+			Addr:            addr,
+			MaxRetries:      retry.MaxRetries,
+			MinRetryBackoff: retry.MinRetryBackoff,
+			MaxRetryBackoff: retry.MaxRetryBackoff,
+		}),
+		breaker: breaker,
+	}
+}
+
+// Get returns the value stored under key, or found=false if it is absent
+// (never set, its TTL has expired, or the circuit breaker is currently
+// open).
+func (c *RedisCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	if !c.breaker.Allow() {
+		return nil, false, nil
+	}
+
+	val, err := c.client.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		c.breaker.RecordSuccess()
+		return nil, false, nil
+	}
+	if err != nil {
+		c.breaker.RecordFailure()
+		return nil, false, fmt.Errorf("cache: get %s: %w", key, err)
+	}
+	c.breaker.RecordSuccess()
+	return val, true, nil
+}
+
+// Set stores value under key, expiring after ttl. It is a no-op while the
+// circuit breaker is open.
+func (c *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if !c.breaker.Allow() {
+		return nil
+	}
+
+	if err := c.client.Set(ctx, key, value, ttl).Err(); err != nil {
+		c.breaker.RecordFailure()
+		return fmt.Errorf("cache: set %s: %w", key, err)
+	}
+	c.breaker.RecordSuccess()
+	return nil
+}
+
+// Delete removes key, if present. It is a no-op while the circuit
+// breaker is open.
+func (c *RedisCache) Delete(ctx context.Context, key string) error {
+	if !c.breaker.Allow() {
+		return nil
+	}
+
+	if err := c.client.Del(ctx, key).Err(); err != nil {
+		c.breaker.RecordFailure()
+		return fmt.Errorf("cache: delete %s: %w", key, err)
+	}
+	c.breaker.RecordSuccess()
+	return nil
+}
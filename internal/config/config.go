@@ -0,0 +1,1739 @@
+// Package config loads service configuration from environment variables,
+// applying sane defaults so the service is runnable with no configuration
+// at all in local development.
+package config
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Pagination holds the default and maximum page size for a listing
+// endpoint. It is the single source of truth for page-size defaulting and
+// capping; handlers and services must not hardcode their own values.
+type Pagination struct {
+	DefaultLimit int
+	MaxLimit     int
+}
+
+// Resolve returns the effective limit for a requested limit, defaulting
+// unset/non-positive requests to DefaultLimit and capping anything larger
+// than MaxLimit.
+func (p Pagination) Resolve(requested int) int {
+	if requested <= 0 {
+		return p.DefaultLimit
+	}
+	if requested > p.MaxLimit {
+		return p.MaxLimit
+	}
+	return requested
+}
+
+// Config is the fully resolved configuration for the audit-log service.
+type Config struct {
+	ListenAddr string
+	// ListenNetwork is passed to net.Listen: "tcp" (dual-stack, the
+	// default), "tcp4", or "tcp6". Binding "tcp" on "0.0.0.0" does not
+	// accept IPv6 clients; use "tcp" with a wildcard address (or no
+	// address) to actually get dual-stack behavior on most platforms.
+	ListenNetwork string
+	// AdminListenAddr, if set, starts a second listener (same
+	// ListenNetwork) serving only the health-check routes, so operators
+	// can expose health checks on a private interface distinct from the
+	// public listener. Empty disables it.
+	AdminListenAddr string
+	// GRPCListenAddr, if set, starts a gRPC listener (see
+	// internal/grpcapi) exposing the same AuditService operations as the
+	// REST API for callers that prefer gRPC. Always "tcp"/"tcp4"/"tcp6"
+	// per ListenNetwork, independent of the HTTP listeners. Empty
+	// disables it.
+	GRPCListenAddr string
+
+	DatabaseURL string
+	DB          DBConfig
+
+	// Pagination is keyed by endpoint name ("audit_logs", "stats", ...) so
+	// operators can tune page sizes per endpoint. The "default" entry is
+	// used for any endpoint without a more specific entry.
+	Pagination map[string]Pagination
+
+	Auth AuthConfig
+
+	// Ingestion is keyed by tenant ID. A tenant with no entry allows every
+	// event, matching the pre-existing behavior.
+	Ingestion map[string]EventPolicy
+
+	// MetaAllowlist is keyed by tenant ID. A tenant with no entry allows
+	// any meta key, matching the pre-existing behavior. Keys not in a
+	// tenant's allowlist are dropped from Meta before the record is
+	// stored.
+	MetaAllowlist map[string][]string
+
+	// MetaDenylist lists meta keys that must never be stored, regardless
+	// of MetaAllowlist, since a producer including one is almost always a
+	// mistake rather than intent (e.g. accidentally logging a credential
+	// into meta). Applied after MetaAllowlist; defaults to
+	// defaultMetaDenylist, and operators can extend (not replace) it via
+	// INGESTION_META_DENYLIST.
+	MetaDenylist []string
+
+	Archival  ArchivalConfig
+	Archive   ArchiveConfig
+	Retention RetentionConfig
+
+	PII        PIIConfig
+	Redaction  RedactionConfig
+	Encryption EncryptionConfig
+
+	AsyncWrite AsyncWriteConfig
+
+	TenantHeader  TenantHeaderConfig
+	ClientIP      ClientIPConfig
+	RequestLimits RequestLimitsConfig
+
+	Notify       NotifyConfig
+	Email        EmailConfig
+	Notification NotificationConfig
+	Throttle     ThrottleConfig
+
+	Dedup       DedupConfig
+	Idempotency IdempotencyConfig
+	Cache       CacheConfig
+	Redis       RedisConfig
+
+	Stats StatsConfig
+
+	Timestamp TimestampConfig
+
+	BusinessHours BusinessHoursConfig
+
+	Quota QuotaConfig
+
+	Sequence SequenceConfig
+
+	AdaptiveLog AdaptiveLogConfig
+
+	FileSink FileSinkConfig
+
+	Kafka KafkaConfig
+	NATS  NATSConfig
+
+	Docs DocsConfig
+
+	Tracing TracingConfig
+
+	Logging LoggingConfig
+
+	AccessAudit AccessAuditConfig
+}
+
+// TimestampConfig bounds how far a client-supplied event timestamp may
+// diverge from the server's clock on a live create, to catch a buggy
+// producer's clock skew. A request running in import mode (see
+// ingestmode) is exempt, since backfills legitimately carry old
+// timestamps.
+type TimestampConfig struct {
+	// MaxPastSkew is how far in the past a live timestamp may be.
+	MaxPastSkew time.Duration
+	// MaxFutureSkew is how far in the future a live timestamp may be.
+	MaxFutureSkew time.Duration
+}
+
+// StatsConfig controls tenant-level stats aggregation (see
+// service.AuditService.GetStats and GET /api/v1/audit/metrics).
+type StatsConfig struct {
+	// ErrorEventSuffixes classifies an event as an error/failure for the
+	// error_rate stat when its name ends in one of these suffixes, e.g.
+	// "payment.failed" matches ".failed".
+	ErrorEventSuffixes []string
+	// MetricsCacheTTL caps how often GET /api/v1/audit/metrics
+	// recomputes a tenant's stats, since it's expected to be polled by a
+	// scraper on a short, regular interval.
+	MetricsCacheTTL time.Duration
+}
+
+// RedisConfig configures the Redis connection shared by features that
+// need a fast, shared-state backend (currently Cache, Dedup, Throttle and
+// AsyncWrite's persistent queue). MaxRetries/MinRetryBackoff/MaxRetryBackoff configure
+// go-redis's own per-call retry; CircuitBreakerThreshold/Cooldown
+// configure the resilience.Breaker each feature wraps its client with so
+// a Redis outage degrades (see dedup.RedisStore and queue.RedisWAL for
+// each feature's fail-open/fail-closed policy) instead of blocking every
+// request on a failed call's timeout.
+type RedisConfig struct {
+	Addr string
+
+	MaxRetries      int
+	MinRetryBackoff time.Duration
+	MaxRetryBackoff time.Duration
+
+	CircuitBreakerThreshold int
+	CircuitBreakerCooldown  time.Duration
+
+	// HealthCheckInterval is how often the background check pings Redis.
+	HealthCheckInterval time.Duration
+	// HealthCheckTimeout bounds each individual ping.
+	HealthCheckTimeout time.Duration
+	// RequiredForReadiness, when true, makes the readiness check's
+	// response fail (503) while Redis is unhealthy. Defaults to false
+	// since every feature that uses Redis (Cache, Dedup, Throttle,
+	// AsyncWrite's persistent queue) already degrades gracefully on its
+	// own when Redis is unavailable, so Redis is not load-bearing for
+	// readiness unless an operator opts in.
+	RequiredForReadiness bool
+}
+
+// credentialTypeAllowlist restricts the AllowedTypes named in a
+// RoutePolicy to credential type strings the auth package actually
+// knows about (see auth.Type).
+var credentialTypeAllowlist = map[string]bool{
+	"api_key": true,
+	"jwt":     true,
+	"mtls":    true,
+}
+
+// defaultMetaDenylist is Config.MetaDenylist's value when
+// INGESTION_META_DENYLIST is unset: keys a producer should never put in
+// meta, even by mistake.
+var defaultMetaDenylist = []string{"password", "secret", "token", "api_key", "access_token", "authorization"}
+
+// dedupFieldAllowlist restricts which AuditLog fields DedupConfig.Fields
+// may name, since they're used to build the content hash.
+var dedupFieldAllowlist = map[string]bool{
+	"tenant_id":   true,
+	"actor":       true,
+	"event":       true,
+	"resource":    true,
+	"resource_id": true,
+	"data":        true,
+}
+
+// DedupConfig controls the optional HTTP-edge request dedup window: an
+// exact-duplicate create request (by content hash over Fields) received
+// again within Window is treated as a retry of the first and returns the
+// original record instead of inserting a second one. This is distinct
+// from full idempotency keys — there's no client-supplied key, just a
+// short best-effort window protecting against buggy producers that retry
+// without one. Disabled by default.
+type DedupConfig struct {
+	Enabled bool
+	Window  time.Duration
+	// Fields lists which AuditLog fields are hashed to detect a
+	// duplicate. Must be a subset of tenant_id, actor, event, resource,
+	// resource_id, data.
+	Fields []string
+}
+
+// IdempotencyConfig controls the optional client-supplied Idempotency-Key
+// header on POST /api/v1/audit (see AuditService.CreateAuditLogIdempotent):
+// a create carrying the same tenant and key as one already stored returns
+// that original record instead of inserting a duplicate, as long as the
+// original is within Window. Unlike DedupConfig, the key is chosen by the
+// client and persisted with the record, so it survives restarts and
+// protects against client-side retries rather than just a short
+// server-side window.
+type IdempotencyConfig struct {
+	// Window bounds how long a key stays eligible to dedupe against; a
+	// match older than Window is treated as expired and a new record is
+	// created. Window <= 0 means keys never expire.
+	Window time.Duration
+}
+
+// CacheConfig controls the read-through cache in front of
+// AuditService.GetAuditLog (see service.AuditService.WithCache).
+type CacheConfig struct {
+	Enabled bool
+	TTL     time.Duration
+}
+
+// TracingConfig controls distributed tracing (see internal/tracing).
+// Disabled by default, in which case tracing.Init installs a no-op tracer
+// provider so instrumented code pays zero overhead.
+type TracingConfig struct {
+	Enabled bool
+	// OTLPEndpoint is the OTLP/gRPC collector address (host:port) spans
+	// are exported to, e.g. "otel-collector:4317". Ignored when Enabled
+	// is false.
+	OTLPEndpoint string
+}
+
+// NotifyConfig controls delivery of webhook/Slack notifications for newly
+// created audit logs.
+type NotifyConfig struct {
+	// PublicBaseURL is prepended to build the "fetch the full record" link
+	// sent in place of an oversized webhook data field, e.g.
+	// "https://api.example.com".
+	PublicBaseURL string
+	// WebhookMaxDataBytes caps the encoded size of the data field sent to
+	// generic webhook subscribers; beyond this it's replaced with a fetch
+	// link. <= 0 means no limit.
+	WebhookMaxDataBytes int
+	// SlackMaxMessageLength caps the formatted message length sent to
+	// Slack incoming webhooks. <= 0 means no limit.
+	SlackMaxMessageLength int
+	// MaxChannelsPerEvent caps how many subscriptions a single event may
+	// fan out to, protecting against a misconfigured tenant registering
+	// hundreds of webhooks. Subscriptions are prioritized oldest-first
+	// (the tenant's original/primary channels win); anything beyond the
+	// cap is dropped and logged. <= 0 means no limit.
+	MaxChannelsPerEvent int
+	// BatchFlushCheckInterval is how often the webhook batching sender
+	// (see notify.BatchingWebhookSender) checks for batches whose linger
+	// deadline has passed. Only relevant to subscriptions that opt into
+	// batching; has no effect otherwise.
+	BatchFlushCheckInterval time.Duration
+	// MaxRetries and RetryBackoff configure retry of transient (network
+	// error or 5xx) delivery failures for the webhook and Slack senders
+	// (see notify.WebhookSender.WithRetry/notify.SlackSender.WithRetry).
+	// MaxRetries <= 0 disables retries, delivering once as before.
+	MaxRetries   int
+	RetryBackoff time.Duration
+	// WebhookSigningSecret, when non-empty, makes the generic webhook
+	// sender attach an HMAC signature to every delivery (see
+	// notify.WebhookSender.WithSigningSecret) so receivers can verify a
+	// delivery actually came from us. Empty disables signing.
+	WebhookSigningSecret string
+	// DeadLetterBackoff is how long after a delivery exhausts its sender-
+	// level retries (see MaxRetries/RetryBackoff above) before
+	// NotificationRetryWorker attempts it again (see
+	// notify.Dispatcher.WithDeadLetter).
+	DeadLetterBackoff time.Duration
+	// DeadLetterRetryInterval is how often NotificationRetryWorker sweeps
+	// the dead-letter queue for notifications due for retry.
+	DeadLetterRetryInterval time.Duration
+	// DeadLetterBatchSize caps how many failed notifications a single
+	// sweep retries.
+	DeadLetterBatchSize int
+}
+
+// EmailConfig controls delivery of email notifications for newly created
+// audit logs, for subscriptions whose URL is an email address rather than
+// a webhook URL (see notify.isEmailURL). Templates is keyed by event
+// name, with "" as the fallback for any event without a more specific
+// entry; a nil/empty Templates leaves every event on
+// notify.defaultEmailTemplate.
+type EmailConfig struct {
+	// SMTPAddr is the SMTP server address, e.g. "smtp.example.com:587".
+	SMTPAddr string
+	// SMTPUsername and SMTPPassword authenticate to SMTPAddr with PLAIN
+	// auth; leaving both empty sends unauthenticated (e.g. to a local
+	// relay).
+	SMTPUsername string
+	SMTPPassword string
+	// From is the envelope and message From address.
+	From string
+	// Templates maps an event name to its template source, with "" as the
+	// fallback for any event without a more specific entry. See
+	// notify.EmailTemplate, which this mirrors field-for-field (config
+	// cannot import notify directly: notify already depends on config by
+	// way of internal/tracing) - cmd/server converts this into
+	// notify.EmailTemplate values when constructing the sender.
+	Templates map[string]EmailTemplate
+}
+
+// EmailTemplate is the operator-supplied template source for one email
+// notification event (or the catch-all default, keyed by "" in
+// EmailConfig.Templates). Subject and Text are Go text/template source;
+// HTML, if non-empty, is Go html/template source and produces a
+// multipart/alternative message instead of a plain-text-only one. See
+// notify.EmailTemplate, which this is converted into.
+type EmailTemplate struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// NotificationConfig controls the bounded worker pool AuditService submits
+// notifications to (see service.NotificationPool), instead of spawning an
+// unbounded goroutine per created audit log.
+type NotificationConfig struct {
+	// Workers is the number of goroutines delivering queued notifications
+	// concurrently.
+	Workers int
+	// QueueSize is how many notifications can be buffered awaiting a free
+	// worker before Notify starts blocking (see SubmitTimeout).
+	QueueSize int
+	// SubmitTimeout is how long Notify blocks once the queue is full
+	// before giving up and dropping the notification (incrementing
+	// metrics.NotificationsDroppedTotal). <= 0 drops immediately instead
+	// of blocking at all.
+	SubmitTimeout time.Duration
+}
+
+// ThrottleConfig controls per-(tenant,event) notification throttling (see
+// notify.Dispatcher's Throttler), on top of the global dedup window. It
+// is independent of Dedup: dedup suppresses exact-duplicate records,
+// while this suppresses repeated notifications for a noisy event type
+// regardless of whether the underlying records are identical.
+type ThrottleConfig struct {
+	Enabled bool
+
+	// DefaultWindow applies to any (tenant, event) pair with no entry in
+	// Windows. <= 0 disables throttling for that pair.
+	DefaultWindow time.Duration
+
+	// Windows overrides DefaultWindow per tenant ID and event name.
+	Windows map[string]map[string]time.Duration
+}
+
+// WindowFor returns the throttle window for tenantID's event, or
+// DefaultWindow if neither has an override.
+func (c ThrottleConfig) WindowFor(tenantID, event string) time.Duration {
+	if byEvent, ok := c.Windows[tenantID]; ok {
+		if w, ok := byEvent[event]; ok {
+			return w
+		}
+	}
+	return c.DefaultWindow
+}
+
+// TenantHeaderConfig controls trusting a tenant ID supplied by an
+// upstream gateway via an HTTP header, instead of requiring it in the
+// request body/query. The header is only honored when the direct TCP
+// peer address matches one of TrustedProxyCIDRs — it is never trusted
+// from an arbitrary client, since that would let any caller impersonate
+// another tenant.
+type TenantHeaderConfig struct {
+	Enabled bool
+	// HeaderName is the header read for the tenant ID, e.g. "X-Tenant-ID".
+	HeaderName string
+	// TrustedProxyCIDRs lists the CIDR ranges of gateways allowed to set
+	// HeaderName.
+	TrustedProxyCIDRs []string
+}
+
+// ClientIPConfig controls how httpapi.clientIPFromRequest extracts the
+// caller's address for domain.AuditLog.ClientIP when requests arrive
+// through a reverse proxy/load balancer that sets X-Forwarded-For.
+// Without TrustedProxyCIDRs configured, the header's leftmost entry is
+// trusted unconditionally (safe only when every caller is already known
+// to go through a proxy that sets it correctly, matching this field's
+// zero-value behavior before it existed); configuring it requires the
+// immediate peer (r.RemoteAddr) to be a trusted proxy before the header
+// is trusted at all, and then skips any private/reserved address within
+// it — a client prepending a fake private-looking hop to spoof its
+// apparent origin — to find the leftmost public one.
+type ClientIPConfig struct {
+	TrustedProxyCIDRs []string
+}
+
+// RequestLimitsConfig bounds the size of an inbound HTTP request body,
+// applied via http.MaxBytesReader before any handler decodes it. <= 0
+// disables the limit.
+type RequestLimitsConfig struct {
+	MaxBodyBytes int64
+}
+
+// AsyncWriteConfig controls the optional async-write ingestion path
+// (POST /api/v1/audit/async), where records are buffered in memory and
+// flushed to Postgres in batches by a background writer instead of being
+// inserted synchronously. This trades durability (a crash between enqueue
+// and flush loses the record) for throughput; the default, synchronous
+// POST /api/v1/audit is unaffected by this config and remains durable.
+type AsyncWriteConfig struct {
+	// BatchSize is the maximum number of buffered records flushed to
+	// Postgres per tick.
+	BatchSize int
+	// FlushInterval is how often the background writer flushes the
+	// buffer.
+	FlushInterval time.Duration
+	// MaxAttempts is how many times a failed write is retried before it
+	// is marked domain.WriteStatusFailed for good. 0 disables retry,
+	// matching the write-once-then-fail behavior from before retries
+	// existed.
+	MaxAttempts int
+	// RetryBackoff is the base delay before a failed write is retried;
+	// successive retries back off exponentially (RetryBackoff * 2^n).
+	RetryBackoff time.Duration
+	// PersistentQueueEnabled backs the buffer with Redis (see
+	// config.RedisConfig) so pending writes survive a process restart,
+	// instead of living only in process memory.
+	PersistentQueueEnabled bool
+	// DegradedQueueDepth is the pending-write count above which the
+	// async queue is reported unhealthy (see health.AsyncQueueChecker).
+	// 0 disables the degradation signal.
+	DegradedQueueDepth int
+	// StatusTransitions restricts which domain.WriteStatus values a
+	// tracked write may move to from its current one, keyed and valued by
+	// the lowercase status name (e.g. "pending": ["retrying", "failed"]).
+	// A status missing from the map, or a nil/empty map entirely, permits
+	// any transition — today's behavior. See
+	// service.AsyncWriteService.WithStatusTransitions.
+	StatusTransitions map[string][]string
+}
+
+// PIIConfig controls the optional, non-blocking PII scanner run over
+// Data and Meta on ingestion.
+type PIIConfig struct {
+	Enabled bool
+
+	// Patterns maps a category name (e.g. "email", "phone") to the regex
+	// used to detect it. A nil/empty map falls back to a built-in set of
+	// patterns for email, phone and credit-card-like numbers.
+	Patterns map[string]string
+}
+
+// RedactionConfig controls an optional redaction step run over Data
+// before storage. Unlike PIIConfig, which only flags a match for
+// visibility, a Keys or Patterns match here replaces the value with
+// "***" before the record is ever written, walking nested objects and
+// arrays so a password buried a few levels deep is masked too.
+type RedactionConfig struct {
+	Enabled bool
+
+	// Keys lists object keys (case-insensitive, matched anywhere in
+	// Data's structure) whose value is always redacted regardless of its
+	// content, e.g. "password", "ssn".
+	Keys []string
+
+	// Patterns lists regexes; any string value matching one, at any
+	// depth, is redacted regardless of its key.
+	Patterns []string
+}
+
+// EncryptionConfig controls field-level encryption of audit_logs.data at
+// rest (see crypto.AESGCMEncryptor and postgres.AuditLogRepo.WithEncryptor).
+// Disabled by default, in which case data is stored exactly as before.
+//
+// Encrypting data makes it opaque to Postgres: any filter, sort, facet or
+// search that inspects data's contents (service.ParseExpression fields,
+// Filter.DataContains, facets over a data key, etc.) will silently stop
+// matching encrypted rows, since the database only ever sees ciphertext.
+// Operators turning this on should restrict such queries to fields outside
+// data (tenant_id, actor, event, resource, tags, meta) or accept that they
+// no longer work.
+type EncryptionConfig struct {
+	Enabled bool
+
+	// ActiveKeyID selects which entry of Keys new writes are sealed under.
+	// It must be present in Keys.
+	ActiveKeyID string
+
+	// Keys maps a key id to a base64-encoded 32-byte AES-256 key. Keys
+	// besides ActiveKeyID are kept only so rows already encrypted under
+	// them keep decrypting after rotation — once every row has been
+	// rewritten under the new active key, the old entry can be removed.
+	Keys map[string]string
+}
+
+// BusinessHoursConfig controls the optional enrichment that tags each
+// audit log with whether it occurred during business hours (and its
+// day of week), based on the tenant's configured window. Disabled by
+// default since "business hours" has no universal default.
+type BusinessHoursConfig struct {
+	Enabled bool
+
+	// Default applies to any tenant with no entry in TenantWindows.
+	Default BusinessHoursWindow
+
+	// TenantWindows overrides Default per tenant ID.
+	TenantWindows map[string]BusinessHoursWindow
+}
+
+// listenNetworkAllowlist restricts Config.ListenNetwork to the network
+// names net.Listen actually accepts for TCP.
+var listenNetworkAllowlist = map[string]bool{
+	"tcp": true, "tcp4": true, "tcp6": true,
+}
+
+// weekdayAllowlist restricts BusinessHoursWindow.Days to valid
+// time.Weekday names.
+var weekdayAllowlist = map[string]bool{
+	"Sunday": true, "Monday": true, "Tuesday": true, "Wednesday": true,
+	"Thursday": true, "Friday": true, "Saturday": true,
+}
+
+// BusinessHoursWindow is a tenant's local business-hours window.
+type BusinessHoursWindow struct {
+	// Timezone is an IANA zone name (e.g. "America/New_York") the
+	// window's StartHour/EndHour are interpreted in.
+	Timezone string `json:"timezone"`
+	// StartHour and EndHour are the inclusive-start, exclusive-end hour
+	// range (0-23) considered business hours on a Days day.
+	StartHour int `json:"start_hour"`
+	EndHour   int `json:"end_hour"`
+	// Days lists the English weekday names (e.g. "Monday") considered
+	// business days; an empty list defaults to Monday-Friday.
+	Days []string `json:"days,omitempty"`
+}
+
+// QuotaConfig controls optional per-tenant storage quota enforcement.
+// Usage is computed on demand from the stored rows (see
+// Repo.TenantStorageBytes) rather than a running counter. Disabled by
+// default.
+type QuotaConfig struct {
+	Enabled bool
+
+	// DefaultBytes applies to any tenant with no entry in TenantBytes.
+	// <= 0 means unlimited.
+	DefaultBytes int64
+
+	// TenantBytes overrides DefaultBytes per tenant ID.
+	TenantBytes map[string]int64
+
+	// SoftLimitRatio, once usage/quota reaches it, logs a warning so an
+	// operator can act before writes start being rejected. 0 disables
+	// the warning.
+	SoftLimitRatio float64
+}
+
+// BytesFor returns tenantID's quota in bytes, or <= 0 for unlimited.
+func (c QuotaConfig) BytesFor(tenantID string) int64 {
+	if b, ok := c.TenantBytes[tenantID]; ok {
+		return b
+	}
+	return c.DefaultBytes
+}
+
+// logDowngradeLevelAllowlist restricts AdaptiveLogConfig.DowngradeLevel to
+// slog levels that make sense as a downgrade target for info-level
+// request logs.
+var logDowngradeLevelAllowlist = map[string]bool{
+	"warn": true, "error": true,
+}
+
+// logLevelAllowlist restricts LoggingConfig.Level to the slog levels the
+// service actually distinguishes.
+var logLevelAllowlist = map[string]bool{
+	"debug": true, "info": true, "warn": true, "error": true,
+}
+
+// LoggingConfig controls the service's base log level — the floor below
+// which AdaptiveLogConfig's throughput-based downgrading and per-request
+// overrides can never raise logs back up to. Unlike most of Config, this
+// is safe to change on a running process (see cmd/server's SIGHUP
+// handler): it only ever adjusts a shared slog.LevelVar, never anything
+// that requires rebuilding a listener or connection.
+type LoggingConfig struct {
+	// Level is one of "debug", "info", "warn", "error". Defaults to
+	// "info".
+	Level string
+}
+
+// AccessAuditConfig controls httpapi.AccessAudit, which logs who read or
+// deleted which tenant's data (see httpapi.AccessAudit's doc comment).
+// Disabled by default: the log line it adds to every read is itself a
+// write of sorts, and a service already under heavy read load shouldn't
+// have that volume doubled without an operator opting in.
+type AccessAuditConfig struct {
+	Enabled bool
+}
+
+// AdaptiveLogConfig controls automatically raising the effective level of
+// per-request logs (see httpapi.RequestLogging) when request throughput
+// exceeds a threshold, so info-level logging doesn't itself become a
+// bottleneck during a traffic spike. Error logs are unaffected — only
+// the per-request info/debug logging this gates. Disabled by default.
+type AdaptiveLogConfig struct {
+	Enabled bool
+
+	// RequestsPerSecondThreshold is the rate above which request logs are
+	// downgraded to DowngradeLevel. Recomputed every Window.
+	RequestsPerSecondThreshold float64
+
+	// Window is how often the request rate is sampled and the effective
+	// level re-evaluated.
+	Window time.Duration
+
+	// DowngradeLevel is the level request logs are raised to while over
+	// threshold ("warn" or "error"). Reverts to info once the rate drops
+	// back down.
+	DowngradeLevel string
+}
+
+// SequenceConfig controls optional per-tenant monotonic sequence numbering
+// (see AuditLogRepo.nextSequence), letting consumers detect gaps in the
+// event stream. Disabled by default since it costs an extra round trip
+// per write.
+type SequenceConfig struct {
+	Enabled bool
+}
+
+// fileSinkModeAllowlist restricts FileSinkConfig.Mode to the two
+// supported roles: "mirror" writes alongside the primary store (for
+// air-gapped auditing/replay without affecting reads), "primary" writes
+// only to the file, replacing Postgres entirely for deployments that
+// don't have one reachable.
+var fileSinkModeAllowlist = map[string]bool{
+	"mirror":  true,
+	"primary": true,
+}
+
+// FileSinkConfig controls the optional local NDJSON file sink (see
+// internal/filesink), for air-gapped/edge deployments without a
+// reachable Postgres, or as a secondary mirror of what's already there.
+// Disabled by default, leaving standard deployments unaffected.
+type FileSinkConfig struct {
+	Enabled bool
+	// Path is the NDJSON file written to; rotated files are written
+	// alongside it (e.g. audit.ndjson-20260101T000000.000.gz).
+	Path string
+	// Mode is "mirror" (write in addition to Postgres) or "primary"
+	// (write instead of Postgres).
+	Mode string
+	// MaxSizeMB is the size in megabytes a file grows to before it's
+	// rotated. 0 falls back to lumberjack's default (100MB).
+	MaxSizeMB int
+	// MaxBackups is how many rotated files are kept; 0 means keep all.
+	MaxBackups int
+	// MaxAgeDays is how long a rotated file is kept before deletion; 0
+	// means no age-based cleanup.
+	MaxAgeDays int
+	Compress   bool
+}
+
+// DocsConfig controls whether the generated OpenAPI spec and Swagger UI
+// (see internal/httpapi's docs_handler.go) are served. Disabled by
+// default so a production deployment doesn't expose its API surface
+// (route list, request/response shapes) to anyone who can reach it,
+// unless an operator opts in.
+type DocsConfig struct {
+	Enabled bool
+}
+
+// KafkaConfig controls the optional Kafka publisher (see internal/kafka),
+// which mirrors every successfully created audit log onto a topic for
+// downstream consumers (SIEM ingestion, stream processing, etc). A
+// publish failure never fails the create request; see
+// metrics.KafkaPublishErrorsTotal. Disabled by default.
+type KafkaConfig struct {
+	Enabled bool
+	// Brokers is the list of "host:port" addresses used to discover the
+	// cluster's partitions.
+	Brokers []string
+	// Topic every audit log is published to. Messages are keyed by
+	// tenant ID so that a given tenant's events land on the same
+	// partition and stay ordered.
+	Topic string
+}
+
+// NATSConfig controls the optional NATS JetStream publisher (see
+// internal/nats), an alternative to KafkaConfig for shops that run NATS
+// instead of Kafka. Every successfully created audit log is published to
+// subject "audit.<tenant>.<event>" with at-least-once (JetStream-acked)
+// delivery. A publish failure never fails the create request; see
+// metrics.NATSPublishErrorsTotal. Disabled by default.
+type NATSConfig struct {
+	Enabled bool
+	// Servers is the list of NATS server URLs (e.g. "nats://host:4222")
+	// the client connects to; it reconnects automatically across
+	// whichever of these stay reachable.
+	Servers []string
+	// Stream is the JetStream stream name backing subject "audit.>".
+	// Created (or updated to match) on startup if it doesn't already
+	// exist.
+	Stream string
+	// AckTimeout bounds how long a single publish waits for JetStream to
+	// acknowledge the message before Publish returns an error.
+	AckTimeout time.Duration
+}
+
+// ArchivalConfig controls automatic archival of "completed" audit records
+// — ones whose meta marks them as done — into a separate archive table
+// once they age past a cutoff. This keeps the hot table smaller without
+// deleting the data outright.
+type ArchivalConfig struct {
+	Enabled bool
+	// After is how long a completed record stays in the hot table before
+	// it becomes eligible for archival.
+	After time.Duration
+	// CheckInterval is how often the background worker sweeps for
+	// newly-eligible records.
+	CheckInterval time.Duration
+	// StatusMetaKey/StatusMetaValue identify a "completed" record: one
+	// whose meta[StatusMetaKey] == StatusMetaValue.
+	StatusMetaKey   string
+	StatusMetaValue string
+}
+
+// ArchiveConfig controls offloading old audit records to S3 (see
+// archive.S3Archiver) before RetentionWorker deletes them, instead of
+// just deleting them outright. Disabled by default, in which case the
+// worker falls back to deleting without archiving first.
+type ArchiveConfig struct {
+	Enabled bool
+	Bucket  string
+	Region  string
+	// Prefix is prepended to every archived object's key, e.g.
+	// "audit-archive" for keys like "audit-archive/<tenant>/<date>/...".
+	Prefix string
+}
+
+// RetentionConfig controls how long audit records are kept before being
+// permanently purged (see postgres.RetentionWorker). This is independent
+// of ArchivalConfig, which copies then removes only "completed" records
+// into a separate table rather than deleting them outright.
+type RetentionConfig struct {
+	// Days is how long a record is kept, in days, before it becomes
+	// eligible for purge. 0 disables the worker entirely.
+	Days int
+	// ByTenant overrides Days per tenant ID.
+	ByTenant map[string]int
+	// CheckInterval is how often the background worker sweeps for
+	// purge-eligible records.
+	CheckInterval time.Duration
+	// ArchiveBatchSize caps how many records RetentionWorker fetches and
+	// archives per archive.Archiver.Archive call when ArchiveConfig is
+	// enabled. Ignored otherwise.
+	ArchiveBatchSize int
+}
+
+// DaysFor returns the retention period for tenantID, or Days if it has no
+// override.
+func (c RetentionConfig) DaysFor(tenantID string) int {
+	if d, ok := c.ByTenant[tenantID]; ok {
+		return d
+	}
+	return c.Days
+}
+
+// EventPolicy restricts which event names a tenant may ingest. At most
+// one of Allowlist/Denylist should be set per tenant; if both are set,
+// Allowlist takes precedence.
+type EventPolicy struct {
+	Allowlist []string `json:"allow,omitempty"`
+	Denylist  []string `json:"deny,omitempty"`
+}
+
+// Allows reports whether event is permitted by the policy.
+func (p EventPolicy) Allows(event string) bool {
+	if len(p.Allowlist) > 0 {
+		return slices.Contains(p.Allowlist, event)
+	}
+	if len(p.Denylist) > 0 {
+		return !slices.Contains(p.Denylist, event)
+	}
+	return true
+}
+
+// DBConfig configures the database connection pool.
+type DBConfig struct {
+	MinConns int32
+	MaxConns int32
+
+	// EagerWarmup, when true, opens MinConns connections at startup
+	// before the readiness probe reports ready, instead of opening them
+	// lazily as the first requests arrive.
+	EagerWarmup bool
+
+	// RejectWhenUnhealthy, when true, makes the service return 503 for
+	// all requests while the background DB health check is failing,
+	// instead of letting each request hit the dead pool individually.
+	RejectWhenUnhealthy bool
+	// HealthCheckInterval is how often the background check pings the
+	// database.
+	HealthCheckInterval time.Duration
+	// HealthCheckTimeout bounds each individual ping.
+	HealthCheckTimeout time.Duration
+
+	// AutoMigrate, when true, applies pending migrations (see
+	// internal/migrate) before the pool is warmed up and the service
+	// reports ready, instead of requiring an operator to run `server
+	// --migrate` (or an external migration step) before each deploy.
+	AutoMigrate bool
+
+	// QueryTimeout bounds every query AuditLogRepo runs (see
+	// postgres.AuditLogRepo.WithQueryTimeout), unless the caller's own
+	// ctx already carries an earlier deadline. Zero (the default) leaves
+	// queries unbounded except by whatever deadline the caller supplies,
+	// matching the behavior before this setting existed.
+	QueryTimeout time.Duration
+}
+
+// AuthConfig configures the credential types the service accepts.
+type AuthConfig struct {
+	// APIKeys maps an API key (raw, or a "sha256:" digest from
+	// auth.HashAPIKey) to the principal ID it authenticates as.
+	APIKeys map[string]string
+
+	// APIKeyTenants optionally restricts an API key (by the same raw key
+	// used in APIKeys) to one or more tenant IDs, so a key issued to one
+	// tenant can't create or read another tenant's logs. A key with no
+	// entry here keeps unrestricted access, for backward compatibility.
+	APIKeyTenants map[string][]string
+
+	// APIKeyScopes optionally restricts an API key (by the same raw key
+	// used in APIKeys) to the scopes it's listed against here, checked
+	// the same way a JWT's "scopes" claim is (see RoutePolicy.
+	// RequiredScopes and auth.RequireScope). A key with no entry here
+	// carries no scopes, so it only satisfies routes with no
+	// RequiredScopes — same backward-compatible default as a JWT with no
+	// "scopes" claim.
+	APIKeyScopes map[string][]string
+
+	// JWTSecret, if non-empty, enables bearer-token auth for HS256 tokens
+	// verified with this HMAC secret.
+	JWTSecret string
+
+	// JWKSURL, if non-empty, enables bearer-token auth for RS256 tokens,
+	// verified against a key fetched from this JWKS endpoint (typically
+	// an OIDC provider's jwks_uri) and selected by the token's "kid"
+	// header. May be set alongside JWTSecret to accept either during a
+	// migration to an OIDC provider.
+	JWKSURL string
+	// JWKSRefreshInterval is how often the JWKS cache is refreshed in the
+	// background, so a provider's key rotation is picked up without
+	// restarting the service.
+	JWKSRefreshInterval time.Duration
+
+	// JWTIssuer, if non-empty, requires the token's "iss" claim to match.
+	JWTIssuer string
+	// JWTAudience, if non-empty, requires the token's "aud" claim to
+	// contain it.
+	JWTAudience string
+
+	MTLS MTLSConfig
+
+	// RoutePolicies overrides the default one-size-fits-all auth
+	// requirement for specific routes, keyed by "METHOD path" using the
+	// same path template chi registers the route with (e.g.
+	// "DELETE /api/v1/tenants/{tenant_id}"). A route with no entry falls
+	// back to the previous behavior: authentication is attempted but not
+	// required.
+	RoutePolicies map[string]RoutePolicy
+}
+
+// RoutePolicy declares what a single route requires of the caller. See
+// AuthConfig.RoutePolicies.
+type RoutePolicy struct {
+	RequireAuth bool `json:"require_auth"`
+	// AllowedTypes restricts which credential type may satisfy this
+	// route ("api_key", "jwt", "mtls"); empty allows any.
+	AllowedTypes []string `json:"allowed_types,omitempty"`
+	// RequiredScopes are scopes the principal must all carry; empty
+	// requires none.
+	RequiredScopes []string `json:"required_scopes,omitempty"`
+}
+
+// MTLSConfig configures mutual-TLS client certificate authentication.
+type MTLSConfig struct {
+	Enabled bool
+
+	// ClientCAFile is the PEM file of CAs used to verify client
+	// certificates. Required when Enabled is true.
+	ClientCAFile string
+
+	// CertFile/KeyFile are the server's own TLS certificate and key.
+	CertFile string
+	KeyFile  string
+
+	// Principals maps a verified client certificate's Subject Common
+	// Name (or a SAN DNS name) to the principal ID it authenticates as.
+	Principals map[string]string
+
+	// Scopes optionally restricts a client certificate (by the same CN/SAN
+	// key used in Principals) to the scopes it's listed against here,
+	// checked the same way a JWT's "scopes" claim or an API key's
+	// AuthConfig.APIKeyScopes entry is (see RoutePolicy.RequiredScopes and
+	// auth.RequireScope). A principal with no entry here carries no
+	// scopes, so it only satisfies routes with no RequiredScopes.
+	Scopes map[string][]string
+}
+
+const defaultPaginationKey = "default"
+
+// PaginationFor returns the configured Pagination for the given endpoint,
+// falling back to the default entry if no endpoint-specific override is
+// configured.
+func (c Config) PaginationFor(endpoint string) Pagination {
+	if p, ok := c.Pagination[endpoint]; ok {
+		return p
+	}
+	return c.Pagination[defaultPaginationKey]
+}
+
+// WindowFor returns the business-hours window for tenantID, falling back
+// to the configured default when the tenant has no override.
+func (c BusinessHoursConfig) WindowFor(tenantID string) BusinessHoursWindow {
+	if w, ok := c.TenantWindows[tenantID]; ok {
+		return w
+	}
+	return c.Default
+}
+
+// Load builds a Config from environment variables, applying defaults for
+// anything left unset.
+func Load() (Config, error) {
+	cfg := Config{
+		ListenAddr:      getEnv("LISTEN_ADDR", ":8080"),
+		ListenNetwork:   getEnv("LISTEN_NETWORK", "tcp"),
+		AdminListenAddr: getEnv("ADMIN_LISTEN_ADDR", ""),
+		GRPCListenAddr:  getEnv("GRPC_LISTEN_ADDR", ""),
+		DatabaseURL:     getEnv("DATABASE_URL", "postgres://localhost:5432/auditlog"),
+		Pagination: map[string]Pagination{
+			defaultPaginationKey: {DefaultLimit: 50, MaxLimit: 1000},
+		},
+	}
+	if !listenNetworkAllowlist[cfg.ListenNetwork] {
+		return Config{}, fmt.Errorf("config: LISTEN_NETWORK must be one of tcp, tcp4, tcp6, got %q", cfg.ListenNetwork)
+	}
+
+	minConns, err := getEnvInt("DB_MIN_CONNS", 2)
+	if err != nil {
+		return Config{}, err
+	}
+	maxConns, err := getEnvInt("DB_MAX_CONNS", 10)
+	if err != nil {
+		return Config{}, err
+	}
+	healthCheckIntervalSec, err := getEnvInt("DB_HEALTH_CHECK_INTERVAL_SECONDS", 5)
+	if err != nil {
+		return Config{}, err
+	}
+	healthCheckTimeoutSec, err := getEnvInt("DB_HEALTH_CHECK_TIMEOUT_SECONDS", 2)
+	if err != nil {
+		return Config{}, err
+	}
+	queryTimeoutSec, err := getEnvInt("DB_QUERY_TIMEOUT_SECONDS", 0)
+	if err != nil {
+		return Config{}, err
+	}
+	cfg.DB = DBConfig{
+		MinConns:            int32(minConns),
+		MaxConns:            int32(maxConns),
+		EagerWarmup:         getEnv("DB_EAGER_WARMUP", "false") == "true",
+		RejectWhenUnhealthy: getEnv("DB_REJECT_WHEN_UNHEALTHY", "false") == "true",
+		HealthCheckInterval: time.Duration(healthCheckIntervalSec) * time.Second,
+		HealthCheckTimeout:  time.Duration(healthCheckTimeoutSec) * time.Second,
+		AutoMigrate:         getEnv("DB_AUTO_MIGRATE", "false") == "true",
+		QueryTimeout:        time.Duration(queryTimeoutSec) * time.Second,
+	}
+
+	defaultLimit, err := getEnvInt("PAGINATION_DEFAULT_LIMIT", cfg.Pagination[defaultPaginationKey].DefaultLimit)
+	if err != nil {
+		return Config{}, err
+	}
+	maxLimit, err := getEnvInt("PAGINATION_MAX_LIMIT", cfg.Pagination[defaultPaginationKey].MaxLimit)
+	if err != nil {
+		return Config{}, err
+	}
+	if defaultLimit <= 0 {
+		return Config{}, fmt.Errorf("config: PAGINATION_DEFAULT_LIMIT must be positive, got %d", defaultLimit)
+	}
+	if maxLimit < defaultLimit {
+		return Config{}, fmt.Errorf("config: PAGINATION_MAX_LIMIT (%d) must be >= PAGINATION_DEFAULT_LIMIT (%d)", maxLimit, defaultLimit)
+	}
+	cfg.Pagination[defaultPaginationKey] = Pagination{DefaultLimit: defaultLimit, MaxLimit: maxLimit}
+
+	// Per-endpoint overrides, e.g. AUDIT_LOGS_PAGINATION_DEFAULT_LIMIT.
+	for _, endpoint := range []string{"audit_logs", "stats"} {
+		envPrefix := envKey(endpoint)
+		def, err := getEnvInt(envPrefix+"_PAGINATION_DEFAULT_LIMIT", 0)
+		if err != nil {
+			return Config{}, err
+		}
+		max, err := getEnvInt(envPrefix+"_PAGINATION_MAX_LIMIT", 0)
+		if err != nil {
+			return Config{}, err
+		}
+		if def == 0 && max == 0 {
+			continue
+		}
+		base := cfg.Pagination[defaultPaginationKey]
+		if def <= 0 {
+			def = base.DefaultLimit
+		}
+		if max <= 0 {
+			max = base.MaxLimit
+		}
+		if max < def {
+			return Config{}, fmt.Errorf("config: %s_PAGINATION_MAX_LIMIT (%d) must be >= default (%d)", envPrefix, max, def)
+		}
+		cfg.Pagination[endpoint] = Pagination{DefaultLimit: def, MaxLimit: max}
+	}
+
+	jwksRefreshIntervalSec, err := getEnvInt("AUTH_JWKS_REFRESH_INTERVAL_SECONDS", 300)
+	if err != nil {
+		return Config{}, err
+	}
+	cfg.Auth = AuthConfig{
+		APIKeys:             parseKeyValueList(getEnv("AUTH_API_KEYS", "")),
+		JWTSecret:           getEnv("AUTH_JWT_SECRET", ""),
+		JWKSURL:             getEnv("AUTH_JWKS_URL", ""),
+		JWKSRefreshInterval: time.Duration(jwksRefreshIntervalSec) * time.Second,
+		JWTIssuer:           getEnv("AUTH_JWT_ISSUER", ""),
+		JWTAudience:         getEnv("AUTH_JWT_AUDIENCE", ""),
+		MTLS: MTLSConfig{
+			Enabled:      getEnv("AUTH_MTLS_ENABLED", "false") == "true",
+			ClientCAFile: getEnv("AUTH_MTLS_CLIENT_CA_FILE", ""),
+			CertFile:     getEnv("AUTH_MTLS_CERT_FILE", ""),
+			KeyFile:      getEnv("AUTH_MTLS_KEY_FILE", ""),
+			Principals:   parseKeyValueList(getEnv("AUTH_MTLS_PRINCIPALS", "")),
+		},
+	}
+	if cfg.Auth.MTLS.Enabled && (cfg.Auth.MTLS.ClientCAFile == "" || cfg.Auth.MTLS.CertFile == "" || cfg.Auth.MTLS.KeyFile == "") {
+		return Config{}, fmt.Errorf("config: AUTH_MTLS_ENABLED requires AUTH_MTLS_CLIENT_CA_FILE, AUTH_MTLS_CERT_FILE and AUTH_MTLS_KEY_FILE")
+	}
+
+	if raw := getEnv("AUTH_API_KEY_TENANTS", ""); raw != "" {
+		var keyTenants map[string][]string
+		if err := json.Unmarshal([]byte(raw), &keyTenants); err != nil {
+			return Config{}, fmt.Errorf("config: AUTH_API_KEY_TENANTS must be valid JSON: %w", err)
+		}
+		cfg.Auth.APIKeyTenants = keyTenants
+	}
+
+	if raw := getEnv("AUTH_API_KEY_SCOPES", ""); raw != "" {
+		var keyScopes map[string][]string
+		if err := json.Unmarshal([]byte(raw), &keyScopes); err != nil {
+			return Config{}, fmt.Errorf("config: AUTH_API_KEY_SCOPES must be valid JSON: %w", err)
+		}
+		cfg.Auth.APIKeyScopes = keyScopes
+	}
+
+	if raw := getEnv("AUTH_MTLS_SCOPES", ""); raw != "" {
+		var mtlsScopes map[string][]string
+		if err := json.Unmarshal([]byte(raw), &mtlsScopes); err != nil {
+			return Config{}, fmt.Errorf("config: AUTH_MTLS_SCOPES must be valid JSON: %w", err)
+		}
+		cfg.Auth.MTLS.Scopes = mtlsScopes
+	}
+
+	if raw := getEnv("AUTH_ROUTE_POLICIES", ""); raw != "" {
+		var policies map[string]RoutePolicy
+		if err := json.Unmarshal([]byte(raw), &policies); err != nil {
+			return Config{}, fmt.Errorf("config: AUTH_ROUTE_POLICIES must be valid JSON: %w", err)
+		}
+		for route, policy := range policies {
+			for _, t := range policy.AllowedTypes {
+				if _, ok := credentialTypeAllowlist[t]; !ok {
+					return Config{}, fmt.Errorf("config: AUTH_ROUTE_POLICIES[%q]: unknown credential type %q", route, t)
+				}
+			}
+		}
+		cfg.Auth.RoutePolicies = policies
+	}
+
+	archivalAfterHours, err := getEnvInt("ARCHIVAL_AFTER_HOURS", 720)
+	if err != nil {
+		return Config{}, err
+	}
+	archivalCheckIntervalMin, err := getEnvInt("ARCHIVAL_CHECK_INTERVAL_MINUTES", 60)
+	if err != nil {
+		return Config{}, err
+	}
+	cfg.Archival = ArchivalConfig{
+		Enabled:         getEnv("ARCHIVAL_ENABLED", "false") == "true",
+		After:           time.Duration(archivalAfterHours) * time.Hour,
+		CheckInterval:   time.Duration(archivalCheckIntervalMin) * time.Minute,
+		StatusMetaKey:   getEnv("ARCHIVAL_STATUS_META_KEY", "status"),
+		StatusMetaValue: getEnv("ARCHIVAL_STATUS_META_VALUE", "completed"),
+	}
+
+	cfg.Archive = ArchiveConfig{
+		Enabled: getEnv("ARCHIVE_ENABLED", "false") == "true",
+		Bucket:  getEnv("ARCHIVE_BUCKET", ""),
+		Region:  getEnv("ARCHIVE_REGION", ""),
+		Prefix:  getEnv("ARCHIVE_PREFIX", "audit-archive"),
+	}
+
+	retentionDays, err := getEnvInt("RETENTION_DAYS", 0)
+	if err != nil {
+		return Config{}, err
+	}
+	retentionCheckIntervalMin, err := getEnvInt("RETENTION_CHECK_INTERVAL_MINUTES", 60)
+	if err != nil {
+		return Config{}, err
+	}
+	retentionArchiveBatchSize, err := getEnvInt("RETENTION_ARCHIVE_BATCH_SIZE", 500)
+	if err != nil {
+		return Config{}, err
+	}
+	cfg.Retention = RetentionConfig{
+		Days:             retentionDays,
+		CheckInterval:    time.Duration(retentionCheckIntervalMin) * time.Minute,
+		ArchiveBatchSize: retentionArchiveBatchSize,
+	}
+	if raw := getEnv("RETENTION_BY_TENANT_DAYS", ""); raw != "" {
+		var byTenant map[string]int
+		if err := json.Unmarshal([]byte(raw), &byTenant); err != nil {
+			return Config{}, fmt.Errorf("config: RETENTION_BY_TENANT_DAYS must be valid JSON: %w", err)
+		}
+		cfg.Retention.ByTenant = byTenant
+	}
+
+	if raw := getEnv("INGESTION_EVENT_POLICY", ""); raw != "" {
+		var policy map[string]EventPolicy
+		if err := json.Unmarshal([]byte(raw), &policy); err != nil {
+			return Config{}, fmt.Errorf("config: INGESTION_EVENT_POLICY must be valid JSON: %w", err)
+		}
+		cfg.Ingestion = policy
+	}
+
+	if raw := getEnv("INGESTION_META_ALLOWLIST", ""); raw != "" {
+		var allowlist map[string][]string
+		if err := json.Unmarshal([]byte(raw), &allowlist); err != nil {
+			return Config{}, fmt.Errorf("config: INGESTION_META_ALLOWLIST must be valid JSON: %w", err)
+		}
+		cfg.MetaAllowlist = allowlist
+	}
+
+	cfg.MetaDenylist = append([]string(nil), defaultMetaDenylist...)
+	if raw := getEnv("INGESTION_META_DENYLIST", ""); raw != "" {
+		cfg.MetaDenylist = append(cfg.MetaDenylist, strings.Split(raw, ",")...)
+	}
+
+	asyncBatchSize, err := getEnvInt("ASYNC_WRITE_BATCH_SIZE", 100)
+	if err != nil {
+		return Config{}, err
+	}
+	asyncFlushIntervalMs, err := getEnvInt("ASYNC_WRITE_FLUSH_INTERVAL_MS", 500)
+	if err != nil {
+		return Config{}, err
+	}
+	asyncMaxAttempts, err := getEnvInt("ASYNC_WRITE_MAX_ATTEMPTS", 5)
+	if err != nil {
+		return Config{}, err
+	}
+	asyncRetryBackoffMs, err := getEnvInt("ASYNC_WRITE_RETRY_BACKOFF_MS", 200)
+	if err != nil {
+		return Config{}, err
+	}
+	asyncDegradedQueueDepth, err := getEnvInt("ASYNC_WRITE_DEGRADED_QUEUE_DEPTH", 10000)
+	if err != nil {
+		return Config{}, err
+	}
+	cfg.AsyncWrite = AsyncWriteConfig{
+		BatchSize:              asyncBatchSize,
+		FlushInterval:          time.Duration(asyncFlushIntervalMs) * time.Millisecond,
+		MaxAttempts:            asyncMaxAttempts,
+		RetryBackoff:           time.Duration(asyncRetryBackoffMs) * time.Millisecond,
+		PersistentQueueEnabled: getEnv("ASYNC_WRITE_PERSISTENT_QUEUE_ENABLED", "false") == "true",
+		DegradedQueueDepth:     asyncDegradedQueueDepth,
+	}
+	if raw := getEnv("ASYNC_WRITE_STATUS_TRANSITIONS", ""); raw != "" {
+		var transitions map[string][]string
+		if err := json.Unmarshal([]byte(raw), &transitions); err != nil {
+			return Config{}, fmt.Errorf("config: ASYNC_WRITE_STATUS_TRANSITIONS must be valid JSON: %w", err)
+		}
+		cfg.AsyncWrite.StatusTransitions = transitions
+	}
+
+	cfg.TenantHeader = TenantHeaderConfig{
+		Enabled:    getEnv("TENANT_HEADER_ENABLED", "false") == "true",
+		HeaderName: getEnv("TENANT_HEADER_NAME", "X-Tenant-ID"),
+	}
+	if raw := getEnv("TENANT_HEADER_TRUSTED_PROXY_CIDRS", ""); raw != "" {
+		cidrs := strings.Split(raw, ",")
+		for i, cidr := range cidrs {
+			cidrs[i] = strings.TrimSpace(cidr)
+		}
+		cfg.TenantHeader.TrustedProxyCIDRs = cidrs
+	}
+	if cfg.TenantHeader.Enabled && len(cfg.TenantHeader.TrustedProxyCIDRs) == 0 {
+		return Config{}, fmt.Errorf("config: TENANT_HEADER_ENABLED requires TENANT_HEADER_TRUSTED_PROXY_CIDRS")
+	}
+	for _, cidr := range cfg.TenantHeader.TrustedProxyCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return Config{}, fmt.Errorf("config: TENANT_HEADER_TRUSTED_PROXY_CIDRS %q is not a valid CIDR: %w", cidr, err)
+		}
+	}
+
+	if raw := getEnv("CLIENT_IP_TRUSTED_PROXY_CIDRS", ""); raw != "" {
+		cidrs := strings.Split(raw, ",")
+		for i, cidr := range cidrs {
+			cidrs[i] = strings.TrimSpace(cidr)
+		}
+		cfg.ClientIP.TrustedProxyCIDRs = cidrs
+	}
+	for _, cidr := range cfg.ClientIP.TrustedProxyCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return Config{}, fmt.Errorf("config: CLIENT_IP_TRUSTED_PROXY_CIDRS %q is not a valid CIDR: %w", cidr, err)
+		}
+	}
+
+	maxBodyBytes, err := getEnvInt("REQUEST_MAX_BODY_BYTES", 1<<20)
+	if err != nil {
+		return Config{}, err
+	}
+	cfg.RequestLimits = RequestLimitsConfig{MaxBodyBytes: int64(maxBodyBytes)}
+
+	webhookMaxDataBytes, err := getEnvInt("NOTIFY_WEBHOOK_MAX_DATA_BYTES", 8192)
+	if err != nil {
+		return Config{}, err
+	}
+	slackMaxMessageLength, err := getEnvInt("NOTIFY_SLACK_MAX_MESSAGE_LENGTH", 4000)
+	if err != nil {
+		return Config{}, err
+	}
+	maxChannelsPerEvent, err := getEnvInt("NOTIFY_MAX_CHANNELS_PER_EVENT", 50)
+	if err != nil {
+		return Config{}, err
+	}
+	batchFlushCheckIntervalSec, err := getEnvInt("NOTIFY_BATCH_FLUSH_CHECK_INTERVAL_SECONDS", 5)
+	if err != nil {
+		return Config{}, err
+	}
+	notifyMaxRetries, err := getEnvInt("NOTIFY_MAX_RETRIES", 0)
+	if err != nil {
+		return Config{}, err
+	}
+	notifyRetryBackoffMS, err := getEnvInt("NOTIFY_RETRY_BACKOFF_MS", 200)
+	if err != nil {
+		return Config{}, err
+	}
+	deadLetterBackoffSec, err := getEnvInt("NOTIFY_DEAD_LETTER_BACKOFF_SECONDS", 60)
+	if err != nil {
+		return Config{}, err
+	}
+	deadLetterRetryIntervalSec, err := getEnvInt("NOTIFY_DEAD_LETTER_RETRY_INTERVAL_SECONDS", 30)
+	if err != nil {
+		return Config{}, err
+	}
+	deadLetterBatchSize, err := getEnvInt("NOTIFY_DEAD_LETTER_BATCH_SIZE", 50)
+	if err != nil {
+		return Config{}, err
+	}
+	cfg.Notify = NotifyConfig{
+		PublicBaseURL:           getEnv("NOTIFY_PUBLIC_BASE_URL", ""),
+		WebhookMaxDataBytes:     webhookMaxDataBytes,
+		SlackMaxMessageLength:   slackMaxMessageLength,
+		MaxChannelsPerEvent:     maxChannelsPerEvent,
+		BatchFlushCheckInterval: time.Duration(batchFlushCheckIntervalSec) * time.Second,
+		MaxRetries:              notifyMaxRetries,
+		RetryBackoff:            time.Duration(notifyRetryBackoffMS) * time.Millisecond,
+		WebhookSigningSecret:    getEnv("NOTIFY_WEBHOOK_SIGNING_SECRET", ""),
+		DeadLetterBackoff:       time.Duration(deadLetterBackoffSec) * time.Second,
+		DeadLetterRetryInterval: time.Duration(deadLetterRetryIntervalSec) * time.Second,
+		DeadLetterBatchSize:     deadLetterBatchSize,
+	}
+
+	cfg.Email = EmailConfig{
+		SMTPAddr:     getEnv("EMAIL_SMTP_ADDR", ""),
+		SMTPUsername: getEnv("EMAIL_SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("EMAIL_SMTP_PASSWORD", ""),
+		From:         getEnv("EMAIL_FROM", ""),
+	}
+	if raw := getEnv("EMAIL_TEMPLATES", ""); raw != "" {
+		var templates map[string]EmailTemplate
+		if err := json.Unmarshal([]byte(raw), &templates); err != nil {
+			return Config{}, fmt.Errorf("config: EMAIL_TEMPLATES must be valid JSON: %w", err)
+		}
+		cfg.Email.Templates = templates
+	}
+
+	notificationWorkers, err := getEnvInt("NOTIFICATION_WORKERS", 10)
+	if err != nil {
+		return Config{}, err
+	}
+	notificationQueueSize, err := getEnvInt("NOTIFICATION_QUEUE_SIZE", 1000)
+	if err != nil {
+		return Config{}, err
+	}
+	notificationSubmitTimeoutMS, err := getEnvInt("NOTIFICATION_SUBMIT_TIMEOUT_MS", 0)
+	if err != nil {
+		return Config{}, err
+	}
+	cfg.Notification = NotificationConfig{
+		Workers:       notificationWorkers,
+		QueueSize:     notificationQueueSize,
+		SubmitTimeout: time.Duration(notificationSubmitTimeoutMS) * time.Millisecond,
+	}
+
+	throttleDefaultWindowSec, err := getEnvInt("NOTIFY_THROTTLE_DEFAULT_WINDOW_SECONDS", 0)
+	if err != nil {
+		return Config{}, err
+	}
+	cfg.Throttle = ThrottleConfig{
+		Enabled:       getEnv("NOTIFY_THROTTLE_ENABLED", "false") == "true",
+		DefaultWindow: time.Duration(throttleDefaultWindowSec) * time.Second,
+	}
+	if raw := getEnv("NOTIFY_THROTTLE_WINDOWS", ""); raw != "" {
+		var windowSeconds map[string]map[string]int
+		if err := json.Unmarshal([]byte(raw), &windowSeconds); err != nil {
+			return Config{}, fmt.Errorf("config: NOTIFY_THROTTLE_WINDOWS must be valid JSON: %w", err)
+		}
+		windows := make(map[string]map[string]time.Duration, len(windowSeconds))
+		for tenantID, byEvent := range windowSeconds {
+			windows[tenantID] = make(map[string]time.Duration, len(byEvent))
+			for event, seconds := range byEvent {
+				windows[tenantID][event] = time.Duration(seconds) * time.Second
+			}
+		}
+		cfg.Throttle.Windows = windows
+	}
+
+	redisMaxRetries, err := getEnvInt("REDIS_MAX_RETRIES", 3)
+	if err != nil {
+		return Config{}, err
+	}
+	redisMinRetryBackoffMs, err := getEnvInt("REDIS_MIN_RETRY_BACKOFF_MS", 8)
+	if err != nil {
+		return Config{}, err
+	}
+	redisMaxRetryBackoffMs, err := getEnvInt("REDIS_MAX_RETRY_BACKOFF_MS", 512)
+	if err != nil {
+		return Config{}, err
+	}
+	redisBreakerThreshold, err := getEnvInt("REDIS_CIRCUIT_BREAKER_THRESHOLD", 5)
+	if err != nil {
+		return Config{}, err
+	}
+	redisBreakerCooldownSec, err := getEnvInt("REDIS_CIRCUIT_BREAKER_COOLDOWN_SECONDS", 30)
+	if err != nil {
+		return Config{}, err
+	}
+	redisHealthCheckIntervalSec, err := getEnvInt("REDIS_HEALTH_CHECK_INTERVAL_SECONDS", 5)
+	if err != nil {
+		return Config{}, err
+	}
+	redisHealthCheckTimeoutSec, err := getEnvInt("REDIS_HEALTH_CHECK_TIMEOUT_SECONDS", 2)
+	if err != nil {
+		return Config{}, err
+	}
+	cfg.Redis = RedisConfig{
+		Addr:                    getEnv("REDIS_ADDR", "localhost:6379"),
+		MaxRetries:              redisMaxRetries,
+		MinRetryBackoff:         time.Duration(redisMinRetryBackoffMs) * time.Millisecond,
+		MaxRetryBackoff:         time.Duration(redisMaxRetryBackoffMs) * time.Millisecond,
+		CircuitBreakerThreshold: redisBreakerThreshold,
+		CircuitBreakerCooldown:  time.Duration(redisBreakerCooldownSec) * time.Second,
+		HealthCheckInterval:     time.Duration(redisHealthCheckIntervalSec) * time.Second,
+		HealthCheckTimeout:      time.Duration(redisHealthCheckTimeoutSec) * time.Second,
+		RequiredForReadiness:    getEnv("REDIS_REQUIRED_FOR_READINESS", "false") == "true",
+	}
+
+	dedupWindowSec, err := getEnvInt("DEDUP_WINDOW_SECONDS", 60)
+	if err != nil {
+		return Config{}, err
+	}
+	dedupFields := []string{"tenant_id", "event", "resource", "data"}
+	if raw := getEnv("DEDUP_FIELDS", ""); raw != "" {
+		dedupFields = strings.Split(raw, ",")
+	}
+	for _, field := range dedupFields {
+		if !dedupFieldAllowlist[field] {
+			return Config{}, fmt.Errorf("config: DEDUP_FIELDS contains unsupported field %q", field)
+		}
+	}
+	cfg.Dedup = DedupConfig{
+		Enabled: getEnv("DEDUP_ENABLED", "false") == "true",
+		Window:  time.Duration(dedupWindowSec) * time.Second,
+		Fields:  dedupFields,
+	}
+
+	idempotencyWindowSec, err := getEnvInt("IDEMPOTENCY_WINDOW_SECONDS", 86400)
+	if err != nil {
+		return Config{}, err
+	}
+	cfg.Idempotency = IdempotencyConfig{
+		Window: time.Duration(idempotencyWindowSec) * time.Second,
+	}
+
+	cacheTTLSec, err := getEnvInt("CACHE_TTL_SECONDS", 300)
+	if err != nil {
+		return Config{}, err
+	}
+	cfg.Cache = CacheConfig{
+		Enabled: getEnv("CACHE_ENABLED", "false") == "true",
+		TTL:     time.Duration(cacheTTLSec) * time.Second,
+	}
+
+	cfg.Tracing = TracingConfig{
+		Enabled:      getEnv("TRACING_ENABLED", "false") == "true",
+		OTLPEndpoint: getEnv("TRACING_OTLP_ENDPOINT", ""),
+	}
+
+	statsCacheTTLSec, err := getEnvInt("STATS_METRICS_CACHE_TTL_SECONDS", 30)
+	if err != nil {
+		return Config{}, err
+	}
+	errorEventSuffixes := []string{".failed", ".error"}
+	if raw := getEnv("STATS_ERROR_EVENT_SUFFIXES", ""); raw != "" {
+		errorEventSuffixes = strings.Split(raw, ",")
+	}
+	cfg.Stats = StatsConfig{
+		ErrorEventSuffixes: errorEventSuffixes,
+		MetricsCacheTTL:    time.Duration(statsCacheTTLSec) * time.Second,
+	}
+
+	maxPastSkewSec, err := getEnvInt("TIMESTAMP_MAX_PAST_SKEW_SECONDS", 24*60*60)
+	if err != nil {
+		return Config{}, err
+	}
+	maxFutureSkewSec, err := getEnvInt("TIMESTAMP_MAX_FUTURE_SKEW_SECONDS", 5*60)
+	if err != nil {
+		return Config{}, err
+	}
+	cfg.Timestamp = TimestampConfig{
+		MaxPastSkew:   time.Duration(maxPastSkewSec) * time.Second,
+		MaxFutureSkew: time.Duration(maxFutureSkewSec) * time.Second,
+	}
+
+	businessHoursStartHour, err := getEnvInt("BUSINESS_HOURS_DEFAULT_START_HOUR", 9)
+	if err != nil {
+		return Config{}, err
+	}
+	businessHoursEndHour, err := getEnvInt("BUSINESS_HOURS_DEFAULT_END_HOUR", 17)
+	if err != nil {
+		return Config{}, err
+	}
+	cfg.BusinessHours = BusinessHoursConfig{
+		Enabled: getEnv("BUSINESS_HOURS_ENABLED", "false") == "true",
+		Default: BusinessHoursWindow{
+			Timezone:  getEnv("BUSINESS_HOURS_DEFAULT_TIMEZONE", "UTC"),
+			StartHour: businessHoursStartHour,
+			EndHour:   businessHoursEndHour,
+		},
+	}
+	if raw := getEnv("BUSINESS_HOURS_DEFAULT_DAYS", ""); raw != "" {
+		cfg.BusinessHours.Default.Days = strings.Split(raw, ",")
+	}
+	if raw := getEnv("BUSINESS_HOURS_TENANT_WINDOWS", ""); raw != "" {
+		var windows map[string]BusinessHoursWindow
+		if err := json.Unmarshal([]byte(raw), &windows); err != nil {
+			return Config{}, fmt.Errorf("config: BUSINESS_HOURS_TENANT_WINDOWS must be valid JSON: %w", err)
+		}
+		cfg.BusinessHours.TenantWindows = windows
+	}
+	if cfg.BusinessHours.Enabled {
+		windows := map[string]BusinessHoursWindow{"default": cfg.BusinessHours.Default}
+		for tenantID, w := range cfg.BusinessHours.TenantWindows {
+			windows[tenantID] = w
+		}
+		for tenantID, w := range windows {
+			if _, err := time.LoadLocation(w.Timezone); err != nil {
+				return Config{}, fmt.Errorf("config: business hours window for %q has invalid timezone %q: %w", tenantID, w.Timezone, err)
+			}
+			if w.StartHour < 0 || w.StartHour > 23 || w.EndHour < 0 || w.EndHour > 23 || w.StartHour >= w.EndHour {
+				return Config{}, fmt.Errorf("config: business hours window for %q must have 0 <= start_hour < end_hour <= 23", tenantID)
+			}
+			for _, day := range w.Days {
+				if _, ok := weekdayAllowlist[day]; !ok {
+					return Config{}, fmt.Errorf("config: business hours window for %q has unknown day %q", tenantID, day)
+				}
+			}
+		}
+	}
+
+	quotaDefaultBytes, err := getEnvInt("QUOTA_DEFAULT_BYTES", 0)
+	if err != nil {
+		return Config{}, err
+	}
+	cfg.Quota = QuotaConfig{
+		Enabled:      getEnv("QUOTA_ENABLED", "false") == "true",
+		DefaultBytes: int64(quotaDefaultBytes),
+	}
+	if raw := getEnv("QUOTA_SOFT_LIMIT_RATIO", ""); raw != "" {
+		ratio, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return Config{}, fmt.Errorf("config: QUOTA_SOFT_LIMIT_RATIO must be a float: %w", err)
+		}
+		cfg.Quota.SoftLimitRatio = ratio
+	}
+	if raw := getEnv("QUOTA_TENANT_BYTES", ""); raw != "" {
+		var tenantBytes map[string]int64
+		if err := json.Unmarshal([]byte(raw), &tenantBytes); err != nil {
+			return Config{}, fmt.Errorf("config: QUOTA_TENANT_BYTES must be valid JSON: %w", err)
+		}
+		cfg.Quota.TenantBytes = tenantBytes
+	}
+
+	cfg.Sequence = SequenceConfig{Enabled: getEnv("SEQUENCE_ENABLED", "false") == "true"}
+
+	adaptiveLogWindowSec, err := getEnvInt("ADAPTIVE_LOG_WINDOW_SECONDS", 10)
+	if err != nil {
+		return Config{}, err
+	}
+	cfg.AdaptiveLog = AdaptiveLogConfig{
+		Enabled:        getEnv("ADAPTIVE_LOG_ENABLED", "false") == "true",
+		Window:         time.Duration(adaptiveLogWindowSec) * time.Second,
+		DowngradeLevel: getEnv("ADAPTIVE_LOG_DOWNGRADE_LEVEL", "warn"),
+	}
+	if raw := getEnv("ADAPTIVE_LOG_THRESHOLD_RPS", ""); raw != "" {
+		threshold, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return Config{}, fmt.Errorf("config: ADAPTIVE_LOG_THRESHOLD_RPS must be a float: %w", err)
+		}
+		cfg.AdaptiveLog.RequestsPerSecondThreshold = threshold
+	}
+	if cfg.AdaptiveLog.Enabled && !logDowngradeLevelAllowlist[cfg.AdaptiveLog.DowngradeLevel] {
+		return Config{}, fmt.Errorf("config: ADAPTIVE_LOG_DOWNGRADE_LEVEL must be one of warn, error, got %q", cfg.AdaptiveLog.DowngradeLevel)
+	}
+
+	cfg.PII = PIIConfig{Enabled: getEnv("PII_DETECTION_ENABLED", "false") == "true"}
+	if raw := getEnv("PII_DETECTION_PATTERNS", ""); raw != "" {
+		var patterns map[string]string
+		if err := json.Unmarshal([]byte(raw), &patterns); err != nil {
+			return Config{}, fmt.Errorf("config: PII_DETECTION_PATTERNS must be valid JSON: %w", err)
+		}
+		for category, expr := range patterns {
+			if _, err := regexp.Compile(expr); err != nil {
+				return Config{}, fmt.Errorf("config: PII_DETECTION_PATTERNS[%s] is not a valid regex: %w", category, err)
+			}
+		}
+		cfg.PII.Patterns = patterns
+	}
+
+	cfg.Redaction = RedactionConfig{Enabled: getEnv("REDACTION_ENABLED", "false") == "true"}
+	if raw := getEnv("REDACTION_KEYS", ""); raw != "" {
+		cfg.Redaction.Keys = strings.Split(raw, ",")
+	}
+	if raw := getEnv("REDACTION_PATTERNS", ""); raw != "" {
+		var patterns []string
+		if err := json.Unmarshal([]byte(raw), &patterns); err != nil {
+			return Config{}, fmt.Errorf("config: REDACTION_PATTERNS must be valid JSON: %w", err)
+		}
+		for _, expr := range patterns {
+			if _, err := regexp.Compile(expr); err != nil {
+				return Config{}, fmt.Errorf("config: REDACTION_PATTERNS contains an invalid regex %q: %w", expr, err)
+			}
+		}
+		cfg.Redaction.Patterns = patterns
+	}
+
+	cfg.Encryption = EncryptionConfig{
+		Enabled:     getEnv("ENCRYPTION_ENABLED", "false") == "true",
+		ActiveKeyID: getEnv("ENCRYPTION_ACTIVE_KEY_ID", ""),
+	}
+	if raw := getEnv("ENCRYPTION_KEYS", ""); raw != "" {
+		var keys map[string]string
+		if err := json.Unmarshal([]byte(raw), &keys); err != nil {
+			return Config{}, fmt.Errorf("config: ENCRYPTION_KEYS must be valid JSON: %w", err)
+		}
+		cfg.Encryption.Keys = keys
+	}
+	if cfg.Encryption.Enabled {
+		if cfg.Encryption.ActiveKeyID == "" {
+			return Config{}, fmt.Errorf("config: ENCRYPTION_ACTIVE_KEY_ID is required when ENCRYPTION_ENABLED is true")
+		}
+		if _, ok := cfg.Encryption.Keys[cfg.Encryption.ActiveKeyID]; !ok {
+			return Config{}, fmt.Errorf("config: ENCRYPTION_ACTIVE_KEY_ID %q has no entry in ENCRYPTION_KEYS", cfg.Encryption.ActiveKeyID)
+		}
+		for id, encoded := range cfg.Encryption.Keys {
+			key, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				return Config{}, fmt.Errorf("config: ENCRYPTION_KEYS[%s] must be base64: %w", id, err)
+			}
+			if len(key) != 32 {
+				return Config{}, fmt.Errorf("config: ENCRYPTION_KEYS[%s] must decode to 32 bytes for AES-256, got %d", id, len(key))
+			}
+		}
+	}
+
+	fileSinkMaxSizeMB, err := getEnvInt("FILESINK_MAX_SIZE_MB", 100)
+	if err != nil {
+		return Config{}, err
+	}
+	fileSinkMaxBackups, err := getEnvInt("FILESINK_MAX_BACKUPS", 0)
+	if err != nil {
+		return Config{}, err
+	}
+	fileSinkMaxAgeDays, err := getEnvInt("FILESINK_MAX_AGE_DAYS", 0)
+	if err != nil {
+		return Config{}, err
+	}
+	cfg.FileSink = FileSinkConfig{
+		Enabled:    getEnv("FILESINK_ENABLED", "false") == "true",
+		Path:       getEnv("FILESINK_PATH", "audit.ndjson"),
+		Mode:       getEnv("FILESINK_MODE", "mirror"),
+		MaxSizeMB:  fileSinkMaxSizeMB,
+		MaxBackups: fileSinkMaxBackups,
+		MaxAgeDays: fileSinkMaxAgeDays,
+		Compress:   getEnv("FILESINK_COMPRESS", "false") == "true",
+	}
+	if cfg.FileSink.Enabled && !fileSinkModeAllowlist[cfg.FileSink.Mode] {
+		return Config{}, fmt.Errorf("config: FILESINK_MODE must be one of mirror, primary, got %q", cfg.FileSink.Mode)
+	}
+
+	cfg.Kafka = KafkaConfig{
+		Enabled: getEnv("KAFKA_ENABLED", "false") == "true",
+		Topic:   getEnv("KAFKA_TOPIC", "audit-logs"),
+	}
+	if raw := getEnv("KAFKA_BROKERS", ""); raw != "" {
+		cfg.Kafka.Brokers = strings.Split(raw, ",")
+	}
+	if cfg.Kafka.Enabled && len(cfg.Kafka.Brokers) == 0 {
+		return Config{}, fmt.Errorf("config: KAFKA_BROKERS is required when KAFKA_ENABLED is true")
+	}
+
+	natsAckTimeoutSec, err := getEnvInt("NATS_ACK_TIMEOUT_SECONDS", 2)
+	if err != nil {
+		return Config{}, err
+	}
+	cfg.NATS = NATSConfig{
+		Enabled:    getEnv("NATS_ENABLED", "false") == "true",
+		Stream:     getEnv("NATS_STREAM", "AUDIT"),
+		AckTimeout: time.Duration(natsAckTimeoutSec) * time.Second,
+	}
+	if raw := getEnv("NATS_SERVERS", ""); raw != "" {
+		cfg.NATS.Servers = strings.Split(raw, ",")
+	}
+	if cfg.NATS.Enabled && len(cfg.NATS.Servers) == 0 {
+		return Config{}, fmt.Errorf("config: NATS_SERVERS is required when NATS_ENABLED is true")
+	}
+
+	cfg.Docs = DocsConfig{
+		Enabled: getEnv("DOCS_ENABLED", "false") == "true",
+	}
+
+	cfg.Logging = LoggingConfig{
+		Level: getEnv("LOG_LEVEL", "info"),
+	}
+	if !logLevelAllowlist[cfg.Logging.Level] {
+		return Config{}, fmt.Errorf("config: LOG_LEVEL must be one of debug, info, warn, error, got %q", cfg.Logging.Level)
+	}
+
+	cfg.AccessAudit = AccessAuditConfig{
+		Enabled: getEnv("ACCESS_AUDIT_ENABLED", "false") == "true",
+	}
+
+	return cfg, nil
+}
+
+// parseKeyValueList parses a comma-separated list of "key=value" pairs,
+// as used for AUTH_API_KEYS and AUTH_MTLS_PRINCIPALS. An empty string
+// yields an empty (non-nil) map.
+func parseKeyValueList(s string) map[string]string {
+	out := map[string]string{}
+	if s == "" {
+		return out
+	}
+	for _, pair := range strings.Split(s, ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		out[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return out
+}
+
+func envKey(endpoint string) string {
+	out := make([]byte, len(endpoint))
+	for i := 0; i < len(endpoint); i++ {
+		c := endpoint[i]
+		if c >= 'a' && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+		out[i] = c
+	}
+	return string(out)
+}
+
+func getEnv(key, fallback string) string {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		return v
+	}
+	return fallback
+}
+
+func getEnvInt(key string, fallback int) (int, error) {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("config: %s must be an integer, got %q: %w", key, v, err)
+	}
+	return n, nil
+}
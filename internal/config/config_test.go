@@ -0,0 +1,845 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPagination_Resolve(t *testing.T) {
+	p := Pagination{DefaultLimit: 50, MaxLimit: 1000}
+
+	cases := []struct {
+		name      string
+		requested int
+		want      int
+	}{
+		{"zero uses default", 0, 50},
+		{"negative uses default", -1, 50},
+		{"within range passes through", 200, 200},
+		{"above max is capped", 5000, 1000},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := p.Resolve(tc.requested); got != tc.want {
+				t.Errorf("Resolve(%d) = %d, want %d", tc.requested, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLoad_PerEndpointOverride(t *testing.T) {
+	t.Setenv("AUDIT_LOGS_PAGINATION_DEFAULT_LIMIT", "10")
+	t.Setenv("AUDIT_LOGS_PAGINATION_MAX_LIMIT", "20")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := cfg.PaginationFor("audit_logs")
+	if got.DefaultLimit != 10 || got.MaxLimit != 20 {
+		t.Errorf("got %+v, want DefaultLimit=10 MaxLimit=20", got)
+	}
+
+	// Untouched endpoints keep falling back to the global default.
+	stats := cfg.PaginationFor("stats")
+	if stats.DefaultLimit != 50 || stats.MaxLimit != 1000 {
+		t.Errorf("stats pagination = %+v, want fallback to default", stats)
+	}
+}
+
+func TestLoad_RejectsMaxBelowDefault(t *testing.T) {
+	t.Setenv("PAGINATION_DEFAULT_LIMIT", "100")
+	t.Setenv("PAGINATION_MAX_LIMIT", "10")
+
+	if _, err := Load(); err == nil {
+		t.Error("expected error when max limit is below default limit")
+	}
+}
+
+func TestLoad_ParsesAuthRoutePolicies(t *testing.T) {
+	t.Setenv("AUTH_ROUTE_POLICIES", `{"DELETE /api/v1/tenants/{tenant_id}":{"require_auth":true,"allowed_types":["jwt"],"required_scopes":["audit:delete"]}}`)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	policy, ok := cfg.Auth.RoutePolicies["DELETE /api/v1/tenants/{tenant_id}"]
+	if !ok {
+		t.Fatal("expected route policy to be parsed")
+	}
+	if !policy.RequireAuth || len(policy.AllowedTypes) != 1 || policy.AllowedTypes[0] != "jwt" {
+		t.Errorf("got policy %+v", policy)
+	}
+}
+
+func TestLoad_DefaultsMetaDenylist(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	found := false
+	for _, key := range cfg.MetaDenylist {
+		if key == "password" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected default meta denylist to include password, got %v", cfg.MetaDenylist)
+	}
+}
+
+func TestLoad_ExtendsMetaDenylist(t *testing.T) {
+	t.Setenv("INGESTION_META_DENYLIST", "ssn,credit_card")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	want := map[string]bool{"password": false, "ssn": false, "credit_card": false}
+	for _, key := range cfg.MetaDenylist {
+		if _, ok := want[key]; ok {
+			want[key] = true
+		}
+	}
+	for key, found := range want {
+		if !found {
+			t.Errorf("expected meta denylist to include %q, got %v", key, cfg.MetaDenylist)
+		}
+	}
+}
+
+func TestLoad_DefaultsFileSinkDisabled(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.FileSink.Enabled {
+		t.Error("expected file sink to be disabled by default")
+	}
+}
+
+func TestLoad_ParsesFileSinkConfig(t *testing.T) {
+	t.Setenv("FILESINK_ENABLED", "true")
+	t.Setenv("FILESINK_PATH", "/var/log/audit.ndjson")
+	t.Setenv("FILESINK_MODE", "primary")
+	t.Setenv("FILESINK_MAX_SIZE_MB", "50")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !cfg.FileSink.Enabled || cfg.FileSink.Path != "/var/log/audit.ndjson" || cfg.FileSink.Mode != "primary" || cfg.FileSink.MaxSizeMB != 50 {
+		t.Errorf("got %+v", cfg.FileSink)
+	}
+}
+
+func TestLoad_RejectsUnknownFileSinkMode(t *testing.T) {
+	t.Setenv("FILESINK_ENABLED", "true")
+	t.Setenv("FILESINK_MODE", "replica")
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for unknown file sink mode")
+	}
+}
+
+func TestLoad_DefaultsKafkaDisabled(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Kafka.Enabled {
+		t.Error("expected kafka publishing to be disabled by default")
+	}
+}
+
+func TestLoad_ParsesKafkaConfig(t *testing.T) {
+	t.Setenv("KAFKA_ENABLED", "true")
+	t.Setenv("KAFKA_BROKERS", "broker-1:9092,broker-2:9092")
+	t.Setenv("KAFKA_TOPIC", "audit-events")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !cfg.Kafka.Enabled || cfg.Kafka.Topic != "audit-events" || len(cfg.Kafka.Brokers) != 2 || cfg.Kafka.Brokers[0] != "broker-1:9092" {
+		t.Errorf("got %+v", cfg.Kafka)
+	}
+}
+
+func TestLoad_RejectsKafkaEnabledWithoutBrokers(t *testing.T) {
+	t.Setenv("KAFKA_ENABLED", "true")
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for missing KAFKA_BROKERS")
+	}
+}
+
+func TestLoad_DefaultsNATSDisabled(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.NATS.Enabled {
+		t.Error("expected NATS publishing to be disabled by default")
+	}
+}
+
+func TestLoad_ParsesNATSConfig(t *testing.T) {
+	t.Setenv("NATS_ENABLED", "true")
+	t.Setenv("NATS_SERVERS", "nats://node-1:4222,nats://node-2:4222")
+	t.Setenv("NATS_STREAM", "AUDIT_EVENTS")
+	t.Setenv("NATS_ACK_TIMEOUT_SECONDS", "5")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !cfg.NATS.Enabled || cfg.NATS.Stream != "AUDIT_EVENTS" || len(cfg.NATS.Servers) != 2 || cfg.NATS.AckTimeout != 5*time.Second {
+		t.Errorf("got %+v", cfg.NATS)
+	}
+}
+
+func TestLoad_RejectsNATSEnabledWithoutServers(t *testing.T) {
+	t.Setenv("NATS_ENABLED", "true")
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for missing NATS_SERVERS")
+	}
+}
+
+func TestLoad_DefaultsDocsDisabled(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Docs.Enabled {
+		t.Error("expected docs UI to be disabled by default")
+	}
+}
+
+func TestLoad_ParsesDocsConfig(t *testing.T) {
+	t.Setenv("DOCS_ENABLED", "true")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !cfg.Docs.Enabled {
+		t.Error("expected docs UI to be enabled")
+	}
+}
+
+func TestLoad_DefaultsAutoMigrateDisabled(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.DB.AutoMigrate {
+		t.Error("expected auto-migrate to be disabled by default")
+	}
+}
+
+func TestLoad_ParsesDBAutoMigrate(t *testing.T) {
+	t.Setenv("DB_AUTO_MIGRATE", "true")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !cfg.DB.AutoMigrate {
+		t.Error("expected auto-migrate to be enabled")
+	}
+}
+
+func TestLoad_DefaultsQueryTimeoutDisabled(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.DB.QueryTimeout != 0 {
+		t.Errorf("expected query timeout to default to 0 (disabled), got %v", cfg.DB.QueryTimeout)
+	}
+}
+
+func TestLoad_ParsesDBQueryTimeout(t *testing.T) {
+	t.Setenv("DB_QUERY_TIMEOUT_SECONDS", "5")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.DB.QueryTimeout != 5*time.Second {
+		t.Errorf("expected query timeout of 5s, got %v", cfg.DB.QueryTimeout)
+	}
+}
+
+func TestLoad_DefaultsMaxBodyBytesTo1MB(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.RequestLimits.MaxBodyBytes != 1<<20 {
+		t.Errorf("expected max body bytes to default to 1MB, got %d", cfg.RequestLimits.MaxBodyBytes)
+	}
+}
+
+func TestLoad_ParsesRequestMaxBodyBytes(t *testing.T) {
+	t.Setenv("REQUEST_MAX_BODY_BYTES", "2048")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.RequestLimits.MaxBodyBytes != 2048 {
+		t.Errorf("expected max body bytes of 2048, got %d", cfg.RequestLimits.MaxBodyBytes)
+	}
+}
+
+func TestLoad_DefaultsEmailTemplatesEmpty(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Email.Templates != nil {
+		t.Errorf("expected no email templates by default, got %v", cfg.Email.Templates)
+	}
+}
+
+func TestLoad_ParsesEmailTemplates(t *testing.T) {
+	t.Setenv("EMAIL_TEMPLATES", `{"":{"Subject":"Audit: {{.Event}}","Text":"{{.Event}} happened"},"user.login":{"Subject":"Login","Text":"{{.Actor}} logged in"}}`)
+	t.Setenv("EMAIL_SMTP_ADDR", "smtp.example.com:587")
+	t.Setenv("EMAIL_FROM", "audit@example.com")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Email.SMTPAddr != "smtp.example.com:587" {
+		t.Errorf("expected SMTP addr to be parsed, got %q", cfg.Email.SMTPAddr)
+	}
+	if cfg.Email.From != "audit@example.com" {
+		t.Errorf("expected From to be parsed, got %q", cfg.Email.From)
+	}
+	if len(cfg.Email.Templates) != 2 {
+		t.Fatalf("expected 2 templates, got %d: %v", len(cfg.Email.Templates), cfg.Email.Templates)
+	}
+	if cfg.Email.Templates["user.login"].Subject != "Login" {
+		t.Errorf("expected user.login subject %q, got %q", "Login", cfg.Email.Templates["user.login"].Subject)
+	}
+}
+
+func TestLoad_RejectsInvalidEmailTemplatesJSON(t *testing.T) {
+	t.Setenv("EMAIL_TEMPLATES", "not json")
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected an error for malformed EMAIL_TEMPLATES")
+	}
+}
+
+func TestLoad_DefaultsThrottleDisabled(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Throttle.Enabled {
+		t.Error("expected throttle to be disabled by default")
+	}
+}
+
+func TestLoad_ParsesThrottleWindows(t *testing.T) {
+	t.Setenv("NOTIFY_THROTTLE_ENABLED", "true")
+	t.Setenv("NOTIFY_THROTTLE_DEFAULT_WINDOW_SECONDS", "60")
+	t.Setenv("NOTIFY_THROTTLE_WINDOWS", `{"tenant-a":{"RATE_LIMIT_EXCEEDED":300}}`)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !cfg.Throttle.Enabled {
+		t.Error("expected throttle to be enabled")
+	}
+	if got := cfg.Throttle.WindowFor("tenant-a", "RATE_LIMIT_EXCEEDED"); got != 300*time.Second {
+		t.Errorf("WindowFor(tenant-a, RATE_LIMIT_EXCEEDED) = %v, want 300s", got)
+	}
+	if got := cfg.Throttle.WindowFor("tenant-a", "user.login"); got != 60*time.Second {
+		t.Errorf("WindowFor(tenant-a, user.login) = %v, want default 60s", got)
+	}
+	if got := cfg.Throttle.WindowFor("tenant-b", "RATE_LIMIT_EXCEEDED"); got != 60*time.Second {
+		t.Errorf("WindowFor(tenant-b, RATE_LIMIT_EXCEEDED) = %v, want default 60s", got)
+	}
+}
+
+func TestLoad_DefaultsRetentionDisabled(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Retention.Days != 0 {
+		t.Errorf("expected retention to default to disabled (Days=0), got %d", cfg.Retention.Days)
+	}
+}
+
+func TestLoad_ParsesRetentionByTenant(t *testing.T) {
+	t.Setenv("RETENTION_DAYS", "90")
+	t.Setenv("RETENTION_BY_TENANT_DAYS", `{"tenant-a":30}`)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := cfg.Retention.DaysFor("tenant-a"); got != 30 {
+		t.Errorf("DaysFor(tenant-a) = %d, want override 30", got)
+	}
+	if got := cfg.Retention.DaysFor("tenant-b"); got != 90 {
+		t.Errorf("DaysFor(tenant-b) = %d, want default 90", got)
+	}
+}
+
+func TestLoad_RejectsInvalidThrottleWindowsJSON(t *testing.T) {
+	t.Setenv("NOTIFY_THROTTLE_WINDOWS", "not json")
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for invalid NOTIFY_THROTTLE_WINDOWS")
+	}
+}
+
+func TestLoad_DefaultsCacheDisabled(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Cache.Enabled {
+		t.Error("expected cache to be disabled by default")
+	}
+	if cfg.Cache.TTL != 300*time.Second {
+		t.Errorf("default CACHE_TTL_SECONDS = %v, want 300s", cfg.Cache.TTL)
+	}
+}
+
+func TestLoad_ParsesCacheEnabled(t *testing.T) {
+	t.Setenv("CACHE_ENABLED", "true")
+	t.Setenv("CACHE_TTL_SECONDS", "30")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !cfg.Cache.Enabled {
+		t.Error("expected cache to be enabled")
+	}
+	if cfg.Cache.TTL != 30*time.Second {
+		t.Errorf("CACHE_TTL_SECONDS = %v, want 30s", cfg.Cache.TTL)
+	}
+}
+
+func TestLoad_DefaultsTracingDisabled(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Tracing.Enabled {
+		t.Error("expected tracing to be disabled by default")
+	}
+	if cfg.Tracing.OTLPEndpoint != "" {
+		t.Errorf("default TRACING_OTLP_ENDPOINT = %q, want empty", cfg.Tracing.OTLPEndpoint)
+	}
+}
+
+func TestLoad_ParsesTracingEnabled(t *testing.T) {
+	t.Setenv("TRACING_ENABLED", "true")
+	t.Setenv("TRACING_OTLP_ENDPOINT", "otel-collector:4317")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !cfg.Tracing.Enabled {
+		t.Error("expected tracing to be enabled")
+	}
+	if cfg.Tracing.OTLPEndpoint != "otel-collector:4317" {
+		t.Errorf("TRACING_OTLP_ENDPOINT = %q, want otel-collector:4317", cfg.Tracing.OTLPEndpoint)
+	}
+}
+
+func TestLoad_ParsesAPIKeyTenants(t *testing.T) {
+	t.Setenv("AUTH_API_KEY_TENANTS", `{"secret-key":["tenant-a","tenant-b"]}`)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	tenants, ok := cfg.Auth.APIKeyTenants["secret-key"]
+	if !ok || len(tenants) != 2 || tenants[0] != "tenant-a" || tenants[1] != "tenant-b" {
+		t.Errorf("got %+v", cfg.Auth.APIKeyTenants)
+	}
+}
+
+func TestLoad_ParsesAPIKeyScopes(t *testing.T) {
+	t.Setenv("AUTH_API_KEY_SCOPES", `{"secret-key":["audit:read","audit:write"]}`)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	scopes, ok := cfg.Auth.APIKeyScopes["secret-key"]
+	if !ok || len(scopes) != 2 || scopes[0] != "audit:read" || scopes[1] != "audit:write" {
+		t.Errorf("got %+v", cfg.Auth.APIKeyScopes)
+	}
+}
+
+func TestLoad_ParsesMTLSScopes(t *testing.T) {
+	t.Setenv("AUTH_MTLS_SCOPES", `{"client.internal":["audit:read","audit:write"]}`)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	scopes, ok := cfg.Auth.MTLS.Scopes["client.internal"]
+	if !ok || len(scopes) != 2 || scopes[0] != "audit:read" || scopes[1] != "audit:write" {
+		t.Errorf("got %+v", cfg.Auth.MTLS.Scopes)
+	}
+}
+
+func TestLoad_RejectsUnknownCredentialTypeInRoutePolicy(t *testing.T) {
+	t.Setenv("AUTH_ROUTE_POLICIES", `{"POST /api/v1/audit":{"allowed_types":["carrier_pigeon"]}}`)
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for unknown credential type")
+	}
+}
+
+func TestLoad_ParsesBusinessHoursTenantWindows(t *testing.T) {
+	t.Setenv("BUSINESS_HOURS_ENABLED", "true")
+	t.Setenv("BUSINESS_HOURS_TENANT_WINDOWS", `{"tenant-a":{"timezone":"America/New_York","start_hour":8,"end_hour":18,"days":["Monday","Tuesday"]}}`)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	window := cfg.BusinessHours.WindowFor("tenant-a")
+	if window.Timezone != "America/New_York" || window.StartHour != 8 || window.EndHour != 18 {
+		t.Errorf("got window %+v", window)
+	}
+}
+
+func TestLoad_RejectsInvalidTimezoneInBusinessHours(t *testing.T) {
+	t.Setenv("BUSINESS_HOURS_ENABLED", "true")
+	t.Setenv("BUSINESS_HOURS_DEFAULT_TIMEZONE", "Mars/Olympus_Mons")
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for invalid timezone")
+	}
+}
+
+func TestLoad_RejectsUnknownWeekdayInBusinessHours(t *testing.T) {
+	t.Setenv("BUSINESS_HOURS_ENABLED", "true")
+	t.Setenv("BUSINESS_HOURS_DEFAULT_DAYS", "Funday")
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for unknown weekday")
+	}
+}
+
+func TestLoad_ParsesQuotaTenantBytes(t *testing.T) {
+	t.Setenv("QUOTA_ENABLED", "true")
+	t.Setenv("QUOTA_DEFAULT_BYTES", "1000000")
+	t.Setenv("QUOTA_TENANT_BYTES", `{"tenant-a":5000000}`)
+	t.Setenv("QUOTA_SOFT_LIMIT_RATIO", "0.9")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Quota.BytesFor("tenant-a") != 5000000 {
+		t.Errorf("got %d, want 5000000", cfg.Quota.BytesFor("tenant-a"))
+	}
+	if cfg.Quota.BytesFor("tenant-b") != 1000000 {
+		t.Errorf("got %d, want 1000000 (default)", cfg.Quota.BytesFor("tenant-b"))
+	}
+	if cfg.Quota.SoftLimitRatio != 0.9 {
+		t.Errorf("got %v, want 0.9", cfg.Quota.SoftLimitRatio)
+	}
+}
+
+func TestLoad_DefaultsListenNetworkToTCP(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.ListenNetwork != "tcp" {
+		t.Errorf("got %q, want tcp", cfg.ListenNetwork)
+	}
+}
+
+func TestLoad_RejectsUnknownListenNetwork(t *testing.T) {
+	t.Setenv("LISTEN_NETWORK", "udp")
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for unsupported listen network")
+	}
+}
+
+func TestLoad_ParsesAdaptiveLogThreshold(t *testing.T) {
+	t.Setenv("ADAPTIVE_LOG_ENABLED", "true")
+	t.Setenv("ADAPTIVE_LOG_THRESHOLD_RPS", "100.5")
+	t.Setenv("ADAPTIVE_LOG_DOWNGRADE_LEVEL", "error")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.AdaptiveLog.RequestsPerSecondThreshold != 100.5 {
+		t.Errorf("got %v, want 100.5", cfg.AdaptiveLog.RequestsPerSecondThreshold)
+	}
+	if cfg.AdaptiveLog.DowngradeLevel != "error" {
+		t.Errorf("got %q, want error", cfg.AdaptiveLog.DowngradeLevel)
+	}
+}
+
+func TestLoad_RejectsUnknownAdaptiveLogDowngradeLevel(t *testing.T) {
+	t.Setenv("ADAPTIVE_LOG_ENABLED", "true")
+	t.Setenv("ADAPTIVE_LOG_DOWNGRADE_LEVEL", "debug")
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for unknown downgrade level")
+	}
+}
+
+func TestLoad_DefaultsLogLevelToInfo(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Logging.Level != "info" {
+		t.Errorf("got %q, want info", cfg.Logging.Level)
+	}
+}
+
+func TestLoad_ParsesLogLevel(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "debug")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Logging.Level != "debug" {
+		t.Errorf("got %q, want debug", cfg.Logging.Level)
+	}
+}
+
+func TestLoad_RejectsUnknownLogLevel(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "trace")
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for unknown log level")
+	}
+}
+
+func TestLoad_DefaultsAccessAuditDisabled(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.AccessAudit.Enabled {
+		t.Error("expected access audit to default to disabled")
+	}
+}
+
+func TestLoad_ParsesAccessAuditEnabled(t *testing.T) {
+	t.Setenv("ACCESS_AUDIT_ENABLED", "true")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !cfg.AccessAudit.Enabled {
+		t.Error("expected access audit to be enabled")
+	}
+}
+
+func TestLoad_DefaultsAsyncWriteStatusTransitionsToNil(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.AsyncWrite.StatusTransitions != nil {
+		t.Errorf("expected StatusTransitions to default to nil, got %+v", cfg.AsyncWrite.StatusTransitions)
+	}
+}
+
+func TestLoad_ParsesAsyncWriteStatusTransitions(t *testing.T) {
+	t.Setenv("ASYNC_WRITE_STATUS_TRANSITIONS", `{"pending":["retrying","written","failed"]}`)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want := []string{"retrying", "written", "failed"}
+	if !reflect.DeepEqual(cfg.AsyncWrite.StatusTransitions["pending"], want) {
+		t.Errorf("StatusTransitions[pending] = %v, want %v", cfg.AsyncWrite.StatusTransitions["pending"], want)
+	}
+}
+
+func TestLoad_RejectsInvalidAsyncWriteStatusTransitionsJSON(t *testing.T) {
+	t.Setenv("ASYNC_WRITE_STATUS_TRANSITIONS", "not json")
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for invalid ASYNC_WRITE_STATUS_TRANSITIONS")
+	}
+}
+
+func TestLoad_DefaultsClientIPTrustedProxiesToEmpty(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(cfg.ClientIP.TrustedProxyCIDRs) != 0 {
+		t.Errorf("expected no trusted proxy CIDRs by default, got %v", cfg.ClientIP.TrustedProxyCIDRs)
+	}
+}
+
+func TestLoad_ParsesClientIPTrustedProxyCIDRs(t *testing.T) {
+	t.Setenv("CLIENT_IP_TRUSTED_PROXY_CIDRS", "10.0.0.0/8, 172.16.0.0/12")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want := []string{"10.0.0.0/8", "172.16.0.0/12"}
+	if !reflect.DeepEqual(cfg.ClientIP.TrustedProxyCIDRs, want) {
+		t.Errorf("got %v, want %v", cfg.ClientIP.TrustedProxyCIDRs, want)
+	}
+}
+
+func TestLoad_RejectsInvalidClientIPTrustedProxyCIDR(t *testing.T) {
+	t.Setenv("CLIENT_IP_TRUSTED_PROXY_CIDRS", "not-a-cidr")
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for invalid CLIENT_IP_TRUSTED_PROXY_CIDRS")
+	}
+}
+
+func TestLoad_DefaultsRedactionToDisabled(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Redaction.Enabled {
+		t.Error("expected redaction to default to disabled")
+	}
+}
+
+func TestLoad_ParsesRedactionKeysAndPatterns(t *testing.T) {
+	t.Setenv("REDACTION_ENABLED", "true")
+	t.Setenv("REDACTION_KEYS", "password,ssn,token")
+	t.Setenv("REDACTION_PATTERNS", `["\\d{3}-\\d{2}-\\d{4}"]`)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !cfg.Redaction.Enabled {
+		t.Error("expected redaction to be enabled")
+	}
+	wantKeys := []string{"password", "ssn", "token"}
+	if !reflect.DeepEqual(cfg.Redaction.Keys, wantKeys) {
+		t.Errorf("Keys = %v, want %v", cfg.Redaction.Keys, wantKeys)
+	}
+	wantPatterns := []string{`\d{3}-\d{2}-\d{4}`}
+	if !reflect.DeepEqual(cfg.Redaction.Patterns, wantPatterns) {
+		t.Errorf("Patterns = %v, want %v", cfg.Redaction.Patterns, wantPatterns)
+	}
+}
+
+func TestLoad_RejectsInvalidRedactionPatternsJSON(t *testing.T) {
+	t.Setenv("REDACTION_PATTERNS", "not json")
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for invalid REDACTION_PATTERNS")
+	}
+}
+
+func TestLoad_RejectsInvalidRedactionPatternRegex(t *testing.T) {
+	t.Setenv("REDACTION_PATTERNS", `["("]`)
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for invalid REDACTION_PATTERNS regex")
+	}
+}
+
+func TestLoad_DefaultsEncryptionToDisabled(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Encryption.Enabled {
+		t.Error("expected encryption to default to disabled")
+	}
+}
+
+func TestLoad_ParsesEncryptionConfig(t *testing.T) {
+	t.Setenv("ENCRYPTION_ENABLED", "true")
+	t.Setenv("ENCRYPTION_ACTIVE_KEY_ID", "k1")
+	t.Setenv("ENCRYPTION_KEYS", `{"k1":"MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="}`)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !cfg.Encryption.Enabled {
+		t.Error("expected encryption to be enabled")
+	}
+	if cfg.Encryption.ActiveKeyID != "k1" {
+		t.Errorf("ActiveKeyID = %q, want k1", cfg.Encryption.ActiveKeyID)
+	}
+	if cfg.Encryption.Keys["k1"] == "" {
+		t.Error("expected Keys[k1] to be populated")
+	}
+}
+
+func TestLoad_RejectsEncryptionEnabledWithoutActiveKeyID(t *testing.T) {
+	t.Setenv("ENCRYPTION_ENABLED", "true")
+	t.Setenv("ENCRYPTION_KEYS", `{"k1":"MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="}`)
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error when ENCRYPTION_ENABLED is true without ENCRYPTION_ACTIVE_KEY_ID")
+	}
+}
+
+func TestLoad_RejectsEncryptionActiveKeyIDMissingFromKeys(t *testing.T) {
+	t.Setenv("ENCRYPTION_ENABLED", "true")
+	t.Setenv("ENCRYPTION_ACTIVE_KEY_ID", "missing")
+	t.Setenv("ENCRYPTION_KEYS", `{"k1":"MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="}`)
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error when ENCRYPTION_ACTIVE_KEY_ID has no entry in ENCRYPTION_KEYS")
+	}
+}
+
+func TestLoad_RejectsEncryptionKeyWrongLength(t *testing.T) {
+	t.Setenv("ENCRYPTION_ENABLED", "true")
+	t.Setenv("ENCRYPTION_ACTIVE_KEY_ID", "k1")
+	t.Setenv("ENCRYPTION_KEYS", `{"k1":"dG9vc2hvcnQ="}`)
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for an encryption key that isn't 32 bytes")
+	}
+}
+
+func TestLoad_RejectsInvalidEncryptionKeysJSON(t *testing.T) {
+	t.Setenv("ENCRYPTION_KEYS", "not json")
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for invalid ENCRYPTION_KEYS")
+	}
+}
@@ -0,0 +1,116 @@
+// Package crypto provides field-level encryption for data stored at rest,
+// used by postgres.AuditLogRepo to protect the audit_logs.data column.
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"io"
+)
+
+// Encryptor is implemented by anything that can encrypt/decrypt a field
+// before it's written to or after it's read from storage. Decrypt must
+// accept anything its own Encrypt ever produced, including blobs produced
+// under a key id that's since been rotated out of the active position.
+type Encryptor interface {
+	Encrypt(plaintext []byte) ([]byte, error)
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+// encodingVersion is the first byte of every blob Encrypt produces, so a
+// future change to the on-disk format (a different AEAD, a different key
+// id encoding) can be introduced without breaking Decrypt on old rows.
+const encodingVersion = 1
+
+// AESGCMEncryptor implements Encryptor with AES-256-GCM. Each blob it
+// produces is self-describing:
+//
+//	[version byte][key id length byte][key id][12-byte nonce][ciphertext+tag]
+//
+// so Decrypt can look up the right key even after ActiveKeyID has moved
+// on to a newer one — rotation only requires adding the new key to keys
+// and changing activeKeyID; old blobs keep decrypting under their
+// original key id until the data is rewritten.
+type AESGCMEncryptor struct {
+	activeKeyID string
+	aeads       map[string]cipher.AEAD
+}
+
+// NewAESGCMEncryptor returns an Encryptor that encrypts under
+// keys[activeKeyID] and can decrypt a blob produced under any key in
+// keys. Every key must be exactly 32 bytes (AES-256).
+func NewAESGCMEncryptor(activeKeyID string, keys map[string][]byte) (*AESGCMEncryptor, error) {
+	if _, ok := keys[activeKeyID]; !ok {
+		return nil, fmt.Errorf("crypto: active key id %q has no corresponding key", activeKeyID)
+	}
+
+	aeads := make(map[string]cipher.AEAD, len(keys))
+	for id, key := range keys {
+		if len(key) != 32 {
+			return nil, fmt.Errorf("crypto: key %q must be 32 bytes for AES-256, got %d", id, len(key))
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("crypto: key %q: %w", id, err)
+		}
+		gcm, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("crypto: key %q: %w", id, err)
+		}
+		aeads[id] = gcm
+	}
+	return &AESGCMEncryptor{activeKeyID: activeKeyID, aeads: aeads}, nil
+}
+
+// Encrypt seals plaintext under the active key, prefixed with the
+// version/key-id header Decrypt needs to find that same key again.
+func (e *AESGCMEncryptor) Encrypt(plaintext []byte) ([]byte, error) {
+	gcm := e.aeads[e.activeKeyID]
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("crypto: generate nonce: %w", err)
+	}
+
+	out := make([]byte, 0, 2+len(e.activeKeyID)+len(nonce)+len(plaintext)+gcm.Overhead())
+	out = append(out, encodingVersion, byte(len(e.activeKeyID)))
+	out = append(out, e.activeKeyID...)
+	out = append(out, nonce...)
+	out = gcm.Seal(out, nonce, plaintext, nil)
+	return out, nil
+}
+
+// Decrypt reverses Encrypt, looking up the AEAD for whichever key id the
+// blob was sealed under rather than assuming it's always the active one.
+func (e *AESGCMEncryptor) Decrypt(ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < 2 {
+		return nil, fmt.Errorf("crypto: ciphertext too short")
+	}
+	if ciphertext[0] != encodingVersion {
+		return nil, fmt.Errorf("crypto: unsupported encoding version %d", ciphertext[0])
+	}
+	keyIDLen := int(ciphertext[1])
+	rest := ciphertext[2:]
+	if len(rest) < keyIDLen {
+		return nil, fmt.Errorf("crypto: ciphertext too short for key id")
+	}
+	keyID := string(rest[:keyIDLen])
+	rest = rest[keyIDLen:]
+
+	gcm, ok := e.aeads[keyID]
+	if !ok {
+		return nil, fmt.Errorf("crypto: unknown key id %q", keyID)
+	}
+	if len(rest) < gcm.NonceSize() {
+		return nil, fmt.Errorf("crypto: ciphertext too short for nonce")
+	}
+	nonce, sealed := rest[:gcm.NonceSize()], rest[gcm.NonceSize():]
+
+	plaintext, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, fmt.Errorf("crypto: decrypt: %w", err)
+	}
+	return plaintext, nil
+}
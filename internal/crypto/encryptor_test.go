@@ -0,0 +1,138 @@
+package crypto
+
+import (
+	"bytes"
+	"testing"
+)
+
+func testKey(b byte) []byte {
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = b
+	}
+	return key
+}
+
+func TestAESGCMEncryptor_RoundTrip(t *testing.T) {
+	enc, err := NewAESGCMEncryptor("k1", map[string][]byte{"k1": testKey(1)})
+	if err != nil {
+		t.Fatalf("NewAESGCMEncryptor: %v", err)
+	}
+
+	plaintext := []byte(`{"actor":"alice","amount":42}`)
+	ciphertext, err := enc.Encrypt(plaintext)
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if bytes.Contains(ciphertext, plaintext) {
+		t.Fatal("ciphertext contains the plaintext verbatim")
+	}
+
+	got, err := enc.Decrypt(ciphertext)
+	if err != nil {
+		t.Fatalf("Decrypt: %v", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Errorf("round trip = %q, want %q", got, plaintext)
+	}
+}
+
+func TestAESGCMEncryptor_EncryptIsNonDeterministic(t *testing.T) {
+	enc, err := NewAESGCMEncryptor("k1", map[string][]byte{"k1": testKey(1)})
+	if err != nil {
+		t.Fatalf("NewAESGCMEncryptor: %v", err)
+	}
+
+	plaintext := []byte("same input")
+	a, err := enc.Encrypt(plaintext)
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	b, err := enc.Encrypt(plaintext)
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if bytes.Equal(a, b) {
+		t.Error("two encryptions of the same plaintext produced identical ciphertext (nonce reuse)")
+	}
+}
+
+func TestAESGCMEncryptor_DecryptAfterKeyRotationUsesOriginalKeyID(t *testing.T) {
+	enc, err := NewAESGCMEncryptor("k1", map[string][]byte{"k1": testKey(1)})
+	if err != nil {
+		t.Fatalf("NewAESGCMEncryptor: %v", err)
+	}
+	plaintext := []byte("encrypted under k1")
+	ciphertext, err := enc.Encrypt(plaintext)
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+
+	rotated, err := NewAESGCMEncryptor("k2", map[string][]byte{"k1": testKey(1), "k2": testKey(2)})
+	if err != nil {
+		t.Fatalf("NewAESGCMEncryptor: %v", err)
+	}
+
+	got, err := rotated.Decrypt(ciphertext)
+	if err != nil {
+		t.Fatalf("Decrypt with rotated active key: %v", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Errorf("round trip after rotation = %q, want %q", got, plaintext)
+	}
+
+	newCiphertext, err := rotated.Encrypt(plaintext)
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if newCiphertext[1] != byte(len("k2")) {
+		t.Errorf("new ciphertext's key id length = %d, want len(%q)", newCiphertext[1], "k2")
+	}
+}
+
+func TestAESGCMEncryptor_DecryptFailsForUnknownKeyID(t *testing.T) {
+	enc, err := NewAESGCMEncryptor("k1", map[string][]byte{"k1": testKey(1)})
+	if err != nil {
+		t.Fatalf("NewAESGCMEncryptor: %v", err)
+	}
+	ciphertext, err := enc.Encrypt([]byte("secret"))
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+
+	other, err := NewAESGCMEncryptor("k2", map[string][]byte{"k2": testKey(2)})
+	if err != nil {
+		t.Fatalf("NewAESGCMEncryptor: %v", err)
+	}
+	if _, err := other.Decrypt(ciphertext); err == nil {
+		t.Fatal("expected error decrypting a blob sealed under a key we don't have")
+	}
+}
+
+func TestAESGCMEncryptor_DecryptFailsForTamperedCiphertext(t *testing.T) {
+	enc, err := NewAESGCMEncryptor("k1", map[string][]byte{"k1": testKey(1)})
+	if err != nil {
+		t.Fatalf("NewAESGCMEncryptor: %v", err)
+	}
+	ciphertext, err := enc.Encrypt([]byte("secret"))
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	ciphertext[len(ciphertext)-1] ^= 0xFF
+
+	if _, err := enc.Decrypt(ciphertext); err == nil {
+		t.Fatal("expected error decrypting tampered ciphertext")
+	}
+}
+
+func TestNewAESGCMEncryptor_RejectsMissingActiveKey(t *testing.T) {
+	if _, err := NewAESGCMEncryptor("missing", map[string][]byte{"k1": testKey(1)}); err == nil {
+		t.Fatal("expected error when active key id has no corresponding key")
+	}
+}
+
+func TestNewAESGCMEncryptor_RejectsWrongKeyLength(t *testing.T) {
+	if _, err := NewAESGCMEncryptor("k1", map[string][]byte{"k1": []byte("too-short")}); err == nil {
+		t.Fatal("expected error for a key that isn't 32 bytes")
+	}
+}
@@ -0,0 +1,75 @@
+// Package dedup implements a lightweight, content-hash-based store for
+// the HTTP-edge request dedup window (see service.AuditService's Deduper),
+// backed by Redis.
+package dedup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/resilience"
+)
+
+// RedisStore stores dedup records in Redis, keyed by content hash, with
+// the caller-supplied window as the key's TTL. Dedup is an optimization,
+// not a correctness requirement, so it fails open: once breaker reports
+// the circuit open, Get and Set skip Redis entirely and behave as a cache
+// miss/no-op instead of blocking CreateAuditLog on a down dependency.
+type RedisStore struct {
+	client  *redis.Client
+	breaker *resilience.Breaker
+}
+
+// NewRedisStore returns a store backed by a Redis client connected to
+// addr, retrying per retry and tripping breaker on repeated failures (see
+// resilience.Breaker). breaker may be nil to disable tripping.
+func NewRedisStore(addr string, retry resilience.RetryOptions, breaker *resilience.Breaker) *RedisStore {
+	return &RedisStore{
+		client: redis.NewClient(&redis.Options{
+			Addr:            addr,
+			MaxRetries:      retry.MaxRetries,
+			MinRetryBackoff: retry.MinRetryBackoff,
+			MaxRetryBackoff: retry.MaxRetryBackoff,
+		}),
+		breaker: breaker,
+	}
+}
+
+// Get returns the value stored under key, or found=false if it is absent
+// (never set, its TTL has expired, or the circuit breaker is currently
+// open).
+func (s *RedisStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	if !s.breaker.Allow() {
+		return nil, false, nil
+	}
+
+	val, err := s.client.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		s.breaker.RecordSuccess()
+		return nil, false, nil
+	}
+	if err != nil {
+		s.breaker.RecordFailure()
+		return nil, false, fmt.Errorf("dedup: get %s: %w", key, err)
+	}
+	s.breaker.RecordSuccess()
+	return val, true, nil
+}
+
+// Set stores value under key, expiring after ttl. It is a no-op while the
+// circuit breaker is open.
+func (s *RedisStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if !s.breaker.Allow() {
+		return nil
+	}
+
+	if err := s.client.Set(ctx, key, value, ttl).Err(); err != nil {
+		s.breaker.RecordFailure()
+		return fmt.Errorf("dedup: set %s: %w", key, err)
+	}
+	s.breaker.RecordSuccess()
+	return nil
+}
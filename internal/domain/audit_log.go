@@ -0,0 +1,209 @@
+// Package domain contains the core types shared across the service, store
+// and HTTP layers. It has no dependency on any of those packages.
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditLog is a single immutable record of something that happened in a
+// tenant's system.
+type AuditLog struct {
+	ID       uuid.UUID `json:"id"`
+	TenantID string    `json:"tenant_id"`
+	Actor    string    `json:"actor"`
+	// AuthType records which credential type (see auth.Type) created this
+	// record — "api_key", "jwt", "mtls", or empty for unauthenticated
+	// writes — so security teams can audit machine-to-machine activity
+	// separately from human actors.
+	AuthType string `json:"auth_type,omitempty"`
+	// ClientIP is the caller's address as seen by the HTTP layer (see
+	// httpapi.clientIPFromRequest), normalized to its canonical text form
+	// by AuditService.CreateAuditLog — e.g. an IPv4-mapped IPv6 address
+	// like "::ffff:192.0.2.1" is stored as "192.0.2.1" so the same client
+	// can't appear under two different strings.
+	ClientIP   string         `json:"client_ip,omitempty"`
+	Event      string         `json:"event"`
+	Resource   string         `json:"resource"`
+	ResourceID string         `json:"resource_id"`
+	Data       map[string]any `json:"data,omitempty"`
+	Meta       map[string]any `json:"meta,omitempty"`
+	// Tags are first-class labels for categorization (e.g. "high-risk",
+	// "reviewed"), distinct from the free-form Meta since they are
+	// indexed and queryable. Order is not significant.
+	Tags []string `json:"tags,omitempty"`
+	// Sequence is a per-tenant, strictly-increasing number assigned at
+	// insert time when config.SequenceConfig is enabled (0 otherwise), so
+	// consumers can detect gaps in the event stream without relying on
+	// CreatedAt, which several records can share.
+	Sequence  int64     `json:"sequence,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	// DeletedAt is set once DeleteAuditLog soft-deletes the record, nil
+	// otherwise. Soft-deleted records are excluded from List/GetByID
+	// unless Filter.IncludeDeleted is set.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// Hash and PrevHash form a per-tenant, insertion-ordered hash chain:
+	// Hash is sha256 of this record's canonical fields together with
+	// PrevHash (the previous record's Hash for the same tenant, or "" for
+	// the first record), so tampering with any historical record, or
+	// reordering the chain, changes every hash after it. See
+	// AuditService.VerifyChain.
+	Hash     string `json:"hash,omitempty"`
+	PrevHash string `json:"prev_hash,omitempty"`
+	// IdempotencyKey, when set (see the Idempotency-Key request header),
+	// lets a client safely retry CreateAuditLog after a network error
+	// without risking a duplicate: a second request with the same
+	// tenant+key within the configured window returns the original record
+	// instead of inserting a new one. See
+	// postgres.AuditLogRepo.CreateWithIdempotencyKey.
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// CreateCondition enables "create only if absent" semantics for
+// AuditService.CreateAuditLogConditional: a record matches a proposed
+// AuditLog when, for the same tenant, its values for every field named in
+// MatchFields are equal and it was created within the last Window.
+type CreateCondition struct {
+	MatchFields []string
+	Window      time.Duration
+}
+
+// Filter describes the criteria used to list audit logs. Zero values mean
+// "no constraint" for that field.
+type Filter struct {
+	TenantID   string
+	Actor      string
+	AuthType   string
+	Event      string
+	Resource   string
+	ResourceID string
+	From       time.Time
+	To         time.Time
+
+	// Limit and Offset are resolved by the service layer from the request
+	// and the configured defaults/caps before reaching the store.
+	Limit  int
+	Offset int
+
+	// Cursor, when set, selects keyset pagination instead of Offset: the
+	// store returns only records after Cursor's position in the List
+	// ordering (created_at DESC, id DESC). Takes precedence over Offset.
+	// Only compatible with the default SortBy/SortOrder.
+	Cursor *Cursor
+
+	// SortBy and SortOrder are resolved by the service layer (see
+	// service.resolveSort) from the request's sort_by/sort_order before
+	// reaching the store: SortBy is already a column name, SortOrder
+	// already "asc" or "desc". Empty means the default (created_at desc).
+	SortBy    string
+	SortOrder string
+
+	// Expr holds additional conditions parsed from a free-form filter
+	// expression (see ParseExpression), already validated against the
+	// store's field allowlist.
+	Expr []ExprCond
+
+	// ContainsPII, if non-nil, restricts results to records whose
+	// meta.contains_pii flag matches its value.
+	ContainsPII *bool
+
+	// Tags restricts results to records whose Tags overlap (or, if
+	// TagsMatchAll is set, fully contain) this set. An empty Tags means
+	// "no constraint".
+	Tags         []string
+	TagsMatchAll bool
+
+	// BusinessHours, if non-nil, restricts results to records whose
+	// meta.business_hours flag (see config.BusinessHoursConfig) matches
+	// its value; false selects off-hours events.
+	BusinessHours *bool
+
+	// IncludeDeleted, when false (the default), excludes soft-deleted
+	// records (see AuditLog.DeletedAt) from results.
+	IncludeDeleted bool
+
+	// Search, when non-empty, restricts results to records whose resource,
+	// event, data, or meta mention the term (see the `q` query parameter).
+	// Matching is full-text for terms of 3+ characters and a plain
+	// substring match otherwise, since full-text search ranks short terms
+	// poorly.
+	Search string
+
+	// Events and Resources, when non-empty, restrict results to records
+	// whose event/resource is one of the given values (column = ANY($n)),
+	// for querying several events or resources at once (e.g.
+	// ?event=USER_DELETED,USER_UPDATED). Only used by List; Event/Resource
+	// above remain the single-value exact match used by TimeSeries,
+	// Facets, and EventCounts.
+	Events    []string
+	Resources []string
+
+	// SkipData and SkipMeta, when true, tell List to return NULL for the
+	// data/meta columns instead of their real JSONB value, saving the
+	// decode cost for a caller that's not going to use them (e.g. a
+	// GraphQL query whose selection set doesn't ask for them). They have
+	// no effect on any other method.
+	SkipData bool
+	SkipMeta bool
+
+	// WithCount, when true, tells List to also compute the filter's
+	// total match count (ignoring Limit/Offset) and populate
+	// Page.Total. It's opt-in because computing it costs an extra
+	// window-function pass over the matching rows that an infinite-scroll
+	// caller, which only ever needs the next page, doesn't benefit from.
+	WithCount bool
+}
+
+// Page is a single page of audit logs along with enough information for
+// the caller to fetch the next page.
+type Page struct {
+	Logs   []AuditLog `json:"logs"`
+	Limit  int        `json:"limit"`
+	Offset int        `json:"offset"`
+	// NextCursor, when non-empty, is passed back as the cursor query
+	// parameter to fetch the page following this one via keyset
+	// pagination (see Filter.Cursor). Set whenever Logs is a full page
+	// (len(Logs) == Limit), regardless of whether the request that
+	// produced it used a cursor or an offset.
+	NextCursor string `json:"next_cursor,omitempty"`
+	// Total is the filter's total match count, ignoring Limit/Offset.
+	// Only populated when the request set Filter.WithCount; nil
+	// otherwise, to distinguish "not requested" from a genuine 0 count.
+	Total *int64 `json:"total,omitempty"`
+}
+
+// ChainVerification is the result of walking a tenant's hash chain (see
+// AuditService.VerifyChain). A broken chain reports the first record where
+// the break was found, not every subsequent one, since every hash after a
+// tampered record will also fail to verify.
+type ChainVerification struct {
+	Valid   bool  `json:"valid"`
+	Checked int64 `json:"checked"`
+	// BrokenAt is the ID of the first record that failed verification, nil
+	// if Valid.
+	BrokenAt *uuid.UUID `json:"broken_at,omitempty"`
+	Reason   string     `json:"reason,omitempty"`
+}
+
+// TimeBucket is one point of a count-per-interval histogram.
+type TimeBucket struct {
+	BucketStart time.Time `json:"bucket_start"`
+	Count       int64     `json:"count"`
+}
+
+// FacetValue is one distinct value of a field along with how many matching
+// audit logs have that value.
+type FacetValue struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// FacetResult is a bounded top-N view over a field's distinct values,
+// along with the total number of distinct values so callers know the list
+// was truncated.
+type FacetResult struct {
+	Values        []FacetValue `json:"values"`
+	TotalDistinct int64        `json:"total_distinct"`
+}
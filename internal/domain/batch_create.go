@@ -0,0 +1,16 @@
+package domain
+
+// BatchCreateResult is the outcome of a multi-entry create: each input
+// entry either lands in Created (in the same relative order it was
+// submitted) or produces a BatchEntryError at its original index, so a
+// caller can match failures back to the request it sent.
+type BatchCreateResult struct {
+	Created []AuditLog        `json:"created"`
+	Failed  []BatchEntryError `json:"failed"`
+}
+
+// BatchEntryError records why the entry at Index was rejected.
+type BatchEntryError struct {
+	Index int    `json:"index"`
+	Error string `json:"error"`
+}
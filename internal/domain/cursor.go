@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Cursor identifies a position in the List ordering (created_at DESC, id
+// DESC), letting ListAuditLogs page by keyset instead of OFFSET: only
+// records strictly after the cursor's position in that ordering are
+// returned. Keyset pagination avoids OFFSET's cost on deep pages and its
+// skipped/duplicated rows when new logs arrive between pages.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// EncodeCursor returns the opaque string a caller passes back as the
+// cursor query parameter to fetch the page following c.
+func EncodeCursor(c Cursor) string {
+	raw := fmt.Sprintf("%d,%s", c.CreatedAt.UnixNano(), c.ID.String())
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a cursor string produced by EncodeCursor.
+func DecodeCursor(s string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("domain: invalid cursor: %w", err)
+	}
+
+	parts := strings.SplitN(string(raw), ",", 2)
+	if len(parts) != 2 {
+		return Cursor{}, fmt.Errorf("domain: invalid cursor")
+	}
+
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("domain: invalid cursor timestamp: %w", err)
+	}
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return Cursor{}, fmt.Errorf("domain: invalid cursor id: %w", err)
+	}
+
+	return Cursor{CreatedAt: time.Unix(0, nanos), ID: id}, nil
+}
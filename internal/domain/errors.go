@@ -0,0 +1,38 @@
+package domain
+
+import "errors"
+
+// ErrEventNotAllowed is returned by AuditService.CreateAuditLog when the
+// event name is rejected by the tenant's configured allowlist/denylist.
+// Use errors.Is to check for it; the returned error wraps the rejected
+// event name for logging/display.
+var ErrEventNotAllowed = errors.New("event not allowed for tenant")
+
+// ErrInvalidArgument is returned by service methods when the caller
+// supplied arguments that are well-formed JSON/query params but violate a
+// business rule (e.g. an unsupported histogram interval). The HTTP layer
+// maps it to 400.
+var ErrInvalidArgument = errors.New("invalid argument")
+
+// ErrNotFound is returned by store methods whose target row does not
+// exist, for cases (like a scoped delete) where pgx itself wouldn't
+// report pgx.ErrNoRows.
+var ErrNotFound = errors.New("not found")
+
+// ErrQuotaExceeded is returned by AuditService.CreateAuditLog when the
+// tenant's stored byte usage (see config.QuotaConfig) is at or above its
+// configured quota. The HTTP layer maps it to 507 Insufficient Storage.
+var ErrQuotaExceeded = errors.New("tenant storage quota exceeded")
+
+// ErrTenantNotAllowed is returned by the HTTP layer (see
+// auth.TenantAllowed) when the caller's credential is bound to a set of
+// tenant IDs that does not include the one being created or read. The
+// HTTP layer maps it to 403 Forbidden.
+var ErrTenantNotAllowed = errors.New("credential is not permitted for this tenant")
+
+// ErrInvalidStatusTransition is returned when a WriteStatus would move to
+// a status not reachable from its current one under a configured
+// AsyncWriteConfig.StatusTransitions (see
+// service.AsyncWriteService.WithStatusTransitions). Callers that surface
+// it over HTTP should map it to 409 Conflict.
+var ErrInvalidStatusTransition = errors.New("invalid status transition")
@@ -0,0 +1,24 @@
+package domain
+
+// ExprOp is a comparison operator allowed in a free-form filter
+// expression. The set is deliberately small and maps 1:1 to safe SQL
+// operators — there is no way to reach arbitrary SQL through it.
+type ExprOp string
+
+const (
+	ExprEq   ExprOp = "="
+	ExprNeq  ExprOp = "!="
+	ExprGt   ExprOp = ">"
+	ExprLt   ExprOp = "<"
+	ExprGte  ExprOp = ">="
+	ExprLte  ExprOp = "<="
+	ExprLike ExprOp = "~"
+)
+
+// ExprCond is one "field op value" condition parsed from a free-form
+// filter expression. Conditions are ANDed together.
+type ExprCond struct {
+	Field string
+	Op    ExprOp
+	Value string
+}
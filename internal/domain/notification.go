@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FailedNotification records a single channel's delivery of an audit log
+// event that failed (after any sender-level retries — see
+// notify.WebhookSender.WithRetry), so NotificationRetryWorker can retry it
+// independently of the write that triggered it.
+type FailedNotification struct {
+	ID         uuid.UUID `json:"id"`
+	AuditLogID uuid.UUID `json:"audit_log_id"`
+	TenantID   string    `json:"tenant_id"`
+	// Channel is the subscription URL the delivery was attempted against,
+	// the same value notify.Dispatcher uses to pick a sender by URL.
+	Channel string `json:"channel"`
+	Error   string `json:"error"`
+	// Attempts counts every delivery attempt recorded so far, including
+	// the original failure that first enqueued this row.
+	Attempts    int       `json:"attempts"`
+	NextRetryAt time.Time `json:"next_retry_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,19 @@
+package domain
+
+// TenantStats summarizes a tenant's audit log activity: overall volume,
+// breakdowns by event type and resource, and how many of those events
+// look like failures (see config.StatsConfig.ErrorEventSuffixes).
+type TenantStats struct {
+	TenantID       string           `json:"tenant_id"`
+	TotalEvents    int64            `json:"total_events"`
+	ErrorEvents    int64            `json:"error_events"`
+	ErrorRate      float64          `json:"error_rate"`
+	EventCounts    map[string]int64 `json:"event_counts"`
+	ResourceCounts map[string]int64 `json:"resource_counts"`
+
+	// StorageBytes and QuotaBytes are only populated when
+	// config.QuotaConfig.Enabled is true. QuotaBytes is 0 for an
+	// unlimited tenant.
+	StorageBytes int64 `json:"storage_bytes,omitempty"`
+	QuotaBytes   int64 `json:"quota_bytes,omitempty"`
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookSubscription is a tenant's registration to receive a callback
+// when matching audit events occur.
+type WebhookSubscription struct {
+	ID       uuid.UUID `json:"id"`
+	TenantID string    `json:"tenant_id"`
+	URL      string    `json:"url"`
+	Events   []string  `json:"events"` // event names to match; empty means "all events"
+
+	// BatchMaxSize and BatchLingerSeconds enable opt-in delivery batching
+	// for generic webhook subscribers (see notify.BatchingWebhookSender):
+	// events are buffered and posted as a single JSON array once the
+	// batch reaches BatchMaxSize events or BatchLingerSeconds elapses,
+	// whichever comes first. BatchMaxSize <= 0 disables batching and
+	// keeps the default one-event-per-POST delivery.
+	BatchMaxSize       int `json:"batch_max_size,omitempty"`
+	BatchLingerSeconds int `json:"batch_linger_seconds,omitempty"`
+
+	// Secret, when non-empty, overrides the service-wide
+	// NOTIFY_WEBHOOK_SIGNING_SECRET for this subscription, so a tenant's
+	// deliveries can be verified with a secret that's never shared with
+	// any other tenant. Empty falls back to the service-wide secret.
+	Secret string `json:"secret,omitempty"`
+
+	// Enabled gates delivery: Notify skips a subscription with Enabled
+	// false instead of matching it, so a subscriber can be paused without
+	// losing its registration (events list, batching settings) by
+	// deleting and later recreating it.
+	Enabled bool `json:"enabled"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,21 @@
+package domain
+
+// WriteStatus is the lifecycle state of a record enqueued through the
+// async-write ingestion path.
+type WriteStatus string
+
+const (
+	WriteStatusPending  WriteStatus = "pending"
+	WriteStatusRetrying WriteStatus = "retrying"
+	WriteStatusWritten  WriteStatus = "written"
+	WriteStatusFailed   WriteStatus = "failed"
+)
+
+// WriteTracking reports the current state of a previously-enqueued
+// async write.
+type WriteTracking struct {
+	TrackingID string      `json:"tracking_id"`
+	Status     WriteStatus `json:"status"`
+	Error      string      `json:"error,omitempty"`
+	Attempts   int         `json:"attempts,omitempty"`
+}
@@ -0,0 +1,49 @@
+package filesink
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+)
+
+func TestSink_WriteAppendsNDJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.ndjson")
+	sink := New(path, 0, 0, 0, false)
+	defer sink.Close()
+
+	want := []string{"user.login", "user.logout"}
+	for _, event := range want {
+		if err := sink.Write(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: event}); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer f.Close()
+
+	var got []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var log domain.AuditLog
+		if err := json.Unmarshal(scanner.Bytes(), &log); err != nil {
+			t.Fatalf("unmarshal line %q: %v", scanner.Text(), err)
+		}
+		got = append(got, log.Event)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d lines, want %d: %v", len(got), len(want), got)
+	}
+	for i, event := range want {
+		if got[i] != event {
+			t.Errorf("line %d: got event %q, want %q", i, got[i], event)
+		}
+	}
+}
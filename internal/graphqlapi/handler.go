@@ -0,0 +1,130 @@
+// Package graphqlapi exposes the audit-log service over GraphQL (see
+// /graphql), alongside the REST API in internal/httpapi and the gRPC API
+// in internal/grpcapi. Like grpcapi, every resolver delegates straight
+// into the same Service the REST handlers call, so validation, tenant
+// policy enforcement and enrichment stay identical regardless of
+// transport.
+package graphqlapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graphql-go/graphql"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/auth"
+	"github.com/naveenkumar2412/audit-log-service/internal/config"
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+	"github.com/naveenkumar2412/audit-log-service/internal/httpapi"
+)
+
+// Service is the subset of httpapi.Service the GraphQL API needs. Kept
+// local (rather than importing httpapi.Service) so this package doesn't
+// depend on the REST transport package for an interface it only uses
+// part of — see grpcapi.Service for the same convention.
+type Service interface {
+	CreateAuditLog(ctx context.Context, log domain.AuditLog) (domain.AuditLog, error)
+	GetAuditLog(ctx context.Context, tenantID, id string, includeDeleted bool) (domain.AuditLog, error)
+	ListAuditLogs(ctx context.Context, filter domain.Filter) (domain.Page, error)
+	GetStats(ctx context.Context, tenantID string) (domain.TenantStats, error)
+}
+
+// Handler exposes the audit-log GraphQL API.
+type Handler struct {
+	svc         Service
+	schema      graphql.Schema
+	routePolicy *config.RoutePolicy
+}
+
+// NewHandler returns a Handler backed by svc. An error here means the
+// schema failed to build, which can only happen if the type definitions
+// in schema.go are malformed — i.e. a bug in this package, not a runtime
+// condition — but it's still surfaced rather than panicking, consistent
+// with how this service treats every other startup failure (see
+// cmd/server/main.go's run()).
+func NewHandler(svc Service) (*Handler, error) {
+	h := &Handler{svc: svc}
+	schema, err := newSchema(h)
+	if err != nil {
+		return nil, err
+	}
+	h.schema = schema
+	return h, nil
+}
+
+// WithAuthPolicies overrides the default auth requirement (attempted but
+// not required) for POST /graphql, keeping it in step with
+// httpapi.Handler.WithAuthPolicies. Since /graphql is a single endpoint
+// fronting several logical operations, only one policy applies; pass the
+// policy keyed "POST /graphql" in cfg.Auth.RoutePolicies.
+func (h *Handler) WithAuthPolicies(policies map[string]config.RoutePolicy) *Handler {
+	if policy, ok := policies["POST /graphql"]; ok {
+		h.routePolicy = &policy
+	}
+	return h
+}
+
+// Routes registers the GraphQL endpoint on r.
+func (h *Handler) Routes(r chi.Router) {
+	if h.routePolicy != nil {
+		r.With(auth.Require(toAuthPolicy(*h.routePolicy))).Post("/graphql", h.serveGraphQL)
+		return
+	}
+	r.Post("/graphql", h.serveGraphQL)
+}
+
+func toAuthPolicy(policy config.RoutePolicy) auth.RoutePolicy {
+	types := make([]auth.Type, len(policy.AllowedTypes))
+	for i, t := range policy.AllowedTypes {
+		types[i] = auth.Type(t)
+	}
+	return auth.RoutePolicy{
+		RequireAuth:    policy.RequireAuth,
+		AllowedTypes:   types,
+		RequiredScopes: policy.RequiredScopes,
+	}
+}
+
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+func (h *Handler) serveGraphQL(w http.ResponseWriter, r *http.Request) {
+	var req graphQLRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid GraphQL request body", http.StatusBadRequest)
+		return
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:         h.schema,
+		RequestString:  req.Query,
+		OperationName:  req.OperationName,
+		VariableValues: req.Variables,
+		Context:        r.Context(),
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(result)
+}
+
+// resolveTenantID mirrors httpapi.resolveTenantID: an explicit argument
+// wins, falling back to the tenant attached by httpapi.TenantFromHeader
+// (run as router middleware ahead of this handler, same as every REST
+// route) and then to the caller's sole allowed tenant.
+func resolveTenantID(ctx context.Context, explicit string) string {
+	if explicit != "" {
+		return explicit
+	}
+	if tenantID, ok := httpapi.TenantFromContext(ctx); ok {
+		return tenantID
+	}
+	if tenantID, ok := auth.EffectiveTenant(ctx); ok {
+		return tenantID
+	}
+	return ""
+}
@@ -0,0 +1,233 @@
+package graphqlapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/auth"
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+)
+
+type fakeService struct {
+	Service
+
+	createLog domain.AuditLog
+	createErr error
+
+	getLog domain.AuditLog
+	getErr error
+
+	page       domain.Page
+	listErr    error
+	lastFilter domain.Filter
+
+	stats    domain.TenantStats
+	statsErr error
+}
+
+func (f *fakeService) CreateAuditLog(ctx context.Context, log domain.AuditLog) (domain.AuditLog, error) {
+	return f.createLog, f.createErr
+}
+
+func (f *fakeService) GetAuditLog(ctx context.Context, tenantID, id string, includeDeleted bool) (domain.AuditLog, error) {
+	return f.getLog, f.getErr
+}
+
+func (f *fakeService) ListAuditLogs(ctx context.Context, filter domain.Filter) (domain.Page, error) {
+	f.lastFilter = filter
+	return f.page, f.listErr
+}
+
+func (f *fakeService) GetStats(ctx context.Context, tenantID string) (domain.TenantStats, error) {
+	return f.stats, f.statsErr
+}
+
+func doGraphQL(t *testing.T, h *Handler, query string, variables map[string]interface{}) map[string]interface{} {
+	t.Helper()
+	result := doGraphQLWithContext(t, h, context.Background(), query, variables)
+	if errs, ok := result["errors"]; ok {
+		t.Fatalf("unexpected GraphQL errors: %v", errs)
+	}
+	return result
+}
+
+func doGraphQLWithContext(t *testing.T, h *Handler, ctx context.Context, query string, variables map[string]interface{}) map[string]interface{} {
+	t.Helper()
+	body, err := json.Marshal(graphQLRequest{Query: query, Variables: variables})
+	if err != nil {
+		t.Fatalf("marshal request: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body)).WithContext(ctx)
+	w := httptest.NewRecorder()
+	h.serveGraphQL(w, req)
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("unmarshal response: %v (body=%s)", err, w.Body.String())
+	}
+	return result
+}
+
+// restrictedContext returns a context carrying an authenticated principal
+// restricted to allowedTenant, mirroring grpcapi's helper of the same name.
+func restrictedContext(allowedTenant string) context.Context {
+	return auth.WithContext(context.Background(), auth.Context{Principal: auth.Principal{AllowedTenants: []string{allowedTenant}}})
+}
+
+func newTestHandler(t *testing.T, svc Service) *Handler {
+	t.Helper()
+	h, err := NewHandler(svc)
+	if err != nil {
+		t.Fatalf("NewHandler: %v", err)
+	}
+	return h
+}
+
+func TestResolveAuditLog_TrimsUnrequestedFields(t *testing.T) {
+	id := uuid.New()
+	svc := &fakeService{getLog: domain.AuditLog{
+		ID:       id,
+		TenantID: "tenant-a",
+		Event:    "USER_LOGIN",
+		Data:     map[string]any{"ip": "10.0.0.1"},
+		Meta:     map[string]any{"trace_id": "abc"},
+	}}
+	h := newTestHandler(t, svc)
+
+	result := doGraphQL(t, h, `query($id: ID!) { auditLog(id: $id, tenantId: "tenant-a") { id event data } }`,
+		map[string]interface{}{"id": id.String()})
+
+	log, ok := result["data"].(map[string]interface{})["auditLog"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected auditLog object, got %#v", result["data"])
+	}
+	if log["data"] == nil {
+		t.Error("expected requested field data to be present")
+	}
+	if _, hasMeta := log["meta"]; hasMeta {
+		t.Error("meta was not selected and should not appear in the response")
+	}
+}
+
+func TestResolveAuditLogs_SetsSkipDataAndSkipMetaFromSelection(t *testing.T) {
+	svc := &fakeService{page: domain.Page{Logs: []domain.AuditLog{{ID: uuid.New(), TenantID: "tenant-a"}}}}
+	h := newTestHandler(t, svc)
+
+	doGraphQL(t, h, `{ auditLogs(filter: {tenantId: "tenant-a"}) { logs { id event } } }`, nil)
+
+	if !svc.lastFilter.SkipData {
+		t.Error("expected SkipData to be true when data isn't selected")
+	}
+	if !svc.lastFilter.SkipMeta {
+		t.Error("expected SkipMeta to be true when meta isn't selected")
+	}
+}
+
+func TestResolveAuditLogs_DoesNotSkipSelectedFields(t *testing.T) {
+	svc := &fakeService{page: domain.Page{Logs: []domain.AuditLog{{ID: uuid.New(), TenantID: "tenant-a"}}}}
+	h := newTestHandler(t, svc)
+
+	doGraphQL(t, h, `{ auditLogs(filter: {tenantId: "tenant-a"}) { logs { id data meta } } }`, nil)
+
+	if svc.lastFilter.SkipData {
+		t.Error("expected SkipData to be false when data is selected")
+	}
+	if svc.lastFilter.SkipMeta {
+		t.Error("expected SkipMeta to be false when meta is selected")
+	}
+}
+
+func TestResolveAuditLogs_MapsFilterAndPagination(t *testing.T) {
+	svc := &fakeService{page: domain.Page{}}
+	h := newTestHandler(t, svc)
+
+	doGraphQL(t, h, `{
+		auditLogs(
+			filter: {tenantId: "tenant-a", events: ["USER_LOGIN", "USER_LOGOUT"], tags: ["reviewed"]},
+			pagination: {limit: 25, offset: 10}
+		) { limit offset }
+	}`, nil)
+
+	f := svc.lastFilter
+	if f.TenantID != "tenant-a" {
+		t.Errorf("got TenantID %q, want tenant-a", f.TenantID)
+	}
+	if len(f.Events) != 2 || f.Events[0] != "USER_LOGIN" || f.Events[1] != "USER_LOGOUT" {
+		t.Errorf("got Events %v, want [USER_LOGIN USER_LOGOUT]", f.Events)
+	}
+	if len(f.Tags) != 1 || f.Tags[0] != "reviewed" {
+		t.Errorf("got Tags %v, want [reviewed]", f.Tags)
+	}
+	if f.Limit != 25 || f.Offset != 10 {
+		t.Errorf("got Limit=%d Offset=%d, want Limit=25 Offset=10", f.Limit, f.Offset)
+	}
+}
+
+func TestResolveAuditLogs_RejectsCrossTenantFilterForRestrictedPrincipal(t *testing.T) {
+	svc := &fakeService{page: domain.Page{Logs: []domain.AuditLog{{ID: uuid.New(), TenantID: "victim-tenant"}}}}
+	h := newTestHandler(t, svc)
+
+	result := doGraphQLWithContext(t, h, restrictedContext("attacker-tenant"),
+		`{ auditLogs(filter: {tenantId: "victim-tenant"}) { logs { id } } }`, nil)
+
+	if _, ok := result["errors"]; !ok {
+		t.Fatalf("expected a cross-tenant rejection error, got %#v", result)
+	}
+	if data, ok := result["data"].(map[string]interface{}); ok && data["auditLogs"] != nil {
+		t.Errorf("expected no data for a rejected cross-tenant query, got %#v", data)
+	}
+}
+
+func TestResolveCreateAuditLog_DelegatesToService(t *testing.T) {
+	created := domain.AuditLog{ID: uuid.New(), TenantID: "tenant-a", Event: "USER_LOGIN"}
+	svc := &fakeService{createLog: created}
+	h := newTestHandler(t, svc)
+
+	result := doGraphQL(t, h, `mutation {
+		createAuditLog(input: {tenantId: "tenant-a", event: "USER_LOGIN", data: {ip: "10.0.0.1"}}) { id event }
+	}`, nil)
+
+	out, ok := result["data"].(map[string]interface{})["createAuditLog"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected createAuditLog object, got %#v", result["data"])
+	}
+	if out["event"] != "USER_LOGIN" {
+		t.Errorf("got event %v, want USER_LOGIN", out["event"])
+	}
+}
+
+func TestResolveAuditStats_DelegatesToService(t *testing.T) {
+	svc := &fakeService{stats: domain.TenantStats{TenantID: "tenant-a", TotalEvents: 42}}
+	h := newTestHandler(t, svc)
+
+	result := doGraphQL(t, h, `{ auditStats(tenantId: "tenant-a") { tenantId totalEvents } }`, nil)
+
+	out, ok := result["data"].(map[string]interface{})["auditStats"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected auditStats object, got %#v", result["data"])
+	}
+	if out["totalEvents"].(float64) != 42 {
+		t.Errorf("got totalEvents %v, want 42", out["totalEvents"])
+	}
+}
+
+func TestResolveAuditStats_RejectsCrossTenantArgForRestrictedPrincipal(t *testing.T) {
+	svc := &fakeService{stats: domain.TenantStats{TenantID: "victim-tenant", TotalEvents: 42}}
+	h := newTestHandler(t, svc)
+
+	result := doGraphQLWithContext(t, h, restrictedContext("attacker-tenant"),
+		`{ auditStats(tenantId: "victim-tenant") { tenantId totalEvents } }`, nil)
+
+	if _, ok := result["errors"]; !ok {
+		t.Fatalf("expected a cross-tenant rejection error, got %#v", result)
+	}
+	if data, ok := result["data"].(map[string]interface{}); ok && data["auditStats"] != nil {
+		t.Errorf("expected no data for a rejected cross-tenant query, got %#v", data)
+	}
+}
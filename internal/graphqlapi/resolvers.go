@@ -0,0 +1,238 @@
+package graphqlapi
+
+import (
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/auth"
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+)
+
+func (h *Handler) resolveAuditLog(p graphql.ResolveParams) (interface{}, error) {
+	id, _ := p.Args["id"].(string)
+	explicitTenant, _ := p.Args["tenantId"].(string)
+	tenantID := resolveTenantID(p.Context, explicitTenant)
+	if !auth.TenantAllowed(p.Context, tenantID) {
+		return nil, domain.ErrTenantNotAllowed
+	}
+	includeDeleted, _ := p.Args["includeDeleted"].(bool)
+
+	log, err := h.svc.GetAuditLog(p.Context, tenantID, id, includeDeleted)
+	if err != nil {
+		return nil, err
+	}
+
+	// auditLog(id) has no DB-level equivalent of auditLogs' SkipData/
+	// SkipMeta (GetAuditLog's signature is scalar-only — see
+	// service.Repo.GetByID — so there's no Filter to carry the flag
+	// through). Trim the unrequested field here instead: it saves the
+	// response payload even though it doesn't save the JSONB decode on
+	// the way out of postgres.
+	requested := requestedFields(p.Info.FieldASTs)
+	if !requested["data"] {
+		log.Data = nil
+	}
+	if !requested["meta"] {
+		log.Meta = nil
+	}
+	return log, nil
+}
+
+func (h *Handler) resolveAuditLogs(p graphql.ResolveParams) (interface{}, error) {
+	filter := filterFromArgs(p.Args["filter"])
+	filter.TenantID = resolveTenantID(p.Context, filter.TenantID)
+	if !auth.TenantAllowed(p.Context, filter.TenantID) {
+		return nil, domain.ErrTenantNotAllowed
+	}
+	applyPagination(&filter, p.Args["pagination"])
+
+	requested := requestedFieldsOf(p.Info.FieldASTs, "logs")
+	filter.SkipData = !requested["data"]
+	filter.SkipMeta = !requested["meta"]
+
+	page, err := h.svc.ListAuditLogs(p.Context, filter)
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+func (h *Handler) resolveAuditStats(p graphql.ResolveParams) (interface{}, error) {
+	explicitTenant, _ := p.Args["tenantId"].(string)
+	tenantID := resolveTenantID(p.Context, explicitTenant)
+	if !auth.TenantAllowed(p.Context, tenantID) {
+		return nil, domain.ErrTenantNotAllowed
+	}
+	// range is accepted (see timeRangeInputType's doc comment) but not
+	// used: AuditService.GetStats has no time-window parameter to pass
+	// it to.
+	return h.svc.GetStats(p.Context, tenantID)
+}
+
+func (h *Handler) resolveCreateAuditLog(p graphql.ResolveParams) (interface{}, error) {
+	input, _ := p.Args["input"].(map[string]interface{})
+
+	newLog := domain.AuditLog{
+		TenantID:   resolveTenantID(p.Context, stringField(input, "tenantId")),
+		Actor:      stringField(input, "actor"),
+		Event:      stringField(input, "event"),
+		Resource:   stringField(input, "resource"),
+		ResourceID: stringField(input, "resourceId"),
+		Data:       mapField(input, "data"),
+		Meta:       mapField(input, "meta"),
+		Tags:       stringSliceField(input, "tags"),
+	}
+	if ts, ok := input["timestamp"].(time.Time); ok {
+		newLog.CreatedAt = ts
+	}
+	if !auth.TenantAllowed(p.Context, newLog.TenantID) {
+		return nil, domain.ErrTenantNotAllowed
+	}
+
+	return h.svc.CreateAuditLog(p.Context, newLog)
+}
+
+func stringField(m map[string]interface{}, key string) string {
+	v, _ := m[key].(string)
+	return v
+}
+
+func mapField(m map[string]interface{}, key string) map[string]interface{} {
+	v, _ := m[key].(map[string]interface{})
+	return v
+}
+
+func stringSliceField(m map[string]interface{}, key string) []string {
+	raw, ok := m[key].([]interface{})
+	if !ok {
+		return nil
+	}
+	out := make([]string, 0, len(raw))
+	for _, v := range raw {
+		if s, ok := v.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func boolField(m map[string]interface{}, key string) bool {
+	v, _ := m[key].(bool)
+	return v
+}
+
+// filterFromArgs maps an AuditLogFilterInput argument onto domain.Filter.
+// A nil/absent filter argument yields the zero Filter, i.e. "no
+// constraint", matching the REST listAuditLogs handler's default.
+func filterFromArgs(raw interface{}) domain.Filter {
+	m, ok := raw.(map[string]interface{})
+	if !ok {
+		return domain.Filter{}
+	}
+	filter := domain.Filter{
+		TenantID:       stringField(m, "tenantId"),
+		Actor:          stringField(m, "actor"),
+		AuthType:       stringField(m, "authType"),
+		Event:          stringField(m, "event"),
+		Resource:       stringField(m, "resource"),
+		ResourceID:     stringField(m, "resourceId"),
+		Events:         stringSliceField(m, "events"),
+		Resources:      stringSliceField(m, "resources"),
+		Tags:           stringSliceField(m, "tags"),
+		TagsMatchAll:   boolField(m, "tagsMatchAll"),
+		Search:         stringField(m, "search"),
+		IncludeDeleted: boolField(m, "includeDeleted"),
+		SortBy:         stringField(m, "sortBy"),
+		SortOrder:      stringField(m, "sortOrder"),
+	}
+	if v, ok := m["from"].(time.Time); ok {
+		filter.From = v
+	}
+	if v, ok := m["to"].(time.Time); ok {
+		filter.To = v
+	}
+	if v, ok := m["containsPii"].(bool); ok {
+		filter.ContainsPII = &v
+	}
+	if v, ok := m["businessHours"].(bool); ok {
+		filter.BusinessHours = &v
+	}
+	return filter
+}
+
+// applyPagination maps a PaginationInput argument onto filter's
+// Limit/Offset/Cursor, the same fields httpapi.listAuditLogs resolves
+// from query parameters. An invalid cursor is ignored rather than
+// returned as an error, since pagination here is best-effort; an
+// unparseable cursor simply falls back to Offset (0 by default).
+func applyPagination(filter *domain.Filter, raw interface{}) {
+	m, ok := raw.(map[string]interface{})
+	if !ok {
+		return
+	}
+	if v, ok := m["limit"].(int); ok {
+		filter.Limit = v
+	}
+	if cursor, ok := m["cursor"].(string); ok && cursor != "" {
+		if decoded, err := domain.DecodeCursor(cursor); err == nil {
+			filter.Cursor = &decoded
+			return
+		}
+	}
+	if v, ok := m["offset"].(int); ok {
+		filter.Offset = v
+	}
+}
+
+// requestedFields returns the set of immediate child field names selected
+// across every occurrence of a field in asts (normally exactly one,
+// unless the same field is requested twice under different aliases,
+// which doesn't change which underlying data is needed). Selections
+// inside fragments aren't expanded — an edge case this helper doesn't
+// need to handle precisely: any fragment marks data and meta as
+// requested, so a field gets fetched that wasn't strictly needed rather
+// than one getting dropped that was.
+func requestedFields(asts []*ast.Field) map[string]bool {
+	fields := map[string]bool{}
+	for _, f := range asts {
+		collectSelectedFields(f.SelectionSet, fields)
+	}
+	return fields
+}
+
+// requestedFieldsOf is requestedFields scoped to the selection set of the
+// single child field named under (e.g. "logs" inside an auditLogs query),
+// for queries whose payload nests the AuditLog selection under another
+// object (see AuditLogPage).
+func requestedFieldsOf(asts []*ast.Field, under string) map[string]bool {
+	fields := map[string]bool{}
+	for _, f := range asts {
+		if f.SelectionSet == nil {
+			continue
+		}
+		for _, sel := range f.SelectionSet.Selections {
+			if child, ok := sel.(*ast.Field); ok && child.Name.Value == under {
+				collectSelectedFields(child.SelectionSet, fields)
+			}
+		}
+	}
+	return fields
+}
+
+func collectSelectedFields(set *ast.SelectionSet, into map[string]bool) {
+	if set == nil {
+		return
+	}
+	for _, sel := range set.Selections {
+		if field, ok := sel.(*ast.Field); ok {
+			into[field.Name.Value] = true
+			continue
+		}
+		// A fragment's selections aren't expanded, so treat the fields
+		// callers may trim as requested rather than risk dropping them.
+		into["data"] = true
+		into["meta"] = true
+	}
+}
@@ -0,0 +1,55 @@
+package grpcapi
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/auth"
+)
+
+func TestAuthUnaryInterceptor_AttachesResolvedPrincipal(t *testing.T) {
+	resolver := auth.APIKeyResolver{Keys: map[string]auth.Principal{
+		"key-a": {ID: "svc-a", AllowedTenants: []string{"tenant-a"}},
+	}}
+	interceptor := AuthUnaryInterceptor(resolver)
+
+	md := metadata.New(map[string]string{"x-api-key": "key-a"})
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+
+	var gotCtx context.Context
+	handler := func(ctx context.Context, req any) (any, error) {
+		gotCtx = ctx
+		return nil, nil
+	}
+
+	if _, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ac, ok := auth.FromContext(gotCtx)
+	if !ok || ac.Principal.ID != "svc-a" {
+		t.Errorf("got auth context %+v, ok=%v, want principal svc-a", ac, ok)
+	}
+}
+
+func TestAuthUnaryInterceptor_PassesThroughUnauthenticated(t *testing.T) {
+	resolver := auth.APIKeyResolver{Keys: map[string]auth.Principal{"key-a": {ID: "svc-a"}}}
+	interceptor := AuthUnaryInterceptor(resolver)
+
+	var gotCtx context.Context
+	handler := func(ctx context.Context, req any) (any, error) {
+		gotCtx = ctx
+		return nil, nil
+	}
+
+	if _, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, handler); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := auth.FromContext(gotCtx); ok {
+		t.Error("expected no auth context for a request with no credential")
+	}
+}
@@ -0,0 +1,205 @@
+// Package grpcapi exposes the audit-log service over gRPC (see
+// proto/audit.proto), alongside the REST API in internal/httpapi. Server
+// is a thin transport adapter: every RPC delegates straight into the same
+// Service the REST handlers call, so validation and enrichment
+// (redaction, PII flagging, business hours, quota, ...) stay identical
+// regardless of which transport a caller uses. Tenant policy enforcement
+// (auth.TenantAllowed) is checked in each RPC method, fed by the identity
+// AuthUnaryInterceptor resolves from the request — the gRPC analogue of
+// the REST API's authMiddleware.
+package grpcapi
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/auth"
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+	"github.com/naveenkumar2412/audit-log-service/internal/grpcapi/auditpb"
+)
+
+// Service is the subset of httpapi.Service that the gRPC API needs.
+// Kept local (rather than importing httpapi.Service) so this package
+// doesn't depend on the REST transport package for an interface it only
+// uses four methods of.
+type Service interface {
+	CreateAuditLog(ctx context.Context, log domain.AuditLog) (domain.AuditLog, error)
+	GetAuditLog(ctx context.Context, tenantID, id string, includeDeleted bool) (domain.AuditLog, error)
+	ListAuditLogs(ctx context.Context, filter domain.Filter) (domain.Page, error)
+	AppendMeta(ctx context.Context, tenantID, id string, patch map[string]any) (domain.AuditLog, error)
+}
+
+// Server implements auditpb.AuditServiceServer.
+type Server struct {
+	auditpb.UnimplementedAuditServiceServer
+
+	svc Service
+}
+
+// NewServer returns a Server backed by svc.
+func NewServer(svc Service) *Server {
+	return &Server{svc: svc}
+}
+
+func (s *Server) Create(ctx context.Context, req *auditpb.CreateRequest) (*auditpb.AuditLog, error) {
+	if req.GetTenantId() == "" || req.GetEvent() == "" {
+		return nil, status.Error(codes.InvalidArgument, "tenant_id and event are required")
+	}
+	if !auth.TenantAllowed(ctx, req.GetTenantId()) {
+		return nil, status.Error(codes.PermissionDenied, domain.ErrTenantNotAllowed.Error())
+	}
+
+	log := domain.AuditLog{
+		TenantID:       req.GetTenantId(),
+		Actor:          req.GetActor(),
+		Event:          req.GetEvent(),
+		Resource:       req.GetResource(),
+		ResourceID:     req.GetResourceId(),
+		Data:           req.GetData().AsMap(),
+		Meta:           req.GetMeta().AsMap(),
+		Tags:           req.GetTags(),
+		IdempotencyKey: req.GetIdempotencyKey(),
+	}
+
+	created, err := s.svc.CreateAuditLog(ctx, log)
+	if err != nil {
+		return nil, createErrorToStatus(err)
+	}
+	return auditLogToProto(created)
+}
+
+func (s *Server) Get(ctx context.Context, req *auditpb.GetRequest) (*auditpb.AuditLog, error) {
+	if !auth.TenantAllowed(ctx, req.GetTenantId()) {
+		return nil, status.Error(codes.PermissionDenied, domain.ErrTenantNotAllowed.Error())
+	}
+
+	log, err := s.svc.GetAuditLog(ctx, req.GetTenantId(), req.GetId(), req.GetIncludeDeleted())
+	if err != nil {
+		return nil, getErrorToStatus(err)
+	}
+	return auditLogToProto(log)
+}
+
+func (s *Server) List(ctx context.Context, req *auditpb.ListRequest) (*auditpb.ListResponse, error) {
+	if !auth.TenantAllowed(ctx, req.GetTenantId()) {
+		return nil, status.Error(codes.PermissionDenied, domain.ErrTenantNotAllowed.Error())
+	}
+
+	filter := domain.Filter{
+		TenantID:   req.GetTenantId(),
+		Actor:      req.GetActor(),
+		Event:      req.GetEvent(),
+		Resource:   req.GetResource(),
+		ResourceID: req.GetResourceId(),
+		Limit:      int(req.GetLimit()),
+		Offset:     int(req.GetOffset()),
+	}
+	if raw := req.GetCursor(); raw != "" {
+		cursor, err := domain.DecodeCursor(raw)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "cursor is invalid")
+		}
+		filter.Cursor = &cursor
+	}
+
+	page, err := s.svc.ListAuditLogs(ctx, filter)
+	if err != nil {
+		return nil, listErrorToStatus(err)
+	}
+
+	logs := make([]*auditpb.AuditLog, len(page.Logs))
+	for i, log := range page.Logs {
+		pb, err := auditLogToProto(log)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "convert audit log: %v", err)
+		}
+		logs[i] = pb
+	}
+	return &auditpb.ListResponse{Logs: logs, NextCursor: page.NextCursor}, nil
+}
+
+// UpdateStatus has no dedicated concept to update: domain.AuditLog has no
+// mutable "status" field. It is implemented as a thin wrapper over
+// AppendMeta that patches meta["status"], the same path a REST caller
+// would use for the same purpose.
+func (s *Server) UpdateStatus(ctx context.Context, req *auditpb.UpdateStatusRequest) (*auditpb.AuditLog, error) {
+	if !auth.TenantAllowed(ctx, req.GetTenantId()) {
+		return nil, status.Error(codes.PermissionDenied, domain.ErrTenantNotAllowed.Error())
+	}
+
+	log, err := s.svc.AppendMeta(ctx, req.GetTenantId(), req.GetId(), map[string]any{"status": req.GetStatus()})
+	if err != nil {
+		return nil, getErrorToStatus(err)
+	}
+	return auditLogToProto(log)
+}
+
+func createErrorToStatus(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrEventNotAllowed):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, domain.ErrInvalidArgument):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrQuotaExceeded):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, domain.ErrTenantNotAllowed):
+		return status.Error(codes.PermissionDenied, err.Error())
+	default:
+		return status.Error(codes.Internal, "failed to create audit log")
+	}
+}
+
+func getErrorToStatus(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return status.Error(codes.NotFound, "audit log not found")
+	case errors.Is(err, domain.ErrTenantNotAllowed):
+		return status.Error(codes.PermissionDenied, err.Error())
+	default:
+		return status.Error(codes.Internal, "failed to get audit log")
+	}
+}
+
+func listErrorToStatus(err error) error {
+	if errors.Is(err, domain.ErrInvalidArgument) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return status.Error(codes.Internal, "failed to list audit logs")
+}
+
+// auditLogToProto converts a domain.AuditLog to its proto representation.
+// The only error it can return comes from Data/Meta containing a value
+// structpb.NewStruct can't represent (e.g. a non-JSON-compatible type),
+// which shouldn't happen for data that round-tripped through the store.
+func auditLogToProto(log domain.AuditLog) (*auditpb.AuditLog, error) {
+	data, err := structpb.NewStruct(log.Data)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := structpb.NewStruct(log.Meta)
+	if err != nil {
+		return nil, err
+	}
+	return &auditpb.AuditLog{
+		Id:         log.ID.String(),
+		TenantId:   log.TenantID,
+		Actor:      log.Actor,
+		Event:      log.Event,
+		Resource:   log.Resource,
+		ResourceId: log.ResourceID,
+		Data:       data,
+		Meta:       meta,
+		Tags:       log.Tags,
+		Sequence:   log.Sequence,
+		AuthType:   log.AuthType,
+		ClientIp:   log.ClientIP,
+		CreatedAt:  timestamppb.New(log.CreatedAt),
+		Hash:       log.Hash,
+		PrevHash:   log.PrevHash,
+	}, nil
+}
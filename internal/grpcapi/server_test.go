@@ -0,0 +1,202 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/auth"
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+	"github.com/naveenkumar2412/audit-log-service/internal/grpcapi/auditpb"
+)
+
+// restrictedContext returns a context carrying an authenticated principal
+// restricted to allowedTenant, as AuthUnaryInterceptor would attach for an
+// API key or JWT scoped to one tenant.
+func restrictedContext(allowedTenant string) context.Context {
+	return auth.WithContext(context.Background(), auth.Context{Principal: auth.Principal{AllowedTenants: []string{allowedTenant}}})
+}
+
+type fakeService struct {
+	Service
+
+	createLog domain.AuditLog
+	createErr error
+
+	getLog domain.AuditLog
+	getErr error
+
+	page    domain.Page
+	listErr error
+
+	appendLog domain.AuditLog
+	appendErr error
+}
+
+func (f *fakeService) CreateAuditLog(ctx context.Context, log domain.AuditLog) (domain.AuditLog, error) {
+	return f.createLog, f.createErr
+}
+
+func (f *fakeService) GetAuditLog(ctx context.Context, tenantID, id string, includeDeleted bool) (domain.AuditLog, error) {
+	return f.getLog, f.getErr
+}
+
+func (f *fakeService) ListAuditLogs(ctx context.Context, filter domain.Filter) (domain.Page, error) {
+	return f.page, f.listErr
+}
+
+func (f *fakeService) AppendMeta(ctx context.Context, tenantID, id string, patch map[string]any) (domain.AuditLog, error) {
+	f.appendLog.Meta = patch
+	return f.appendLog, f.appendErr
+}
+
+func statusCode(t *testing.T, err error) codes.Code {
+	t.Helper()
+	st, ok := status.FromError(err)
+	if !ok {
+		t.Fatalf("expected a gRPC status error, got %v", err)
+	}
+	return st.Code()
+}
+
+func TestCreate_RejectsMissingTenantIDOrEvent(t *testing.T) {
+	s := NewServer(&fakeService{})
+
+	_, err := s.Create(context.Background(), &auditpb.CreateRequest{Event: "login"})
+	if statusCode(t, err) != codes.InvalidArgument {
+		t.Errorf("got %v, want InvalidArgument", err)
+	}
+
+	_, err = s.Create(context.Background(), &auditpb.CreateRequest{TenantId: "tenant-a"})
+	if statusCode(t, err) != codes.InvalidArgument {
+		t.Errorf("got %v, want InvalidArgument", err)
+	}
+}
+
+func TestCreate_ReturnsCreatedLogOnSuccess(t *testing.T) {
+	id := uuid.New()
+	data, _ := structpb.NewStruct(map[string]any{"k": "v"})
+	s := NewServer(&fakeService{createLog: domain.AuditLog{ID: id, TenantID: "tenant-a", Event: "login", Data: data.AsMap()}})
+
+	resp, err := s.Create(context.Background(), &auditpb.CreateRequest{TenantId: "tenant-a", Event: "login"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.GetId() != id.String() || resp.GetEvent() != "login" {
+		t.Errorf("got %+v", resp)
+	}
+}
+
+func TestCreate_MapsDomainErrorsToStatusCodes(t *testing.T) {
+	cases := []struct {
+		err  error
+		want codes.Code
+	}{
+		{domain.ErrEventNotAllowed, codes.PermissionDenied},
+		{domain.ErrInvalidArgument, codes.InvalidArgument},
+		{domain.ErrQuotaExceeded, codes.ResourceExhausted},
+		{domain.ErrTenantNotAllowed, codes.PermissionDenied},
+		{errors.New("boom"), codes.Internal},
+	}
+	for _, tc := range cases {
+		s := NewServer(&fakeService{createErr: tc.err})
+		_, err := s.Create(context.Background(), &auditpb.CreateRequest{TenantId: "tenant-a", Event: "login"})
+		if got := statusCode(t, err); got != tc.want {
+			t.Errorf("err=%v: got %v, want %v", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestGet_ReturnsNotFoundForErrNotFound(t *testing.T) {
+	s := NewServer(&fakeService{getErr: domain.ErrNotFound})
+
+	_, err := s.Get(context.Background(), &auditpb.GetRequest{TenantId: "tenant-a", Id: uuid.New().String()})
+	if statusCode(t, err) != codes.NotFound {
+		t.Errorf("got %v, want NotFound", err)
+	}
+}
+
+func TestList_ReturnsLogsAndNextCursor(t *testing.T) {
+	id := uuid.New()
+	s := NewServer(&fakeService{page: domain.Page{Logs: []domain.AuditLog{{ID: id, TenantID: "tenant-a"}}, NextCursor: "abc"}})
+
+	resp, err := s.List(context.Background(), &auditpb.ListRequest{TenantId: "tenant-a"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.GetLogs()) != 1 || resp.GetLogs()[0].GetId() != id.String() || resp.GetNextCursor() != "abc" {
+		t.Errorf("got %+v", resp)
+	}
+}
+
+func TestList_RejectsInvalidCursor(t *testing.T) {
+	s := NewServer(&fakeService{})
+
+	_, err := s.List(context.Background(), &auditpb.ListRequest{TenantId: "tenant-a", Cursor: "not-valid-base64!!"})
+	if statusCode(t, err) != codes.InvalidArgument {
+		t.Errorf("got %v, want InvalidArgument", err)
+	}
+}
+
+func TestUpdateStatus_PatchesStatusViaAppendMeta(t *testing.T) {
+	id := uuid.New()
+	s := NewServer(&fakeService{appendLog: domain.AuditLog{ID: id, TenantID: "tenant-a"}})
+
+	resp, err := s.UpdateStatus(context.Background(), &auditpb.UpdateStatusRequest{TenantId: "tenant-a", Id: id.String(), Status: "reviewed"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.GetMeta().AsMap()["status"] != "reviewed" {
+		t.Errorf("got meta %+v, want status=reviewed", resp.GetMeta().AsMap())
+	}
+}
+
+func TestUpdateStatus_ReturnsNotFoundForErrNotFound(t *testing.T) {
+	s := NewServer(&fakeService{appendErr: domain.ErrNotFound})
+
+	_, err := s.UpdateStatus(context.Background(), &auditpb.UpdateStatusRequest{TenantId: "tenant-a", Id: uuid.New().String(), Status: "reviewed"})
+	if statusCode(t, err) != codes.NotFound {
+		t.Errorf("got %v, want NotFound", err)
+	}
+}
+
+func TestCreate_RejectsTenantOutsidePrincipalsAllowedTenants(t *testing.T) {
+	s := NewServer(&fakeService{})
+
+	_, err := s.Create(restrictedContext("tenant-a"), &auditpb.CreateRequest{TenantId: "tenant-b", Event: "login"})
+	if statusCode(t, err) != codes.PermissionDenied {
+		t.Errorf("got %v, want PermissionDenied", err)
+	}
+}
+
+func TestGet_RejectsTenantOutsidePrincipalsAllowedTenants(t *testing.T) {
+	s := NewServer(&fakeService{})
+
+	_, err := s.Get(restrictedContext("tenant-a"), &auditpb.GetRequest{TenantId: "tenant-b", Id: uuid.New().String()})
+	if statusCode(t, err) != codes.PermissionDenied {
+		t.Errorf("got %v, want PermissionDenied", err)
+	}
+}
+
+func TestList_RejectsTenantOutsidePrincipalsAllowedTenants(t *testing.T) {
+	s := NewServer(&fakeService{})
+
+	_, err := s.List(restrictedContext("tenant-a"), &auditpb.ListRequest{TenantId: "tenant-b"})
+	if statusCode(t, err) != codes.PermissionDenied {
+		t.Errorf("got %v, want PermissionDenied", err)
+	}
+}
+
+func TestUpdateStatus_RejectsTenantOutsidePrincipalsAllowedTenants(t *testing.T) {
+	s := NewServer(&fakeService{})
+
+	_, err := s.UpdateStatus(restrictedContext("tenant-a"), &auditpb.UpdateStatusRequest{TenantId: "tenant-b", Id: uuid.New().String(), Status: "reviewed"})
+	if statusCode(t, err) != codes.PermissionDenied {
+		t.Errorf("got %v, want PermissionDenied", err)
+	}
+}
@@ -0,0 +1,50 @@
+package health
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// QueueDepther reports how many writes are currently buffered (e.g.
+// service.AsyncWriteService.QueueDepth).
+type QueueDepther interface {
+	QueueDepth() int
+}
+
+// AsyncQueueChecker reports the async-write buffer as degraded once it
+// reaches a configured threshold, so a load balancer or alert can
+// react to a backlog building up (e.g. Postgres being down) instead of
+// only finding out once writes start failing outright.
+type AsyncQueueChecker struct {
+	q         QueueDepther
+	threshold int
+}
+
+// NewAsyncQueueChecker returns a checker that reports degraded once q's
+// depth reaches threshold. A threshold of 0 disables the signal (always
+// healthy), matching the checker being optional.
+func NewAsyncQueueChecker(q QueueDepther, threshold int) *AsyncQueueChecker {
+	return &AsyncQueueChecker{q: q, threshold: threshold}
+}
+
+// Healthy reports whether the buffer is currently within its threshold.
+func (c *AsyncQueueChecker) Healthy() bool {
+	if c.threshold <= 0 {
+		return true
+	}
+	return c.q.QueueDepth() < c.threshold
+}
+
+// Handler responds 200 when healthy and 503 with the current depth
+// otherwise, suitable for a degradation probe separate from liveness.
+func (c *AsyncQueueChecker) Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		depth := c.q.QueueDepth()
+		if !c.Healthy() {
+			http.Error(w, fmt.Sprintf("async write queue degraded: depth=%d", depth), http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "depth=%d", depth)
+	}
+}
@@ -0,0 +1,49 @@
+package health
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type fakeQueueDepther struct {
+	depth int
+}
+
+func (f *fakeQueueDepther) QueueDepth() int {
+	return f.depth
+}
+
+func TestAsyncQueueChecker_ReportsDegradedAtThreshold(t *testing.T) {
+	q := &fakeQueueDepther{depth: 5}
+	checker := NewAsyncQueueChecker(q, 10)
+
+	if !checker.Healthy() {
+		t.Fatal("expected checker to be healthy below threshold")
+	}
+
+	q.depth = 10
+	if checker.Healthy() {
+		t.Error("expected checker to report degraded at threshold")
+	}
+}
+
+func TestAsyncQueueChecker_ZeroThresholdAlwaysHealthy(t *testing.T) {
+	checker := NewAsyncQueueChecker(&fakeQueueDepther{depth: 999999}, 0)
+
+	if !checker.Healthy() {
+		t.Error("expected checker with zero threshold to always be healthy")
+	}
+}
+
+func TestAsyncQueueChecker_HandlerReflectsHealth(t *testing.T) {
+	q := &fakeQueueDepther{depth: 50}
+	checker := NewAsyncQueueChecker(q, 10)
+
+	rec := httptest.NewRecorder()
+	checker.Handler()(rec, httptest.NewRequest(http.MethodGet, "/healthz/async", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", rec.Code)
+	}
+}
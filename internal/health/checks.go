@@ -0,0 +1,84 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Checker is the minimal capability CheckHandler needs from a dependency
+// check (satisfied by DBChecker and RedisChecker).
+type Checker interface {
+	Healthy() bool
+}
+
+type namedCheck struct {
+	name     string
+	checker  Checker
+	required bool
+}
+
+// CheckHandler aggregates one or more named dependency checks into a
+// single readiness response, reporting each under its name in the
+// response body's Checks map. A check registered as required fails the
+// whole response (503) when unhealthy; one registered as optional is
+// still reported, but never drops the status below 200, matching a
+// dependency that degrades functionality (e.g. Redis-backed caching)
+// rather than correctness.
+type CheckHandler struct {
+	checks []namedCheck
+}
+
+// NewCheckHandler returns a handler with no checks registered yet; add
+// them with AddCheck.
+func NewCheckHandler() *CheckHandler {
+	return &CheckHandler{}
+}
+
+// AddCheck registers checker under name. checker may be nil, in which
+// case the call is a no-op, so deployments that don't wire up a given
+// dependency (e.g. no Redis configured) don't need to special-case the
+// wiring call site. Note this only catches an untyped nil passed
+// directly (or a nil interface value) — a typed nil pointer assigned to
+// the Checker interface is a non-nil interface and won't be caught, so
+// a caller holding a possibly-nil concrete pointer should check it
+// itself before calling AddCheck.
+func (h *CheckHandler) AddCheck(name string, checker Checker, required bool) *CheckHandler {
+	if checker == nil {
+		return h
+	}
+	h.checks = append(h.checks, namedCheck{name: name, checker: checker, required: required})
+	return h
+}
+
+type checkResponse struct {
+	Checks map[string]string `json:"checks"`
+}
+
+// Handler responds 200 with every check's status once all required
+// checks are healthy, and 503 otherwise. Optional checks are always
+// reported but never cause a 503 on their own.
+func (h *CheckHandler) Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		resp := checkResponse{Checks: make(map[string]string, len(h.checks))}
+		ready := true
+
+		for _, c := range h.checks {
+			if c.checker.Healthy() {
+				resp.Checks[c.name] = "ok"
+				continue
+			}
+			resp.Checks[c.name] = "unhealthy"
+			if c.required {
+				ready = false
+			}
+		}
+
+		status := http.StatusOK
+		if !ready {
+			status = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(resp)
+	}
+}
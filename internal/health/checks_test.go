@@ -0,0 +1,63 @@
+package health
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type fakeChecker struct {
+	healthy bool
+}
+
+func (f *fakeChecker) Healthy() bool {
+	return f.healthy
+}
+
+func TestCheckHandler_OKWhenAllHealthy(t *testing.T) {
+	h := NewCheckHandler().
+		AddCheck("database", &fakeChecker{healthy: true}, true).
+		AddCheck("redis", &fakeChecker{healthy: true}, false)
+
+	rec := httptest.NewRecorder()
+	h.Handler()(rec, httptest.NewRequest(http.MethodGet, "/healthz/checks", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", rec.Code)
+	}
+}
+
+func TestCheckHandler_FailsWhenRequiredCheckUnhealthy(t *testing.T) {
+	h := NewCheckHandler().AddCheck("database", &fakeChecker{healthy: false}, true)
+
+	rec := httptest.NewRecorder()
+	h.Handler()(rec, httptest.NewRequest(http.MethodGet, "/healthz/checks", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", rec.Code)
+	}
+}
+
+func TestCheckHandler_TolerantWhenOptionalCheckUnhealthy(t *testing.T) {
+	h := NewCheckHandler().
+		AddCheck("database", &fakeChecker{healthy: true}, true).
+		AddCheck("redis", &fakeChecker{healthy: false}, false)
+
+	rec := httptest.NewRecorder()
+	h.Handler()(rec, httptest.NewRequest(http.MethodGet, "/healthz/checks", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200 (optional check should not fail readiness)", rec.Code)
+	}
+}
+
+func TestCheckHandler_NilCheckerIsNoOp(t *testing.T) {
+	h := NewCheckHandler().AddCheck("redis", nil, true)
+
+	rec := httptest.NewRecorder()
+	h.Handler()(rec, httptest.NewRequest(http.MethodGet, "/healthz/checks", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200 (nil checker should not be registered)", rec.Code)
+	}
+}
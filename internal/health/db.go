@@ -0,0 +1,69 @@
+package health
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+)
+
+// Pinger is the subset of *pgxpool.Pool the DB checker needs.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// DBChecker periodically pings a database pool and exposes the result as
+// a simple healthy/unhealthy flag, so HTTP middleware can cheaply check
+// it on every request instead of pinging per-request.
+type DBChecker struct {
+	pinger  Pinger
+	timeout time.Duration
+	healthy atomic.Bool
+}
+
+// NewDBChecker returns a checker that starts in the healthy state until
+// its first failed ping, using timeout for each ping attempt.
+func NewDBChecker(pinger Pinger, timeout time.Duration) *DBChecker {
+	c := &DBChecker{pinger: pinger, timeout: timeout}
+	c.healthy.Store(true)
+	return c
+}
+
+// Healthy reports the result of the most recent ping.
+func (c *DBChecker) Healthy() bool {
+	return c.healthy.Load()
+}
+
+// Run pings the database every interval until ctx is canceled, updating
+// Healthy() and logging on every transition.
+func (c *DBChecker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.check(ctx)
+		}
+	}
+}
+
+func (c *DBChecker) check(ctx context.Context) {
+	pingCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	err := c.pinger.Ping(pingCtx)
+	wasHealthy := c.healthy.Load()
+	nowHealthy := err == nil
+	c.healthy.Store(nowHealthy)
+
+	if wasHealthy != nowHealthy {
+		if nowHealthy {
+			log.Printf("health: database recovered")
+		} else {
+			log.Printf("health: database unhealthy: %v", err)
+		}
+	}
+}
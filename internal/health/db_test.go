@@ -0,0 +1,37 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakePinger struct {
+	err error
+}
+
+func (f *fakePinger) Ping(ctx context.Context) error {
+	return f.err
+}
+
+func TestDBChecker_ReflectsPingFailure(t *testing.T) {
+	pinger := &fakePinger{}
+	checker := NewDBChecker(pinger, time.Second)
+
+	if !checker.Healthy() {
+		t.Fatal("expected checker to start healthy")
+	}
+
+	pinger.err = errors.New("connection refused")
+	checker.check(context.Background())
+	if checker.Healthy() {
+		t.Error("expected checker to report unhealthy after failed ping")
+	}
+
+	pinger.err = nil
+	checker.check(context.Background())
+	if !checker.Healthy() {
+		t.Error("expected checker to recover after successful ping")
+	}
+}
@@ -0,0 +1,38 @@
+// Package health exposes a process-wide readiness flag for use by a
+// readiness probe endpoint, separate from liveness.
+package health
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// Readiness is a flippable, concurrency-safe ready flag. The zero value
+// starts not-ready, matching a server that shouldn't take traffic until
+// its startup sequence (e.g. DB pool warmup) completes.
+type Readiness struct {
+	ready atomic.Bool
+}
+
+// SetReady marks the service as ready to serve traffic.
+func (r *Readiness) SetReady() {
+	r.ready.Store(true)
+}
+
+// Ready reports whether the service is ready.
+func (r *Readiness) Ready() bool {
+	return r.ready.Load()
+}
+
+// Handler responds 200 when ready and 503 otherwise, suitable for a
+// Kubernetes readiness probe.
+func (r *Readiness) Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if !r.Ready() {
+			http.Error(w, "not ready", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ready"))
+	}
+}
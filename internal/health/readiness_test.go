@@ -0,0 +1,29 @@
+package health
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestReadiness_NotReadyByDefault(t *testing.T) {
+	r := &Readiness{}
+	rec := httptest.NewRecorder()
+	r.Handler()(rec, httptest.NewRequest(http.MethodGet, "/healthz/ready", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("got status %d, want 503", rec.Code)
+	}
+}
+
+func TestReadiness_ReadyAfterSetReady(t *testing.T) {
+	r := &Readiness{}
+	r.SetReady()
+
+	rec := httptest.NewRecorder()
+	r.Handler()(rec, httptest.NewRequest(http.MethodGet, "/healthz/ready", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("got status %d, want 200", rec.Code)
+	}
+}
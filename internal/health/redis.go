@@ -0,0 +1,77 @@
+package health
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+)
+
+// PingFunc adapts a plain ping call (e.g. (*redis.Client).Ping(ctx).Err())
+// into a Pinger, since go-redis's Ping returns a *redis.StatusCmd rather
+// than satisfying Pinger directly.
+type PingFunc func(ctx context.Context) error
+
+// Ping implements Pinger.
+func (f PingFunc) Ping(ctx context.Context) error {
+	return f(ctx)
+}
+
+// RedisChecker periodically pings a Redis client and exposes the result
+// as a simple healthy/unhealthy flag, mirroring DBChecker. Redis is
+// typically an optional dependency (caching, dedup, throttling), so
+// callers decide for themselves whether RedisChecker's result should
+// gate readiness (see CheckHandler's required flag) rather than this
+// type assuming it's load-bearing.
+type RedisChecker struct {
+	pinger  Pinger
+	timeout time.Duration
+	healthy atomic.Bool
+}
+
+// NewRedisChecker returns a checker that starts in the healthy state
+// until its first failed ping, using timeout for each ping attempt.
+func NewRedisChecker(pinger Pinger, timeout time.Duration) *RedisChecker {
+	c := &RedisChecker{pinger: pinger, timeout: timeout}
+	c.healthy.Store(true)
+	return c
+}
+
+// Healthy reports the result of the most recent ping.
+func (c *RedisChecker) Healthy() bool {
+	return c.healthy.Load()
+}
+
+// Run pings Redis every interval until ctx is canceled, updating
+// Healthy() and logging on every transition.
+func (c *RedisChecker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.check(ctx)
+		}
+	}
+}
+
+func (c *RedisChecker) check(ctx context.Context) {
+	pingCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	err := c.pinger.Ping(pingCtx)
+	wasHealthy := c.healthy.Load()
+	nowHealthy := err == nil
+	c.healthy.Store(nowHealthy)
+
+	if wasHealthy != nowHealthy {
+		if nowHealthy {
+			log.Printf("health: redis recovered")
+		} else {
+			log.Printf("health: redis unhealthy: %v", err)
+		}
+	}
+}
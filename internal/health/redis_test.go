@@ -0,0 +1,29 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRedisChecker_ReflectsPingFailure(t *testing.T) {
+	var pingErr error
+	checker := NewRedisChecker(PingFunc(func(ctx context.Context) error { return pingErr }), time.Second)
+
+	if !checker.Healthy() {
+		t.Fatal("expected checker to start healthy")
+	}
+
+	pingErr = errors.New("connection refused")
+	checker.check(context.Background())
+	if checker.Healthy() {
+		t.Error("expected checker to report unhealthy after failed ping")
+	}
+
+	pingErr = nil
+	checker.check(context.Background())
+	if !checker.Healthy() {
+		t.Error("expected checker to recover after successful ping")
+	}
+}
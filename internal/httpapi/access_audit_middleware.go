@@ -0,0 +1,58 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/auth"
+	"github.com/naveenkumar2412/audit-log-service/internal/config"
+	"github.com/naveenkumar2412/audit-log-service/internal/logging"
+)
+
+// AccessAudit logs a structured access record for every GET (read) or
+// DELETE request once the handler has run, capturing who read or deleted
+// which tenant's data — the audit-log service otherwise has no record of
+// access to itself. It must run after TenantFromHeader, authMiddleware,
+// and RequestLogging so the principal, tenant, and request-scoped logger
+// are already attached to the request context, and after chi has matched
+// a route so RoutePattern is populated.
+//
+// It is a no-op (zero overhead beyond the no-op wrap) unless cfg.Enabled,
+// since every access generates a log line of its own and a deployment
+// already near its logging volume budget needs to opt in deliberately.
+func AccessAudit(cfg config.AccessAuditConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !cfg.Enabled {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodDelete {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+
+			principal := "anonymous"
+			if ac, ok := auth.FromContext(r.Context()); ok {
+				principal = ac.Principal.ID
+			}
+			route := chi.RouteContext(r.Context()).RoutePattern()
+			if route == "" {
+				route = "unmatched"
+			}
+
+			logging.FromContext(r.Context()).Info("access audit",
+				"principal", principal,
+				"method", r.Method,
+				"route", route,
+				"tenant_id", resolveTenantID(r),
+				"status", ww.Status(),
+				"bytes_written", ww.BytesWritten(),
+			)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package httpapi
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/config"
+)
+
+func TestAccessAudit_LogsReadsAndDeletes(t *testing.T) {
+	var buf bytes.Buffer
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+
+	r := chi.NewRouter()
+	r.Use(AccessAudit(config.AccessAuditConfig{Enabled: true}))
+	r.Get("/api/v1/audit/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	r.Delete("/api/v1/audit/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/audit/log-1", nil))
+	if !strings.Contains(buf.String(), "access audit") || !strings.Contains(buf.String(), "route=/api/v1/audit/{id}") {
+		t.Errorf("expected a GET access audit log line, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/v1/audit/log-1", nil))
+	if !strings.Contains(buf.String(), "access audit") || !strings.Contains(buf.String(), "method=DELETE") {
+		t.Errorf("expected a DELETE access audit log line, got: %s", buf.String())
+	}
+}
+
+func TestAccessAudit_SkipsNonReadNonDeleteMethods(t *testing.T) {
+	var buf bytes.Buffer
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+
+	r := chi.NewRouter()
+	r.Use(AccessAudit(config.AccessAuditConfig{Enabled: true}))
+	r.Post("/api/v1/audit", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/audit", nil))
+
+	if strings.Contains(buf.String(), "access audit") {
+		t.Errorf("expected no access audit log line for POST, got: %s", buf.String())
+	}
+}
+
+func TestAccessAudit_Disabled(t *testing.T) {
+	var buf bytes.Buffer
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+
+	r := chi.NewRouter()
+	r.Use(AccessAudit(config.AccessAuditConfig{Enabled: false}))
+	r.Get("/api/v1/audit/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/audit/log-1", nil))
+
+	if strings.Contains(buf.String(), "access audit") {
+		t.Errorf("expected no access audit log line when disabled, got: %s", buf.String())
+	}
+}
@@ -0,0 +1,90 @@
+package httpapi
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/config"
+)
+
+// levelFromName maps AdaptiveLogConfig.DowngradeLevel's allowed values to
+// their slog.Level, mirroring config.logDowngradeLevelAllowlist.
+var levelFromName = map[string]slog.Level{
+	"warn":  slog.LevelWarn,
+	"error": slog.LevelError,
+}
+
+// AdaptiveLevel tracks request throughput and raises a shared slog level
+// once it exceeds cfg.RequestsPerSecondThreshold, reverting to info once
+// the rate subsides, so per-request info logging (see RequestLogging)
+// can't itself become a bottleneck during a traffic spike. Error logs
+// use their own level and are never affected by the downgrade.
+type AdaptiveLevel struct {
+	cfg   atomic.Pointer[config.AdaptiveLogConfig]
+	level *slog.LevelVar
+	count atomic.Int64
+}
+
+// NewAdaptiveLevel returns an AdaptiveLevel starting at slog.LevelInfo.
+// Run must be started for it to ever downgrade; with cfg.Enabled false
+// it stays at info forever until SetConfig enables it.
+func NewAdaptiveLevel(cfg config.AdaptiveLogConfig) *AdaptiveLevel {
+	a := &AdaptiveLevel{level: &slog.LevelVar{}}
+	a.cfg.Store(&cfg)
+	return a
+}
+
+// SetConfig atomically swaps in a new AdaptiveLogConfig, for a live
+// config reload (see cmd/server's SIGHUP handler). Takes effect on
+// Run's next tick; if the new config disables adaptive logging, the
+// level is immediately reset to info rather than left downgraded.
+func (a *AdaptiveLevel) SetConfig(cfg config.AdaptiveLogConfig) {
+	a.cfg.Store(&cfg)
+	if !cfg.Enabled {
+		a.level.Set(slog.LevelInfo)
+	}
+}
+
+// Level returns the current effective level as a slog.Leveler, suitable
+// for slog.HandlerOptions.Level.
+func (a *AdaptiveLevel) Level() slog.Leveler {
+	return a.level
+}
+
+// recordRequest tallies one request toward the current window's rate.
+func (a *AdaptiveLevel) recordRequest() {
+	a.count.Add(1)
+}
+
+// Run recomputes the request rate every tick and adjusts the effective
+// level until ctx is done, re-reading cfg (see SetConfig) on every tick
+// so a live reload that enables or retunes adaptive logging takes effect
+// without restarting Run.
+func (a *AdaptiveLevel) Run(ctx context.Context, tick time.Duration) {
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cfg := a.cfg.Load()
+			n := a.count.Swap(0)
+			if !cfg.Enabled {
+				continue
+			}
+			rate := float64(n) / tick.Seconds()
+
+			next := slog.LevelInfo
+			if rate > cfg.RequestsPerSecondThreshold {
+				next = levelFromName[cfg.DowngradeLevel]
+			}
+			if next != a.level.Level() {
+				slog.Info("adaptive log level changed", "rate_rps", rate, "threshold_rps", cfg.RequestsPerSecondThreshold, "level", next.String())
+				a.level.Set(next)
+			}
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package httpapi
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/config"
+)
+
+func TestAdaptiveLevel_DowngradesWhenRateExceedsThreshold(t *testing.T) {
+	adaptive := NewAdaptiveLevel(config.AdaptiveLogConfig{
+		Enabled:                    true,
+		RequestsPerSecondThreshold: 1,
+		DowngradeLevel:             "warn",
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go adaptive.Run(ctx, 10*time.Millisecond)
+
+	for i := 0; i < 50; i++ {
+		adaptive.recordRequest()
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if adaptive.Level().Level() == slog.LevelWarn {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("expected level to downgrade to warn, stayed at %s", adaptive.Level().Level())
+}
+
+func TestAdaptiveLevel_DisabledNeverDowngrades(t *testing.T) {
+	adaptive := NewAdaptiveLevel(config.AdaptiveLogConfig{Enabled: false})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go adaptive.Run(ctx, 10*time.Millisecond)
+
+	for i := 0; i < 50; i++ {
+		adaptive.recordRequest()
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	if adaptive.Level().Level() != slog.LevelInfo {
+		t.Errorf("expected level to stay at info when disabled, got %s", adaptive.Level().Level())
+	}
+}
+
+func TestAdaptiveLevel_SetConfigEnablesDowngradeLive(t *testing.T) {
+	adaptive := NewAdaptiveLevel(config.AdaptiveLogConfig{Enabled: false})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go adaptive.Run(ctx, 10*time.Millisecond)
+
+	adaptive.SetConfig(config.AdaptiveLogConfig{
+		Enabled:                    true,
+		RequestsPerSecondThreshold: 1,
+		DowngradeLevel:             "error",
+	})
+
+	for i := 0; i < 50; i++ {
+		adaptive.recordRequest()
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if adaptive.Level().Level() == slog.LevelError {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("expected level to downgrade to error after SetConfig, stayed at %s", adaptive.Level().Level())
+}
+
+func TestAdaptiveLevel_SetConfigDisablingResetsToInfo(t *testing.T) {
+	adaptive := NewAdaptiveLevel(config.AdaptiveLogConfig{
+		Enabled:                    true,
+		RequestsPerSecondThreshold: 1,
+		DowngradeLevel:             "warn",
+	})
+	adaptive.level.Set(slog.LevelWarn)
+
+	adaptive.SetConfig(config.AdaptiveLogConfig{Enabled: false})
+
+	if got := adaptive.Level().Level(); got != slog.LevelInfo {
+		t.Errorf("expected level reset to info when disabled via SetConfig, got %s", got)
+	}
+}
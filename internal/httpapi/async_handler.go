@@ -0,0 +1,104 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+)
+
+// AsyncWriteService is the business-logic interface the async-write
+// handler depends on.
+type AsyncWriteService interface {
+	Enqueue(ctx context.Context, log domain.AuditLog) (string, error)
+	GetStatus(trackingID string) (domain.WriteTracking, error)
+}
+
+// AsyncHandler exposes the optional async-write ingestion API. Unlike
+// Handler.createAuditLog, these endpoints return before the record is
+// durably persisted — see service.AsyncWriteService's doc comment for the
+// durability tradeoff.
+type AsyncHandler struct {
+	svc AsyncWriteService
+}
+
+// NewAsyncHandler returns an AsyncHandler backed by the given service.
+func NewAsyncHandler(svc AsyncWriteService) *AsyncHandler {
+	return &AsyncHandler{svc: svc}
+}
+
+// Routes registers the async-write endpoints on r.
+func (h *AsyncHandler) Routes(r chi.Router) {
+	r.Post("/api/v1/audit/async", h.createAuditLogAsync)
+	r.Get("/api/v1/audit/async/{tracking_id}", h.getStatus)
+}
+
+// @Summary Submit an audit log for async write
+// @Tags async
+// @Accept json
+// @Produce json
+// @Param request body createAuditLogRequest true "Audit log to create"
+// @Success 202 {object} domain.WriteTracking
+// @Failure 400 {object} errorResponse
+// @Router /audit/async [post]
+func (h *AsyncHandler) createAuditLogAsync(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeCreateAuditLogRequest(r)
+	if err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	if req.TenantID == "" || req.Event == "" {
+		writeError(w, http.StatusBadRequest, "tenant_id and event are required")
+		return
+	}
+	if err := reqValidator.Struct(req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	trackingID, err := h.svc.Enqueue(r.Context(), domain.AuditLog{
+		TenantID:   req.TenantID,
+		Actor:      req.Actor,
+		AuthType:   authTypeFromContext(r.Context()),
+		Event:      req.Event,
+		Resource:   req.Resource,
+		ResourceID: req.ResourceID,
+		Data:       req.Data,
+		Meta:       req.Meta,
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to enqueue write")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(domain.WriteTracking{TrackingID: trackingID, Status: domain.WriteStatusPending})
+}
+
+// @Summary Get async write status
+// @Tags async
+// @Produce json
+// @Param tracking_id path string true "Tracking ID returned by the async create"
+// @Success 200 {object} domain.WriteTracking
+// @Failure 404 {object} errorResponse
+// @Router /audit/async/{tracking_id} [get]
+func (h *AsyncHandler) getStatus(w http.ResponseWriter, r *http.Request) {
+	trackingID := chi.URLParam(r, "tracking_id")
+
+	status, err := h.svc.GetStatus(trackingID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to get write status")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, status)
+}
@@ -0,0 +1,134 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+)
+
+// BatchIngestService is the business-logic interface the batch handler
+// depends on.
+type BatchIngestService interface {
+	OpenSession(tenantID string) (string, error)
+	AppendChunk(token string, logs []domain.AuditLog) (int, error)
+	Commit(ctx context.Context, token string) ([]domain.AuditLog, error)
+}
+
+// BatchHandler exposes the chunked/resumable batch ingestion HTTP API.
+type BatchHandler struct {
+	svc BatchIngestService
+}
+
+// NewBatchHandler returns a BatchHandler backed by the given service.
+func NewBatchHandler(svc BatchIngestService) *BatchHandler {
+	return &BatchHandler{svc: svc}
+}
+
+// Routes registers the batch session endpoints on r.
+func (h *BatchHandler) Routes(r chi.Router) {
+	r.Post("/api/v1/audit/batch/sessions", h.openSession)
+	r.Post("/api/v1/audit/batch/sessions/{token}/chunks", h.appendChunk)
+	r.Post("/api/v1/audit/batch/sessions/{token}/commit", h.commit)
+}
+
+type openSessionRequest struct {
+	TenantID string `json:"tenant_id"`
+}
+
+type openSessionResponse struct {
+	Token string `json:"token"`
+}
+
+func (h *BatchHandler) openSession(w http.ResponseWriter, r *http.Request) {
+	var req openSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	token, err := h.svc.OpenSession(req.TenantID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, openSessionResponse{Token: token})
+}
+
+type appendChunkRequest struct {
+	Logs []createAuditLogRequest `json:"logs"`
+}
+
+type appendChunkResponse struct {
+	TotalBuffered int `json:"total_buffered"`
+}
+
+func (h *BatchHandler) appendChunk(w http.ResponseWriter, r *http.Request) {
+	token := chi.URLParam(r, "token")
+
+	var req appendChunkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	authType := authTypeFromContext(r.Context())
+	logs := make([]domain.AuditLog, len(req.Logs))
+	for i, l := range req.Logs {
+		logs[i] = domain.AuditLog{
+			Actor:      l.Actor,
+			AuthType:   authType,
+			Event:      l.Event,
+			Resource:   l.Resource,
+			ResourceID: l.ResourceID,
+			Data:       l.Data,
+			Meta:       l.Meta,
+		}
+	}
+
+	total, err := h.svc.AppendChunk(token, logs)
+	if err != nil {
+		writeBatchSessionError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, appendChunkResponse{TotalBuffered: total})
+}
+
+type commitResponse struct {
+	Created   int      `json:"created"`
+	Locations []string `json:"locations"`
+}
+
+func (h *BatchHandler) commit(w http.ResponseWriter, r *http.Request) {
+	token := chi.URLParam(r, "token")
+
+	created, err := h.svc.Commit(r.Context(), token)
+	if err != nil {
+		writeBatchSessionError(w, err)
+		return
+	}
+
+	locations := make([]string, len(created))
+	for i, log := range created {
+		locations[i] = "/api/v1/audit/" + log.ID.String()
+	}
+
+	writeJSON(w, http.StatusOK, commitResponse{Created: len(created), Locations: locations})
+}
+
+func writeBatchSessionError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrInvalidArgument):
+		writeError(w, http.StatusBadRequest, err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, "failed to process batch session")
+	}
+}
@@ -0,0 +1,36 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/config"
+)
+
+// MaxBodyBytes wraps every request body in an http.MaxBytesReader capped
+// at cfg.MaxBodyBytes, so an oversized payload (e.g. a runaway "data"
+// field) is rejected with 413 before a handler ever reads it, instead of
+// a handler decoding an arbitrarily large body into memory. <= 0 disables
+// the limit.
+func MaxBodyBytes(cfg config.RequestLimitsConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if cfg.MaxBodyBytes <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.Body = http.MaxBytesReader(w, r.Body, cfg.MaxBodyBytes)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// isMaxBytesError reports whether err came from a body exceeding the
+// limit MaxBodyBytes installed, so a handler can map it to 413 instead of
+// the 400 it would otherwise give a malformed-JSON error. Go 1.19+'s
+// http.MaxBytesReader returns a *http.MaxBytesError that satisfies this
+// with errors.As; json.Decoder.Decode wraps it, so errors.As still finds
+// it through the wrapping.
+func isMaxBytesError(err error) bool {
+	var maxErr *http.MaxBytesError
+	return errors.As(err, &maxErr)
+}
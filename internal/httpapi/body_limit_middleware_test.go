@@ -0,0 +1,66 @@
+package httpapi
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/config"
+)
+
+func TestMaxBodyBytes_RejectsOversizedBody(t *testing.T) {
+	cfg := config.RequestLimitsConfig{MaxBodyBytes: 5}
+
+	var readErr error
+	handler := MaxBodyBytes(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, readErr = io.ReadAll(r.Body)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/audit", strings.NewReader(`{"tenant_id":"t1"}`))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if readErr == nil {
+		t.Fatal("expected reading an oversized body to fail")
+	}
+	if !isMaxBytesError(readErr) {
+		t.Errorf("expected a max-bytes error, got %q", readErr.Error())
+	}
+}
+
+func TestMaxBodyBytes_AllowsBodyWithinLimit(t *testing.T) {
+	cfg := config.RequestLimitsConfig{MaxBodyBytes: 1024}
+
+	var body []byte
+	var readErr error
+	handler := MaxBodyBytes(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, readErr = io.ReadAll(r.Body)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/audit", strings.NewReader(`{"tenant_id":"t1"}`))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if readErr != nil {
+		t.Fatalf("unexpected error: %v", readErr)
+	}
+	if string(body) != `{"tenant_id":"t1"}` {
+		t.Errorf("got body %q", body)
+	}
+}
+
+func TestMaxBodyBytes_DisabledWhenNonPositive(t *testing.T) {
+	cfg := config.RequestLimitsConfig{MaxBodyBytes: 0}
+
+	var readErr error
+	handler := MaxBodyBytes(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, readErr = io.ReadAll(r.Body)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/audit", strings.NewReader(strings.Repeat("x", 10000)))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if readErr != nil {
+		t.Fatalf("expected no limit to be enforced, got error: %v", readErr)
+	}
+}
@@ -0,0 +1,174 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/validator"
+)
+
+// reqValidator enforces createAuditLogRequest's format tags (tenant_id,
+// event_name, resource_name, actor_name), shared across every handler
+// that accepts a createAuditLogRequest since a Validator built by
+// validator.New() is immutable and safe for concurrent use.
+var reqValidator = validator.New()
+
+// apiVersionHeader lets a caller pin the request schema version of a
+// create payload (see apiVersionTranslators). Omitting it defaults to
+// currentAPIVersion, so existing producers are unaffected.
+const apiVersionHeader = "X-Api-Version"
+
+// currentAPIVersion is the payload shape createAuditLogRequest currently
+// decodes into.
+const currentAPIVersion = "1"
+
+// apiVersionTranslators maps a supported X-Api-Version to a function that
+// maps that version's payload shape onto the current createAuditLogRequest
+// model. Today there is only one version, so the translation is the
+// identity function; this is the extension point for adding e.g. "2" once
+// the payload shape actually needs to change, so older producers can keep
+// sending the old shape unmodified against a newer server. A version not
+// present here is rejected with 400 rather than silently guessed at.
+var apiVersionTranslators = map[string]func(createAuditLogRequest) createAuditLogRequest{
+	currentAPIVersion: func(req createAuditLogRequest) createAuditLogRequest { return req },
+}
+
+// decodeCreateAuditLogRequest reads req into a createAuditLogRequest,
+// choosing the decoder by the request's Content-Type (JSON is the
+// default for an empty or unrecognized Content-Type, matching the
+// service's original JSON-only behavior) and then translating it from
+// the request's X-Api-Version into the current model.
+func decodeCreateAuditLogRequest(r *http.Request) (createAuditLogRequest, error) {
+	version := r.Header.Get(apiVersionHeader)
+	if version == "" {
+		version = currentAPIVersion
+	}
+	translate, ok := apiVersionTranslators[version]
+	if !ok {
+		return createAuditLogRequest{}, fmt.Errorf("unsupported %s %q", apiVersionHeader, version)
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = contentType
+	}
+
+	var req createAuditLogRequest
+	switch mediaType {
+	case "", "application/json":
+		dec := json.NewDecoder(r.Body)
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&req); err != nil {
+			return createAuditLogRequest{}, describeJSONError(err)
+		}
+	case "application/msgpack", "application/x-msgpack":
+		if err := msgpack.NewDecoder(r.Body).Decode(&req); err != nil {
+			return createAuditLogRequest{}, fmt.Errorf("invalid msgpack body: %w", err)
+		}
+	case "application/x-www-form-urlencoded":
+		if err := r.ParseForm(); err != nil {
+			return createAuditLogRequest{}, fmt.Errorf("invalid form body: %w", err)
+		}
+		req, err = createAuditLogRequestFromForm(r.PostForm)
+		if err != nil {
+			return createAuditLogRequest{}, err
+		}
+	default:
+		return createAuditLogRequest{}, fmt.Errorf("unsupported Content-Type %q", mediaType)
+	}
+
+	return translate(req), nil
+}
+
+// writeDecodeError maps a decodeCreateAuditLogRequest (or equivalent
+// batch-entry) error to the response it should produce: 413 when the
+// body was rejected for exceeding MaxBodyBytes, 400 for every other
+// decode failure (malformed JSON, an unrecognized field, a wrong-typed
+// field).
+func writeDecodeError(w http.ResponseWriter, err error) {
+	if isMaxBytesError(err) {
+		writeError(w, http.StatusRequestEntityTooLarge, err.Error())
+		return
+	}
+	writeError(w, http.StatusBadRequest, err.Error())
+}
+
+// describeJSONError turns a json.Decoder.Decode error into a message that
+// tells a caller what was actually wrong with their body, rather than
+// Go's generic decoder error text: a body over MaxBodyBytes, malformed
+// JSON syntax, a field of the wrong type, or (with DisallowUnknownFields)
+// a field the API doesn't recognize — each needs a different fix on the
+// caller's side, so each gets its own message.
+func describeJSONError(err error) error {
+	if isMaxBytesError(err) {
+		return fmt.Errorf("request body exceeds the maximum allowed size: %w", err)
+	}
+	if errors.Is(err, io.EOF) {
+		return errors.New("request body must not be empty")
+	}
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return fmt.Errorf("malformed JSON body at offset %d: %w", syntaxErr.Offset, err)
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return fmt.Errorf("field %q must be a %s, got %s", typeErr.Field, typeErr.Type, typeErr.Value)
+	}
+	if strings.HasPrefix(err.Error(), "json: unknown field ") {
+		return fmt.Errorf("unrecognized field in request body: %w", err)
+	}
+	return fmt.Errorf("invalid JSON body: %w", err)
+}
+
+func createAuditLogRequestFromForm(form map[string][]string) (createAuditLogRequest, error) {
+	get := func(key string) string {
+		if v := form[key]; len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+
+	req := createAuditLogRequest{
+		TenantID:   get("tenant_id"),
+		Actor:      get("actor"),
+		Event:      get("event"),
+		Resource:   get("resource"),
+		ResourceID: get("resource_id"),
+	}
+
+	// data/meta arrive as JSON-encoded strings within the form, since
+	// form encoding has no native notion of a nested object.
+	if raw := get("data"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &req.Data); err != nil {
+			return createAuditLogRequest{}, fmt.Errorf("data must be a JSON object: %w", err)
+		}
+	}
+	if raw := get("meta"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &req.Meta); err != nil {
+			return createAuditLogRequest{}, fmt.Errorf("meta must be a JSON object: %w", err)
+		}
+	}
+	if raw := get("tags"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &req.Tags); err != nil {
+			return createAuditLogRequest{}, fmt.Errorf("tags must be a JSON array: %w", err)
+		}
+	}
+	if raw := get("timestamp"); raw != "" {
+		ts, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return createAuditLogRequest{}, fmt.Errorf("timestamp must be RFC3339: %w", err)
+		}
+		req.Timestamp = &ts
+	}
+
+	return req, nil
+}
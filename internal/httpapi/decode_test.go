@@ -0,0 +1,146 @@
+package httpapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecodeCreateAuditLogRequest_JSON(t *testing.T) {
+	body := `{"tenant_id":"t1","event":"user.login"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/audit", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	got, err := decodeCreateAuditLogRequest(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.TenantID != "t1" || got.Event != "user.login" {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestDecodeCreateAuditLogRequest_Form(t *testing.T) {
+	body := `tenant_id=t1&event=user.login&data={"ip":"1.2.3.4"}&tags=["high-risk"]`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/audit", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	got, err := decodeCreateAuditLogRequest(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.TenantID != "t1" || got.Event != "user.login" {
+		t.Errorf("got %+v", got)
+	}
+	if got.Data["ip"] != "1.2.3.4" {
+		t.Errorf("expected data.ip to be parsed, got %+v", got.Data)
+	}
+	if len(got.Tags) != 1 || got.Tags[0] != "high-risk" {
+		t.Errorf("expected tags to be parsed, got %+v", got.Tags)
+	}
+}
+
+func TestDecodeCreateAuditLogRequest_FormTimestamp(t *testing.T) {
+	body := `tenant_id=t1&event=user.login&timestamp=2025-01-01T00:00:00Z`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/audit", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	got, err := decodeCreateAuditLogRequest(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Timestamp == nil || got.Timestamp.Format(time.RFC3339) != "2025-01-01T00:00:00Z" {
+		t.Errorf("expected timestamp to be parsed, got %+v", got.Timestamp)
+	}
+}
+
+func TestDecodeCreateAuditLogRequest_FormInvalidTimestamp(t *testing.T) {
+	body := `tenant_id=t1&event=user.login&timestamp=not-a-time`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/audit", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	if _, err := decodeCreateAuditLogRequest(req); err == nil {
+		t.Fatal("expected error for invalid timestamp")
+	}
+}
+
+func TestDecodeCreateAuditLogRequest_ExplicitCurrentVersion(t *testing.T) {
+	body := `{"tenant_id":"t1","event":"user.login"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/audit", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(apiVersionHeader, currentAPIVersion)
+
+	got, err := decodeCreateAuditLogRequest(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.TenantID != "t1" {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestDecodeCreateAuditLogRequest_RejectsUnknownVersion(t *testing.T) {
+	body := `{"tenant_id":"t1","event":"user.login"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/audit", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(apiVersionHeader, "99")
+
+	if _, err := decodeCreateAuditLogRequest(req); err == nil {
+		t.Fatal("expected error for unknown api version")
+	}
+}
+
+func TestDecodeCreateAuditLogRequest_UnsupportedContentType(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/audit", strings.NewReader("whatever"))
+	req.Header.Set("Content-Type", "application/xml")
+
+	if _, err := decodeCreateAuditLogRequest(req); err == nil {
+		t.Fatal("expected error for unsupported content type")
+	}
+}
+
+func TestDecodeCreateAuditLogRequest_RejectsUnknownField(t *testing.T) {
+	body := `{"tenant_id":"t1","event":"user.login","nonexistent_field":"x"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/audit", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	_, err := decodeCreateAuditLogRequest(req)
+	if err == nil {
+		t.Fatal("expected error for unknown field")
+	}
+	if !strings.Contains(err.Error(), "nonexistent_field") {
+		t.Errorf("expected error to name the unrecognized field, got %q", err.Error())
+	}
+}
+
+func TestDecodeCreateAuditLogRequest_MalformedJSONNamesOffset(t *testing.T) {
+	body := `{"tenant_id":"t1","event":}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/audit", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	_, err := decodeCreateAuditLogRequest(req)
+	if err == nil {
+		t.Fatal("expected error for malformed JSON")
+	}
+	if !strings.Contains(err.Error(), "malformed JSON body") {
+		t.Errorf("expected malformed-JSON message, got %q", err.Error())
+	}
+}
+
+func TestDecodeCreateAuditLogRequest_OversizedBodyIsReportedAsMaxBytesError(t *testing.T) {
+	body := `{"tenant_id":"t1","event":"user.login"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/audit", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	req.Body = http.MaxBytesReader(rec, req.Body, 5)
+
+	_, err := decodeCreateAuditLogRequest(req)
+	if err == nil {
+		t.Fatal("expected error for oversized body")
+	}
+	if !isMaxBytesError(err) {
+		t.Errorf("expected a max-bytes error, got %q", err.Error())
+	}
+}
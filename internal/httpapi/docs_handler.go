@@ -0,0 +1,46 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	httpSwagger "github.com/swaggo/http-swagger/v2"
+	"github.com/swaggo/swag"
+
+	_ "github.com/naveenkumar2412/audit-log-service/internal/docs"
+)
+
+// DocsHandler serves the generated OpenAPI spec (see internal/docs,
+// produced by `swag init` from the @Summary/@Router annotations on the
+// handlers in this package) and a Swagger UI to browse it. Gated behind
+// config.DocsConfig since the spec lists every route and request/response
+// shape — useful for client teams, but not something a production
+// deployment should expose by default.
+type DocsHandler struct {
+	enabled bool
+}
+
+// NewDocsHandler returns a DocsHandler that serves its routes only when
+// enabled is true; Routes registers nothing otherwise.
+func NewDocsHandler(enabled bool) *DocsHandler {
+	return &DocsHandler{enabled: enabled}
+}
+
+// Routes registers the docs endpoints on r, if enabled.
+func (h *DocsHandler) Routes(r chi.Router) {
+	if !h.enabled {
+		return
+	}
+	r.Get("/openapi.json", h.openAPIJSON)
+	r.Get("/swagger/*", httpSwagger.Handler(httpSwagger.URL("/openapi.json")))
+}
+
+func (h *DocsHandler) openAPIJSON(w http.ResponseWriter, r *http.Request) {
+	spec, err := swag.ReadDoc()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to read OpenAPI spec")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(spec))
+}
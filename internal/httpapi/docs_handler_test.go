@@ -0,0 +1,41 @@
+package httpapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestDocsHandler_DisabledRegistersNoRoutes(t *testing.T) {
+	r := chi.NewRouter()
+	NewDocsHandler(false).Routes(r)
+
+	req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("got status %d, want 404 when docs are disabled", w.Code)
+	}
+}
+
+func TestDocsHandler_EnabledServesOpenAPISpec(t *testing.T) {
+	r := chi.NewRouter()
+	NewDocsHandler(true).Routes(r)
+
+	req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want 200", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("got Content-Type %q, want application/json", ct)
+	}
+	if w.Body.Len() == 0 {
+		t.Error("expected a non-empty OpenAPI spec body")
+	}
+}
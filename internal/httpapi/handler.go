@@ -0,0 +1,1130 @@
+// Package httpapi wires the audit-log service onto HTTP handlers.
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/auth"
+	"github.com/naveenkumar2412/audit-log-service/internal/config"
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+	"github.com/naveenkumar2412/audit-log-service/internal/ingestmode"
+	"github.com/naveenkumar2412/audit-log-service/internal/service"
+)
+
+// importModeScope is the auth scope that marks a caller as permitted to
+// submit historical/backfilled audit logs, exempting them from the
+// live-ingestion timestamp-skew check (see ingestmode).
+const importModeScope = "audit:import"
+
+// Service is the business-logic interface the handler depends on.
+type Service interface {
+	CreateAuditLog(ctx context.Context, log domain.AuditLog) (domain.AuditLog, error)
+	CreateAuditLogConditional(ctx context.Context, log domain.AuditLog, cond domain.CreateCondition) (domain.AuditLog, bool, error)
+	CreateAuditLogIdempotent(ctx context.Context, log domain.AuditLog) (domain.AuditLog, bool, error)
+	CreateAuditLogsBatch(ctx context.Context, logs []domain.AuditLog, allOrNothing bool) (domain.BatchCreateResult, error)
+	ListAuditLogs(ctx context.Context, filter domain.Filter) (domain.Page, error)
+	GetAuditLog(ctx context.Context, tenantID, id string, includeDeleted bool) (domain.AuditLog, error)
+	DeleteAuditLog(ctx context.Context, tenantID, id string) error
+	HardDeleteAuditLog(ctx context.Context, tenantID, id string) error
+	DeleteAuditLogsByFilter(ctx context.Context, filter domain.Filter) (int64, error)
+	GetAuditLogHead(ctx context.Context, tenantID, id string) (domain.AuditLog, error)
+	GetTimeSeries(ctx context.Context, filter domain.Filter, interval string) ([]domain.TimeBucket, error)
+	PurgeTenant(ctx context.Context, tenantID string) (int64, error)
+	GetFacets(ctx context.Context, filter domain.Filter, field string, topN int) (domain.FacetResult, error)
+	GetLatestPerResource(ctx context.Context, filter domain.Filter) ([]domain.AuditLog, error)
+	AddTags(ctx context.Context, tenantID, id string, tags []string) (domain.AuditLog, error)
+	RemoveTags(ctx context.Context, tenantID, id string, tags []string) (domain.AuditLog, error)
+	AppendMeta(ctx context.Context, tenantID, id string, patch map[string]any) (domain.AuditLog, error)
+	GetStats(ctx context.Context, tenantID string) (domain.TenantStats, error)
+	VerifyChain(ctx context.Context, tenantID string) (domain.ChainVerification, error)
+}
+
+// Handler exposes the audit-log HTTP API.
+type Handler struct {
+	svc                    Service
+	metricsCache           *statsCache
+	routePolicies          map[string]config.RoutePolicy
+	clientIPTrustedProxies []*net.IPNet
+}
+
+// NewHandler returns a Handler backed by the given service.
+func NewHandler(svc Service) *Handler {
+	return &Handler{svc: svc, metricsCache: newStatsCache(defaultMetricsCacheTTL)}
+}
+
+// WithAuthPolicies overrides the default auth requirement (attempted but
+// not required) for specific routes, keyed by "METHOD path" exactly as
+// registered in Routes (e.g. "DELETE /api/v1/tenants/{tenant_id}").
+func (h *Handler) WithAuthPolicies(policies map[string]config.RoutePolicy) *Handler {
+	h.routePolicies = policies
+	return h
+}
+
+// WithClientIPConfig sets the trusted-proxy CIDRs clientIPFromRequest
+// requires before trusting X-Forwarded-For's leftmost public address over
+// its unconditional-trust default. See config.ClientIPConfig.
+func (h *Handler) WithClientIPConfig(cfg config.ClientIPConfig) *Handler {
+	for _, cidr := range cfg.TrustedProxyCIDRs {
+		if _, ipnet, err := net.ParseCIDR(cidr); err == nil {
+			h.clientIPTrustedProxies = append(h.clientIPTrustedProxies, ipnet)
+		}
+	}
+	return h
+}
+
+// Routes registers the audit-log endpoints on r.
+func (h *Handler) Routes(r chi.Router) {
+	h.method(r, http.MethodPost, "/api/v1/audit", h.createAuditLog)
+	h.method(r, http.MethodPost, "/api/v1/audit/batch", h.createAuditLogsBatch)
+	h.method(r, http.MethodGet, "/api/v1/audit", h.listAuditLogs)
+	h.method(r, http.MethodDelete, "/api/v1/audit", h.deleteAuditLogsByFilter)
+	h.method(r, http.MethodGet, "/api/v1/audit/timeseries", h.getTimeSeries)
+	h.method(r, http.MethodGet, "/api/v1/audit/facets", h.getFacets)
+	h.method(r, http.MethodGet, "/api/v1/audit/latest", h.getLatestPerResource)
+	h.method(r, http.MethodGet, "/api/v1/audit/verify", h.verifyChain)
+	h.method(r, http.MethodGet, "/api/v1/audit/journal", h.getJournal)
+	h.method(r, http.MethodGet, "/api/v1/audit/metrics", h.getMetrics)
+	h.method(r, http.MethodGet, "/api/v1/audit/{id}", h.getAuditLog)
+	h.method(r, http.MethodHead, "/api/v1/audit/{id}", h.headAuditLog)
+	h.method(r, http.MethodDelete, "/api/v1/audit/{id}", h.deleteAuditLog)
+	h.method(r, http.MethodDelete, "/api/v1/audit/{id}/hard", h.hardDeleteAuditLog)
+	h.method(r, http.MethodPost, "/api/v1/audit/{id}/tags", h.addTags)
+	h.method(r, http.MethodDelete, "/api/v1/audit/{id}/tags", h.removeTags)
+	h.method(r, http.MethodPatch, "/api/v1/audit/{id}/meta", h.appendMeta)
+	h.method(r, http.MethodDelete, "/api/v1/tenants/{tenant_id}", h.purgeTenant)
+}
+
+// method registers handler for method+pattern, wrapping it with the
+// configured RoutePolicy for that route, if any (see WithAuthPolicies).
+func (h *Handler) method(r chi.Router, method, pattern string, handler http.HandlerFunc) {
+	policy, ok := h.routePolicies[method+" "+pattern]
+	if !ok {
+		r.Method(method, pattern, handler)
+		return
+	}
+	r.With(auth.Require(toAuthPolicy(policy))).Method(method, pattern, handler)
+}
+
+func toAuthPolicy(policy config.RoutePolicy) auth.RoutePolicy {
+	types := make([]auth.Type, len(policy.AllowedTypes))
+	for i, t := range policy.AllowedTypes {
+		types[i] = auth.Type(t)
+	}
+	return auth.RoutePolicy{
+		RequireAuth:    policy.RequireAuth,
+		AllowedTypes:   types,
+		RequiredScopes: policy.RequiredScopes,
+	}
+}
+
+type createAuditLogRequest struct {
+	TenantID   string         `json:"tenant_id" msgpack:"tenant_id" validate:"omitempty,tenant_id"`
+	Actor      string         `json:"actor" msgpack:"actor" validate:"omitempty,actor_name"`
+	Event      string         `json:"event" msgpack:"event" validate:"omitempty,event_name"`
+	Resource   string         `json:"resource" msgpack:"resource" validate:"omitempty,resource_name"`
+	ResourceID string         `json:"resource_id" msgpack:"resource_id"`
+	Data       map[string]any `json:"data,omitempty" msgpack:"data,omitempty"`
+	Meta       map[string]any `json:"meta,omitempty" msgpack:"meta,omitempty"`
+	Tags       []string       `json:"tags,omitempty" msgpack:"tags,omitempty"`
+	Timestamp  *time.Time     `json:"timestamp,omitempty" msgpack:"timestamp,omitempty"`
+	// IfAbsent, when set, makes the create conditional (see
+	// domain.CreateCondition): the record is only inserted if no match
+	// already exists, and the response reports created=false otherwise.
+	IfAbsent *ifAbsentRequest `json:"if_absent,omitempty" msgpack:"if_absent,omitempty"`
+}
+
+type ifAbsentRequest struct {
+	MatchFields   []string `json:"match_fields" msgpack:"match_fields"`
+	WindowSeconds int      `json:"window_seconds" msgpack:"window_seconds"`
+}
+
+// createAuditLogResponse is the conditional-create response shape: the
+// record plus whether it was newly inserted. The unconditional create
+// path keeps returning the bare domain.AuditLog for backward
+// compatibility.
+type createAuditLogResponse struct {
+	domain.AuditLog
+	Created bool `json:"created"`
+}
+
+// @Summary Create an audit log
+// @Tags audit
+// @Accept json
+// @Produce json
+// @Param request body createAuditLogRequest true "Audit log to create"
+// @Success 201 {object} domain.AuditLog
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 422 {object} errorResponse
+// @Failure 507 {object} errorResponse
+// @Router /audit [post]
+func (h *Handler) createAuditLog(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeCreateAuditLogRequest(r)
+	if err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	if req.TenantID == "" {
+		if tenantID, ok := TenantFromContext(r.Context()); ok {
+			req.TenantID = tenantID
+		}
+	}
+	if req.TenantID == "" || req.Event == "" {
+		writeError(w, http.StatusBadRequest, "tenant_id and event are required")
+		return
+	}
+	if err := reqValidator.Struct(req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !auth.TenantAllowed(r.Context(), req.TenantID) {
+		writeError(w, http.StatusForbidden, domain.ErrTenantNotAllowed.Error())
+		return
+	}
+
+	var createdAt time.Time
+	if req.Timestamp != nil {
+		createdAt = *req.Timestamp
+	}
+
+	newLog := domain.AuditLog{
+		TenantID:       req.TenantID,
+		Actor:          req.Actor,
+		AuthType:       authTypeFromContext(r.Context()),
+		ClientIP:       h.clientIPFromRequest(r),
+		Event:          req.Event,
+		Resource:       req.Resource,
+		ResourceID:     req.ResourceID,
+		Data:           req.Data,
+		Meta:           req.Meta,
+		Tags:           req.Tags,
+		CreatedAt:      createdAt,
+		IdempotencyKey: r.Header.Get("Idempotency-Key"),
+	}
+
+	ctx := ingestmode.WithContext(r.Context(), isImportMode(r))
+
+	var log domain.AuditLog
+	var created bool
+	switch {
+	case req.IfAbsent != nil:
+		cond := domain.CreateCondition{
+			MatchFields: req.IfAbsent.MatchFields,
+			Window:      time.Duration(req.IfAbsent.WindowSeconds) * time.Second,
+		}
+		log, created, err = h.svc.CreateAuditLogConditional(ctx, newLog, cond)
+	case newLog.IdempotencyKey != "":
+		log, created, err = h.svc.CreateAuditLogIdempotent(ctx, newLog)
+	default:
+		log, err = h.svc.CreateAuditLog(ctx, newLog)
+		created = true
+	}
+	if err != nil {
+		if errors.Is(err, domain.ErrEventNotAllowed) {
+			writeError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidArgument) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrQuotaExceeded) {
+			writeError(w, http.StatusInsufficientStorage, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to create audit log")
+		return
+	}
+
+	w.Header().Set("Location", "/api/v1/audit/"+log.ID.String())
+	if req.IfAbsent != nil {
+		status := http.StatusOK
+		if created {
+			status = http.StatusCreated
+		}
+		writeJSON(w, status, createAuditLogResponse{AuditLog: log, Created: created})
+		return
+	}
+	if newLog.IdempotencyKey != "" {
+		status := http.StatusOK
+		if created {
+			status = http.StatusCreated
+		}
+		writeJSON(w, status, log)
+		return
+	}
+	writeJSON(w, http.StatusCreated, log)
+}
+
+// createAuditLogsBatchResponse is the batch endpoint's response shape: the
+// created records, the per-index failures, and a count for a caller that
+// only cares whether everything succeeded.
+type createAuditLogsBatchResponse struct {
+	domain.BatchCreateResult
+	CreatedCount int `json:"created_count"`
+}
+
+// createAuditLogsBatch accepts a JSON array of createAuditLogRequest (the
+// same shape as createAuditLog, capped at maxBatchCreateEntries) and
+// creates them in one call. By default a validation failure on one entry
+// doesn't stop the others from being created; passing ?all_or_nothing=true
+// rejects the whole batch if any entry fails validation.
+//
+// @Summary Create a batch of audit logs
+// @Tags audit
+// @Accept json
+// @Produce json
+// @Param all_or_nothing query bool false "Reject the whole batch if any entry fails validation"
+// @Param request body []createAuditLogRequest true "Audit logs to create"
+// @Success 207 {object} createAuditLogsBatchResponse
+// @Failure 400 {object} errorResponse
+// @Router /audit/batch [post]
+func (h *Handler) createAuditLogsBatch(w http.ResponseWriter, r *http.Request) {
+	var reqs []createAuditLogRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&reqs); err != nil {
+		writeDecodeError(w, describeJSONError(err))
+		return
+	}
+	if len(reqs) == 0 {
+		writeError(w, http.StatusBadRequest, "batch must contain at least one entry")
+		return
+	}
+
+	allOrNothing, _ := strconv.ParseBool(r.URL.Query().Get("all_or_nothing"))
+
+	// Entries that fail the required-field or format check never reach
+	// prepareCreate, so they're recorded as BatchEntryErrors here instead
+	// of aborting the whole request; see the handler's doc comment. Only
+	// the tenant-isolation check is a hard abort, since it's an
+	// authorization boundary rather than a per-entry validation failure.
+	logs := make([]domain.AuditLog, 0, len(reqs))
+	logIndex := make([]int, 0, len(reqs))
+	var preFailed []domain.BatchEntryError
+	for i, req := range reqs {
+		if req.TenantID == "" {
+			if tenantID, ok := TenantFromContext(r.Context()); ok {
+				req.TenantID = tenantID
+			}
+		}
+		if req.TenantID == "" || req.Event == "" {
+			preFailed = append(preFailed, domain.BatchEntryError{Index: i, Error: "tenant_id and event are required"})
+			continue
+		}
+		if err := reqValidator.Struct(req); err != nil {
+			preFailed = append(preFailed, domain.BatchEntryError{Index: i, Error: err.Error()})
+			continue
+		}
+		if !auth.TenantAllowed(r.Context(), req.TenantID) {
+			writeError(w, http.StatusForbidden, domain.ErrTenantNotAllowed.Error())
+			return
+		}
+
+		var createdAt time.Time
+		if req.Timestamp != nil {
+			createdAt = *req.Timestamp
+		}
+		logs = append(logs, domain.AuditLog{
+			TenantID:   req.TenantID,
+			Actor:      req.Actor,
+			AuthType:   authTypeFromContext(r.Context()),
+			ClientIP:   h.clientIPFromRequest(r),
+			Event:      req.Event,
+			Resource:   req.Resource,
+			ResourceID: req.ResourceID,
+			Data:       req.Data,
+			Meta:       req.Meta,
+			Tags:       req.Tags,
+			CreatedAt:  createdAt,
+		})
+		logIndex = append(logIndex, i)
+	}
+
+	if allOrNothing && len(preFailed) > 0 {
+		writeJSON(w, http.StatusUnprocessableEntity, createAuditLogsBatchResponse{BatchCreateResult: domain.BatchCreateResult{Failed: preFailed}})
+		return
+	}
+
+	result := domain.BatchCreateResult{Failed: preFailed}
+	if len(logs) > 0 {
+		ctx := ingestmode.WithContext(r.Context(), isImportMode(r))
+		svcResult, err := h.svc.CreateAuditLogsBatch(ctx, logs, allOrNothing)
+		if err != nil {
+			if errors.Is(err, domain.ErrInvalidArgument) {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			writeError(w, http.StatusInternalServerError, "failed to create audit log batch")
+			return
+		}
+		result.Created = svcResult.Created
+		for _, f := range svcResult.Failed {
+			result.Failed = append(result.Failed, domain.BatchEntryError{Index: logIndex[f.Index], Error: f.Error})
+		}
+		sort.Slice(result.Failed, func(a, b int) bool { return result.Failed[a].Index < result.Failed[b].Index })
+	}
+
+	status := http.StatusCreated
+	switch {
+	case len(result.Failed) > 0 && len(result.Created) == 0:
+		status = http.StatusUnprocessableEntity
+	case len(result.Failed) > 0:
+		status = http.StatusMultiStatus
+	}
+	writeJSON(w, status, createAuditLogsBatchResponse{BatchCreateResult: result, CreatedCount: len(result.Created)})
+}
+
+// authTypeFromContext returns the credential type (see auth.Type) that
+// authenticated ctx's request, or "" for an unauthenticated write.
+func authTypeFromContext(ctx context.Context) string {
+	ac, ok := auth.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	return string(ac.Type)
+}
+
+// clientIPFromRequest returns the caller's address for domain.AuditLog.ClientIP.
+// Without WithClientIPConfig's trusted proxies configured, it trusts the
+// first entry of X-Forwarded-For unconditionally if present (the original
+// behavior, only safe when every caller is already known to go through a
+// proxy that sets the header correctly), otherwise r.RemoteAddr with its
+// port stripped. With trusted proxies configured, the header is trusted
+// only when the immediate peer (r.RemoteAddr) is one of them — mirroring
+// TenantFromHeader's trust model — and the value used is the leftmost
+// entry that isn't a private/reserved address, since a client can prepend
+// a fake private-looking hop to the header it sends to spoof its apparent
+// origin. The result is returned as-is, unvalidated; see AuditService's
+// normalizeClientIP for parsing and canonicalization.
+func (h *Handler) clientIPFromRequest(r *http.Request) string {
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+		if len(h.clientIPTrustedProxies) == 0 {
+			if first := strings.TrimSpace(strings.SplitN(xff, ",", 2)[0]); first != "" {
+				return first
+			}
+		} else if isTrustedPeer(r.RemoteAddr, h.clientIPTrustedProxies) {
+			for _, hop := range strings.Split(xff, ",") {
+				candidate := strings.TrimSpace(hop)
+				if candidate == "" || isPrivateOrReservedIP(candidate) {
+					continue
+				}
+				return candidate
+			}
+		}
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// isPrivateOrReservedIP reports whether raw parses as an IP that is
+// private, loopback, link-local, or unspecified — the address ranges a
+// spoofed X-Forwarded-For hop would plausibly use, and never a real
+// public client address. An unparseable raw is treated as reserved, since
+// it can't be a valid client address either.
+func isPrivateOrReservedIP(raw string) bool {
+	ip := net.ParseIP(raw)
+	if ip == nil {
+		return true
+	}
+	return ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified()
+}
+
+// isImportMode reports whether r should be treated as a historical
+// backfill/import rather than live ingestion, either via an explicit
+// X-Import-Mode header or the authenticated principal's audit:import
+// scope. The header is trusted unconditionally: it only relaxes a
+// data-integrity check, not a security boundary.
+func isImportMode(r *http.Request) bool {
+	if v, err := strconv.ParseBool(r.Header.Get("X-Import-Mode")); err == nil && v {
+		return true
+	}
+	if ac, ok := auth.FromContext(r.Context()); ok {
+		for _, scope := range ac.Principal.Scopes {
+			if scope == importModeScope {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// splitFilterValues splits a comma-separated query value into its
+// individual, trimmed tokens, skipping empty ones (e.g. from a trailing
+// comma) so a multi-value filter can't accidentally match the empty
+// string. Returns nil for an empty v.
+func splitFilterValues(v string) []string {
+	if v == "" {
+		return nil
+	}
+	parts := strings.Split(v, ",")
+	values := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			values = append(values, p)
+		}
+	}
+	return values
+}
+
+// parseFilter builds the common domain.Filter fields shared by the list,
+// timeseries and facets endpoints from query parameters. It does not
+// parse limit/offset, which only apply to list. If the request has no
+// explicit tenant_id, it falls back to the header-extracted tenant (see
+// TenantFromHeader) so a trusted gateway's header scopes the query.
+//
+// event and resource accept a comma-separated list of values, which
+// populates Filter.Events/Resources for an IN-clause match instead of the
+// single-value exact match (see domain.Filter). status and method are not
+// supported, since AuditLog has no corresponding fields in this schema.
+func parseFilter(r *http.Request) (domain.Filter, error) {
+	q := r.URL.Query()
+
+	filter := domain.Filter{
+		TenantID:       q.Get("tenant_id"),
+		Actor:          q.Get("actor"),
+		AuthType:       q.Get("auth_type"),
+		Event:          q.Get("event"),
+		Resource:       q.Get("resource"),
+		ResourceID:     q.Get("resource_id"),
+		IncludeDeleted: q.Get("include_deleted") == "true",
+		Search:         q.Get("q"),
+	}
+	if filter.TenantID == "" {
+		if tenantID, ok := TenantFromContext(r.Context()); ok {
+			filter.TenantID = tenantID
+		}
+	}
+	if filter.TenantID == "" {
+		if tenantID, ok := auth.EffectiveTenant(r.Context()); ok {
+			filter.TenantID = tenantID
+		}
+	}
+	// Checked unconditionally, not just when TenantID is set: an empty
+	// TenantID means "all tenants" to the store layer (see domain.Filter),
+	// so a tenant-restricted caller who simply omits tenant_id must still
+	// be rejected rather than silently handed an unconstrained query.
+	if !auth.TenantAllowed(r.Context(), filter.TenantID) {
+		return domain.Filter{}, domain.ErrTenantNotAllowed
+	}
+
+	if values := splitFilterValues(q.Get("event")); len(values) > 1 {
+		filter.Events = values
+	}
+	if values := splitFilterValues(q.Get("resource")); len(values) > 1 {
+		filter.Resources = values
+	}
+
+	if v := q.Get("from"); v != "" {
+		from, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return domain.Filter{}, errors.New("from must be an RFC3339 timestamp")
+		}
+		filter.From = from
+	}
+	if v := q.Get("to"); v != "" {
+		to, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return domain.Filter{}, errors.New("to must be an RFC3339 timestamp")
+		}
+		filter.To = to
+	}
+
+	if v := q.Get("filter"); v != "" {
+		expr, err := service.ParseExpression(v)
+		if err != nil {
+			return domain.Filter{}, err
+		}
+		filter.Expr = expr
+	}
+
+	if v := q.Get("contains_pii"); v != "" {
+		containsPII, err := strconv.ParseBool(v)
+		if err != nil {
+			return domain.Filter{}, errors.New("contains_pii must be true or false")
+		}
+		filter.ContainsPII = &containsPII
+	}
+
+	if v := q.Get("business_hours"); v != "" {
+		businessHours, err := strconv.ParseBool(v)
+		if err != nil {
+			return domain.Filter{}, errors.New("business_hours must be true or false")
+		}
+		filter.BusinessHours = &businessHours
+	}
+
+	if v := q.Get("tags"); v != "" {
+		filter.Tags = strings.Split(v, ",")
+	}
+	if v := q.Get("tags_match"); v != "" {
+		switch v {
+		case "all":
+			filter.TagsMatchAll = true
+		case "any":
+			filter.TagsMatchAll = false
+		default:
+			return domain.Filter{}, errors.New("tags_match must be \"all\" or \"any\"")
+		}
+	}
+
+	return filter, nil
+}
+
+// writeFilterError reports err from parseFilter with the right status:
+// 403 if the caller's credential isn't bound to the requested tenant,
+// 400 for any other (malformed query param) error.
+func writeFilterError(w http.ResponseWriter, err error) {
+	if errors.Is(err, domain.ErrTenantNotAllowed) {
+		writeError(w, http.StatusForbidden, err.Error())
+		return
+	}
+	writeError(w, http.StatusBadRequest, err.Error())
+}
+
+// @Summary List audit logs
+// @Tags audit
+// @Produce json
+// @Param tenant_id query string false "Tenant ID"
+// @Param event query string false "Event name"
+// @Param resource query string false "Resource"
+// @Param limit query int false "Page size"
+// @Param offset query int false "Page offset"
+// @Param cursor query string false "Keyset pagination cursor, takes precedence over offset"
+// @Param sort_by query string false "Sort column"
+// @Param sort_order query string false "asc or desc"
+// @Param count query bool false "Also compute the total match count (default false)"
+// @Success 200 {object} domain.Page
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Router /audit [get]
+func (h *Handler) listAuditLogs(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	filter, err := parseFilter(r)
+	if err != nil {
+		writeFilterError(w, err)
+		return
+	}
+
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "limit must be an integer")
+			return
+		}
+		filter.Limit = limit
+	}
+
+	// cursor takes precedence over offset when both are present, so
+	// callers can switch a saved "next page" link to keyset pagination
+	// without also having to strip an offset they're still carrying.
+	if v := q.Get("cursor"); v != "" {
+		cursor, err := domain.DecodeCursor(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "cursor is invalid")
+			return
+		}
+		filter.Cursor = &cursor
+	} else if v := q.Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "offset must be an integer")
+			return
+		}
+		filter.Offset = offset
+	}
+
+	filter.SortBy = q.Get("sort_by")
+	filter.SortOrder = q.Get("sort_order")
+
+	if v := q.Get("count"); v != "" {
+		withCount, err := strconv.ParseBool(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "count must be true or false")
+			return
+		}
+		filter.WithCount = withCount
+	}
+
+	page, err := h.svc.ListAuditLogs(r.Context(), filter)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidArgument) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to list audit logs")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, page)
+}
+
+// journalEntry is one event in a tenant's audit journal. Action
+// identifies what kind of event it is; only "create" exists today (see
+// getJournal).
+type journalEntry struct {
+	domain.AuditLog
+	Action string `json:"action"`
+}
+
+type journalResponse struct {
+	Entries []journalEntry `json:"entries"`
+	Limit   int            `json:"limit"`
+	Offset  int            `json:"offset"`
+}
+
+// getJournal returns a tenant's audit trail as a single time-ordered,
+// paginated stream, for compliance auditors who want one feed rather
+// than calling ListAuditLogs repeatedly.
+//
+// Every entry's Action is currently "create": this tree has no
+// status-history, deletion-history, or anonymization-history tables to
+// merge in, so the journal today is exactly the tenant's audit log
+// creates in order. Action is included now so that once those history
+// tables exist, their records can be merged into the same stream without
+// a breaking response change.
+func (h *Handler) getJournal(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	filter, err := parseFilter(r)
+	if err != nil {
+		writeFilterError(w, err)
+		return
+	}
+	if filter.TenantID == "" {
+		writeError(w, http.StatusBadRequest, "tenant_id is required")
+		return
+	}
+
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "limit must be an integer")
+			return
+		}
+		filter.Limit = limit
+	}
+	if v := q.Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "offset must be an integer")
+			return
+		}
+		filter.Offset = offset
+	}
+
+	page, err := h.svc.ListAuditLogs(r.Context(), filter)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to build audit journal")
+		return
+	}
+
+	entries := make([]journalEntry, len(page.Logs))
+	for i, log := range page.Logs {
+		entries[i] = journalEntry{AuditLog: log, Action: "create"}
+	}
+
+	writeJSON(w, http.StatusOK, journalResponse{Entries: entries, Limit: page.Limit, Offset: page.Offset})
+}
+
+func (h *Handler) getTimeSeries(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	filter, err := parseFilter(r)
+	if err != nil {
+		writeFilterError(w, err)
+		return
+	}
+
+	interval := q.Get("interval")
+	if interval == "" {
+		interval = "day"
+	}
+
+	buckets, err := h.svc.GetTimeSeries(r.Context(), filter, interval)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidArgument) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to get time series")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, buckets)
+}
+
+func (h *Handler) getFacets(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	filter, err := parseFilter(r)
+	if err != nil {
+		writeFilterError(w, err)
+		return
+	}
+
+	field := q.Get("field")
+	topN := 0
+	if v := q.Get("top_n"); v != "" {
+		topN, err = strconv.Atoi(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "top_n must be an integer")
+			return
+		}
+	}
+
+	result, err := h.svc.GetFacets(r.Context(), filter, field, topN)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidArgument) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to get facets")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// resolveTenantID determines which tenant a request is scoped to: the
+// tenant_id query param if given, else the tenant a single-tenant header
+// sets (see TenantFromContext), else — for a principal restricted to
+// exactly one tenant, e.g. a JWT carrying a "tenant_id" claim — that
+// tenant (see auth.EffectiveTenant). Callers must still check the result
+// against auth.TenantAllowed before using it; this only picks a default,
+// it does not enforce one.
+func resolveTenantID(r *http.Request) string {
+	tenantID := r.URL.Query().Get("tenant_id")
+	if tenantID == "" {
+		if fromHeader, ok := TenantFromContext(r.Context()); ok {
+			tenantID = fromHeader
+		}
+	}
+	if tenantID == "" {
+		if effective, ok := auth.EffectiveTenant(r.Context()); ok {
+			tenantID = effective
+		}
+	}
+	return tenantID
+}
+
+func (h *Handler) getLatestPerResource(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseFilter(r)
+	if err != nil {
+		writeFilterError(w, err)
+		return
+	}
+
+	logs, err := h.svc.GetLatestPerResource(r.Context(), filter)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to get latest per resource")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, logs)
+}
+
+// verifyChain walks the caller's tenant's tamper-evident hash chain and
+// reports whether it is intact. tenant_id is required, mirroring
+// getMetrics, since this always scopes to a single tenant's chain.
+func (h *Handler) verifyChain(w http.ResponseWriter, r *http.Request) {
+	tenantID := resolveTenantID(r)
+	if !auth.TenantAllowed(r.Context(), tenantID) {
+		writeError(w, http.StatusForbidden, domain.ErrTenantNotAllowed.Error())
+		return
+	}
+
+	result, err := h.svc.VerifyChain(r.Context(), tenantID)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidArgument) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to verify chain")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (h *Handler) getAuditLog(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	tenantID := resolveTenantID(r)
+	if !auth.TenantAllowed(r.Context(), tenantID) {
+		writeError(w, http.StatusForbidden, domain.ErrTenantNotAllowed.Error())
+		return
+	}
+
+	includeDeleted := r.URL.Query().Get("include_deleted") == "true"
+	log, err := h.svc.GetAuditLog(r.Context(), tenantID, id, includeDeleted)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "audit log not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to get audit log")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, log)
+}
+
+// deleteAuditLog soft-deletes a single record: it stops appearing in
+// List/GetAuditLog (unless include_deleted=true) but the row, and its
+// tamper-evident history, remains intact. For true removal see
+// hardDeleteAuditLog.
+func (h *Handler) deleteAuditLog(w http.ResponseWriter, r *http.Request) {
+	h.deleteAuditLogBy(w, r, h.svc.DeleteAuditLog)
+}
+
+// hardDeleteAuditLog permanently removes a single record; there is no
+// undo. Operators are expected to restrict this route to a trusted
+// caller via config.RoutePolicy.RequiredScopes (see
+// Handler.WithAuthPolicies) — this handler itself does not check a role
+// or scope.
+func (h *Handler) hardDeleteAuditLog(w http.ResponseWriter, r *http.Request) {
+	h.deleteAuditLogBy(w, r, h.svc.HardDeleteAuditLog)
+}
+
+func (h *Handler) deleteAuditLogBy(w http.ResponseWriter, r *http.Request, op func(ctx context.Context, tenantID, id string) error) {
+	id := chi.URLParam(r, "id")
+	tenantID := resolveTenantID(r)
+	if !auth.TenantAllowed(r.Context(), tenantID) {
+		writeError(w, http.StatusForbidden, domain.ErrTenantNotAllowed.Error())
+		return
+	}
+
+	if err := op(r.Context(), tenantID, id); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "audit log not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to delete audit log")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// headAuditLog answers HEAD /api/v1/audit/{id} with ETag/Last-Modified
+// headers and no body, using a lightweight existence+metadata query so
+// monitoring tools and caches checking freshness don't pay for
+// transferring a potentially large Data/Meta blob.
+func (h *Handler) headAuditLog(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	tenantID := resolveTenantID(r)
+	if !auth.TenantAllowed(r.Context(), tenantID) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	log, err := h.svc.GetAuditLogHead(r.Context(), tenantID, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("ETag", etagFor(log))
+	w.Header().Set("Last-Modified", log.CreatedAt.UTC().Format(http.TimeFormat))
+	w.WriteHeader(http.StatusOK)
+}
+
+// etagFor builds a weak ETag from a record's ID and Sequence (which only
+// ever changes by getting assigned once, at insert) so the HEAD response
+// has something stable to compare against. It is not a full caching
+// layer — there is no matching If-None-Match handling yet.
+func etagFor(log domain.AuditLog) string {
+	return `"` + log.ID.String() + "-" + strconv.FormatInt(log.Sequence, 10) + `"`
+}
+
+type tagsRequest struct {
+	Tags []string `json:"tags"`
+}
+
+func (h *Handler) addTags(w http.ResponseWriter, r *http.Request) {
+	h.updateTags(w, r, h.svc.AddTags)
+}
+
+func (h *Handler) removeTags(w http.ResponseWriter, r *http.Request) {
+	h.updateTags(w, r, h.svc.RemoveTags)
+}
+
+// updateTags decodes a tagsRequest body and applies op (AddTags or
+// RemoveTags) to the record identified by the id path param, scoped to
+// the caller's tenant.
+func (h *Handler) updateTags(w http.ResponseWriter, r *http.Request, op func(ctx context.Context, tenantID, id string, tags []string) (domain.AuditLog, error)) {
+	id := chi.URLParam(r, "id")
+	tenantID := resolveTenantID(r)
+	if !auth.TenantAllowed(r.Context(), tenantID) {
+		writeError(w, http.StatusForbidden, domain.ErrTenantNotAllowed.Error())
+		return
+	}
+
+	var req tagsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if len(req.Tags) == 0 {
+		writeError(w, http.StatusBadRequest, "tags is required")
+		return
+	}
+
+	log, err := op(r.Context(), tenantID, id, req.Tags)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "audit log not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to update tags")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, log)
+}
+
+// appendMeta merges a JSON object body into the record's existing meta,
+// without touching data or any meta key not present in the body. The
+// body must decode to a JSON object; an array or scalar top-level value
+// is rejected rather than silently coerced.
+func (h *Handler) appendMeta(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	tenantID := resolveTenantID(r)
+	if !auth.TenantAllowed(r.Context(), tenantID) {
+		writeError(w, http.StatusForbidden, domain.ErrTenantNotAllowed.Error())
+		return
+	}
+
+	dec := json.NewDecoder(r.Body)
+	var raw json.RawMessage
+	if err := dec.Decode(&raw); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	var patch map[string]any
+	if err := json.Unmarshal(raw, &patch); err != nil {
+		writeError(w, http.StatusBadRequest, "body must be a JSON object")
+		return
+	}
+	if len(patch) == 0 {
+		writeError(w, http.StatusBadRequest, "body must contain at least one key")
+		return
+	}
+
+	log, err := h.svc.AppendMeta(r.Context(), tenantID, id, patch)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "audit log not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to update meta")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, log)
+}
+
+type deleteByFilterResponse struct {
+	Deleted int64 `json:"deleted"`
+}
+
+// @Summary Bulk delete audit logs matching a filter
+// @Tags audit
+// @Produce json
+// @Param tenant_id query string true "Tenant ID"
+// @Param event query string false "Event name"
+// @Param resource query string false "Resource"
+// @Param from query string false "RFC3339 lower bound on created_at"
+// @Param to query string false "RFC3339 upper bound on created_at"
+// @Success 200 {object} deleteByFilterResponse
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Router /audit [delete]
+//
+// deleteAuditLogsByFilter permanently deletes every record matching the
+// query's filter; there is no undo. It is meant for bulk cleanup (e.g.
+// purging a test environment's logs) where deleting one record at a time
+// via DELETE /api/v1/audit/{id} isn't practical. Operators are expected
+// to restrict this route to a trusted caller via config.RoutePolicy.
+// RequiredScopes (see Handler.WithAuthPolicies), the same as
+// hardDeleteAuditLog — this handler itself does not check a role or
+// scope. AuditService.DeleteAuditLogsByFilter refuses a filter that
+// isn't scoped beyond tenant_id, to guard against an accidental
+// full-tenant delete through this route instead of PurgeTenant.
+func (h *Handler) deleteAuditLogsByFilter(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseFilter(r)
+	if err != nil {
+		writeFilterError(w, err)
+		return
+	}
+
+	deleted, err := h.svc.DeleteAuditLogsByFilter(r.Context(), filter)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidArgument) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to delete audit logs")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, deleteByFilterResponse{Deleted: deleted})
+}
+
+type purgeTenantResponse struct {
+	Deleted int64 `json:"deleted"`
+}
+
+// purgeTenant deletes all audit logs for a tenant. It is meant for
+// offboarding: there is no confirmation step or soft-delete, the caller
+// is expected to have already confirmed this out of band.
+func (h *Handler) purgeTenant(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenant_id")
+	if tenantID == "" {
+		writeError(w, http.StatusBadRequest, "tenant_id is required")
+		return
+	}
+	if !auth.TenantAllowed(r.Context(), tenantID) {
+		writeError(w, http.StatusForbidden, domain.ErrTenantNotAllowed.Error())
+		return
+	}
+
+	deleted, err := h.svc.PurgeTenant(r.Context(), tenantID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to purge tenant")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, purgeTenantResponse{Deleted: deleted})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, errorResponse{Error: msg})
+}
@@ -0,0 +1,592 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/auth"
+	"github.com/naveenkumar2412/audit-log-service/internal/config"
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+)
+
+func TestIsImportMode_Header(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/audit", nil)
+	req.Header.Set("X-Import-Mode", "true")
+
+	if !isImportMode(req) {
+		t.Error("expected import mode from header")
+	}
+}
+
+func TestIsImportMode_Scope(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/audit", nil)
+	ctx := auth.WithContext(req.Context(), auth.Context{Principal: auth.Principal{Scopes: []string{"audit:import"}}})
+	req = req.WithContext(ctx)
+
+	if !isImportMode(req) {
+		t.Error("expected import mode from scope")
+	}
+}
+
+func TestIsImportMode_DefaultsFalse(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/audit", nil)
+
+	if isImportMode(req) {
+		t.Error("expected import mode false by default")
+	}
+}
+
+func TestAuthTypeFromContext_ReturnsResolvedType(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/audit", nil)
+	ctx := auth.WithContext(req.Context(), auth.Context{Principal: auth.Principal{ID: "svc-a"}, Type: auth.TypeAPIKey})
+	req = req.WithContext(ctx)
+
+	if got := authTypeFromContext(req.Context()); got != "api_key" {
+		t.Errorf("got %q, want api_key", got)
+	}
+}
+
+func TestAuthTypeFromContext_EmptyWhenUnauthenticated(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/audit", nil)
+
+	if got := authTypeFromContext(req.Context()); got != "" {
+		t.Errorf("got %q, want empty", got)
+	}
+}
+
+func TestClientIPFromRequest_UsesForwardedForFirstEntry(t *testing.T) {
+	h := &Handler{}
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/audit", nil)
+	req.Header.Set("X-Forwarded-For", "203.0.113.5, 70.41.3.18, 150.172.238.178")
+	req.RemoteAddr = "10.0.0.1:12345"
+
+	if got := h.clientIPFromRequest(req); got != "203.0.113.5" {
+		t.Errorf("got %q, want 203.0.113.5", got)
+	}
+}
+
+func TestClientIPFromRequest_FallsBackToRemoteAddrWithoutForwardedFor(t *testing.T) {
+	h := &Handler{}
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/audit", nil)
+	req.RemoteAddr = "192.0.2.1:54321"
+
+	if got := h.clientIPFromRequest(req); got != "192.0.2.1" {
+		t.Errorf("got %q, want 192.0.2.1 (port stripped)", got)
+	}
+}
+
+func TestClientIPFromRequest_RemoteAddrWithoutPort(t *testing.T) {
+	h := &Handler{}
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/audit", nil)
+	req.RemoteAddr = "192.0.2.1"
+
+	if got := h.clientIPFromRequest(req); got != "192.0.2.1" {
+		t.Errorf("got %q, want 192.0.2.1", got)
+	}
+}
+
+func TestClientIPFromRequest_TrustedProxiesRequirePeerInCIDR(t *testing.T) {
+	h := NewHandler(nil).WithClientIPConfig(config.ClientIPConfig{TrustedProxyCIDRs: []string{"10.0.0.0/8"}})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/audit", nil)
+	req.Header.Set("X-Forwarded-For", "203.0.113.5")
+	req.RemoteAddr = "198.51.100.1:12345"
+
+	if got := h.clientIPFromRequest(req); got != "198.51.100.1" {
+		t.Errorf("got %q, want 198.51.100.1 (untrusted peer, X-Forwarded-For ignored)", got)
+	}
+}
+
+func TestClientIPFromRequest_TrustedProxySkipsSpoofedPrivateHop(t *testing.T) {
+	h := NewHandler(nil).WithClientIPConfig(config.ClientIPConfig{TrustedProxyCIDRs: []string{"10.0.0.0/8"}})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/audit", nil)
+	req.Header.Set("X-Forwarded-For", "192.168.1.1, 203.0.113.5, 70.41.3.18")
+	req.RemoteAddr = "10.0.0.1:12345"
+
+	if got := h.clientIPFromRequest(req); got != "203.0.113.5" {
+		t.Errorf("got %q, want 203.0.113.5 (leftmost public address, spoofed private hop skipped)", got)
+	}
+}
+
+func TestClientIPFromRequest_TrustedProxyAllPrivateFallsBackToRemoteAddr(t *testing.T) {
+	h := NewHandler(nil).WithClientIPConfig(config.ClientIPConfig{TrustedProxyCIDRs: []string{"10.0.0.0/8"}})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/audit", nil)
+	req.Header.Set("X-Forwarded-For", "192.168.1.1, 127.0.0.1")
+	req.RemoteAddr = "10.0.0.1:12345"
+
+	if got := h.clientIPFromRequest(req); got != "10.0.0.1" {
+		t.Errorf("got %q, want 10.0.0.1 (no public address in X-Forwarded-For)", got)
+	}
+}
+
+func TestParseFilter_ParsesAuthType(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/audit?auth_type=jwt", nil)
+
+	filter, err := parseFilter(req)
+	if err != nil {
+		t.Fatalf("parseFilter: %v", err)
+	}
+	if filter.AuthType != "jwt" {
+		t.Errorf("got AuthType=%q, want jwt", filter.AuthType)
+	}
+}
+
+func TestSplitFilterValues_SkipsEmptyTokens(t *testing.T) {
+	got := splitFilterValues("a,,b, ,c")
+	want := []string{"a", "b", "c"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestSplitFilterValues_EmptyInputReturnsNil(t *testing.T) {
+	if got := splitFilterValues(""); got != nil {
+		t.Errorf("got %v, want nil", got)
+	}
+}
+
+func TestParseFilter_SingleEventLeavesEventsUnset(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/audit?event=USER_DELETED", nil)
+
+	filter, err := parseFilter(req)
+	if err != nil {
+		t.Fatalf("parseFilter: %v", err)
+	}
+	if filter.Event != "USER_DELETED" {
+		t.Errorf("got Event=%q, want USER_DELETED", filter.Event)
+	}
+	if filter.Events != nil {
+		t.Errorf("got Events=%v, want nil", filter.Events)
+	}
+}
+
+func TestParseFilter_ParsesMultipleEventsAndResourcesAsCommaList(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/audit?event=USER_DELETED,USER_UPDATED,&resource=doc,doc", nil)
+
+	filter, err := parseFilter(req)
+	if err != nil {
+		t.Fatalf("parseFilter: %v", err)
+	}
+	wantEvents := []string{"USER_DELETED", "USER_UPDATED"}
+	if !reflect.DeepEqual(filter.Events, wantEvents) {
+		t.Errorf("got Events=%v, want %v", filter.Events, wantEvents)
+	}
+	wantResources := []string{"doc", "doc"}
+	if !reflect.DeepEqual(filter.Resources, wantResources) {
+		t.Errorf("got Resources=%v, want %v", filter.Resources, wantResources)
+	}
+}
+
+func TestCreateAuditLog_RejectsMalformedTenantIDWithFormattedMessage(t *testing.T) {
+	h := NewHandler(nil)
+	r := chi.NewRouter()
+	h.Routes(r)
+
+	body := `{"tenant_id":"Not A Valid Tenant!","event":"user.login"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/audit", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("got status %d, want 400; body %s", rec.Code, rec.Body.String())
+	}
+	want := "tenant_id must be a lowercase identifier"
+	if !strings.Contains(rec.Body.String(), want) {
+		t.Errorf("got body %s, want it to contain %q", rec.Body.String(), want)
+	}
+}
+
+// fakeGetService implements Service with GetAuditLog stubbed to whatever
+// a test configures, and every other method panicking — tests using it
+// only exercise the getAuditLog handler's status-code mapping.
+type fakeGetService struct {
+	Service
+	log domain.AuditLog
+	err error
+}
+
+func (f *fakeGetService) GetAuditLog(ctx context.Context, tenantID, id string, includeDeleted bool) (domain.AuditLog, error) {
+	return f.log, f.err
+}
+
+func TestGetAuditLog_ReturnsNotFoundForErrNotFound(t *testing.T) {
+	h := NewHandler(&fakeGetService{err: domain.ErrNotFound})
+	r := chi.NewRouter()
+	h.Routes(r)
+
+	id := uuid.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/audit/"+id.String()+"?tenant_id=tenant-a", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("got status %d, want 404", rec.Code)
+	}
+}
+
+func TestGetAuditLog_ReturnsInternalErrorForOtherFailures(t *testing.T) {
+	h := NewHandler(&fakeGetService{err: errors.New("connection reset by peer")})
+	r := chi.NewRouter()
+	h.Routes(r)
+
+	id := uuid.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/audit/"+id.String()+"?tenant_id=tenant-a", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("got status %d, want 500", rec.Code)
+	}
+}
+
+// fakeDeleteService implements Service with DeleteAuditLog stubbed to
+// whatever a test configures, and every other method panicking — tests
+// using it only exercise the deleteAuditLog handler's status-code
+// mapping.
+type fakeDeleteService struct {
+	Service
+	err error
+}
+
+func (f *fakeDeleteService) DeleteAuditLog(ctx context.Context, tenantID, id string) error {
+	return f.err
+}
+
+func TestDeleteAuditLog_ReturnsNotFoundForErrNotFound(t *testing.T) {
+	h := NewHandler(&fakeDeleteService{err: domain.ErrNotFound})
+	r := chi.NewRouter()
+	h.Routes(r)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/audit/"+uuid.New().String()+"?tenant_id=tenant-a", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("got status %d, want 404", rec.Code)
+	}
+}
+
+func TestDeleteAuditLog_ReturnsInternalErrorForOtherFailures(t *testing.T) {
+	h := NewHandler(&fakeDeleteService{err: errors.New("connection reset by peer")})
+	r := chi.NewRouter()
+	h.Routes(r)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/audit/"+uuid.New().String()+"?tenant_id=tenant-a", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("got status %d, want 500", rec.Code)
+	}
+}
+
+// fakeDeleteByFilterService implements Service with DeleteAuditLogsByFilter
+// stubbed to whatever a test configures, and every other method
+// panicking — tests using it only exercise the deleteAuditLogsByFilter
+// handler's status-code mapping.
+type fakeDeleteByFilterService struct {
+	Service
+	deleted int64
+	err     error
+}
+
+func (f *fakeDeleteByFilterService) DeleteAuditLogsByFilter(ctx context.Context, filter domain.Filter) (int64, error) {
+	return f.deleted, f.err
+}
+
+func TestDeleteAuditLogsByFilter_ReturnsDeletedCountOnSuccess(t *testing.T) {
+	h := NewHandler(&fakeDeleteByFilterService{deleted: 42})
+	r := chi.NewRouter()
+	h.Routes(r)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/audit?tenant_id=tenant-a&event=test.run", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got status %d, want 200; body %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), `"deleted":42`) {
+		t.Errorf("got body %s, want deleted:42", rec.Body.String())
+	}
+}
+
+func TestDeleteAuditLogsByFilter_ReturnsBadRequestForUnboundedFilter(t *testing.T) {
+	h := NewHandler(&fakeDeleteByFilterService{err: fmt.Errorf("%w: filter must include at least one bound besides tenant_id", domain.ErrInvalidArgument)})
+	r := chi.NewRouter()
+	h.Routes(r)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/audit?tenant_id=tenant-a", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("got status %d, want 400", rec.Code)
+	}
+}
+
+func TestDeleteAuditLogsByFilter_ReturnsInternalErrorForOtherFailures(t *testing.T) {
+	h := NewHandler(&fakeDeleteByFilterService{err: errors.New("connection reset by peer")})
+	r := chi.NewRouter()
+	h.Routes(r)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/audit?tenant_id=tenant-a&event=test.run", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("got status %d, want 500", rec.Code)
+	}
+}
+
+// fakeAppendMetaService implements Service with AppendMeta stubbed to
+// whatever a test configures, and every other method panicking — tests
+// using it only exercise the appendMeta handler's validation and
+// status-code mapping.
+type fakeAppendMetaService struct {
+	Service
+	log domain.AuditLog
+	err error
+}
+
+func (f *fakeAppendMetaService) AppendMeta(ctx context.Context, tenantID, id string, patch map[string]any) (domain.AuditLog, error) {
+	return f.log, f.err
+}
+
+func TestAppendMeta_ReturnsUpdatedLogOnSuccess(t *testing.T) {
+	id := uuid.New()
+	h := NewHandler(&fakeAppendMetaService{log: domain.AuditLog{ID: id, Meta: map[string]any{"correlation_id": "abc"}}})
+	r := chi.NewRouter()
+	h.Routes(r)
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/v1/audit/"+id.String()+"/meta?tenant_id=tenant-a", strings.NewReader(`{"correlation_id":"abc"}`))
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("got status %d, want 200", rec.Code)
+	}
+}
+
+func TestAppendMeta_ReturnsNotFoundForErrNotFound(t *testing.T) {
+	h := NewHandler(&fakeAppendMetaService{err: domain.ErrNotFound})
+	r := chi.NewRouter()
+	h.Routes(r)
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/v1/audit/"+uuid.New().String()+"/meta?tenant_id=tenant-a", strings.NewReader(`{"correlation_id":"abc"}`))
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("got status %d, want 404", rec.Code)
+	}
+}
+
+func TestAppendMeta_ReturnsInternalErrorForOtherFailures(t *testing.T) {
+	h := NewHandler(&fakeAppendMetaService{err: errors.New("connection reset by peer")})
+	r := chi.NewRouter()
+	h.Routes(r)
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/v1/audit/"+uuid.New().String()+"/meta?tenant_id=tenant-a", strings.NewReader(`{"correlation_id":"abc"}`))
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("got status %d, want 500", rec.Code)
+	}
+}
+
+func TestAppendMeta_RejectsInvalidJSON(t *testing.T) {
+	h := NewHandler(&fakeAppendMetaService{})
+	r := chi.NewRouter()
+	h.Routes(r)
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/v1/audit/"+uuid.New().String()+"/meta?tenant_id=tenant-a", strings.NewReader(`not json`))
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("got status %d, want 400", rec.Code)
+	}
+}
+
+func TestAppendMeta_RejectsNonObjectJSON(t *testing.T) {
+	h := NewHandler(&fakeAppendMetaService{})
+	r := chi.NewRouter()
+	h.Routes(r)
+
+	for _, body := range []string{`[1,2,3]`, `"a string"`, `42`} {
+		req := httptest.NewRequest(http.MethodPatch, "/api/v1/audit/"+uuid.New().String()+"/meta?tenant_id=tenant-a", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("body %q: got status %d, want 400", body, rec.Code)
+		}
+	}
+}
+
+func TestAppendMeta_RejectsEmptyObject(t *testing.T) {
+	h := NewHandler(&fakeAppendMetaService{})
+	r := chi.NewRouter()
+	h.Routes(r)
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/v1/audit/"+uuid.New().String()+"/meta?tenant_id=tenant-a", strings.NewReader(`{}`))
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("got status %d, want 400", rec.Code)
+	}
+}
+
+// jwtRestrictedContext simulates a JWT carrying a "tenant_id" claim,
+// which JWTResolver.Resolve turns into a single-tenant AllowedTenants
+// (see auth.JWTResolver), without needing to mint and verify a real
+// token for a test that only cares about the resulting Principal.
+func jwtRestrictedContext(ctx context.Context, tenantID string) context.Context {
+	return auth.WithContext(ctx, auth.Context{
+		Principal: auth.Principal{ID: "user-1", AllowedTenants: []string{tenantID}},
+		Type:      auth.TypeJWT,
+	})
+}
+
+func TestParseFilter_AutoScopesToJWTTenantWhenOmitted(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/audit", nil)
+	req = req.WithContext(jwtRestrictedContext(req.Context(), "tenant-a"))
+
+	filter, err := parseFilter(req)
+	if err != nil {
+		t.Fatalf("parseFilter: %v", err)
+	}
+	if filter.TenantID != "tenant-a" {
+		t.Errorf("got TenantID=%q, want tenant-a", filter.TenantID)
+	}
+}
+
+func TestParseFilter_RejectsCrossTenantQueryForJWTCaller(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/audit?tenant_id=tenant-b", nil)
+	req = req.WithContext(jwtRestrictedContext(req.Context(), "tenant-a"))
+
+	_, err := parseFilter(req)
+	if !errors.Is(err, domain.ErrTenantNotAllowed) {
+		t.Errorf("parseFilter error = %v, want domain.ErrTenantNotAllowed", err)
+	}
+}
+
+func TestParseFilter_AllowsOwnTenantQueryForJWTCaller(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/audit?tenant_id=tenant-a", nil)
+	req = req.WithContext(jwtRestrictedContext(req.Context(), "tenant-a"))
+
+	filter, err := parseFilter(req)
+	if err != nil {
+		t.Fatalf("parseFilter: %v", err)
+	}
+	if filter.TenantID != "tenant-a" {
+		t.Errorf("got TenantID=%q, want tenant-a", filter.TenantID)
+	}
+}
+
+func TestResolveTenantID_AutoScopesToJWTTenantWhenOmitted(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/audit/latest", nil)
+	req = req.WithContext(jwtRestrictedContext(req.Context(), "tenant-a"))
+
+	if got := resolveTenantID(req); got != "tenant-a" {
+		t.Errorf("resolveTenantID = %q, want tenant-a", got)
+	}
+}
+
+func TestEtagFor_StableForSameRecord(t *testing.T) {
+	log := domain.AuditLog{ID: uuid.New(), Sequence: 42}
+
+	if etagFor(log) != etagFor(log) {
+		t.Error("expected etagFor to be stable for the same record")
+	}
+}
+
+func TestEtagFor_DiffersBySequence(t *testing.T) {
+	id := uuid.New()
+
+	if etagFor(domain.AuditLog{ID: id, Sequence: 1}) == etagFor(domain.AuditLog{ID: id, Sequence: 2}) {
+		t.Error("expected etagFor to differ when sequence differs")
+	}
+}
+
+// fakeBatchService implements Service with CreateAuditLogsBatch stubbed to
+// create every log it's handed, so tests can assert on which entries the
+// handler forwarded to it and which it rejected before ever calling it.
+type fakeBatchService struct {
+	Service
+	gotLogs []domain.AuditLog
+}
+
+func (f *fakeBatchService) CreateAuditLogsBatch(ctx context.Context, logs []domain.AuditLog, allOrNothing bool) (domain.BatchCreateResult, error) {
+	f.gotLogs = logs
+	created := make([]domain.AuditLog, len(logs))
+	copy(created, logs)
+	return domain.BatchCreateResult{Created: created}, nil
+}
+
+func TestCreateAuditLogsBatch_RequestLevelValidationFailureDoesNotAbortOtherEntries(t *testing.T) {
+	svc := &fakeBatchService{}
+	h := NewHandler(svc)
+	r := chi.NewRouter()
+	h.Routes(r)
+
+	body := `[{"tenant_id":"tenant-a","actor":"alice"},{"tenant_id":"tenant-a","event":"user.login"}]`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/audit/batch", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusMultiStatus {
+		t.Fatalf("got status %d, want 207; body %s", rec.Code, rec.Body.String())
+	}
+	if len(svc.gotLogs) != 1 || svc.gotLogs[0].Event != "user.login" {
+		t.Errorf("expected only the valid entry to reach the service, got %+v", svc.gotLogs)
+	}
+
+	var resp createAuditLogsBatchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	if len(resp.Failed) != 1 || resp.Failed[0].Index != 0 {
+		t.Errorf("got Failed=%+v, want one failure at index 0", resp.Failed)
+	}
+	if resp.CreatedCount != 1 {
+		t.Errorf("got CreatedCount=%d, want 1", resp.CreatedCount)
+	}
+}
+
+func TestCreateAuditLogsBatch_AllOrNothingAbortsBeforeCallingServiceOnRequestLevelFailure(t *testing.T) {
+	svc := &fakeBatchService{}
+	h := NewHandler(svc)
+	r := chi.NewRouter()
+	h.Routes(r)
+
+	body := `[{"tenant_id":"tenant-a","actor":"alice"},{"tenant_id":"tenant-a","event":"user.login"}]`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/audit/batch?all_or_nothing=true", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("got status %d, want 422; body %s", rec.Code, rec.Body.String())
+	}
+	if svc.gotLogs != nil {
+		t.Errorf("expected the service not to be called, got %+v", svc.gotLogs)
+	}
+}
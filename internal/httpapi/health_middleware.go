@@ -0,0 +1,24 @@
+package httpapi
+
+import "net/http"
+
+// HealthChecker reports the last-known health of a dependency.
+type HealthChecker interface {
+	Healthy() bool
+}
+
+// RejectUnhealthyDB returns 503 for every request while checker reports
+// the database as unhealthy, instead of letting each handler fail
+// individually against a dead pool. Intended to be mounted above the
+// readiness/liveness endpoints so probes keep working.
+func RejectUnhealthyDB(checker HealthChecker) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !checker.Healthy() {
+				http.Error(w, "database unavailable", http.StatusServiceUnavailable)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
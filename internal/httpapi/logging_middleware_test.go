@@ -0,0 +1,57 @@
+package httpapi
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/config"
+	"github.com/naveenkumar2412/audit-log-service/internal/logging"
+)
+
+func TestRequestLogging_AttachesRequestIDToContextLogger(t *testing.T) {
+	var buf bytes.Buffer
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+
+	handler := RequestLogging(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logging.FromContext(r.Context()).Info("inside handler")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Header().Get("X-Request-ID") == "" {
+		t.Error("expected X-Request-ID response header to be set")
+	}
+	if !strings.Contains(buf.String(), "request_id=") {
+		t.Errorf("log output missing request_id field: %s", buf.String())
+	}
+}
+
+func TestRequestLogging_DowngradesBelowEffectiveLevel(t *testing.T) {
+	var buf bytes.Buffer
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+
+	adaptive := NewAdaptiveLevel(config.AdaptiveLogConfig{Enabled: true, DowngradeLevel: "warn"})
+	adaptive.level.Set(slog.LevelWarn)
+
+	handler := RequestLogging(adaptive)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logging.FromContext(r.Context()).Info("should be filtered out")
+		logging.FromContext(r.Context()).Warn("should still appear")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if strings.Contains(buf.String(), "should be filtered out") {
+		t.Errorf("expected info log to be filtered while downgraded, got: %s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "should still appear") {
+		t.Errorf("expected warn log to still appear, got: %s", buf.String())
+	}
+}
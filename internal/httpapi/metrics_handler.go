@@ -0,0 +1,148 @@
+package httpapi
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/auth"
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+)
+
+// defaultMetricsCacheTTL is used until WithMetricsCacheTTL overrides it.
+const defaultMetricsCacheTTL = 30 * time.Second
+
+// statsCache caches a tenant's Prometheus-formatted stats for a short
+// window, since GET /api/v1/audit/metrics is expected to be polled by a
+// scraper on a regular interval.
+type statsCache struct {
+	ttl time.Duration
+
+	mu       sync.Mutex
+	byTenant map[string]cachedMetrics
+}
+
+type cachedMetrics struct {
+	body    []byte
+	expires time.Time
+}
+
+func newStatsCache(ttl time.Duration) *statsCache {
+	return &statsCache{ttl: ttl, byTenant: map[string]cachedMetrics{}}
+}
+
+func (c *statsCache) get(tenantID string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.byTenant[tenantID]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.body, true
+}
+
+func (c *statsCache) set(tenantID string, body []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.byTenant[tenantID] = cachedMetrics{body: body, expires: time.Now().Add(c.ttl)}
+}
+
+// WithMetricsCacheTTL overrides how long GET /api/v1/audit/metrics caches
+// a tenant's computed stats, and returns h for chaining.
+func (h *Handler) WithMetricsCacheTTL(ttl time.Duration) *Handler {
+	h.metricsCache = newStatsCache(ttl)
+	return h
+}
+
+// getMetrics returns the caller's tenant stats in Prometheus exposition
+// format, distinct from any service-wide /metrics endpoint: it requires
+// authentication and, for a principal restricted to specific tenants (see
+// auth.TenantAllowed), only ever exposes one of its own tenants' data.
+//
+// @Summary Get tenant stats
+// @Description Returns Prometheus exposition format, not JSON, since this
+// @Description is scraped as a per-tenant metrics endpoint.
+// @Tags stats
+// @Produce plain
+// @Param tenant_id query string false "Tenant ID (required unless resolvable from auth context)"
+// @Success 200 {string} string "Prometheus exposition format"
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Router /audit/metrics [get]
+func (h *Handler) getMetrics(w http.ResponseWriter, r *http.Request) {
+	if _, ok := auth.FromContext(r.Context()); !ok {
+		writeError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	tenantID := resolveTenantID(r)
+	if tenantID == "" {
+		writeError(w, http.StatusBadRequest, "tenant_id is required")
+		return
+	}
+	if !auth.TenantAllowed(r.Context(), tenantID) {
+		writeError(w, http.StatusForbidden, domain.ErrTenantNotAllowed.Error())
+		return
+	}
+
+	if body, ok := h.metricsCache.get(tenantID); ok {
+		writePrometheusMetrics(w, body)
+		return
+	}
+
+	stats, err := h.svc.GetStats(r.Context(), tenantID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to get stats")
+		return
+	}
+
+	body := formatPrometheusMetrics(stats)
+	h.metricsCache.set(tenantID, body)
+	writePrometheusMetrics(w, body)
+}
+
+func writePrometheusMetrics(w http.ResponseWriter, body []byte) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+func formatPrometheusMetrics(stats domain.TenantStats) []byte {
+	var sb strings.Builder
+
+	sb.WriteString("# HELP audit_log_events_total Total audit log events ingested for the tenant, by event type.\n")
+	sb.WriteString("# TYPE audit_log_events_total counter\n")
+	events := make([]string, 0, len(stats.EventCounts))
+	for event := range stats.EventCounts {
+		events = append(events, event)
+	}
+	sort.Strings(events)
+	for _, event := range events {
+		fmt.Fprintf(&sb, "audit_log_events_total{event=%q} %d\n", event, stats.EventCounts[event])
+	}
+
+	sb.WriteString("# HELP audit_log_events_by_resource_total Total audit log events ingested for the tenant, by resource.\n")
+	sb.WriteString("# TYPE audit_log_events_by_resource_total counter\n")
+	resources := make([]string, 0, len(stats.ResourceCounts))
+	for resource := range stats.ResourceCounts {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+	for _, resource := range resources {
+		fmt.Fprintf(&sb, "audit_log_events_by_resource_total{resource=%q} %d\n", resource, stats.ResourceCounts[resource])
+	}
+
+	sb.WriteString("# HELP audit_log_error_events_total Total audit log events classified as errors for the tenant.\n")
+	sb.WriteString("# TYPE audit_log_error_events_total counter\n")
+	fmt.Fprintf(&sb, "audit_log_error_events_total %d\n", stats.ErrorEvents)
+
+	sb.WriteString("# HELP audit_log_error_rate Fraction of the tenant's events classified as errors.\n")
+	sb.WriteString("# TYPE audit_log_error_rate gauge\n")
+	fmt.Fprintf(&sb, "audit_log_error_rate %g\n", stats.ErrorRate)
+
+	return []byte(sb.String())
+}
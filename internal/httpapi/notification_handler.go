@@ -0,0 +1,54 @@
+package httpapi
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/auth"
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+)
+
+// NotificationService is the business-logic interface the notification
+// handler depends on.
+type NotificationService interface {
+	ListFailedNotifications(ctx context.Context, tenantID string) ([]domain.FailedNotification, error)
+}
+
+// NotificationHandler exposes the failed-notification dead-letter queue
+// HTTP API.
+type NotificationHandler struct {
+	svc NotificationService
+}
+
+// NewNotificationHandler returns a NotificationHandler backed by the given
+// service.
+func NewNotificationHandler(svc NotificationService) *NotificationHandler {
+	return &NotificationHandler{svc: svc}
+}
+
+// Routes registers the notification endpoints on r.
+func (h *NotificationHandler) Routes(r chi.Router) {
+	r.Get("/api/v1/notifications/failed", h.listFailed)
+}
+
+func (h *NotificationHandler) listFailed(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.URL.Query().Get("tenant_id")
+	if tenantID == "" {
+		writeError(w, http.StatusBadRequest, "tenant_id is required")
+		return
+	}
+	if !auth.TenantAllowed(r.Context(), tenantID) {
+		writeError(w, http.StatusForbidden, domain.ErrTenantNotAllowed.Error())
+		return
+	}
+
+	failed, err := h.svc.ListFailedNotifications(r.Context(), tenantID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to list failed notifications")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, failed)
+}
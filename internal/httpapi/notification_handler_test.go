@@ -0,0 +1,78 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/auth"
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+)
+
+type fakeNotificationService struct {
+	tenantID string
+	list     []domain.FailedNotification
+}
+
+func (f *fakeNotificationService) ListFailedNotifications(ctx context.Context, tenantID string) ([]domain.FailedNotification, error) {
+	f.tenantID = tenantID
+	return f.list, nil
+}
+
+func TestNotificationHandler_ListFailedRequiresTenantID(t *testing.T) {
+	h := NewNotificationHandler(&fakeNotificationService{})
+	r := chi.NewRouter()
+	h.Routes(r)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/notifications/failed", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("got status %d, want 400", rec.Code)
+	}
+}
+
+func TestNotificationHandler_ListFailedRejectsDisallowedTenant(t *testing.T) {
+	h := NewNotificationHandler(&fakeNotificationService{})
+	r := chi.NewRouter()
+	h.Routes(r)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/notifications/failed?tenant_id=tenant-b", nil)
+	ctx := auth.WithContext(req.Context(), auth.Context{Principal: auth.Principal{AllowedTenants: []string{"tenant-a"}}})
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req.WithContext(ctx))
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("got status %d, want 403", rec.Code)
+	}
+}
+
+func TestNotificationHandler_ListFailedReturnsBacklog(t *testing.T) {
+	svc := &fakeNotificationService{list: []domain.FailedNotification{{Channel: "https://example.com/hook"}}}
+	h := NewNotificationHandler(svc)
+	r := chi.NewRouter()
+	h.Routes(r)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/notifications/failed?tenant_id=tenant-a", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got status %d, want 200", rec.Code)
+	}
+	if svc.tenantID != "tenant-a" {
+		t.Errorf("got tenantID=%q, want tenant-a", svc.tenantID)
+	}
+	var got []domain.FailedNotification
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(got) != 1 || got[0].Channel != "https://example.com/hook" {
+		t.Errorf("got %+v", got)
+	}
+}
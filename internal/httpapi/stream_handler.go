@@ -0,0 +1,85 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/auth"
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+	"github.com/naveenkumar2412/audit-log-service/internal/stream"
+)
+
+// StreamHub is the pub/sub backend the stream handler subscribes to,
+// satisfied by *stream.Hub.
+type StreamHub interface {
+	Subscribe(filter stream.Filter) *stream.Subscription
+}
+
+// StreamHandler exposes a live Server-Sent Events feed of newly created
+// audit logs (GET /api/v1/audit/stream), filtered per connection by
+// tenant (required, enforced the same way as Handler.listAuditLogs) and
+// optionally event/resource. What it streams never touches the store —
+// it's whatever AuditService.CreateAuditLog published to hub via
+// stream.Hub (wired in as a service.Notifier; see service.Notifiers to
+// combine it with a webhook notifier).
+type StreamHandler struct {
+	hub StreamHub
+}
+
+// NewStreamHandler returns a StreamHandler backed by the given hub.
+func NewStreamHandler(hub StreamHub) *StreamHandler {
+	return &StreamHandler{hub: hub}
+}
+
+// Routes registers the stream endpoint on r.
+func (h *StreamHandler) Routes(r chi.Router) {
+	r.Get("/api/v1/audit/stream", h.stream)
+}
+
+func (h *StreamHandler) stream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	tenantID := resolveTenantID(r)
+	if tenantID == "" {
+		writeError(w, http.StatusBadRequest, "tenant_id is required")
+		return
+	}
+	if !auth.TenantAllowed(r.Context(), tenantID) {
+		writeError(w, http.StatusForbidden, domain.ErrTenantNotAllowed.Error())
+		return
+	}
+
+	q := r.URL.Query()
+	sub := h.hub.Subscribe(stream.Filter{TenantID: tenantID, Event: q.Get("event"), Resource: q.Get("resource")})
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case log := <-sub.Events:
+			if dropped := sub.Dropped(); dropped > 0 {
+				fmt.Fprintf(w, "event: dropped\ndata: {\"count\":%d}\n\n", dropped)
+			}
+			data, err := json.Marshal(log)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: audit_log\ndata: %s\n\n", data)
+			flusher.Flush()
+		}
+	}
+}
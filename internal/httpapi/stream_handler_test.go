@@ -0,0 +1,74 @@
+package httpapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/auth"
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+	"github.com/naveenkumar2412/audit-log-service/internal/stream"
+)
+
+func TestStreamAuditLogs_RequiresTenantID(t *testing.T) {
+	h := NewStreamHandler(stream.NewHub())
+	r := chi.NewRouter()
+	h.Routes(r)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/audit/stream", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("got status %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestStreamAuditLogs_ForbiddenForDisallowedTenant(t *testing.T) {
+	h := NewStreamHandler(stream.NewHub())
+	r := chi.NewRouter()
+	h.Routes(r)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/audit/stream?tenant_id=tenant-a", nil)
+	ctx := auth.WithContext(req.Context(), auth.Context{Principal: auth.Principal{AllowedTenants: []string{"tenant-b"}}})
+	req = req.WithContext(ctx)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("got status %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
+
+func TestStreamAuditLogs_DeliversMatchingEvent(t *testing.T) {
+	hub := stream.NewHub()
+	h := NewStreamHandler(hub)
+	r := chi.NewRouter()
+	h.Routes(r)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/audit/stream?tenant_id=tenant-a", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		r.ServeHTTP(rec, req)
+		close(done)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	hub.Notify(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "login"})
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	<-done
+
+	if !strings.Contains(rec.Body.String(), "event: audit_log") || !strings.Contains(rec.Body.String(), `"event":"login"`) {
+		t.Errorf("got body %q", rec.Body.String())
+	}
+}
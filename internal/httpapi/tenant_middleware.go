@@ -0,0 +1,62 @@
+package httpapi
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/config"
+)
+
+type tenantContextKey struct{}
+
+// TenantFromContext returns the tenant ID extracted by TenantFromHeader,
+// if any.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenantID, ok := ctx.Value(tenantContextKey{}).(string)
+	return tenantID, ok
+}
+
+// TenantFromHeader reads the tenant ID from cfg.HeaderName and attaches it
+// to the request context, but only when the request's direct peer address
+// falls within one of cfg.TrustedProxyCIDRs. The header is stripped of any
+// meaning for every other caller, so a client can't impersonate a tenant
+// by setting the header itself unless it is connecting from a trusted
+// gateway's address.
+func TenantFromHeader(cfg config.TenantHeaderConfig) func(http.Handler) http.Handler {
+	var trusted []*net.IPNet
+	for _, cidr := range cfg.TrustedProxyCIDRs {
+		if _, ipnet, err := net.ParseCIDR(cidr); err == nil {
+			trusted = append(trusted, ipnet)
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		if !cfg.Enabled {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if tenantID := r.Header.Get(cfg.HeaderName); tenantID != "" && isTrustedPeer(r.RemoteAddr, trusted) {
+				r = r.WithContext(context.WithValue(r.Context(), tenantContextKey{}, tenantID))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func isTrustedPeer(remoteAddr string, trusted []*net.IPNet) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, ipnet := range trusted {
+		if ipnet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
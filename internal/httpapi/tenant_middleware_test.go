@@ -0,0 +1,63 @@
+package httpapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/config"
+)
+
+func TestTenantFromHeader_TrustedPeerSetsTenant(t *testing.T) {
+	cfg := config.TenantHeaderConfig{Enabled: true, HeaderName: "X-Tenant-ID", TrustedProxyCIDRs: []string{"10.0.0.0/8"}}
+
+	var got string
+	handler := TenantFromHeader(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = TenantFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Tenant-ID", "tenant-a")
+	req.RemoteAddr = "10.1.2.3:54321"
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if got != "tenant-a" {
+		t.Errorf("got tenant %q, want tenant-a", got)
+	}
+}
+
+func TestTenantFromHeader_UntrustedPeerIgnored(t *testing.T) {
+	cfg := config.TenantHeaderConfig{Enabled: true, HeaderName: "X-Tenant-ID", TrustedProxyCIDRs: []string{"10.0.0.0/8"}}
+
+	var ok bool
+	handler := TenantFromHeader(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, ok = TenantFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Tenant-ID", "tenant-a")
+	req.RemoteAddr = "203.0.113.5:54321"
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if ok {
+		t.Error("expected no tenant in context for untrusted peer")
+	}
+}
+
+func TestTenantFromHeader_Disabled(t *testing.T) {
+	cfg := config.TenantHeaderConfig{Enabled: false}
+
+	var ok bool
+	handler := TenantFromHeader(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, ok = TenantFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Tenant-ID", "tenant-a")
+	req.RemoteAddr = "10.1.2.3:54321"
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if ok {
+		t.Error("expected no tenant in context when disabled")
+	}
+}
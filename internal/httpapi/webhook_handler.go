@@ -0,0 +1,162 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/auth"
+	"github.com/naveenkumar2412/audit-log-service/internal/config"
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+)
+
+// WebhookService is the business-logic interface the webhook handler
+// depends on.
+type WebhookService interface {
+	Subscribe(ctx context.Context, sub domain.WebhookSubscription) (domain.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context, tenantID string) ([]domain.WebhookSubscription, error)
+	Unsubscribe(ctx context.Context, tenantID string, id uuid.UUID) error
+}
+
+// WebhookHandler exposes the webhook subscription HTTP API.
+type WebhookHandler struct {
+	svc           WebhookService
+	routePolicies map[string]config.RoutePolicy
+}
+
+// NewWebhookHandler returns a WebhookHandler backed by the given service.
+func NewWebhookHandler(svc WebhookService) *WebhookHandler {
+	return &WebhookHandler{svc: svc}
+}
+
+// WithAuthPolicies overrides the default auth requirement (attempted but
+// not required) for specific routes, keyed by "METHOD path" exactly as
+// registered in Routes (e.g. "POST /api/v1/webhooks"). See Handler's
+// method of the same name.
+func (h *WebhookHandler) WithAuthPolicies(policies map[string]config.RoutePolicy) *WebhookHandler {
+	h.routePolicies = policies
+	return h
+}
+
+// Routes registers the webhook subscription endpoints on r.
+func (h *WebhookHandler) Routes(r chi.Router) {
+	h.method(r, http.MethodPost, "/api/v1/webhooks", h.subscribe)
+	h.method(r, http.MethodGet, "/api/v1/webhooks", h.list)
+	h.method(r, http.MethodDelete, "/api/v1/webhooks/{id}", h.unsubscribe)
+}
+
+// method registers handler for method+pattern, wrapping it with the
+// configured RoutePolicy for that route, if any (see WithAuthPolicies).
+func (h *WebhookHandler) method(r chi.Router, method, pattern string, handler http.HandlerFunc) {
+	policy, ok := h.routePolicies[method+" "+pattern]
+	if !ok {
+		r.Method(method, pattern, handler)
+		return
+	}
+	r.With(auth.Require(toAuthPolicy(policy))).Method(method, pattern, handler)
+}
+
+type subscribeRequest struct {
+	TenantID           string   `json:"tenant_id"`
+	URL                string   `json:"url"`
+	Events             []string `json:"events,omitempty"`
+	Secret             string   `json:"secret,omitempty"`
+	Enabled            *bool    `json:"enabled,omitempty"`
+	BatchMaxSize       int      `json:"batch_max_size,omitempty"`
+	BatchLingerSeconds int      `json:"batch_linger_seconds,omitempty"`
+}
+
+func (h *WebhookHandler) subscribe(w http.ResponseWriter, r *http.Request) {
+	var req subscribeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.TenantID == "" {
+		if tenantID, ok := TenantFromContext(r.Context()); ok {
+			req.TenantID = tenantID
+		}
+	}
+	if !auth.TenantAllowed(r.Context(), req.TenantID) {
+		writeError(w, http.StatusForbidden, domain.ErrTenantNotAllowed.Error())
+		return
+	}
+
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	sub, err := h.svc.Subscribe(r.Context(), domain.WebhookSubscription{
+		TenantID:           req.TenantID,
+		URL:                req.URL,
+		Events:             req.Events,
+		Secret:             req.Secret,
+		Enabled:            enabled,
+		BatchMaxSize:       req.BatchMaxSize,
+		BatchLingerSeconds: req.BatchLingerSeconds,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidArgument) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to create webhook subscription")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, sub)
+}
+
+func (h *WebhookHandler) list(w http.ResponseWriter, r *http.Request) {
+	tenantID := resolveTenantID(r)
+	if tenantID == "" {
+		writeError(w, http.StatusBadRequest, "tenant_id is required")
+		return
+	}
+	if !auth.TenantAllowed(r.Context(), tenantID) {
+		writeError(w, http.StatusForbidden, domain.ErrTenantNotAllowed.Error())
+		return
+	}
+
+	subs, err := h.svc.ListSubscriptions(r.Context(), tenantID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to list webhook subscriptions")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, subs)
+}
+
+func (h *WebhookHandler) unsubscribe(w http.ResponseWriter, r *http.Request) {
+	tenantID := resolveTenantID(r)
+	if tenantID == "" {
+		writeError(w, http.StatusBadRequest, "tenant_id is required")
+		return
+	}
+	if !auth.TenantAllowed(r.Context(), tenantID) {
+		writeError(w, http.StatusForbidden, domain.ErrTenantNotAllowed.Error())
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	if err := h.svc.Unsubscribe(r.Context(), tenantID, id); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "webhook subscription not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to delete webhook subscription")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,119 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/auth"
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+)
+
+type fakeWebhookService struct {
+	subscribed domain.WebhookSubscription
+	listedFor  string
+	list       []domain.WebhookSubscription
+	deletedFor string
+	deletedID  uuid.UUID
+}
+
+func (f *fakeWebhookService) Subscribe(ctx context.Context, sub domain.WebhookSubscription) (domain.WebhookSubscription, error) {
+	f.subscribed = sub
+	return sub, nil
+}
+
+func (f *fakeWebhookService) ListSubscriptions(ctx context.Context, tenantID string) ([]domain.WebhookSubscription, error) {
+	f.listedFor = tenantID
+	return f.list, nil
+}
+
+func (f *fakeWebhookService) Unsubscribe(ctx context.Context, tenantID string, id uuid.UUID) error {
+	f.deletedFor = tenantID
+	f.deletedID = id
+	return nil
+}
+
+func TestWebhookHandler_SubscribeRejectsDisallowedTenant(t *testing.T) {
+	svc := &fakeWebhookService{}
+	h := NewWebhookHandler(svc)
+	r := chi.NewRouter()
+	h.Routes(r)
+
+	body, _ := json.Marshal(subscribeRequest{TenantID: "tenant-b", URL: "https://attacker.example/sink"})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks", bytes.NewReader(body))
+	ctx := auth.WithContext(req.Context(), auth.Context{Principal: auth.Principal{AllowedTenants: []string{"tenant-a"}}})
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req.WithContext(ctx))
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("got status %d, want 403", rec.Code)
+	}
+	if svc.subscribed.TenantID != "" {
+		t.Errorf("expected Subscribe not to be called, got %+v", svc.subscribed)
+	}
+}
+
+func TestWebhookHandler_SubscribeAllowsOwnTenant(t *testing.T) {
+	svc := &fakeWebhookService{}
+	h := NewWebhookHandler(svc)
+	r := chi.NewRouter()
+	h.Routes(r)
+
+	body, _ := json.Marshal(subscribeRequest{TenantID: "tenant-a", URL: "https://example.com/hook"})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks", bytes.NewReader(body))
+	ctx := auth.WithContext(req.Context(), auth.Context{Principal: auth.Principal{AllowedTenants: []string{"tenant-a"}}})
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req.WithContext(ctx))
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("got status %d, want 201", rec.Code)
+	}
+	if svc.subscribed.TenantID != "tenant-a" {
+		t.Errorf("got subscribed tenant %q, want tenant-a", svc.subscribed.TenantID)
+	}
+}
+
+func TestWebhookHandler_ListRejectsDisallowedTenant(t *testing.T) {
+	svc := &fakeWebhookService{}
+	h := NewWebhookHandler(svc)
+	r := chi.NewRouter()
+	h.Routes(r)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/webhooks?tenant_id=tenant-b", nil)
+	ctx := auth.WithContext(req.Context(), auth.Context{Principal: auth.Principal{AllowedTenants: []string{"tenant-a"}}})
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req.WithContext(ctx))
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("got status %d, want 403", rec.Code)
+	}
+	if svc.listedFor != "" {
+		t.Errorf("expected ListSubscriptions not to be called, got tenant %q", svc.listedFor)
+	}
+}
+
+func TestWebhookHandler_UnsubscribeRejectsDisallowedTenant(t *testing.T) {
+	svc := &fakeWebhookService{}
+	h := NewWebhookHandler(svc)
+	r := chi.NewRouter()
+	h.Routes(r)
+
+	id := uuid.New()
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/webhooks/"+id.String()+"?tenant_id=tenant-b", nil)
+	ctx := auth.WithContext(req.Context(), auth.Context{Principal: auth.Principal{AllowedTenants: []string{"tenant-a"}}})
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req.WithContext(ctx))
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("got status %d, want 403", rec.Code)
+	}
+	if svc.deletedFor != "" {
+		t.Errorf("expected Unsubscribe not to be called, got tenant %q", svc.deletedFor)
+	}
+}
@@ -0,0 +1,21 @@
+// Package ingestmode carries whether the current create request is an
+// explicit historical backfill/import rather than live ingestion, which
+// relaxes timestamp-skew validation (see
+// service.AuditService.CreateAuditLog and config.TimestampConfig).
+package ingestmode
+
+import "context"
+
+type contextKey struct{}
+
+// WithContext attaches importMode to ctx.
+func WithContext(ctx context.Context, importMode bool) context.Context {
+	return context.WithValue(ctx, contextKey{}, importMode)
+}
+
+// FromContext reports whether ctx is marked as a backfill/import request.
+// Unset contexts default to false (live ingestion).
+func FromContext(ctx context.Context) bool {
+	importMode, _ := ctx.Value(contextKey{}).(bool)
+	return importMode
+}
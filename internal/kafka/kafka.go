@@ -0,0 +1,69 @@
+// Package kafka implements the optional Kafka publisher that mirrors
+// newly created audit logs onto a topic for downstream consumers (see
+// config.KafkaConfig).
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	segmentio "github.com/segmentio/kafka-go"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+	"github.com/naveenkumar2412/audit-log-service/internal/logging"
+	"github.com/naveenkumar2412/audit-log-service/internal/metrics"
+)
+
+// Publisher publishes audit logs to a Kafka topic, keyed by tenant ID so
+// a given tenant's events stay ordered on the same partition. Writes are
+// async (see segmentio/kafka-go's Writer.Async): Publish hands the
+// message to the writer's internal batching and returns without waiting
+// for an ack, and a failed delivery is reported through Completion
+// instead of Publish's return value. Close flushes any batches still
+// pending and must be called on shutdown.
+type Publisher struct {
+	writer *segmentio.Writer
+}
+
+// New returns a Publisher that writes to topic on the given brokers.
+// Delivery failures reported asynchronously by the underlying writer are
+// logged and counted in metrics.KafkaPublishErrorsTotal.
+func New(brokers []string, topic string) *Publisher {
+	w := &segmentio.Writer{
+		Addr:     segmentio.TCP(brokers...),
+		Topic:    topic,
+		Balancer: &segmentio.Hash{},
+		Async:    true,
+		Completion: func(messages []segmentio.Message, err error) {
+			if err != nil {
+				logging.FromContext(context.Background()).Error("kafka: async publish failed", "error", err, "messages", len(messages))
+				metrics.KafkaPublishErrorsTotal.Add(float64(len(messages)))
+			}
+		},
+	}
+	return &Publisher{writer: w}
+}
+
+// Publish serializes log as JSON and writes it to the configured topic,
+// keyed by log.TenantID. Because the writer is async, a nil error only
+// means the message was accepted for batching, not that it was
+// delivered; delivery failures surface via the Completion callback
+// passed to New instead.
+func (p *Publisher) Publish(ctx context.Context, log domain.AuditLog) error {
+	body, err := json.Marshal(log)
+	if err != nil {
+		return fmt.Errorf("kafka: marshal record: %w", err)
+	}
+	return p.writer.WriteMessages(ctx, segmentio.Message{
+		Key:   []byte(log.TenantID),
+		Value: body,
+	})
+}
+
+// Close flushes any batches still pending and closes the underlying
+// connections. It blocks until Completion has been called for every
+// in-flight message.
+func (p *Publisher) Close() error {
+	return p.writer.Close()
+}
@@ -0,0 +1,35 @@
+// Package logging carries a request-scoped structured logger through
+// context.Context, so a request ID (and tenant/user once known) attached
+// at the HTTP layer automatically shows up on every log line the
+// service and repository layers emit for that request, with no manual
+// field-passing.
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+type contextKey struct{}
+
+// WithContext attaches logger to ctx.
+func WithContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger attached to ctx, or slog.Default() if
+// none was attached.
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// With returns a context whose logger is FromContext(ctx) with args
+// added, so callers can enrich the correlation fields (e.g. once the
+// tenant or principal becomes known) without needing a reference to the
+// original logger.
+func With(ctx context.Context, args ...any) context.Context {
+	return WithContext(ctx, FromContext(ctx).With(args...))
+}
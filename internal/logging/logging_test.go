@@ -0,0 +1,29 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestWithContext_AddsFieldsVisibleInFromContext(t *testing.T) {
+	var buf bytes.Buffer
+	base := slog.New(slog.NewTextHandler(&buf, nil))
+
+	ctx := WithContext(context.Background(), base)
+	ctx = With(ctx, "request_id", "req-1")
+
+	FromContext(ctx).Info("hello")
+
+	if !strings.Contains(buf.String(), "request_id=req-1") {
+		t.Errorf("log output missing request_id field: %s", buf.String())
+	}
+}
+
+func TestFromContext_DefaultsWhenUnset(t *testing.T) {
+	if FromContext(context.Background()) == nil {
+		t.Error("expected a non-nil default logger")
+	}
+}
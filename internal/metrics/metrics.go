@@ -0,0 +1,128 @@
+// Package metrics holds the service's Prometheus collectors and the
+// middleware/handler that expose them, so both the HTTP and service
+// layers can record against the same registry without importing each
+// other.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry is the registry every collector in this package is registered
+// against. It's a dedicated registry, not prometheus.DefaultRegisterer,
+// so Handler only ever exposes this service's own metrics.
+var Registry = prometheus.NewRegistry()
+
+var (
+	// AuditLogsCreatedTotal counts every audit log AuditService successfully
+	// creates (via CreateAuditLog, CreateAuditLogConditional,
+	// CreateAuditLogIdempotent or CreateAuditLogsBatch), labeled by tenant
+	// and event so a specific
+	// tenant/event's volume can be graphed without scraping logs.
+	AuditLogsCreatedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "audit_logs_created_total",
+		Help: "Total number of audit logs created, labeled by tenant and event.",
+	}, []string{"tenant", "event"})
+
+	// AuditLogsDeletedTotal counts every audit log removed via PurgeTenant
+	// or DeleteAuditLogsByFilter, labeled by tenant.
+	AuditLogsDeletedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "audit_logs_deleted_total",
+		Help: "Total number of audit logs deleted, labeled by tenant.",
+	}, []string{"tenant"})
+
+	// RequestDuration buckets HTTP request latency by method, the route's
+	// path template (not the raw URL, so path params like {id} don't
+	// explode cardinality), and response status code.
+	RequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency in seconds, labeled by method, path template, and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "path", "status"})
+
+	// DBPoolInUseConnections reports the pgx pool's current in-use
+	// connection count. Set via SetDBPoolStatsFunc, since the pool is
+	// constructed in main and this package has no reference to it.
+	DBPoolInUseConnections = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "db_pool_in_use_connections",
+		Help: "Number of connections currently checked out of the pgx pool.",
+	})
+
+	// NotificationsDroppedTotal counts notifications dropped by
+	// service.NotificationPool because its queue was full, for alerting on
+	// under-provisioned Workers/QueueSize (see config.NotificationConfig).
+	NotificationsDroppedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "notifications_dropped_total",
+		Help: "Total number of notifications dropped because the notification worker pool's queue was full.",
+	})
+
+	// StreamSubscribersActive reports the current number of live
+	// GET /api/v1/audit/stream connections across every tenant (see
+	// stream.Hub).
+	StreamSubscribersActive = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "stream_subscribers_active",
+		Help: "Number of currently connected audit log stream subscribers.",
+	})
+
+	// StreamEventsDroppedTotal counts events stream.Hub dropped for a slow
+	// subscriber because its buffer was full, for alerting on a consumer
+	// that can't keep up with its filtered event rate.
+	StreamEventsDroppedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "stream_events_dropped_total",
+		Help: "Total number of stream events dropped because a subscriber's buffer was full.",
+	})
+
+	// KafkaPublishErrorsTotal counts failed attempts to publish a newly
+	// created audit log to Kafka (see config.KafkaConfig), for alerting
+	// on a broken/unreachable cluster. Publish failures never fail the
+	// create request itself.
+	KafkaPublishErrorsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "kafka_publish_errors_total",
+		Help: "Total number of audit logs that failed to publish to Kafka.",
+	})
+
+	// NATSPublishErrorsTotal counts failed attempts to publish a newly
+	// created audit log to NATS JetStream (see config.NATSConfig), for
+	// alerting on a broken/unreachable cluster or an ack that timed out.
+	// Publish failures never fail the create request itself.
+	NATSPublishErrorsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "nats_publish_errors_total",
+		Help: "Total number of audit logs that failed to publish to NATS JetStream.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(AuditLogsCreatedTotal, AuditLogsDeletedTotal, RequestDuration, DBPoolInUseConnections, NotificationsDroppedTotal, StreamSubscribersActive, StreamEventsDroppedTotal, KafkaPublishErrorsTotal, NATSPublishErrorsTotal)
+}
+
+// Handler returns the HTTP handler that serves Registry's metrics in the
+// Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// Middleware records each request's method, path template, and status
+// code into RequestDuration. It must run after chi has matched a route
+// (i.e. be registered with r.Use, not wrap an individual handler) so that
+// chi.RouteContext's RoutePattern is populated by the time it reads it.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		next.ServeHTTP(ww, r)
+
+		path := chi.RouteContext(r.Context()).RoutePattern()
+		if path == "" {
+			path = "unmatched"
+		}
+		RequestDuration.WithLabelValues(r.Method, path, strconv.Itoa(ww.Status())).Observe(time.Since(start).Seconds())
+	})
+}
@@ -0,0 +1,25 @@
+package migrate
+
+import (
+	"os"
+	"testing"
+)
+
+// TestUpAndDown applies every migration, then rolls every one back,
+// against a throwaway database. It's skipped unless TEST_DATABASE_URL
+// points at one, since this sandbox has no Postgres to run it against
+// by default; CI is expected to set it to a scratch database that's
+// safe to migrate up and down.
+func TestUpAndDown(t *testing.T) {
+	dbURL := os.Getenv("TEST_DATABASE_URL")
+	if dbURL == "" {
+		t.Skip("TEST_DATABASE_URL not set; skipping migration test")
+	}
+
+	if err := Up(dbURL); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if err := Down(dbURL); err != nil {
+		t.Fatalf("Down: %v", err)
+	}
+}
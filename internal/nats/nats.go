@@ -0,0 +1,96 @@
+// Package nats implements the optional NATS JetStream publisher that
+// mirrors newly created audit logs onto a durable stream for downstream
+// consumers (see config.NATSConfig) — an alternative to internal/kafka
+// for shops that run NATS instead of Kafka.
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	natsgo "github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+	"github.com/naveenkumar2412/audit-log-service/internal/logging"
+)
+
+// Publisher publishes audit logs to a JetStream stream, one subject per
+// tenant/event ("audit.<tenant>.<event>"), waiting for JetStream's ack on
+// every publish for at-least-once delivery. Reconnection against
+// Servers is handled by the underlying *nats.Conn, which retries
+// indefinitely in the background; Publish itself just reports whatever
+// the connection's current state yields.
+type Publisher struct {
+	conn       *natsgo.Conn
+	js         jetstream.JetStream
+	ackTimeout time.Duration
+}
+
+// New connects to servers and ensures stream exists (bound to subjects
+// "audit.>"), creating it if necessary. A failure to reach the cluster
+// or provision the stream at startup is returned rather than logged,
+// since unlike a transient publish failure it means the publisher isn't
+// usable at all; callers should treat it the same as any other optional
+// backend failing to initialize.
+func New(servers []string, stream string, ackTimeout time.Duration) (*Publisher, error) {
+	conn, err := natsgo.Connect(strings.Join(servers, ","),
+		natsgo.MaxReconnects(-1),
+		natsgo.ReconnectWait(2*time.Second),
+		natsgo.DisconnectErrHandler(func(_ *natsgo.Conn, err error) {
+			if err != nil {
+				logging.FromContext(context.Background()).Warn("nats: disconnected", "error", err)
+			}
+		}),
+		natsgo.ReconnectHandler(func(c *natsgo.Conn) {
+			logging.FromContext(context.Background()).Info("nats: reconnected", "url", c.ConnectedUrl())
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("nats: connect: %w", err)
+	}
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("nats: jetstream context: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:     stream,
+		Subjects: []string{"audit.>"},
+	}); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("nats: ensure stream %q: %w", stream, err)
+	}
+
+	return &Publisher{conn: conn, js: js, ackTimeout: ackTimeout}, nil
+}
+
+// Publish publishes log to subject "audit.<tenant_id>.<event>" and waits
+// up to p.ackTimeout for the JetStream ack confirming durable,
+// at-least-once delivery.
+func (p *Publisher) Publish(ctx context.Context, log domain.AuditLog) error {
+	body, err := json.Marshal(log)
+	if err != nil {
+		return fmt.Errorf("nats: marshal record: %w", err)
+	}
+	subject := fmt.Sprintf("audit.%s.%s", log.TenantID, log.Event)
+
+	ctx, cancel := context.WithTimeout(ctx, p.ackTimeout)
+	defer cancel()
+	if _, err := p.js.Publish(ctx, subject, body); err != nil {
+		return fmt.Errorf("nats: publish to %s: %w", subject, err)
+	}
+	return nil
+}
+
+// Close drains in-flight publishes and closes the underlying connection.
+func (p *Publisher) Close() error {
+	return p.conn.Drain()
+}
@@ -0,0 +1,236 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+	"github.com/naveenkumar2412/audit-log-service/internal/logging"
+	"github.com/naveenkumar2412/audit-log-service/internal/tracing"
+)
+
+// FailedNotificationStore is the dead-letter storage interface Dispatcher
+// and NotificationRetryWorker depend on. It is satisfied by
+// internal/store/postgres.FailedNotificationRepo.
+type FailedNotificationStore interface {
+	Enqueue(ctx context.Context, fn domain.FailedNotification) error
+	DueForRetry(ctx context.Context, limit int) ([]domain.FailedNotification, error)
+	MarkSucceeded(ctx context.Context, id uuid.UUID) error
+	MarkFailed(ctx context.Context, id uuid.UUID, errMsg string, nextRetryAt time.Time) error
+}
+
+// SubscriptionLister is the read access the dispatcher needs into webhook
+// subscriptions. It is satisfied by internal/store/postgres.WebhookRepo.
+type SubscriptionLister interface {
+	ListByTenant(ctx context.Context, tenantID string) ([]domain.WebhookSubscription, error)
+}
+
+// Throttler gates how often a given (tenant, event) pair may notify,
+// independent of the global dedup window. It is satisfied by
+// internal/throttle.RedisThrottle.
+type Throttler interface {
+	Allow(ctx context.Context, key string, window time.Duration) (sendNow bool, rollupSuppressedCount int, err error)
+}
+
+// Dispatcher fans an audit log event out to every webhook subscription
+// for its tenant whose Events list matches (or is empty, meaning "all
+// events"), choosing a Slack-, Teams-, or generic-webhook-formatted
+// delivery based on the subscription's URL.
+type Dispatcher struct {
+	subs    SubscriptionLister
+	webhook Sender
+	slack   Sender
+	teams   Sender
+	email   Sender
+	// MaxChannelsPerEvent caps how many subscriptions a single event may
+	// fan out to. <= 0 means no limit. Guards against a misconfigured
+	// tenant registering hundreds of webhooks and turning every write
+	// into a fan-out storm.
+	MaxChannelsPerEvent int
+
+	throttle      Throttler
+	throttleRules atomic.Pointer[throttleRules]
+
+	deadLetter        FailedNotificationStore
+	deadLetterBackoff time.Duration
+}
+
+// NewDispatcher returns a Dispatcher backed by subs, delivering through
+// webhook for generic URLs, slack for Slack incoming-webhook URLs, and
+// teams for Microsoft Teams incoming-webhook URLs.
+func NewDispatcher(subs SubscriptionLister, webhook, slack, teams Sender) *Dispatcher {
+	return &Dispatcher{subs: subs, webhook: webhook, slack: slack, teams: teams}
+}
+
+// WithMaxChannelsPerEvent sets the fan-out cap and returns d for chaining.
+func (d *Dispatcher) WithMaxChannelsPerEvent(max int) *Dispatcher {
+	d.MaxChannelsPerEvent = max
+	return d
+}
+
+// WithEmailSender sets the sender used for subscriptions whose URL is an
+// email address (see isEmailURL) and returns d for chaining. Not calling
+// WithEmailSender leaves such subscriptions delivered through webhook,
+// as before email support existed.
+func (d *Dispatcher) WithEmailSender(sender Sender) *Dispatcher {
+	d.email = sender
+	return d
+}
+
+// throttleRules is the pair of settings SetThrottleWindows swaps
+// atomically, so Notify never observes a defaultWindow from one reload
+// paired with windows from another.
+type throttleRules struct {
+	defaultWindow time.Duration
+	windows       map[string]map[string]time.Duration
+}
+
+// WithThrottle sets the backend and per-(tenant,event) windows for
+// notification throttling and returns d for chaining. defaultWindow
+// applies to any (tenant, event) pair with no entry in windows; <= 0
+// disables throttling for that pair. Not calling WithThrottle disables
+// throttling entirely.
+func (d *Dispatcher) WithThrottle(t Throttler, defaultWindow time.Duration, windows map[string]map[string]time.Duration) *Dispatcher {
+	d.throttle = t
+	d.throttleRules.Store(&throttleRules{defaultWindow: defaultWindow, windows: windows})
+	return d
+}
+
+// SetThrottleWindows atomically replaces the per-(tenant,event) throttle
+// windows and default window, for a live config reload (see cmd/server's
+// SIGHUP handler), without disturbing in-flight Notify calls. It has no
+// effect if WithThrottle was never called, since there is then no
+// Throttler backend to apply windows to.
+func (d *Dispatcher) SetThrottleWindows(defaultWindow time.Duration, windows map[string]map[string]time.Duration) {
+	d.throttleRules.Store(&throttleRules{defaultWindow: defaultWindow, windows: windows})
+}
+
+// WithDeadLetter sets the store a delivery is persisted to once it fails,
+// and the initial backoff before NotificationRetryWorker's first retry,
+// and returns d for chaining. Not calling WithDeadLetter leaves failed
+// deliveries logged only, as before.
+func (d *Dispatcher) WithDeadLetter(store FailedNotificationStore, backoff time.Duration) *Dispatcher {
+	d.deadLetter = store
+	d.deadLetterBackoff = backoff
+	return d
+}
+
+func (d *Dispatcher) windowFor(tenantID, event string) time.Duration {
+	rules := d.throttleRules.Load()
+	if rules == nil {
+		return 0
+	}
+	if byEvent, ok := rules.windows[tenantID]; ok {
+		if w, ok := byEvent[event]; ok {
+			return w
+		}
+	}
+	return rules.defaultWindow
+}
+
+// Notify delivers log to every enabled, matching subscription for its
+// tenant, up to MaxChannelsPerEvent. Subscriptions with Enabled false are
+// skipped entirely, as if unregistered. When the matching subscriptions
+// exceed the cap, the
+// oldest-registered ones (a tenant's original/primary channels) win and the
+// rest are dropped; the truncation is logged since it is otherwise silent
+// data loss.
+//
+// When throttling is configured (see WithThrottle) and the (tenant,event)
+// pair's window is still active, Notify is suppressed entirely rather
+// than fanned out; the window's opening call instead carries a "suppressed
+// N similar alerts" rollup note to every subscription it does reach.
+//
+// Delivery failures are logged, not returned, since notification is
+// best-effort and must never affect the outcome of the write that
+// triggered it.
+func (d *Dispatcher) Notify(ctx context.Context, log_ domain.AuditLog) {
+	ctx, span := tracing.StartDetached(ctx, "notify.Dispatcher.Notify", trace.WithAttributes(
+		attribute.String("tenant_id", log_.TenantID),
+		attribute.String("event", log_.Event),
+	))
+	defer span.End()
+
+	var rollupNote string
+	if d.throttle != nil {
+		if window := d.windowFor(log_.TenantID, log_.Event); window > 0 {
+			sendNow, rollupCount, err := d.throttle.Allow(ctx, log_.TenantID+":"+log_.Event, window)
+			if err != nil {
+				log.Printf("notify: throttle check for %s/%s: %v", log_.TenantID, log_.Event, err)
+			}
+			if !sendNow {
+				logging.FromContext(ctx).Info("notify: suppressed by per-event throttle",
+					"tenant_id", log_.TenantID, "event", log_.Event, "window", window)
+				return
+			}
+			if rollupCount > 0 {
+				rollupNote = fmt.Sprintf("suppressed %d similar alert(s) during the previous %s window", rollupCount, window)
+			}
+		}
+	}
+
+	subs, err := d.subs.ListByTenant(ctx, log_.TenantID)
+	if err != nil {
+		log.Printf("notify: list subscriptions for tenant %s: %v", log_.TenantID, err)
+		return
+	}
+
+	matched := make([]domain.WebhookSubscription, 0, len(subs))
+	for _, sub := range subs {
+		if sub.Enabled && matches(sub, log_.Event) {
+			matched = append(matched, sub)
+		}
+	}
+
+	if d.MaxChannelsPerEvent > 0 && len(matched) > d.MaxChannelsPerEvent {
+		sort.Slice(matched, func(i, j int) bool {
+			return matched[i].CreatedAt.Before(matched[j].CreatedAt)
+		})
+		dropped := len(matched) - d.MaxChannelsPerEvent
+		matched = matched[:d.MaxChannelsPerEvent]
+		logging.FromContext(ctx).Info("notify: fan-out capped, dropping lowest-priority channels",
+			"tenant_id", log_.TenantID, "event", log_.Event, "matched", len(matched)+dropped,
+			"cap", d.MaxChannelsPerEvent, "dropped", dropped)
+	}
+
+	for _, sub := range matched {
+		sender := d.webhook
+		switch {
+		case isSlackURL(sub.URL):
+			sender = d.slack
+		case isTeamsURL(sub.URL):
+			sender = d.teams
+		case d.email != nil && isEmailURL(sub.URL):
+			sender = d.email
+		}
+		if err := sender.Send(ctx, sub, log_, rollupNote); err != nil {
+			log.Printf("notify: deliver to %s: %v", sub.URL, err)
+			if d.deadLetter != nil {
+				dl := domain.FailedNotification{
+					AuditLogID:  log_.ID,
+					TenantID:    log_.TenantID,
+					Channel:     sub.URL,
+					Error:       err.Error(),
+					Attempts:    1,
+					NextRetryAt: time.Now().Add(d.deadLetterBackoff),
+				}
+				if dlErr := d.deadLetter.Enqueue(ctx, dl); dlErr != nil {
+					log.Printf("notify: enqueue dead letter for %s: %v", sub.URL, dlErr)
+				}
+			}
+		}
+	}
+}
+
+func matches(sub domain.WebhookSubscription, event string) bool {
+	return len(sub.Events) == 0 || slices.Contains(sub.Events, event)
+}
@@ -0,0 +1,259 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+)
+
+type fakeLister struct {
+	subs []domain.WebhookSubscription
+}
+
+func (f *fakeLister) ListByTenant(ctx context.Context, tenantID string) ([]domain.WebhookSubscription, error) {
+	return f.subs, nil
+}
+
+type recordingSender struct {
+	sent []domain.WebhookSubscription
+}
+
+func (r *recordingSender) Send(ctx context.Context, sub domain.WebhookSubscription, log domain.AuditLog, rollupNote string) error {
+	r.sent = append(r.sent, sub)
+	return nil
+}
+
+type failingSender struct {
+	err error
+}
+
+func (f *failingSender) Send(ctx context.Context, sub domain.WebhookSubscription, log domain.AuditLog, rollupNote string) error {
+	return f.err
+}
+
+type recordingDeadLetterStore struct {
+	enqueued []domain.FailedNotification
+}
+
+func (r *recordingDeadLetterStore) Enqueue(ctx context.Context, fn domain.FailedNotification) error {
+	r.enqueued = append(r.enqueued, fn)
+	return nil
+}
+
+func (r *recordingDeadLetterStore) DueForRetry(ctx context.Context, limit int) ([]domain.FailedNotification, error) {
+	return nil, nil
+}
+
+func (r *recordingDeadLetterStore) MarkSucceeded(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func (r *recordingDeadLetterStore) MarkFailed(ctx context.Context, id uuid.UUID, errMsg string, nextRetryAt time.Time) error {
+	return nil
+}
+
+func TestDispatcher_RoutesByURLAndFiltersEvents(t *testing.T) {
+	genericSub := domain.WebhookSubscription{URL: "https://example.com/hook", Events: []string{"user.login"}, Enabled: true}
+	slackSub := domain.WebhookSubscription{URL: "https://hooks.slack.com/services/x", Events: nil, Enabled: true}
+	teamsSub := domain.WebhookSubscription{URL: "https://example.webhook.office.com/webhookb2/x", Events: nil, Enabled: true}
+	unmatchedSub := domain.WebhookSubscription{URL: "https://example.com/other", Events: []string{"user.logout"}, Enabled: true}
+
+	lister := &fakeLister{subs: []domain.WebhookSubscription{genericSub, slackSub, teamsSub, unmatchedSub}}
+	webhook := &recordingSender{}
+	slack := &recordingSender{}
+	teams := &recordingSender{}
+	d := NewDispatcher(lister, webhook, slack, teams)
+
+	d.Notify(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "user.login"})
+
+	if len(webhook.sent) != 1 || webhook.sent[0].URL != genericSub.URL {
+		t.Errorf("webhook sent = %+v, want [genericSub]", webhook.sent)
+	}
+	if len(slack.sent) != 1 || slack.sent[0].URL != slackSub.URL {
+		t.Errorf("slack sent = %+v, want [slackSub]", slack.sent)
+	}
+	if len(teams.sent) != 1 || teams.sent[0].URL != teamsSub.URL {
+		t.Errorf("teams sent = %+v, want [teamsSub]", teams.sent)
+	}
+}
+
+func TestDispatcher_CapsFanOutToOldestSubscriptions(t *testing.T) {
+	base := time.Now()
+	oldest := domain.WebhookSubscription{URL: "https://example.com/oldest", CreatedAt: base, Enabled: true}
+	middle := domain.WebhookSubscription{URL: "https://example.com/middle", CreatedAt: base.Add(time.Minute), Enabled: true}
+	newest := domain.WebhookSubscription{URL: "https://example.com/newest", CreatedAt: base.Add(2 * time.Minute), Enabled: true}
+
+	lister := &fakeLister{subs: []domain.WebhookSubscription{newest, oldest, middle}}
+	webhook := &recordingSender{}
+	slack := &recordingSender{}
+	d := NewDispatcher(lister, webhook, slack, slack).WithMaxChannelsPerEvent(2)
+
+	d.Notify(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "user.login"})
+
+	if len(webhook.sent) != 2 {
+		t.Fatalf("webhook sent %d subscriptions, want 2", len(webhook.sent))
+	}
+	for _, sub := range webhook.sent {
+		if sub.URL == newest.URL {
+			t.Errorf("expected newest subscription to be dropped by the cap, but it was sent")
+		}
+	}
+}
+
+type fakeThrottle struct {
+	sendNow     bool
+	rollupCount int
+	calls       []string
+}
+
+func (f *fakeThrottle) Allow(ctx context.Context, key string, window time.Duration) (bool, int, error) {
+	f.calls = append(f.calls, key)
+	return f.sendNow, f.rollupCount, nil
+}
+
+type rollupRecordingSender struct {
+	notes []string
+}
+
+func (r *rollupRecordingSender) Send(ctx context.Context, sub domain.WebhookSubscription, log domain.AuditLog, rollupNote string) error {
+	r.notes = append(r.notes, rollupNote)
+	return nil
+}
+
+func TestDispatcher_ThrottleSuppressesWithinWindow(t *testing.T) {
+	sub := domain.WebhookSubscription{URL: "https://example.com/hook", Enabled: true}
+	lister := &fakeLister{subs: []domain.WebhookSubscription{sub}}
+	webhook := &rollupRecordingSender{}
+	throttle := &fakeThrottle{sendNow: false}
+	d := NewDispatcher(lister, webhook, webhook, webhook).WithThrottle(throttle, time.Minute, nil)
+
+	d.Notify(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "user.login"})
+
+	if len(webhook.notes) != 0 {
+		t.Errorf("expected delivery to be suppressed, got %d deliveries", len(webhook.notes))
+	}
+	if len(throttle.calls) != 1 || throttle.calls[0] != "tenant-a:user.login" {
+		t.Errorf("throttle checked with %+v, want [tenant-a:user.login]", throttle.calls)
+	}
+}
+
+func TestDispatcher_ThrottleAttachesRollupNoteWhenWindowReopens(t *testing.T) {
+	sub := domain.WebhookSubscription{URL: "https://example.com/hook", Enabled: true}
+	lister := &fakeLister{subs: []domain.WebhookSubscription{sub}}
+	webhook := &rollupRecordingSender{}
+	throttle := &fakeThrottle{sendNow: true, rollupCount: 42}
+	d := NewDispatcher(lister, webhook, webhook, webhook).WithThrottle(throttle, time.Minute, nil)
+
+	d.Notify(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "user.login"})
+
+	if len(webhook.notes) != 1 || webhook.notes[0] == "" {
+		t.Fatalf("expected a rollup note to be attached, got %+v", webhook.notes)
+	}
+}
+
+func TestDispatcher_NoThrottleWhenWindowUnconfigured(t *testing.T) {
+	sub := domain.WebhookSubscription{URL: "https://example.com/hook", Enabled: true}
+	lister := &fakeLister{subs: []domain.WebhookSubscription{sub}}
+	webhook := &rollupRecordingSender{}
+	throttle := &fakeThrottle{sendNow: false}
+	d := NewDispatcher(lister, webhook, webhook, webhook).WithThrottle(throttle, 0, nil)
+
+	d.Notify(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "user.login"})
+
+	if len(webhook.notes) != 1 {
+		t.Errorf("expected delivery since no window is configured, got %d deliveries", len(webhook.notes))
+	}
+	if len(throttle.calls) != 0 {
+		t.Errorf("expected throttle not to be consulted when window <= 0, got %+v", throttle.calls)
+	}
+}
+
+func TestDispatcher_SetThrottleWindowsTakesEffectLive(t *testing.T) {
+	sub := domain.WebhookSubscription{URL: "https://example.com/hook", Enabled: true}
+	lister := &fakeLister{subs: []domain.WebhookSubscription{sub}}
+	webhook := &rollupRecordingSender{}
+	throttle := &fakeThrottle{sendNow: false}
+	d := NewDispatcher(lister, webhook, webhook, webhook).WithThrottle(throttle, 0, nil)
+
+	d.SetThrottleWindows(time.Minute, nil)
+	d.Notify(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "user.login"})
+
+	if len(webhook.notes) != 0 {
+		t.Errorf("expected delivery to be suppressed after SetThrottleWindows, got %d deliveries", len(webhook.notes))
+	}
+	if len(throttle.calls) != 1 {
+		t.Errorf("expected throttle to be consulted after SetThrottleWindows, got %+v", throttle.calls)
+	}
+}
+
+func TestDispatcher_NoCapWhenMaxChannelsUnset(t *testing.T) {
+	subs := make([]domain.WebhookSubscription, 0, 5)
+	for i := 0; i < 5; i++ {
+		subs = append(subs, domain.WebhookSubscription{URL: fmt.Sprintf("https://example.com/%d", i), Enabled: true})
+	}
+	lister := &fakeLister{subs: subs}
+	webhook := &recordingSender{}
+	slack := &recordingSender{}
+	d := NewDispatcher(lister, webhook, slack, slack)
+
+	d.Notify(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "user.login"})
+
+	if len(webhook.sent) != 5 {
+		t.Errorf("webhook sent %d subscriptions, want 5 (no cap configured)", len(webhook.sent))
+	}
+}
+
+func TestDispatcher_SkipsDisabledSubscriptions(t *testing.T) {
+	enabledSub := domain.WebhookSubscription{URL: "https://example.com/enabled", Enabled: true}
+	disabledSub := domain.WebhookSubscription{URL: "https://example.com/disabled", Enabled: false}
+
+	lister := &fakeLister{subs: []domain.WebhookSubscription{enabledSub, disabledSub}}
+	webhook := &recordingSender{}
+	d := NewDispatcher(lister, webhook, webhook, webhook)
+
+	d.Notify(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "user.login"})
+
+	if len(webhook.sent) != 1 || webhook.sent[0].URL != enabledSub.URL {
+		t.Errorf("webhook sent = %+v, want [enabledSub]", webhook.sent)
+	}
+}
+
+func TestDispatcher_EnqueuesDeadLetterOnDeliveryFailure(t *testing.T) {
+	sub := domain.WebhookSubscription{URL: "https://example.com/hook", Enabled: true}
+	lister := &fakeLister{subs: []domain.WebhookSubscription{sub}}
+	sendErr := errors.New("connection refused")
+	webhook := &failingSender{err: sendErr}
+	store := &recordingDeadLetterStore{}
+	d := NewDispatcher(lister, webhook, webhook, webhook).WithDeadLetter(store, time.Minute)
+
+	logID := uuid.New()
+	d.Notify(context.Background(), domain.AuditLog{ID: logID, TenantID: "tenant-a", Event: "user.login"})
+
+	if len(store.enqueued) != 1 {
+		t.Fatalf("got %d dead-lettered notifications, want 1", len(store.enqueued))
+	}
+	got := store.enqueued[0]
+	if got.AuditLogID != logID || got.TenantID != "tenant-a" || got.Channel != sub.URL || got.Error != sendErr.Error() || got.Attempts != 1 {
+		t.Errorf("got %+v, want it to record the failed delivery", got)
+	}
+}
+
+func TestDispatcher_NoDeadLetterWhenDeliverySucceeds(t *testing.T) {
+	sub := domain.WebhookSubscription{URL: "https://example.com/hook", Enabled: true}
+	lister := &fakeLister{subs: []domain.WebhookSubscription{sub}}
+	webhook := &recordingSender{}
+	store := &recordingDeadLetterStore{}
+	d := NewDispatcher(lister, webhook, webhook, webhook).WithDeadLetter(store, time.Minute)
+
+	d.Notify(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "user.login"})
+
+	if len(store.enqueued) != 0 {
+		t.Errorf("got %d dead-lettered notifications, want 0", len(store.enqueued))
+	}
+}
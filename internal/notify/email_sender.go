@@ -0,0 +1,303 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	htmltemplate "html/template"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+)
+
+// EmailTemplateData is what an email template (subject, text body, or
+// HTML body) renders against. It exposes the same fields webhook
+// subscribers see in their JSON payload (see webhookPayload), under the
+// names a template author would expect.
+type EmailTemplateData struct {
+	ID         string
+	TenantID   string
+	Actor      string
+	Event      string
+	Resource   string
+	ResourceID string
+	Data       map[string]any
+	Meta       map[string]any
+	CreatedAt  time.Time
+	// RollupNote summarizes alerts suppressed by per-event throttling; see
+	// webhookPayload.RollupNote.
+	RollupNote string
+}
+
+// EmailTemplate is the operator-supplied template source for one event
+// type (or the catch-all default, keyed by the empty string - see
+// SMTPEmailSender.WithTemplates). Subject and Text are Go text/template
+// source; HTML, if non-empty, is Go html/template source and makes
+// buildMessage produce a multipart/alternative message with both parts
+// instead of a plain-text-only one.
+type EmailTemplate struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// defaultEmailTemplate is used for any event with no entry given to
+// SMTPEmailSender.WithTemplates (and when it was given nil entirely),
+// matching the plain layout this sender used before templating existed.
+var defaultEmailTemplate = EmailTemplate{
+	Subject: `Audit event: {{.Event}}`,
+	Text: `Tenant:   {{.TenantID}}
+Event:    {{.Event}}
+Resource: {{.Resource}}/{{.ResourceID}}
+Actor:    {{.Actor}}
+Time:     {{.CreatedAt}}
+{{if .RollupNote}}
+Note: {{.RollupNote}}
+{{end}}`,
+}
+
+// compiledEmailTemplate holds a parsed subject/text/[html] template set,
+// so SMTPEmailSender.buildMessage never re-parses a template per send.
+type compiledEmailTemplate struct {
+	subject *texttemplate.Template
+	text    *texttemplate.Template
+	html    *htmltemplate.Template // nil when the source template has no HTML part
+}
+
+// SMTPEmailSender delivers an audit log event as an email over SMTP,
+// rendering its subject and body from a configurable, per-event template
+// (see WithTemplates), falling back to defaultEmailTemplate for any event
+// without one. sub.URL (reused from the generic WebhookSubscription
+// shape, the same way WebhookSender reuses it for Slack/Teams detection)
+// holds the recipient address.
+type SMTPEmailSender struct {
+	// Addr is the SMTP server address, e.g. "smtp.example.com:587".
+	Addr string
+	// Auth authenticates to Addr; nil sends unauthenticated (e.g. a local
+	// relay).
+	Auth smtp.Auth
+	// From is the envelope and message From address.
+	From string
+
+	// SendFunc actually delivers msg; defaults to smtp.SendMail. Tests
+	// override this to capture the rendered message instead of dialing a
+	// real SMTP server.
+	SendFunc func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+
+	// compiled maps an event name to its parsed template, as set by
+	// WithTemplates; the entry keyed by "" is used for any event without
+	// a more specific entry.
+	compiled map[string]compiledEmailTemplate
+}
+
+// NewSMTPEmailSender returns a sender for addr/from using smtp.SendMail,
+// with only the built-in default template until WithTemplates is called.
+func NewSMTPEmailSender(addr, from string) *SMTPEmailSender {
+	s := &SMTPEmailSender{
+		Addr:     addr,
+		From:     from,
+		SendFunc: smtp.SendMail,
+	}
+	return s.WithTemplates(nil)
+}
+
+// WithAuth sets the SMTP auth and returns s for chaining.
+func (s *SMTPEmailSender) WithAuth(auth smtp.Auth) *SMTPEmailSender {
+	s.Auth = auth
+	return s
+}
+
+// WithTemplates compiles templates (keyed by event name, "" for the
+// default) and returns s for chaining. Called with nil, it (re)compiles
+// just defaultEmailTemplate under the "" key. A template that fails to
+// parse is dropped with defaultEmailTemplate used in its place for that
+// event, rather than failing the whole sender over one bad template.
+func (s *SMTPEmailSender) WithTemplates(templates map[string]EmailTemplate) *SMTPEmailSender {
+	compiled := make(map[string]compiledEmailTemplate, len(templates)+1)
+	if _, ok := templates[""]; !ok {
+		compiled[""] = mustCompileEmailTemplate(defaultEmailTemplate)
+	}
+	for event, tmpl := range templates {
+		ct, err := compileEmailTemplate(tmpl)
+		if err != nil {
+			ct = mustCompileEmailTemplate(defaultEmailTemplate)
+		}
+		compiled[event] = ct
+	}
+	s.compiled = compiled
+	return s
+}
+
+func mustCompileEmailTemplate(tmpl EmailTemplate) compiledEmailTemplate {
+	ct, err := compileEmailTemplate(tmpl)
+	if err != nil {
+		panic(fmt.Sprintf("notify: default email template failed to compile: %v", err))
+	}
+	return ct
+}
+
+func compileEmailTemplate(tmpl EmailTemplate) (compiledEmailTemplate, error) {
+	var ct compiledEmailTemplate
+	var err error
+	ct.subject, err = texttemplate.New("subject").Parse(tmpl.Subject)
+	if err != nil {
+		return compiledEmailTemplate{}, fmt.Errorf("notify: parse subject template: %w", err)
+	}
+	ct.text, err = texttemplate.New("text").Parse(tmpl.Text)
+	if err != nil {
+		return compiledEmailTemplate{}, fmt.Errorf("notify: parse text template: %w", err)
+	}
+	if tmpl.HTML != "" {
+		ct.html, err = htmltemplate.New("html").Parse(tmpl.HTML)
+		if err != nil {
+			return compiledEmailTemplate{}, fmt.Errorf("notify: parse html template: %w", err)
+		}
+	}
+	return ct, nil
+}
+
+func (s *SMTPEmailSender) templateFor(event string) compiledEmailTemplate {
+	if ct, ok := s.compiled[event]; ok {
+		return ct
+	}
+	return s.compiled[""]
+}
+
+// buildMessage renders sub/log's template into a complete RFC 5322
+// message (headers + body) ready to hand to an SMTP DATA command. When
+// the event's template has an HTML part, the body is a multipart/
+// alternative MIME message with the text part first (per RFC 2046, the
+// preferred simplest-first ordering) so a client that can't render HTML
+// still shows something readable.
+func (s *SMTPEmailSender) buildMessage(sub domain.WebhookSubscription, log domain.AuditLog, rollupNote string) ([]byte, error) {
+	data := EmailTemplateData{
+		ID:         log.ID.String(),
+		TenantID:   log.TenantID,
+		Actor:      log.Actor,
+		Event:      log.Event,
+		Resource:   log.Resource,
+		ResourceID: log.ResourceID,
+		Data:       log.Data,
+		Meta:       log.Meta,
+		CreatedAt:  log.CreatedAt,
+		RollupNote: rollupNote,
+	}
+
+	ct := s.templateFor(log.Event)
+
+	var subjectBuf bytes.Buffer
+	if err := ct.subject.Execute(&subjectBuf, data); err != nil {
+		return nil, fmt.Errorf("notify: render email subject: %w", err)
+	}
+	var textBuf bytes.Buffer
+	if err := ct.text.Execute(&textBuf, data); err != nil {
+		return nil, fmt.Errorf("notify: render email text body: %w", err)
+	}
+
+	var buf bytes.Buffer
+	headers := textproto.MIMEHeader{}
+	headers.Set("From", s.From)
+	headers.Set("To", sub.URL)
+	headers.Set("Subject", mime.QEncoding.Encode("utf-8", subjectBuf.String()))
+	headers.Set("MIME-Version", "1.0")
+
+	if ct.html == nil {
+		headers.Set("Content-Type", `text/plain; charset="utf-8"`)
+		writeHeaders(&buf, headers)
+		buf.WriteString("\r\n")
+		buf.Write(textBuf.Bytes())
+		return buf.Bytes(), nil
+	}
+
+	var htmlBuf bytes.Buffer
+	if err := ct.html.Execute(&htmlBuf, data); err != nil {
+		return nil, fmt.Errorf("notify: render email html body: %w", err)
+	}
+
+	mw := multipart.NewWriter(&buf)
+	headers.Set("Content-Type", fmt.Sprintf(`multipart/alternative; boundary="%s"`, mw.Boundary()))
+	var headerBuf bytes.Buffer
+	writeHeaders(&headerBuf, headers)
+
+	var bodyBuf bytes.Buffer
+	mw2 := multipart.NewWriter(&bodyBuf)
+	if err := mw2.SetBoundary(mw.Boundary()); err != nil {
+		return nil, fmt.Errorf("notify: set mime boundary: %w", err)
+	}
+	textPart, err := mw2.CreatePart(textproto.MIMEHeader{"Content-Type": {`text/plain; charset="utf-8"`}})
+	if err != nil {
+		return nil, fmt.Errorf("notify: create text mime part: %w", err)
+	}
+	textPart.Write(textBuf.Bytes())
+	htmlPart, err := mw2.CreatePart(textproto.MIMEHeader{"Content-Type": {`text/html; charset="utf-8"`}})
+	if err != nil {
+		return nil, fmt.Errorf("notify: create html mime part: %w", err)
+	}
+	htmlPart.Write(htmlBuf.Bytes())
+	if err := mw2.Close(); err != nil {
+		return nil, fmt.Errorf("notify: close mime writer: %w", err)
+	}
+
+	var msg bytes.Buffer
+	msg.Write(headerBuf.Bytes())
+	msg.WriteString("\r\n")
+	msg.Write(bodyBuf.Bytes())
+	return msg.Bytes(), nil
+}
+
+// writeHeaders writes headers in a stable, readable order (not Go map
+// iteration order) as "Key: Value\r\n" lines.
+func writeHeaders(buf *bytes.Buffer, headers textproto.MIMEHeader) {
+	for _, key := range []string{"From", "To", "Subject", "MIME-Version", "Content-Type"} {
+		if v := headers.Get(key); v != "" {
+			fmt.Fprintf(buf, "%s: %s\r\n", key, v)
+		}
+	}
+}
+
+// Send renders sub/log's template (see buildMessage) and delivers it via
+// SendFunc to the single recipient in sub.URL.
+func (s *SMTPEmailSender) Send(ctx context.Context, sub domain.WebhookSubscription, log domain.AuditLog, rollupNote string) error {
+	if err := validateRecipient(sub.URL); err != nil {
+		return err
+	}
+
+	msg, err := s.buildMessage(sub, log, rollupNote)
+	if err != nil {
+		return err
+	}
+	if err := s.SendFunc(s.Addr, s.Auth, s.From, []string{sub.URL}, msg); err != nil {
+		return fmt.Errorf("notify: email delivery to %s: %w", sub.URL, err)
+	}
+	return nil
+}
+
+// validateRecipient rejects a subscription URL that isn't a well-formed
+// email address before it's written into the "To:" header and handed to
+// SendFunc as the SMTP envelope recipient. Without this, a URL containing
+// "\r\n" could inject arbitrary extra headers (e.g. "Bcc:") or SMTP
+// RCPT TO data, since the same string is used in both places.
+func validateRecipient(recipient string) error {
+	if strings.ContainsAny(recipient, "\r\n") {
+		return fmt.Errorf("notify: email recipient %q contains control characters", recipient)
+	}
+	if _, err := mail.ParseAddress(recipient); err != nil {
+		return fmt.Errorf("notify: email recipient %q is not a valid address: %w", recipient, err)
+	}
+	return nil
+}
+
+// isEmailURL reports whether sub.URL looks like an email address rather
+// than an HTTP(S) webhook URL, so the dispatcher can route it to
+// SMTPEmailSender automatically the same way isSlackURL/isTeamsURL route
+// by URL shape.
+func isEmailURL(url string) bool {
+	return !strings.Contains(url, "://") && strings.Contains(url, "@")
+}
@@ -0,0 +1,152 @@
+package notify
+
+import (
+	"context"
+	"net/smtp"
+	"strings"
+	"testing"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+)
+
+func TestSMTPEmailSender_BuildMessageRendersSampleTemplate(t *testing.T) {
+	s := NewSMTPEmailSender("smtp.example.com:587", "audit@example.com").WithTemplates(map[string]EmailTemplate{
+		"user.login": {
+			Subject: "Login by {{.Actor}}",
+			Text:    "{{.Actor}} logged into {{.TenantID}} at {{.CreatedAt}}",
+			HTML:    "<p>{{.Actor}} logged into <b>{{.TenantID}}</b></p>",
+		},
+	})
+
+	sub := domain.WebhookSubscription{URL: "security@example.com"}
+	log := domain.AuditLog{TenantID: "tenant-a", Actor: "alice", Event: "user.login", Resource: "session", ResourceID: "1"}
+
+	msg, err := s.buildMessage(sub, log, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	body := string(msg)
+	if !strings.Contains(body, "To: security@example.com") {
+		t.Errorf("expected To header for recipient, got:\n%s", body)
+	}
+	if !strings.Contains(body, "multipart/alternative") {
+		t.Errorf("expected a multipart/alternative message when an HTML template is configured, got:\n%s", body)
+	}
+	if !strings.Contains(body, "alice logged into tenant-a") {
+		t.Errorf("expected rendered text part, got:\n%s", body)
+	}
+	if !strings.Contains(body, "<p>alice logged into <b>tenant-a</b></p>") {
+		t.Errorf("expected rendered html part, got:\n%s", body)
+	}
+}
+
+func TestSMTPEmailSender_FallsBackToDefaultTemplateForUnconfiguredEvent(t *testing.T) {
+	s := NewSMTPEmailSender("smtp.example.com:587", "audit@example.com")
+
+	sub := domain.WebhookSubscription{URL: "security@example.com"}
+	log := domain.AuditLog{TenantID: "tenant-a", Actor: "alice", Event: "invoice.paid"}
+
+	msg, err := s.buildMessage(sub, log, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	body := string(msg)
+	if strings.Contains(body, "multipart/alternative") {
+		t.Errorf("expected a plain-text message when no html template is configured, got:\n%s", body)
+	}
+	if !strings.Contains(body, "Event:    invoice.paid") {
+		t.Errorf("expected default template body, got:\n%s", body)
+	}
+}
+
+func TestSMTPEmailSender_AttachesRollupNote(t *testing.T) {
+	s := NewSMTPEmailSender("smtp.example.com:587", "audit@example.com")
+
+	sub := domain.WebhookSubscription{URL: "security@example.com"}
+	log := domain.AuditLog{TenantID: "tenant-a", Event: "invoice.paid"}
+
+	msg, err := s.buildMessage(sub, log, "suppressed 3 similar alert(s)")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(msg), "suppressed 3 similar alert(s)") {
+		t.Errorf("expected rollup note in body, got:\n%s", msg)
+	}
+}
+
+func TestSMTPEmailSender_SendDeliversRenderedMessage(t *testing.T) {
+	s := NewSMTPEmailSender("smtp.example.com:587", "audit@example.com")
+
+	var gotAddr, gotFrom string
+	var gotTo []string
+	var gotMsg []byte
+	s.SendFunc = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotFrom, gotTo, gotMsg = addr, from, to, msg
+		return nil
+	}
+
+	sub := domain.WebhookSubscription{URL: "security@example.com"}
+	log := domain.AuditLog{TenantID: "tenant-a", Event: "invoice.paid"}
+
+	if err := s.Send(context.Background(), sub, log, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotAddr != "smtp.example.com:587" {
+		t.Errorf("got addr %q", gotAddr)
+	}
+	if gotFrom != "audit@example.com" {
+		t.Errorf("got from %q", gotFrom)
+	}
+	if len(gotTo) != 1 || gotTo[0] != "security@example.com" {
+		t.Errorf("got to %v", gotTo)
+	}
+	if len(gotMsg) == 0 {
+		t.Error("expected a non-empty rendered message")
+	}
+}
+
+func TestSMTPEmailSender_SendRejectsRecipientWithCRLFInjection(t *testing.T) {
+	s := NewSMTPEmailSender("smtp.example.com:587", "audit@example.com")
+
+	var sendCalled bool
+	s.SendFunc = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		sendCalled = true
+		return nil
+	}
+
+	sub := domain.WebhookSubscription{URL: "victim@example.com\r\nBcc: attacker@evil.example"}
+	log := domain.AuditLog{TenantID: "tenant-a", Event: "invoice.paid"}
+
+	if err := s.Send(context.Background(), sub, log, ""); err == nil {
+		t.Fatal("expected an error for a recipient containing CRLF, got nil")
+	}
+	if sendCalled {
+		t.Error("expected SendFunc not to be called for a rejected recipient")
+	}
+}
+
+func TestSMTPEmailSender_SendRejectsMalformedRecipient(t *testing.T) {
+	s := NewSMTPEmailSender("smtp.example.com:587", "audit@example.com")
+
+	sub := domain.WebhookSubscription{URL: "not-an-email-address"}
+	log := domain.AuditLog{TenantID: "tenant-a", Event: "invoice.paid"}
+
+	if err := s.Send(context.Background(), sub, log, ""); err == nil {
+		t.Fatal("expected an error for a malformed recipient, got nil")
+	}
+}
+
+func TestIsEmailURL(t *testing.T) {
+	cases := map[string]bool{
+		"security@example.com":      true,
+		"https://hooks.example.com": false,
+		"https://hooks.slack.com/x": false,
+	}
+	for url, want := range cases {
+		if got := isEmailURL(url); got != want {
+			t.Errorf("isEmailURL(%q) = %v, want %v", url, got, want)
+		}
+	}
+}
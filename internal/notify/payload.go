@@ -0,0 +1,95 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+)
+
+// webhookPayload is the JSON body posted to a webhook subscriber.
+type webhookPayload struct {
+	ID         string         `json:"id"`
+	TenantID   string         `json:"tenant_id"`
+	Actor      string         `json:"actor"`
+	Event      string         `json:"event"`
+	Resource   string         `json:"resource"`
+	ResourceID string         `json:"resource_id"`
+	Data       map[string]any `json:"data,omitempty"`
+	DataTooBig *dataTooBig    `json:"data_omitted,omitempty"`
+	Meta       map[string]any `json:"meta,omitempty"`
+	MetaError  string         `json:"meta_omitted,omitempty"`
+	CreatedAt  string         `json:"created_at"`
+	// RollupNote summarizes alerts suppressed by per-event throttling
+	// during the window this event reopened (see notify.Dispatcher's
+	// Throttler). Empty when throttling is disabled or had nothing to
+	// report.
+	RollupNote string `json:"rollup_note,omitempty"`
+}
+
+// dataTooBig replaces Data in the payload when it exceeds the configured
+// size threshold, pointing the receiver at the full record instead of
+// failing delivery outright.
+type dataTooBig struct {
+	Reason   string `json:"reason"`
+	FetchURL string `json:"fetch_url"`
+}
+
+// buildWebhookPayload marshals auditLog into a webhook payload, replacing
+// Data with a fetch link when its encoded size exceeds maxDataBytes (<=0
+// means no limit). fetchURLFor builds the "go fetch the full record" URL
+// for a log whose data was omitted.
+func buildWebhookPayload(auditLog domain.AuditLog, maxDataBytes int, rollupNote string, fetchURLFor func(domain.AuditLog) string) ([]byte, error) {
+	payload, err := newWebhookPayload(auditLog, maxDataBytes, rollupNote, fetchURLFor)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(payload)
+}
+
+// newWebhookPayload builds the webhookPayload for auditLog without
+// marshaling it, so callers (e.g. a batching sender) can combine several
+// into one array body.
+func newWebhookPayload(auditLog domain.AuditLog, maxDataBytes int, rollupNote string, fetchURLFor func(domain.AuditLog) string) (webhookPayload, error) {
+	payload := webhookPayload{
+		ID:         auditLog.ID.String(),
+		TenantID:   auditLog.TenantID,
+		Actor:      auditLog.Actor,
+		Event:      auditLog.Event,
+		Resource:   auditLog.Resource,
+		ResourceID: auditLog.ResourceID,
+		Data:       auditLog.Data,
+		Meta:       auditLog.Meta,
+		CreatedAt:  auditLog.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		RollupNote: rollupNote,
+	}
+
+	if maxDataBytes > 0 && len(auditLog.Data) > 0 {
+		encoded, err := json.Marshal(auditLog.Data)
+		if err != nil {
+			return webhookPayload{}, fmt.Errorf("notify: marshal data: %w", err)
+		}
+		if len(encoded) > maxDataBytes {
+			payload.Data = nil
+			payload.DataTooBig = &dataTooBig{
+				Reason:   fmt.Sprintf("data exceeds %d byte limit", maxDataBytes),
+				FetchURL: fetchURLFor(auditLog),
+			}
+		}
+	}
+
+	// Meta is attacker/producer-controlled free-form data and may contain
+	// a value json can't marshal (e.g. a NaN float or a channel). Marshal
+	// it separately so that failure only drops meta instead of silently
+	// losing the whole notification when the final payload is marshaled.
+	if len(payload.Meta) > 0 {
+		if _, err := json.Marshal(payload.Meta); err != nil {
+			log.Printf("notify: log %s has unmarshalable meta, omitting: %v", auditLog.ID, err)
+			payload.Meta = nil
+			payload.MetaError = fmt.Sprintf("meta omitted: %v", err)
+		}
+	}
+
+	return payload, nil
+}
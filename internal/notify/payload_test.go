@@ -0,0 +1,88 @@
+package notify
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+)
+
+func TestBuildWebhookPayload_SmallDataKept(t *testing.T) {
+	log := domain.AuditLog{TenantID: "tenant-a", Event: "e", Data: map[string]any{"k": "v"}}
+
+	body, err := buildWebhookPayload(log, 1000, "", func(domain.AuditLog) string { return "http://fetch" })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var payload webhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if payload.Data["k"] != "v" {
+		t.Errorf("expected data kept, got %+v", payload.Data)
+	}
+	if payload.DataTooBig != nil {
+		t.Errorf("expected no data_omitted, got %+v", payload.DataTooBig)
+	}
+}
+
+func TestBuildWebhookPayload_OversizedDataReplacedWithLink(t *testing.T) {
+	log := domain.AuditLog{TenantID: "tenant-a", Event: "e", Data: map[string]any{"k": "this is a long value that exceeds the tiny limit"}}
+
+	body, err := buildWebhookPayload(log, 10, "", func(domain.AuditLog) string { return "http://fetch" })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var payload webhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if payload.Data != nil {
+		t.Errorf("expected data omitted, got %+v", payload.Data)
+	}
+	if payload.DataTooBig == nil || payload.DataTooBig.FetchURL != "http://fetch" {
+		t.Errorf("expected data_omitted with fetch url, got %+v", payload.DataTooBig)
+	}
+}
+
+func TestBuildWebhookPayload_UnmarshalableMetaOmittedNotFatal(t *testing.T) {
+	log := domain.AuditLog{TenantID: "tenant-a", Event: "e", Meta: map[string]any{"bad": make(chan int)}}
+
+	body, err := buildWebhookPayload(log, 0, "", func(domain.AuditLog) string { return "" })
+	if err != nil {
+		t.Fatalf("expected unmarshalable meta to be handled gracefully, got error: %v", err)
+	}
+
+	var payload webhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if payload.Meta != nil {
+		t.Errorf("expected meta to be omitted, got %+v", payload.Meta)
+	}
+	if payload.MetaError == "" {
+		t.Error("expected meta_omitted to explain why meta was dropped")
+	}
+}
+
+func TestBuildWebhookPayload_ValidMetaKept(t *testing.T) {
+	log := domain.AuditLog{TenantID: "tenant-a", Event: "e", Meta: map[string]any{"k": "v"}}
+
+	body, err := buildWebhookPayload(log, 0, "", func(domain.AuditLog) string { return "" })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var payload webhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if payload.Meta["k"] != "v" {
+		t.Errorf("expected meta kept, got %+v", payload.Meta)
+	}
+	if payload.MetaError != "" {
+		t.Errorf("expected no meta_omitted, got %q", payload.MetaError)
+	}
+}
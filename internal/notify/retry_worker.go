@@ -0,0 +1,136 @@
+package notify
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+)
+
+// AuditLogGetter is the read access NotificationRetryWorker needs to
+// reload the full record a queued retry applies to. It is satisfied by
+// internal/store/postgres.AuditLogRepo.
+type AuditLogGetter interface {
+	GetByID(ctx context.Context, tenantID, id string, includeDeleted bool) (domain.AuditLog, error)
+}
+
+// NotificationRetryWorker periodically re-dispatches the notifications in
+// FailedNotificationStore's backlog (see Dispatcher.WithDeadLetter), one
+// batch per tick. Run it in its own goroutine; it stops when ctx is
+// canceled.
+type NotificationRetryWorker struct {
+	store   FailedNotificationStore
+	logs    AuditLogGetter
+	webhook Sender
+	slack   Sender
+	teams   Sender
+	email   Sender
+
+	// BatchSize caps how many due notifications a single sweep retries.
+	BatchSize int
+	// RetryBackoff is added to time.Now() to reschedule a notification
+	// that fails again.
+	RetryBackoff time.Duration
+	// MaxAttempts is the attempt count from which every further failed
+	// retry of a notification is logged as a give-up warning; the row is
+	// still retried (and can still succeed), not deleted, so it stays
+	// visible in the backlog endpoint. <= 0 means unlimited (no warning).
+	MaxAttempts int
+}
+
+// NewNotificationRetryWorker returns a worker backed by store for the
+// dead-letter queue and logs to reload each record, delivering through
+// webhook/slack/teams exactly like Dispatcher does.
+func NewNotificationRetryWorker(store FailedNotificationStore, logs AuditLogGetter, webhook, slack, teams Sender) *NotificationRetryWorker {
+	return &NotificationRetryWorker{store: store, logs: logs, webhook: webhook, slack: slack, teams: teams, BatchSize: 50}
+}
+
+// WithBatchSize sets BatchSize and returns w for chaining.
+func (w *NotificationRetryWorker) WithBatchSize(n int) *NotificationRetryWorker {
+	w.BatchSize = n
+	return w
+}
+
+// WithRetryBackoff sets RetryBackoff and returns w for chaining.
+func (w *NotificationRetryWorker) WithRetryBackoff(d time.Duration) *NotificationRetryWorker {
+	w.RetryBackoff = d
+	return w
+}
+
+// WithMaxAttempts sets MaxAttempts and returns w for chaining.
+func (w *NotificationRetryWorker) WithMaxAttempts(n int) *NotificationRetryWorker {
+	w.MaxAttempts = n
+	return w
+}
+
+// WithEmailSender sets the sender used to retry notifications whose
+// channel is an email address (see isEmailURL) and returns w for
+// chaining, mirroring Dispatcher.WithEmailSender.
+func (w *NotificationRetryWorker) WithEmailSender(sender Sender) *NotificationRetryWorker {
+	w.email = sender
+	return w
+}
+
+// Run sweeps for due notifications every interval until ctx is canceled.
+func (w *NotificationRetryWorker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.sweep(ctx)
+		}
+	}
+}
+
+func (w *NotificationRetryWorker) sweep(ctx context.Context) {
+	due, err := w.store.DueForRetry(ctx, w.BatchSize)
+	if err != nil {
+		log.Printf("notify: retry worker: list due notifications: %v", err)
+		return
+	}
+	for _, fn := range due {
+		w.retryOne(ctx, fn)
+	}
+}
+
+func (w *NotificationRetryWorker) retryOne(ctx context.Context, fn domain.FailedNotification) {
+	log_, err := w.logs.GetByID(ctx, fn.TenantID, fn.AuditLogID.String(), true)
+	if err != nil {
+		log.Printf("notify: retry worker: reload audit log %s: %v", fn.AuditLogID, err)
+		if markErr := w.store.MarkFailed(ctx, fn.ID, err.Error(), time.Now().Add(w.RetryBackoff)); markErr != nil {
+			log.Printf("notify: retry worker: reschedule %s: %v", fn.ID, markErr)
+		}
+		return
+	}
+
+	sender := w.webhook
+	switch {
+	case isSlackURL(fn.Channel):
+		sender = w.slack
+	case isTeamsURL(fn.Channel):
+		sender = w.teams
+	case w.email != nil && isEmailURL(fn.Channel):
+		sender = w.email
+	}
+
+	sub := domain.WebhookSubscription{URL: fn.Channel}
+	if sendErr := sender.Send(ctx, sub, log_, ""); sendErr != nil {
+		attempts := fn.Attempts + 1
+		if w.MaxAttempts > 0 && attempts >= w.MaxAttempts {
+			log.Printf("notify: retry worker: %s for audit log %s has failed %d times: %v", fn.Channel, fn.AuditLogID, attempts, sendErr)
+		}
+		if markErr := w.store.MarkFailed(ctx, fn.ID, sendErr.Error(), time.Now().Add(w.RetryBackoff)); markErr != nil {
+			log.Printf("notify: retry worker: reschedule %s: %v", fn.ID, markErr)
+		}
+		return
+	}
+
+	if err := w.store.MarkSucceeded(ctx, fn.ID); err != nil {
+		log.Printf("notify: retry worker: mark %s succeeded: %v", fn.ID, err)
+	}
+}
@@ -0,0 +1,126 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+)
+
+type fakeDeadLetterStore struct {
+	due       []domain.FailedNotification
+	succeeded []uuid.UUID
+	failed    []domain.FailedNotification
+}
+
+func (f *fakeDeadLetterStore) Enqueue(ctx context.Context, fn domain.FailedNotification) error {
+	return nil
+}
+
+func (f *fakeDeadLetterStore) DueForRetry(ctx context.Context, limit int) ([]domain.FailedNotification, error) {
+	return f.due, nil
+}
+
+func (f *fakeDeadLetterStore) MarkSucceeded(ctx context.Context, id uuid.UUID) error {
+	f.succeeded = append(f.succeeded, id)
+	return nil
+}
+
+func (f *fakeDeadLetterStore) MarkFailed(ctx context.Context, id uuid.UUID, errMsg string, nextRetryAt time.Time) error {
+	f.failed = append(f.failed, domain.FailedNotification{ID: id, Error: errMsg, NextRetryAt: nextRetryAt})
+	return nil
+}
+
+type fakeAuditLogGetter struct {
+	logs map[uuid.UUID]domain.AuditLog
+	err  error
+}
+
+func (f *fakeAuditLogGetter) GetByID(ctx context.Context, tenantID, id string, includeDeleted bool) (domain.AuditLog, error) {
+	if f.err != nil {
+		return domain.AuditLog{}, f.err
+	}
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return domain.AuditLog{}, err
+	}
+	return f.logs[parsed], nil
+}
+
+func TestNotificationRetryWorker_MarksSucceededOnSuccessfulRetry(t *testing.T) {
+	logID := uuid.New()
+	fn := domain.FailedNotification{ID: uuid.New(), AuditLogID: logID, TenantID: "tenant-a", Channel: "https://example.com/hook", Attempts: 1}
+	store := &fakeDeadLetterStore{due: []domain.FailedNotification{fn}}
+	logs := &fakeAuditLogGetter{logs: map[uuid.UUID]domain.AuditLog{logID: {ID: logID, TenantID: "tenant-a", Event: "e"}}}
+	webhook := &recordingSender{}
+	w := NewNotificationRetryWorker(store, logs, webhook, webhook, webhook)
+
+	w.sweep(context.Background())
+
+	if len(store.succeeded) != 1 || store.succeeded[0] != fn.ID {
+		t.Errorf("got succeeded %+v, want [%s]", store.succeeded, fn.ID)
+	}
+	if len(webhook.sent) != 1 || webhook.sent[0].URL != fn.Channel {
+		t.Errorf("got sent %+v, want delivery to %s", webhook.sent, fn.Channel)
+	}
+}
+
+func TestNotificationRetryWorker_ReschedulesOnRepeatedFailure(t *testing.T) {
+	logID := uuid.New()
+	fn := domain.FailedNotification{ID: uuid.New(), AuditLogID: logID, TenantID: "tenant-a", Channel: "https://example.com/hook", Attempts: 1}
+	store := &fakeDeadLetterStore{due: []domain.FailedNotification{fn}}
+	logs := &fakeAuditLogGetter{logs: map[uuid.UUID]domain.AuditLog{logID: {ID: logID, TenantID: "tenant-a", Event: "e"}}}
+	webhook := &failingSender{err: errors.New("still down")}
+	w := NewNotificationRetryWorker(store, logs, webhook, webhook, webhook).WithRetryBackoff(time.Minute)
+
+	w.sweep(context.Background())
+
+	if len(store.failed) != 1 || store.failed[0].ID != fn.ID || store.failed[0].Error != "still down" {
+		t.Errorf("got failed %+v, want a reschedule of %s", store.failed, fn.ID)
+	}
+	if len(store.succeeded) != 0 {
+		t.Errorf("got %d succeeded, want 0", len(store.succeeded))
+	}
+}
+
+func TestNotificationRetryWorker_ReschedulesWhenAuditLogReloadFails(t *testing.T) {
+	fn := domain.FailedNotification{ID: uuid.New(), AuditLogID: uuid.New(), TenantID: "tenant-a", Channel: "https://example.com/hook", Attempts: 1}
+	store := &fakeDeadLetterStore{due: []domain.FailedNotification{fn}}
+	logs := &fakeAuditLogGetter{err: domain.ErrNotFound}
+	webhook := &recordingSender{}
+	w := NewNotificationRetryWorker(store, logs, webhook, webhook, webhook)
+
+	w.sweep(context.Background())
+
+	if len(store.failed) != 1 || store.failed[0].ID != fn.ID {
+		t.Errorf("got failed %+v, want a reschedule of %s", store.failed, fn.ID)
+	}
+	if len(webhook.sent) != 0 {
+		t.Errorf("got %d deliveries, want 0 when the audit log can't be reloaded", len(webhook.sent))
+	}
+}
+
+func TestNotificationRetryWorker_StopsOnContextCancellation(t *testing.T) {
+	store := &fakeDeadLetterStore{}
+	logs := &fakeAuditLogGetter{}
+	webhook := &recordingSender{}
+	w := NewNotificationRetryWorker(store, logs, webhook, webhook, webhook)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		w.Run(ctx, time.Millisecond)
+		close(done)
+	}()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected Run to return after context cancellation")
+	}
+}
@@ -0,0 +1,535 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+)
+
+// Sender delivers a single audit log event to one webhook subscription.
+// rollupNote, when non-empty, is a human-readable summary (e.g.
+// "suppressed 42 similar alerts") to attach alongside the event, set by
+// the dispatcher when per-event throttling just reopened a window.
+type Sender interface {
+	Send(ctx context.Context, sub domain.WebhookSubscription, log domain.AuditLog, rollupNote string) error
+}
+
+// WebhookSender posts a JSON payload to generic webhook subscribers. Data
+// that exceeds MaxDataBytes is replaced with a link back to
+// GET /api/v1/audit/{id} rather than omitted silently.
+type WebhookSender struct {
+	Client       *http.Client
+	MaxDataBytes int
+	// PublicBaseURL is prepended to the audit log ID to build the
+	// fetch-full-record link, e.g. "https://api.example.com".
+	PublicBaseURL string
+
+	// MaxRetries and RetryBackoff configure retry of transient delivery
+	// failures (network errors and 5xx responses only — see
+	// postWithRetry). MaxRetries <= 0 disables retries, sending once.
+	MaxRetries   int
+	RetryBackoff time.Duration
+
+	// SigningSecret, when non-empty, makes Send attach X-Audit-Signature
+	// and X-Audit-Timestamp headers (see SignWebhookPayload) so receivers
+	// can verify a delivery actually came from us. Empty disables signing.
+	SigningSecret string
+}
+
+// NewWebhookSender returns a sender with a sane default HTTP timeout and
+// retries disabled; chain WithRetry to enable them.
+func NewWebhookSender(maxDataBytes int, publicBaseURL string) *WebhookSender {
+	return &WebhookSender{
+		Client:        &http.Client{Timeout: 10 * time.Second},
+		MaxDataBytes:  maxDataBytes,
+		PublicBaseURL: publicBaseURL,
+	}
+}
+
+// WithRetry sets the retry count and base backoff and returns s for
+// chaining. See postWithRetry for the retry/backoff behavior.
+func (s *WebhookSender) WithRetry(maxRetries int, backoff time.Duration) *WebhookSender {
+	s.MaxRetries = maxRetries
+	s.RetryBackoff = backoff
+	return s
+}
+
+// WithSigningSecret sets the HMAC secret used to sign outgoing payloads
+// and returns s for chaining. See SignWebhookPayload.
+func (s *WebhookSender) WithSigningSecret(secret string) *WebhookSender {
+	s.SigningSecret = secret
+	return s
+}
+
+func (s *WebhookSender) Send(ctx context.Context, sub domain.WebhookSubscription, log domain.AuditLog, rollupNote string) error {
+	body, err := buildWebhookPayload(log, s.MaxDataBytes, rollupNote, func(l domain.AuditLog) string {
+		return fmt.Sprintf("%s/api/v1/audit/%s?tenant_id=%s", s.PublicBaseURL, l.ID, l.TenantID)
+	})
+	if err != nil {
+		return err
+	}
+
+	secret := s.SigningSecret
+	if sub.Secret != "" {
+		secret = sub.Secret
+	}
+
+	var headers map[string]string
+	if secret != "" {
+		timestamp := time.Now().Unix()
+		headers = map[string]string{
+			"X-Audit-Timestamp": strconv.FormatInt(timestamp, 10),
+			"X-Audit-Signature": SignWebhookPayload(secret, timestamp, body),
+		}
+	}
+
+	resp, err := postWithRetry(ctx, s.Client, sub.URL, body, headers, s.MaxRetries, s.RetryBackoff, "webhook")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("notify: webhook %s returned status %d", sub.URL, resp.StatusCode)
+	}
+	return nil
+}
+
+// postWithRetry POSTs body to url as application/json, setting any extra
+// headers (e.g. the signing headers set by WebhookSender.Send) on every
+// attempt, and retrying on network errors and 5xx responses (never 4xx,
+// which are treated as permanent) until maxRetries is exhausted. Each
+// retry waits backoffBase * 2^attempt with full jitter, and aborts early
+// if ctx is done. maxRetries <= 0 disables retries, sending exactly once.
+// A non-5xx response (including 3xx/4xx) is returned to the caller as-is,
+// without being retried or treated as an error here — the caller decides
+// what counts as success.
+func postWithRetry(ctx context.Context, client *http.Client, url string, body []byte, headers map[string]string, maxRetries int, backoffBase time.Duration, logPrefix string) (*http.Response, error) {
+	var lastErr error
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("notify: build %s request: %w", logPrefix, err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+
+		resp, doErr := client.Do(req)
+		switch {
+		case doErr == nil && resp.StatusCode < 500:
+			return resp, nil
+		case doErr == nil:
+			lastErr = fmt.Errorf("notify: %s %s returned status %d", logPrefix, url, resp.StatusCode)
+			resp.Body.Close()
+		default:
+			lastErr = fmt.Errorf("notify: %s delivery to %s: %w", logPrefix, url, doErr)
+		}
+
+		if attempt >= maxRetries {
+			return nil, lastErr
+		}
+
+		delay := backoffBase * time.Duration(1<<attempt)
+		if delay > 0 {
+			delay = time.Duration(rand.Int63n(int64(delay)))
+		}
+		log.Printf("notify: %s attempt %d failed, retrying in %s: %v", logPrefix, attempt+1, delay, lastErr)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// BatchingWebhookSender wraps a WebhookSender, buffering events for
+// subscriptions that opt in (WebhookSubscription.BatchMaxSize > 0) and
+// flushing them as a single JSON array POST once the batch reaches
+// BatchMaxSize or its linger deadline passes, whichever comes first.
+// Subscriptions that don't opt in are delivered immediately through the
+// wrapped sender, unchanged.
+type BatchingWebhookSender struct {
+	single *WebhookSender
+
+	mu      sync.Mutex
+	batches map[uuid.UUID]*webhookBatch
+}
+
+type webhookBatch struct {
+	sub      domain.WebhookSubscription
+	payloads []webhookPayload
+	deadline time.Time
+}
+
+// NewBatchingWebhookSender returns a batching sender that falls back to
+// single for subscriptions with batching disabled.
+func NewBatchingWebhookSender(single *WebhookSender) *BatchingWebhookSender {
+	return &BatchingWebhookSender{single: single, batches: make(map[uuid.UUID]*webhookBatch)}
+}
+
+func (s *BatchingWebhookSender) Send(ctx context.Context, sub domain.WebhookSubscription, log domain.AuditLog, rollupNote string) error {
+	if sub.BatchMaxSize <= 0 {
+		return s.single.Send(ctx, sub, log, rollupNote)
+	}
+
+	payload, err := newWebhookPayload(log, s.single.MaxDataBytes, rollupNote, func(l domain.AuditLog) string {
+		return fmt.Sprintf("%s/api/v1/audit/%s?tenant_id=%s", s.single.PublicBaseURL, l.ID, l.TenantID)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	batch, ok := s.batches[sub.ID]
+	if !ok {
+		batch = &webhookBatch{sub: sub, deadline: time.Now().Add(time.Duration(sub.BatchLingerSeconds) * time.Second)}
+		s.batches[sub.ID] = batch
+	}
+	batch.payloads = append(batch.payloads, payload)
+	var toFlush *webhookBatch
+	if len(batch.payloads) >= sub.BatchMaxSize {
+		toFlush = batch
+		delete(s.batches, sub.ID)
+	}
+	s.mu.Unlock()
+
+	if toFlush != nil {
+		return s.deliver(ctx, toFlush)
+	}
+	return nil
+}
+
+// Run periodically flushes batches whose linger deadline has passed. It is
+// meant to be started once, in its own goroutine, at service startup.
+func (s *BatchingWebhookSender) Run(ctx context.Context, tick time.Duration) {
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.flushAll()
+			return
+		case <-ticker.C:
+			s.flushExpired()
+		}
+	}
+}
+
+func (s *BatchingWebhookSender) flushExpired() {
+	now := time.Now()
+	var due []*webhookBatch
+	s.mu.Lock()
+	for id, batch := range s.batches {
+		if now.After(batch.deadline) {
+			due = append(due, batch)
+			delete(s.batches, id)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, batch := range due {
+		if err := s.deliver(context.Background(), batch); err != nil {
+			log.Printf("notify: deliver webhook batch to %s: %v", batch.sub.URL, err)
+		}
+	}
+}
+
+func (s *BatchingWebhookSender) flushAll() {
+	s.mu.Lock()
+	batches := make([]*webhookBatch, 0, len(s.batches))
+	for id, batch := range s.batches {
+		batches = append(batches, batch)
+		delete(s.batches, id)
+	}
+	s.mu.Unlock()
+
+	for _, batch := range batches {
+		if err := s.deliver(context.Background(), batch); err != nil {
+			log.Printf("notify: deliver webhook batch to %s: %v", batch.sub.URL, err)
+		}
+	}
+}
+
+func (s *BatchingWebhookSender) deliver(ctx context.Context, batch *webhookBatch) error {
+	body, err := json.Marshal(batch.payloads)
+	if err != nil {
+		return fmt.Errorf("notify: marshal webhook batch: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, batch.sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("notify: build webhook batch request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.single.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("notify: webhook batch delivery to %s: %w", batch.sub.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("notify: webhook batch %s returned status %d", batch.sub.URL, resp.StatusCode)
+	}
+	return nil
+}
+
+// SlackSender posts a Block Kit message to a Slack incoming-webhook URL,
+// color-coded by the event's inferred severity (see severityForEvent)
+// with a button linking back to the audit log's detail URL. Slack
+// rejects overly long messages, so the plain-text fallback is truncated
+// to MaxMessageLength before sending; the Block Kit fields are not
+// truncated, since Slack's own block-level limits are far higher than a
+// typical audit log summary.
+type SlackSender struct {
+	Client           *http.Client
+	MaxMessageLength int
+	// PublicBaseURL is prepended to the audit log ID to build the detail
+	// link sent as the message's button, e.g. "https://api.example.com".
+	// Empty disables the button.
+	PublicBaseURL string
+
+	// MaxRetries and RetryBackoff configure retry of transient delivery
+	// failures; see WebhookSender.MaxRetries/RetryBackoff and
+	// postWithRetry.
+	MaxRetries   int
+	RetryBackoff time.Duration
+}
+
+// NewSlackSender returns a sender with a sane default HTTP timeout and
+// retries disabled; chain WithRetry to enable them.
+func NewSlackSender(maxMessageLength int, publicBaseURL string) *SlackSender {
+	return &SlackSender{
+		Client:           &http.Client{Timeout: 10 * time.Second},
+		MaxMessageLength: maxMessageLength,
+		PublicBaseURL:    publicBaseURL,
+	}
+}
+
+// WithRetry sets the retry count and base backoff and returns s for
+// chaining. See postWithRetry for the retry/backoff behavior.
+func (s *SlackSender) WithRetry(maxRetries int, backoff time.Duration) *SlackSender {
+	s.MaxRetries = maxRetries
+	s.RetryBackoff = backoff
+	return s
+}
+
+// slackMessage is a Slack incoming-webhook payload. Text is the
+// plain-text fallback shown in notifications and by clients that don't
+// render Block Kit; Attachments carries the color-coded, structured
+// rendering everything else sees.
+type slackMessage struct {
+	Text        string            `json:"text"`
+	Attachments []slackAttachment `json:"attachments,omitempty"`
+}
+
+// slackAttachment is a Block Kit secondary attachment: Color renders as
+// a vertical bar alongside Blocks, the one piece of visual severity
+// coding attachments still support that blocks alone don't.
+type slackAttachment struct {
+	Color  string       `json:"color"`
+	Blocks []slackBlock `json:"blocks,omitempty"`
+}
+
+// slackBlock is a single Block Kit block. Only the fields used by the
+// block types this sender emits (section, actions) are modeled; see
+// https://api.slack.com/reference/block-kit/blocks.
+type slackBlock struct {
+	Type     string         `json:"type"`
+	Text     *slackText     `json:"text,omitempty"`
+	Fields   []slackText    `json:"fields,omitempty"`
+	Elements []slackElement `json:"elements,omitempty"`
+}
+
+// slackText is a Block Kit text composition object.
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// slackElement is a Block Kit interactive element; this sender only
+// emits "button" elements.
+type slackElement struct {
+	Type string     `json:"type"`
+	Text *slackText `json:"text,omitempty"`
+	URL  string     `json:"url,omitempty"`
+}
+
+// slackColorForEvent returns a Block Kit attachment color (a "#RRGGBB"
+// hex string) for log's inferred severity, reusing the same
+// classification TeamsSender uses for its card's themeColor.
+func slackColorForEvent(event string) string {
+	return "#" + severityForEvent(event)
+}
+
+// buildSlackMessage renders log into a Block Kit message: a section
+// block with the event summary as fields, plus a button linking to the
+// record's detail URL when publicBaseURL is set, wrapped in a
+// color-coded attachment. text is kept as the plain-text fallback Slack
+// shows in notifications and to clients that don't render Block Kit.
+func buildSlackMessage(log domain.AuditLog, rollupNote, publicBaseURL string, maxMessageLength int) slackMessage {
+	text := fmt.Sprintf("[%s] %s on %s/%s by %s", log.TenantID, log.Event, log.Resource, log.ResourceID, log.Actor)
+	if rollupNote != "" {
+		text = fmt.Sprintf("%s (%s)", text, rollupNote)
+	}
+	text = TruncateMessage(text, maxMessageLength)
+
+	fields := []slackText{
+		{Type: "mrkdwn", Text: fmt.Sprintf("*Tenant*\n%s", log.TenantID)},
+		{Type: "mrkdwn", Text: fmt.Sprintf("*Event*\n%s", log.Event)},
+		{Type: "mrkdwn", Text: fmt.Sprintf("*Resource*\n%s/%s", log.Resource, log.ResourceID)},
+		{Type: "mrkdwn", Text: fmt.Sprintf("*Actor*\n%s", log.Actor)},
+	}
+	blocks := []slackBlock{
+		{Type: "section", Text: &slackText{Type: "mrkdwn", Text: text}, Fields: fields},
+	}
+	if rollupNote != "" {
+		blocks = append(blocks, slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: "_" + rollupNote + "_"}})
+	}
+	if publicBaseURL != "" {
+		detailURL := fmt.Sprintf("%s/api/v1/audit/%s?tenant_id=%s", publicBaseURL, log.ID, log.TenantID)
+		blocks = append(blocks, slackBlock{
+			Type: "actions",
+			Elements: []slackElement{
+				{Type: "button", Text: &slackText{Type: "plain_text", Text: "View audit log"}, URL: detailURL},
+			},
+		})
+	}
+
+	return slackMessage{
+		Text: text,
+		Attachments: []slackAttachment{
+			{Color: slackColorForEvent(log.Event), Blocks: blocks},
+		},
+	}
+}
+
+func (s *SlackSender) Send(ctx context.Context, sub domain.WebhookSubscription, log domain.AuditLog, rollupNote string) error {
+	body, err := json.Marshal(buildSlackMessage(log, rollupNote, s.PublicBaseURL, s.MaxMessageLength))
+	if err != nil {
+		return fmt.Errorf("notify: marshal slack message: %w", err)
+	}
+
+	resp, err := postWithRetry(ctx, s.Client, sub.URL, body, nil, s.MaxRetries, s.RetryBackoff, "slack")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("notify: slack webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// isSlackURL reports whether url points at a Slack incoming webhook, so
+// the dispatcher can pick the right formatter automatically without
+// requiring subscribers to declare a channel type.
+func isSlackURL(url string) bool {
+	return strings.Contains(url, "hooks.slack.com")
+}
+
+// TeamsSender posts a MessageCard to a Microsoft Teams incoming webhook
+// URL, color-coded by the event's inferred severity (see
+// severityForEvent). AuditLog has no explicit severity field, so this is a
+// best-effort classification from the event name rather than an accurate
+// read of caller intent.
+type TeamsSender struct {
+	Client *http.Client
+}
+
+// NewTeamsSender returns a sender with a sane default HTTP timeout.
+func NewTeamsSender() *TeamsSender {
+	return &TeamsSender{Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// teamsCard is a Microsoft Teams "MessageCard", the connector format Teams
+// incoming webhooks accept (the newer Adaptive Card schema requires the
+// Workflows connector, which isn't universally available yet).
+type teamsCard struct {
+	Type       string `json:"@type"`
+	Context    string `json:"@context"`
+	ThemeColor string `json:"themeColor"`
+	Summary    string `json:"summary"`
+	Text       string `json:"text"`
+}
+
+const (
+	teamsColorCritical = "D32F2F" // red: destructive or failure events
+	teamsColorWarning  = "F9A825" // amber: denied/blocked events
+	teamsColorInfo     = "2E7D32" // green: everything else
+)
+
+// severityForEvent infers a display severity from an event name, since
+// AuditLog has no explicit severity field. Matches are substring and
+// case-insensitive so "USER_DELETED" and "user.deleted" both classify the
+// same way.
+func severityForEvent(event string) string {
+	upper := strings.ToUpper(event)
+	switch {
+	case strings.Contains(upper, "DELETE"), strings.Contains(upper, "FAIL"), strings.Contains(upper, "ERROR"):
+		return teamsColorCritical
+	case strings.Contains(upper, "DENY"), strings.Contains(upper, "DENIED"), strings.Contains(upper, "BLOCK"):
+		return teamsColorWarning
+	default:
+		return teamsColorInfo
+	}
+}
+
+func (s *TeamsSender) Send(ctx context.Context, sub domain.WebhookSubscription, log domain.AuditLog, rollupNote string) error {
+	text := fmt.Sprintf("**%s** on %s/%s by %s (tenant %s)", log.Event, log.Resource, log.ResourceID, log.Actor, log.TenantID)
+	if rollupNote != "" {
+		text = fmt.Sprintf("%s\n\n_%s_", text, rollupNote)
+	}
+
+	body, err := json.Marshal(teamsCard{
+		Type:       "MessageCard",
+		Context:    "http://schema.org/extensions",
+		ThemeColor: severityForEvent(log.Event),
+		Summary:    log.Event,
+		Text:       text,
+	})
+	if err != nil {
+		return fmt.Errorf("notify: marshal teams card: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("notify: build teams request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("notify: teams delivery to %s: %w", sub.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("notify: teams webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// isTeamsURL reports whether url points at a Microsoft Teams incoming
+// webhook (either the classic connector host or the newer Power Automate
+// workflow trigger host), so the dispatcher can pick the right formatter
+// automatically without requiring subscribers to declare a channel type.
+func isTeamsURL(url string) bool {
+	return strings.Contains(url, "webhook.office.com") || strings.Contains(url, ".logic.azure.com")
+}
@@ -0,0 +1,412 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+)
+
+func TestTeamsSender_PostsColorCodedCard(t *testing.T) {
+	var posted teamsCard
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&posted); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	sender := NewTeamsSender()
+	sub := domain.WebhookSubscription{ID: uuid.New(), URL: server.URL}
+	log := domain.AuditLog{TenantID: "tenant-a", Event: "USER_DELETED", Resource: "user", ResourceID: "u1", Actor: "admin"}
+
+	if err := sender.Send(context.Background(), sub, log, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if posted.Type != "MessageCard" {
+		t.Errorf("got @type=%q, want MessageCard", posted.Type)
+	}
+	if posted.ThemeColor != teamsColorCritical {
+		t.Errorf("got ThemeColor=%q, want %q for a DELETE event", posted.ThemeColor, teamsColorCritical)
+	}
+	if !strings.Contains(posted.Text, "USER_DELETED") || !strings.Contains(posted.Text, "admin") {
+		t.Errorf("got Text=%q, want it to mention the event and actor", posted.Text)
+	}
+}
+
+func TestTeamsSender_AppendsRollupNote(t *testing.T) {
+	var posted teamsCard
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&posted)
+	}))
+	defer server.Close()
+
+	sender := NewTeamsSender()
+	sub := domain.WebhookSubscription{ID: uuid.New(), URL: server.URL}
+	log := domain.AuditLog{Event: "USER_LOGIN"}
+
+	if err := sender.Send(context.Background(), sub, log, "suppressed 3 similar alert(s)"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(posted.Text, "suppressed 3 similar alert(s)") {
+		t.Errorf("got Text=%q, want it to include the rollup note", posted.Text)
+	}
+}
+
+func TestBuildSlackMessage_ProducesColorCodedBlockKitStructure(t *testing.T) {
+	log := domain.AuditLog{ID: uuid.New(), TenantID: "tenant-a", Event: "USER_DELETED", Resource: "user", ResourceID: "u1", Actor: "admin"}
+
+	msg := buildSlackMessage(log, "", "https://api.example.com", 4000)
+
+	if msg.Text == "" {
+		t.Error("expected a non-empty plain-text fallback")
+	}
+	if !strings.Contains(msg.Text, "USER_DELETED") || !strings.Contains(msg.Text, "admin") {
+		t.Errorf("got Text=%q, want it to mention the event and actor", msg.Text)
+	}
+
+	if len(msg.Attachments) != 1 {
+		t.Fatalf("got %d attachments, want 1", len(msg.Attachments))
+	}
+	attachment := msg.Attachments[0]
+	if attachment.Color != "#"+teamsColorCritical {
+		t.Errorf("got Color=%q, want %q for a DELETE event", attachment.Color, "#"+teamsColorCritical)
+	}
+
+	if len(attachment.Blocks) < 2 {
+		t.Fatalf("got %d blocks, want at least a section and an actions block", len(attachment.Blocks))
+	}
+	section := attachment.Blocks[0]
+	if section.Type != "section" {
+		t.Errorf("got first block type %q, want %q", section.Type, "section")
+	}
+	if len(section.Fields) != 4 {
+		t.Errorf("got %d fields, want 4 (tenant, event, resource, actor)", len(section.Fields))
+	}
+
+	actions := attachment.Blocks[len(attachment.Blocks)-1]
+	if actions.Type != "actions" {
+		t.Fatalf("got last block type %q, want %q", actions.Type, "actions")
+	}
+	if len(actions.Elements) != 1 || actions.Elements[0].Type != "button" {
+		t.Fatalf("got elements %+v, want a single button", actions.Elements)
+	}
+	wantURL := "https://api.example.com/api/v1/audit/" + log.ID.String() + "?tenant_id=tenant-a"
+	if actions.Elements[0].URL != wantURL {
+		t.Errorf("got button URL %q, want %q", actions.Elements[0].URL, wantURL)
+	}
+
+	body, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var roundTripped map[string]any
+	if err := json.Unmarshal(body, &roundTripped); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if _, ok := roundTripped["text"]; !ok {
+		t.Error("expected top-level \"text\" key for the plain-text fallback")
+	}
+}
+
+func TestBuildSlackMessage_OmitsButtonWhenNoBaseURL(t *testing.T) {
+	log := domain.AuditLog{ID: uuid.New(), TenantID: "tenant-a", Event: "user.login"}
+
+	msg := buildSlackMessage(log, "", "", 4000)
+
+	for _, block := range msg.Attachments[0].Blocks {
+		if block.Type == "actions" {
+			t.Fatalf("expected no actions block when publicBaseURL is empty, got %+v", block)
+		}
+	}
+}
+
+func TestBuildSlackMessage_AppendsRollupNoteBlock(t *testing.T) {
+	log := domain.AuditLog{Event: "user.login"}
+
+	msg := buildSlackMessage(log, "suppressed 3 similar alert(s)", "", 4000)
+
+	var found bool
+	for _, block := range msg.Attachments[0].Blocks {
+		if block.Text != nil && strings.Contains(block.Text.Text, "suppressed 3 similar alert(s)") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected a block containing the rollup note, got %+v", msg.Attachments[0].Blocks)
+	}
+}
+
+func TestSlackSender_Send_PostsBuiltMessage(t *testing.T) {
+	var posted slackMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&posted); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	sender := NewSlackSender(4000, "https://api.example.com")
+	sub := domain.WebhookSubscription{ID: uuid.New(), URL: server.URL}
+	log := domain.AuditLog{ID: uuid.New(), TenantID: "tenant-a", Event: "user.login", Actor: "alice"}
+
+	if err := sender.Send(context.Background(), sub, log, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(posted.Attachments) != 1 {
+		t.Fatalf("got %d attachments, want 1", len(posted.Attachments))
+	}
+}
+
+func TestSeverityForEvent(t *testing.T) {
+	cases := map[string]string{
+		"USER_DELETED":    teamsColorCritical,
+		"login.failed":    teamsColorCritical,
+		"ACCESS_DENIED":   teamsColorWarning,
+		"REQUEST_BLOCKED": teamsColorWarning,
+		"USER_CREATED":    teamsColorInfo,
+	}
+	for event, want := range cases {
+		if got := severityForEvent(event); got != want {
+			t.Errorf("severityForEvent(%q) = %q, want %q", event, got, want)
+		}
+	}
+}
+
+func TestIsTeamsURL(t *testing.T) {
+	cases := map[string]bool{
+		"https://example.webhook.office.com/webhookb2/x":     true,
+		"https://example.westus.logic.azure.com/workflows/x": true,
+		"https://hooks.slack.com/services/x":                 false,
+		"https://example.com/hook":                           false,
+	}
+	for url, want := range cases {
+		if got := isTeamsURL(url); got != want {
+			t.Errorf("isTeamsURL(%q) = %v, want %v", url, got, want)
+		}
+	}
+}
+
+func TestWebhookSender_RetriesOn503ThenSucceeds(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if n := atomic.AddInt32(&attempts, 1); n <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sender := NewWebhookSender(0, "").WithRetry(3, time.Millisecond)
+	sub := domain.WebhookSubscription{ID: uuid.New(), URL: server.URL}
+
+	if err := sender.Send(context.Background(), sub, domain.AuditLog{Event: "e"}, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Errorf("got %d attempts, want 3", got)
+	}
+}
+
+func TestWebhookSender_GivesUpAfterMaxRetries(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	sender := NewWebhookSender(0, "").WithRetry(2, time.Millisecond)
+	sub := domain.WebhookSubscription{ID: uuid.New(), URL: server.URL}
+
+	if err := sender.Send(context.Background(), sub, domain.AuditLog{Event: "e"}, ""); err == nil {
+		t.Fatal("expected an error after exhausting retries")
+	}
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Errorf("got %d attempts, want 3 (1 initial + 2 retries)", got)
+	}
+}
+
+func TestWebhookSender_DoesNotRetryOn4xx(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	sender := NewWebhookSender(0, "").WithRetry(3, time.Millisecond)
+	sub := domain.WebhookSubscription{ID: uuid.New(), URL: server.URL}
+
+	if err := sender.Send(context.Background(), sub, domain.AuditLog{Event: "e"}, ""); err == nil {
+		t.Fatal("expected an error for a 4xx response")
+	}
+	if got := atomic.LoadInt32(&attempts); got != 1 {
+		t.Errorf("got %d attempts, want 1 (4xx must not be retried)", got)
+	}
+}
+
+func TestWebhookSender_SignsRequestWhenSecretConfigured(t *testing.T) {
+	var gotTimestamp, gotSignature string
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotTimestamp = r.Header.Get("X-Audit-Timestamp")
+		gotSignature = r.Header.Get("X-Audit-Signature")
+		gotBody, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	sender := NewWebhookSender(0, "").WithSigningSecret("secret")
+	sub := domain.WebhookSubscription{ID: uuid.New(), URL: server.URL}
+
+	if err := sender.Send(context.Background(), sub, domain.AuditLog{Event: "e"}, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotTimestamp == "" {
+		t.Fatal("expected X-Audit-Timestamp to be set")
+	}
+	ts, err := strconv.ParseInt(gotTimestamp, 10, 64)
+	if err != nil {
+		t.Fatalf("X-Audit-Timestamp not an integer: %v", err)
+	}
+	if want := SignWebhookPayload("secret", ts, gotBody); gotSignature != want {
+		t.Errorf("got X-Audit-Signature=%q, want %q", gotSignature, want)
+	}
+}
+
+func TestWebhookSender_SubscriptionSecretOverridesServiceWideSecret(t *testing.T) {
+	var gotTimestamp, gotSignature string
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotTimestamp = r.Header.Get("X-Audit-Timestamp")
+		gotSignature = r.Header.Get("X-Audit-Signature")
+		gotBody, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	sender := NewWebhookSender(0, "").WithSigningSecret("service-wide-secret")
+	sub := domain.WebhookSubscription{ID: uuid.New(), URL: server.URL, Secret: "tenant-secret"}
+
+	if err := sender.Send(context.Background(), sub, domain.AuditLog{Event: "e"}, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ts, err := strconv.ParseInt(gotTimestamp, 10, 64)
+	if err != nil {
+		t.Fatalf("X-Audit-Timestamp not an integer: %v", err)
+	}
+	if want := SignWebhookPayload("tenant-secret", ts, gotBody); gotSignature != want {
+		t.Errorf("got X-Audit-Signature=%q, want signed with the subscription's own secret %q", gotSignature, want)
+	}
+}
+
+func TestWebhookSender_NoSignatureHeaderWhenSecretUnset(t *testing.T) {
+	var gotSignature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSignature = r.Header.Get("X-Audit-Signature")
+	}))
+	defer server.Close()
+
+	sender := NewWebhookSender(0, "")
+	sub := domain.WebhookSubscription{ID: uuid.New(), URL: server.URL}
+
+	if err := sender.Send(context.Background(), sub, domain.AuditLog{Event: "e"}, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotSignature != "" {
+		t.Errorf("got X-Audit-Signature=%q, want empty when no secret is configured", gotSignature)
+	}
+}
+
+func TestBatchingWebhookSender_DeliversImmediatelyWhenBatchingDisabled(t *testing.T) {
+	var posts [][]byte
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := make([]byte, r.ContentLength)
+		r.Body.Read(body)
+		mu.Lock()
+		posts = append(posts, body)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	sender := NewBatchingWebhookSender(NewWebhookSender(0, ""))
+	sub := domain.WebhookSubscription{ID: uuid.New(), URL: server.URL}
+
+	if err := sender.Send(context.Background(), sub, domain.AuditLog{Event: "e"}, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(posts) != 1 {
+		t.Fatalf("got %d posts, want 1", len(posts))
+	}
+}
+
+func TestBatchingWebhookSender_FlushesAtMaxBatchSize(t *testing.T) {
+	var batches [][]webhookPayload
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payloads []webhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&payloads); err != nil {
+			t.Errorf("decode batch: %v", err)
+		}
+		mu.Lock()
+		batches = append(batches, payloads)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	sender := NewBatchingWebhookSender(NewWebhookSender(0, ""))
+	sub := domain.WebhookSubscription{ID: uuid.New(), URL: server.URL, BatchMaxSize: 2, BatchLingerSeconds: 60}
+
+	for i := 0; i < 2; i++ {
+		if err := sender.Send(context.Background(), sub, domain.AuditLog{Event: "e"}, ""); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(batches) != 1 || len(batches[0]) != 2 {
+		t.Fatalf("got batches %+v, want one batch of 2", batches)
+	}
+}
+
+func TestBatchingWebhookSender_FlushesOnLingerExpiry(t *testing.T) {
+	flushed := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flushed <- struct{}{}
+	}))
+	defer server.Close()
+
+	sender := NewBatchingWebhookSender(NewWebhookSender(0, ""))
+	sub := domain.WebhookSubscription{ID: uuid.New(), URL: server.URL, BatchMaxSize: 100, BatchLingerSeconds: 0}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go sender.Run(ctx, 10*time.Millisecond)
+
+	if err := sender.Send(context.Background(), sub, domain.AuditLog{Event: "e"}, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case <-flushed:
+	case <-time.After(time.Second):
+		t.Fatal("expected batch to flush after linger deadline")
+	}
+}
@@ -0,0 +1,34 @@
+package notify
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+// SignWebhookPayload computes the X-Audit-Signature header value for body,
+// keyed with secret and bound to timestamp (the same value sent as
+// X-Audit-Timestamp) so a captured request can't be replayed indefinitely.
+//
+// The canonical signing string is "<timestamp>.<body>" (the decimal Unix
+// timestamp, a literal ".", then the raw request body), HMAC-SHA256'd with
+// secret. The returned header value is "sha256=<hex digest>".
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature is a reference implementation receivers can copy: it
+// reports whether signature (the X-Audit-Signature header value) is valid
+// for body as signed with secret and timestamp (the X-Audit-Timestamp
+// header value, parsed to a Unix timestamp). Callers should also reject
+// timestamps outside an acceptable clock-skew window themselves — this
+// only checks the signature, not its age.
+func VerifySignature(secret string, timestamp int64, body []byte, signature string) bool {
+	want := SignWebhookPayload(secret, timestamp, body)
+	return hmac.Equal([]byte(want), []byte(signature))
+}
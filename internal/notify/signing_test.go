@@ -0,0 +1,39 @@
+package notify
+
+import "testing"
+
+func TestSignWebhookPayload_MatchesKnownVector(t *testing.T) {
+	// Computed independently: HMAC-SHA256("secret", "1700000000.{\"event\":\"e\"}").
+	got := SignWebhookPayload("secret", 1700000000, []byte(`{"event":"e"}`))
+	want := "sha256=5e11beed2f1b6ef68697f248559c525fc6ce8acc5abffc826ed88ea3490df518"
+
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestVerifySignature_AcceptsMatchingSignature(t *testing.T) {
+	body := []byte(`{"event":"e"}`)
+	sig := SignWebhookPayload("secret", 1700000000, body)
+
+	if !VerifySignature("secret", 1700000000, body, sig) {
+		t.Error("expected a freshly computed signature to verify")
+	}
+}
+
+func TestVerifySignature_RejectsTamperedBody(t *testing.T) {
+	sig := SignWebhookPayload("secret", 1700000000, []byte(`{"event":"e"}`))
+
+	if VerifySignature("secret", 1700000000, []byte(`{"event":"tampered"}`), sig) {
+		t.Error("expected verification to fail for a tampered body")
+	}
+}
+
+func TestVerifySignature_RejectsWrongSecret(t *testing.T) {
+	body := []byte(`{"event":"e"}`)
+	sig := SignWebhookPayload("secret", 1700000000, body)
+
+	if VerifySignature("wrong-secret", 1700000000, body, sig) {
+		t.Error("expected verification to fail for the wrong secret")
+	}
+}
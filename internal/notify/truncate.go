@@ -0,0 +1,22 @@
+// Package notify formats and delivers audit-log notifications to webhook
+// and Slack-compatible endpoints, applying per-channel size limits so a
+// large event can't get silently dropped or rejected by the receiver.
+package notify
+
+import "fmt"
+
+const truncationNote = "... [truncated, %d of %d bytes shown]"
+
+// TruncateMessage shortens msg to at most maxLen bytes, appending a note
+// that says how much was cut. maxLen <= 0 means no limit.
+func TruncateMessage(msg string, maxLen int) string {
+	if maxLen <= 0 || len(msg) <= maxLen {
+		return msg
+	}
+	note := fmt.Sprintf(truncationNote, maxLen, len(msg))
+	if maxLen <= len(note) {
+		return msg[:maxLen]
+	}
+	shown := maxLen - len(note)
+	return msg[:shown] + fmt.Sprintf(truncationNote, shown, len(msg))
+}
@@ -0,0 +1,31 @@
+package notify
+
+import "testing"
+
+func TestTruncateMessage_NoLimitReturnsUnchanged(t *testing.T) {
+	msg := "hello world"
+	if got := TruncateMessage(msg, 0); got != msg {
+		t.Errorf("got %q, want unchanged", got)
+	}
+}
+
+func TestTruncateMessage_ShortMessageUnchanged(t *testing.T) {
+	msg := "hello"
+	if got := TruncateMessage(msg, 100); got != msg {
+		t.Errorf("got %q, want unchanged", got)
+	}
+}
+
+func TestTruncateMessage_TruncatesAndAddsNote(t *testing.T) {
+	msg := ""
+	for i := 0; i < 200; i++ {
+		msg += "0123456789"
+	}
+	got := TruncateMessage(msg, 60)
+	if len(got) > 60 {
+		t.Errorf("got len %d, want <= 60", len(got))
+	}
+	if got == msg[:60] {
+		t.Error("expected a truncation note, got a plain substring")
+	}
+}
@@ -0,0 +1,44 @@
+// Package perf provides a small exponential-bucket histogram used to
+// classify request latencies for performance logging, without pulling in
+// a full metrics backend.
+package perf
+
+import (
+	"fmt"
+	"time"
+)
+
+// Histogram buckets durations into exponentially growing ranges, e.g.
+// [0,1ms) [1ms,2ms) [2ms,4ms) [4ms,8ms) ... [last, +Inf).
+type Histogram struct {
+	bounds []time.Duration
+}
+
+// NewExponentialHistogram builds a Histogram with `count` buckets starting
+// at `start` and doubling in width `count-1` times.
+func NewExponentialHistogram(start time.Duration, count int) Histogram {
+	bounds := make([]time.Duration, count)
+	b := start
+	for i := range bounds {
+		bounds[i] = b
+		b *= 2
+	}
+	return Histogram{bounds: bounds}
+}
+
+// Bucket returns the label of the bucket d falls into, e.g. "[4ms,8ms)"
+// or "[256ms,+Inf)" for anything past the last bound.
+func (h Histogram) Bucket(d time.Duration) string {
+	for i, bound := range h.bounds {
+		if d < bound {
+			if i == 0 {
+				return fmt.Sprintf("[0,%s)", bound)
+			}
+			return fmt.Sprintf("[%s,%s)", h.bounds[i-1], bound)
+		}
+	}
+	if len(h.bounds) == 0 {
+		return "[0,+Inf)"
+	}
+	return fmt.Sprintf("[%s,+Inf)", h.bounds[len(h.bounds)-1])
+}
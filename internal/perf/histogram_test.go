@@ -0,0 +1,25 @@
+package perf
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHistogram_Bucket(t *testing.T) {
+	h := NewExponentialHistogram(time.Millisecond, 4) // [0,1ms) [1ms,2ms) [2ms,4ms) [4ms,8ms) [8ms,+Inf)
+
+	cases := []struct {
+		d    time.Duration
+		want string
+	}{
+		{500 * time.Microsecond, "[0,1ms)"},
+		{1500 * time.Microsecond, "[1ms,2ms)"},
+		{3 * time.Millisecond, "[2ms,4ms)"},
+		{50 * time.Millisecond, "[8ms,+Inf)"},
+	}
+	for _, tc := range cases {
+		if got := h.Bucket(tc.d); got != tc.want {
+			t.Errorf("Bucket(%s) = %q, want %q", tc.d, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+// Package queue implements a durable, Redis-backed write-ahead queue for
+// service.AsyncWriteService, so buffered writes survive a process
+// restart instead of living only in memory (see AsyncWriteConfig.
+// PersistentQueueEnabled).
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+	"github.com/naveenkumar2412/audit-log-service/internal/resilience"
+)
+
+// key is the single Redis list the queue lives in. The service is
+// single-tenant-agnostic at this layer; tenant scoping happens in the
+// buffered domain.AuditLog itself, same as the in-memory queue it backs.
+const key = "audit:async_write_queue"
+
+// Entry is a durably-queued write, round-tripped through JSON so it can
+// live in a Redis list.
+type Entry struct {
+	TrackingID string          `json:"tracking_id"`
+	Log        domain.AuditLog `json:"log"`
+	Attempts   int             `json:"attempts"`
+	EnqueuedAt time.Time       `json:"enqueued_at"`
+}
+
+// RedisWAL is a FIFO queue backed by a Redis list: Push appends (RPUSH),
+// Pop removes from the head (LPOP). A failed write is expected to be
+// re-Pushed by the caller, which puts it back at the tail rather than
+// the head, so one stuck record can't block the rest of the queue
+// (head-of-line blocking) at the cost of strict ordering across retries.
+// Unlike dedup.RedisStore, a persistent queue is a durability guarantee,
+// so it fails closed: once breaker reports the circuit open, every
+// method returns an error immediately rather than attempting (and
+// waiting out the timeout of) a call that's unlikely to succeed.
+type RedisWAL struct {
+	client  *redis.Client
+	breaker *resilience.Breaker
+}
+
+// NewRedisWAL returns a queue backed by a Redis client connected to addr,
+// retrying per retry and tripping breaker on repeated failures (see
+// resilience.Breaker). breaker may be nil to disable tripping.
+func NewRedisWAL(addr string, retry resilience.RetryOptions, breaker *resilience.Breaker) *RedisWAL {
+	return &RedisWAL{
+		client: redis.NewClient(&redis.Options{
+			Addr:            addr,
+			MaxRetries:      retry.MaxRetries,
+			MinRetryBackoff: retry.MinRetryBackoff,
+			MaxRetryBackoff: retry.MaxRetryBackoff,
+		}),
+		breaker: breaker,
+	}
+}
+
+// ErrCircuitOpen is returned by RedisWAL's methods instead of attempting
+// a call while the circuit breaker is open.
+var ErrCircuitOpen = fmt.Errorf("queue: circuit breaker open, redis unavailable")
+
+// Push appends entry to the tail of the queue.
+func (q *RedisWAL) Push(ctx context.Context, entry Entry) error {
+	if !q.breaker.Allow() {
+		return ErrCircuitOpen
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("queue: marshal entry: %w", err)
+	}
+	if err := q.client.RPush(ctx, key, data).Err(); err != nil {
+		q.breaker.RecordFailure()
+		return fmt.Errorf("queue: push: %w", err)
+	}
+	q.breaker.RecordSuccess()
+	return nil
+}
+
+// Pop removes and returns up to max entries from the head of the queue.
+// It returns fewer than max (possibly zero) entries, never an error,
+// when the queue has less than max entries buffered.
+func (q *RedisWAL) Pop(ctx context.Context, max int) ([]Entry, error) {
+	if !q.breaker.Allow() {
+		return nil, ErrCircuitOpen
+	}
+
+	raws, err := q.client.LPopCount(ctx, key, max).Result()
+	if err == redis.Nil {
+		q.breaker.RecordSuccess()
+		return nil, nil
+	}
+	if err != nil {
+		q.breaker.RecordFailure()
+		return nil, fmt.Errorf("queue: pop: %w", err)
+	}
+	q.breaker.RecordSuccess()
+
+	entries := make([]Entry, 0, len(raws))
+	for _, raw := range raws {
+		var entry Entry
+		if err := json.Unmarshal([]byte(raw), &entry); err != nil {
+			return nil, fmt.Errorf("queue: unmarshal entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Len returns the current queue depth.
+func (q *RedisWAL) Len(ctx context.Context) (int64, error) {
+	if !q.breaker.Allow() {
+		return 0, ErrCircuitOpen
+	}
+
+	n, err := q.client.LLen(ctx, key).Result()
+	if err != nil {
+		q.breaker.RecordFailure()
+		return 0, fmt.Errorf("queue: len: %w", err)
+	}
+	q.breaker.RecordSuccess()
+	return n, nil
+}
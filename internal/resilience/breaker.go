@@ -0,0 +1,104 @@
+// Package resilience provides small call-reliability primitives shared by
+// every Redis-dependent feature in this repo (dedup, the async-write
+// persistent queue, and future callers), so a Redis outage degrades
+// instead of making every request wait out a failed call's timeout.
+package resilience
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// RetryOptions configures a client's built-in connection retry/backoff
+// (e.g. go-redis's Options.MaxRetries/MinRetryBackoff/MaxRetryBackoff).
+// A zero value disables retries.
+type RetryOptions struct {
+	MaxRetries      int
+	MinRetryBackoff time.Duration
+	MaxRetryBackoff time.Duration
+}
+
+// Breaker is a simple consecutive-failure circuit breaker: once Threshold
+// calls fail in a row it opens, rejecting calls via Allow without the
+// caller ever hitting the dependency, until Cooldown has passed, at which
+// point Allow lets calls through again (half-open) to test recovery. It
+// does not limit how many calls pass while half-open: every one does
+// until RecordSuccess closes the breaker or RecordFailure restarts the
+// cooldown.
+// A Breaker with a non-positive Threshold never opens, so it is safe to
+// always construct one and let configuration decide whether it's active.
+type Breaker struct {
+	name      string
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	failures int
+	open     bool
+	openedAt time.Time
+}
+
+// NewBreaker returns a breaker identified by name (used in log messages
+// on state transitions) that opens after threshold consecutive failures
+// and stays open for cooldown before allowing a trial call through.
+func NewBreaker(name string, threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{name: name, threshold: threshold, cooldown: cooldown}
+}
+
+// Allow reports whether the caller should attempt the call. Callers that
+// get false back should apply their feature's fail-open/fail-closed
+// policy themselves (see dedup.RedisStore and queue.RedisWAL) without
+// touching the dependency. A nil Breaker always allows, so it's safe to
+// use Allow/RecordSuccess/RecordFailure on a feature that didn't opt into
+// one.
+func (b *Breaker) Allow() bool {
+	if b == nil || b.threshold <= 0 {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.open {
+		return true
+	}
+	return time.Since(b.openedAt) >= b.cooldown
+}
+
+// RecordSuccess resets the failure count and closes the breaker if it was
+// open, logging the recovery.
+func (b *Breaker) RecordSuccess() {
+	if b == nil || b.threshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.open {
+		log.Printf("resilience: %s circuit breaker closed, call succeeded", b.name)
+	}
+	b.failures = 0
+	b.open = false
+}
+
+// RecordFailure counts a failed call, opening the breaker once threshold
+// consecutive failures are reached (or re-arming its cooldown if a
+// half-open trial call failed), logging the transition.
+func (b *Breaker) RecordFailure() {
+	if b == nil || b.threshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	if b.open {
+		b.openedAt = time.Now()
+		return
+	}
+	if b.failures >= b.threshold {
+		b.open = true
+		b.openedAt = time.Now()
+		log.Printf("resilience: %s circuit breaker open after %d consecutive failures", b.name, b.failures)
+	}
+}
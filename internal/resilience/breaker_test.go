@@ -0,0 +1,65 @@
+package resilience
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBreaker_OpensAfterThresholdFailures(t *testing.T) {
+	b := NewBreaker("test", 3, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		b.RecordFailure()
+		if !b.Allow() {
+			t.Fatalf("expected breaker to stay closed before threshold (failure %d)", i+1)
+		}
+	}
+
+	b.RecordFailure()
+	if b.Allow() {
+		t.Fatal("expected breaker to open after threshold consecutive failures")
+	}
+}
+
+func TestBreaker_ClosesOnSuccess(t *testing.T) {
+	b := NewBreaker("test", 1, time.Minute)
+
+	b.RecordFailure()
+	if b.Allow() {
+		t.Fatal("expected breaker to open after one failure")
+	}
+
+	b.RecordSuccess()
+	if !b.Allow() {
+		t.Fatal("expected breaker to close again after RecordSuccess")
+	}
+}
+
+func TestBreaker_AllowsTrialCallAfterCooldown(t *testing.T) {
+	b := NewBreaker("test", 1, 0)
+
+	b.RecordFailure()
+	if !b.Allow() {
+		t.Fatal("expected breaker to allow a trial call once cooldown has elapsed")
+	}
+}
+
+func TestBreaker_ZeroThresholdNeverOpens(t *testing.T) {
+	b := NewBreaker("test", 0, time.Minute)
+
+	for i := 0; i < 10; i++ {
+		b.RecordFailure()
+	}
+	if !b.Allow() {
+		t.Fatal("expected a zero-threshold breaker to never open")
+	}
+}
+
+func TestBreaker_NilIsAlwaysAllowed(t *testing.T) {
+	var b *Breaker
+
+	b.RecordFailure()
+	if !b.Allow() {
+		t.Fatal("expected a nil breaker to always allow")
+	}
+}
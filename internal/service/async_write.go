@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+	"github.com/naveenkumar2412/audit-log-service/internal/queue"
+)
+
+// PersistentQueue backs AsyncWriteService's buffer with durable storage
+// (see queue.RedisWAL) so pending writes survive a process restart
+// instead of living only in memory. It is optional: an AsyncWriteService
+// without one keeps the original best-effort, in-memory-only behavior.
+type PersistentQueue interface {
+	Push(ctx context.Context, entry queue.Entry) error
+	Pop(ctx context.Context, max int) ([]queue.Entry, error)
+	Len(ctx context.Context) (int64, error)
+}
+
+// AsyncWriteService buffers audit logs and flushes them to the repo in
+// batches on a timer, trading durability for ingestion throughput. A
+// failed flush is retried with exponential backoff up to the maxAttempts
+// given to WithRetry before being given up on. Without a PersistentQueue (see WithPersistentQueue),
+// the buffer lives only in process memory, so a crash between Enqueue and
+// the next successful flush loses the record; callers that need a strict
+// durability guarantee should use AuditService.CreateAuditLog instead.
+type AsyncWriteService struct {
+	repo          Repo
+	batchSize     int
+	flushInterval time.Duration
+	maxAttempts   int
+	retryBackoff  time.Duration
+	persistent    PersistentQueue
+
+	statusTransitions map[string][]string
+
+	mu       sync.Mutex
+	queue    []queuedWrite
+	tracking map[string]*domain.WriteTracking
+}
+
+type queuedWrite struct {
+	trackingID    string
+	log           domain.AuditLog
+	attempts      int
+	enqueuedAt    time.Time
+	nextAttemptAt time.Time
+}
+
+// NewAsyncWriteService returns a service backed by repo, flushing at most
+// batchSize records every flushInterval. Failed writes are not retried
+// until WithRetry is called.
+func NewAsyncWriteService(repo Repo, batchSize int, flushInterval time.Duration) *AsyncWriteService {
+	return &AsyncWriteService{
+		repo:          repo,
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		tracking:      make(map[string]*domain.WriteTracking),
+	}
+}
+
+// WithRetry enables retrying a failed flush up to maxAttempts times,
+// backing off exponentially (backoff * 2^n) between attempts.
+func (s *AsyncWriteService) WithRetry(maxAttempts int, backoff time.Duration) *AsyncWriteService {
+	s.maxAttempts = maxAttempts
+	s.retryBackoff = backoff
+	return s
+}
+
+// WithPersistentQueue makes the buffer durable: every Enqueue is
+// persisted to q before being acknowledged, and Recover reloads whatever
+// is still pending in q after a restart.
+func (s *AsyncWriteService) WithPersistentQueue(q PersistentQueue) *AsyncWriteService {
+	s.persistent = q
+	return s
+}
+
+// WithStatusTransitions restricts which domain.WriteStatus a tracked
+// write's status may move to from its current one (see
+// config.AsyncWriteConfig.StatusTransitions). flush silently keeps a
+// write's status unchanged, and records domain.ErrInvalidStatusTransition
+// as its error, rather than apply a transition not listed for its current
+// status. A nil/empty transitions permits any transition, matching the
+// behavior before this existed.
+func (s *AsyncWriteService) WithStatusTransitions(transitions map[string][]string) *AsyncWriteService {
+	s.statusTransitions = transitions
+	return s
+}
+
+// isTransitionAllowed reports whether moving from status "from" to status
+// "to" is permitted by transitions. A nil/empty transitions, or a
+// transition to the same status, is always allowed; otherwise "from" must
+// have an entry in transitions listing "to".
+func isTransitionAllowed(transitions map[string][]string, from, to domain.WriteStatus) bool {
+	if len(transitions) == 0 || from == to {
+		return true
+	}
+	for _, allowed := range transitions[string(from)] {
+		if allowed == string(to) {
+			return true
+		}
+	}
+	return false
+}
+
+// Enqueue buffers log for a later batched write and returns a tracking ID
+// that GetStatus can be polled with. If a PersistentQueue is configured,
+// Enqueue does not return until the write has been durably persisted.
+func (s *AsyncWriteService) Enqueue(ctx context.Context, log domain.AuditLog) (string, error) {
+	trackingID := uuid.NewString()
+	now := time.Now()
+
+	if s.persistent != nil {
+		entry := queue.Entry{TrackingID: trackingID, Log: log, EnqueuedAt: now}
+		if err := s.persistent.Push(ctx, entry); err != nil {
+			return "", fmt.Errorf("async write: persist enqueue: %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queue = append(s.queue, queuedWrite{trackingID: trackingID, log: log, enqueuedAt: now})
+	s.tracking[trackingID] = &domain.WriteTracking{TrackingID: trackingID, Status: domain.WriteStatusPending}
+	return trackingID, nil
+}
+
+// GetStatus returns the current tracking state for trackingID, or
+// domain.ErrNotFound if it's unknown.
+func (s *AsyncWriteService) GetStatus(trackingID string) (domain.WriteTracking, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tracking[trackingID]
+	if !ok {
+		return domain.WriteTracking{}, fmt.Errorf("%w: unknown tracking id", domain.ErrNotFound)
+	}
+	return *t, nil
+}
+
+// QueueDepth returns the number of writes currently buffered (pending or
+// awaiting retry).
+func (s *AsyncWriteService) QueueDepth() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.queue)
+}
+
+// OldestPendingAge returns how long the oldest buffered write has been
+// waiting, or 0 if the buffer is empty.
+func (s *AsyncWriteService) OldestPendingAge() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.queue) == 0 {
+		return 0
+	}
+	return time.Since(s.queue[0].enqueuedAt)
+}
+
+// Recover reloads any writes left behind in the PersistentQueue by a
+// previous process (e.g. one that crashed before flushing them), so they
+// are retried instead of lost. It is a no-op without a PersistentQueue.
+// Call it once at startup, before Run.
+func (s *AsyncWriteService) Recover(ctx context.Context) error {
+	if s.persistent == nil {
+		return nil
+	}
+
+	for {
+		entries, err := s.persistent.Pop(ctx, s.batchSize)
+		if err != nil {
+			return fmt.Errorf("async write: recover: %w", err)
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+
+		s.mu.Lock()
+		for _, entry := range entries {
+			s.queue = append(s.queue, queuedWrite{
+				trackingID: entry.TrackingID,
+				log:        entry.Log,
+				attempts:   entry.Attempts,
+				enqueuedAt: entry.EnqueuedAt,
+			})
+			s.tracking[entry.TrackingID] = &domain.WriteTracking{TrackingID: entry.TrackingID, Status: domain.WriteStatusPending, Attempts: entry.Attempts}
+		}
+		s.mu.Unlock()
+	}
+}
+
+// Run flushes the buffer every flushInterval until ctx is canceled. It is
+// meant to be started once, in its own goroutine, at service startup.
+func (s *AsyncWriteService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.flush(ctx)
+		}
+	}
+}
+
+func (s *AsyncWriteService) flush(ctx context.Context) {
+	now := time.Now()
+
+	s.mu.Lock()
+	var due []queuedWrite
+	var notYetDue []queuedWrite
+	for _, item := range s.queue {
+		if len(due) >= s.batchSize {
+			notYetDue = append(notYetDue, item)
+			continue
+		}
+		if item.nextAttemptAt.After(now) {
+			notYetDue = append(notYetDue, item)
+			continue
+		}
+		due = append(due, item)
+	}
+	s.queue = notYetDue
+	s.mu.Unlock()
+
+	for _, item := range due {
+		_, err := s.repo.Create(ctx, item.log)
+
+		s.mu.Lock()
+		t := s.tracking[item.trackingID]
+
+		var next domain.WriteStatus
+		if err != nil {
+			item.attempts++
+			if item.attempts <= s.maxAttempts {
+				next = domain.WriteStatusRetrying
+			} else {
+				next = domain.WriteStatusFailed
+			}
+		} else {
+			next = domain.WriteStatusWritten
+		}
+
+		if !isTransitionAllowed(s.statusTransitions, t.Status, next) {
+			t.Error = domain.ErrInvalidStatusTransition.Error()
+			s.mu.Unlock()
+			continue
+		}
+
+		if err != nil {
+			t.Attempts = item.attempts
+			t.Status = next
+			t.Error = err.Error()
+			if next == domain.WriteStatusRetrying {
+				item.nextAttemptAt = now.Add(s.retryBackoff << (item.attempts - 1))
+				s.queue = append(s.queue, item)
+			}
+		} else {
+			t.Status = next
+		}
+		s.mu.Unlock()
+	}
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+	"github.com/naveenkumar2412/audit-log-service/internal/queue"
+)
+
+// failingRepo fails the first failUntil Create calls, then succeeds.
+type failingRepo struct {
+	fakeRepo
+	mu        sync.Mutex
+	calls     int
+	failUntil int
+}
+
+func (f *failingRepo) Create(ctx context.Context, log domain.AuditLog) (domain.AuditLog, error) {
+	f.mu.Lock()
+	f.calls++
+	fail := f.calls <= f.failUntil
+	f.mu.Unlock()
+	if fail {
+		return domain.AuditLog{}, errors.New("transient failure")
+	}
+	return f.fakeRepo.Create(ctx, log)
+}
+
+type fakePersistentQueue struct {
+	mu      sync.Mutex
+	entries []queue.Entry
+}
+
+func (q *fakePersistentQueue) Push(ctx context.Context, entry queue.Entry) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.entries = append(q.entries, entry)
+	return nil
+}
+
+func (q *fakePersistentQueue) Pop(ctx context.Context, max int) ([]queue.Entry, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	n := max
+	if n > len(q.entries) {
+		n = len(q.entries)
+	}
+	popped := q.entries[:n]
+	q.entries = q.entries[n:]
+	return popped, nil
+}
+
+func (q *fakePersistentQueue) Len(ctx context.Context) (int64, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return int64(len(q.entries)), nil
+}
+
+func TestAsyncWriteService_EnqueueFlushAndStatus(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewAsyncWriteService(repo, 10, time.Millisecond)
+
+	trackingID, err := svc.Enqueue(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "e1"})
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	status, err := svc.GetStatus(trackingID)
+	if err != nil {
+		t.Fatalf("GetStatus: %v", err)
+	}
+	if status.Status != domain.WriteStatusPending {
+		t.Fatalf("status = %v, want pending", status.Status)
+	}
+
+	svc.flush(context.Background())
+
+	status, err = svc.GetStatus(trackingID)
+	if err != nil {
+		t.Fatalf("GetStatus after flush: %v", err)
+	}
+	if status.Status != domain.WriteStatusWritten {
+		t.Fatalf("status = %v, want written", status.Status)
+	}
+}
+
+func TestAsyncWriteService_UnknownTrackingID(t *testing.T) {
+	svc := NewAsyncWriteService(&fakeRepo{}, 10, time.Second)
+
+	if _, err := svc.GetStatus("missing"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestAsyncWriteService_RetriesFailedFlushThenSucceeds(t *testing.T) {
+	repo := &failingRepo{failUntil: 1}
+	svc := NewAsyncWriteService(repo, 10, time.Millisecond).WithRetry(3, time.Millisecond)
+
+	trackingID, err := svc.Enqueue(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "e1"})
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	svc.flush(context.Background())
+	status, _ := svc.GetStatus(trackingID)
+	if status.Status != domain.WriteStatusRetrying {
+		t.Fatalf("status after first failed flush = %v, want retrying", status.Status)
+	}
+	if svc.QueueDepth() != 1 {
+		t.Fatalf("QueueDepth = %d, want 1 (requeued for retry)", svc.QueueDepth())
+	}
+
+	time.Sleep(2 * time.Millisecond)
+	svc.flush(context.Background())
+	status, _ = svc.GetStatus(trackingID)
+	if status.Status != domain.WriteStatusWritten {
+		t.Fatalf("status after retry = %v, want written", status.Status)
+	}
+}
+
+func TestAsyncWriteService_GivesUpAfterMaxAttempts(t *testing.T) {
+	repo := &failingRepo{failUntil: 100}
+	svc := NewAsyncWriteService(repo, 10, time.Millisecond).WithRetry(2, time.Millisecond)
+
+	trackingID, err := svc.Enqueue(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "e1"})
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		time.Sleep(2 * time.Millisecond)
+		svc.flush(context.Background())
+	}
+
+	status, _ := svc.GetStatus(trackingID)
+	if status.Status != domain.WriteStatusFailed {
+		t.Fatalf("status after exhausting retries = %v, want failed", status.Status)
+	}
+	if svc.QueueDepth() != 0 {
+		t.Fatalf("QueueDepth = %d, want 0 (given up, not requeued)", svc.QueueDepth())
+	}
+}
+
+func TestAsyncWriteService_AllowedStatusTransitionApplies(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewAsyncWriteService(repo, 10, time.Millisecond).
+		WithStatusTransitions(map[string][]string{"pending": {"written"}})
+
+	trackingID, err := svc.Enqueue(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "e1"})
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	svc.flush(context.Background())
+
+	status, _ := svc.GetStatus(trackingID)
+	if status.Status != domain.WriteStatusWritten {
+		t.Fatalf("status = %v, want written", status.Status)
+	}
+}
+
+func TestAsyncWriteService_DisallowedStatusTransitionIsBlocked(t *testing.T) {
+	repo := &failingRepo{failUntil: 100}
+	svc := NewAsyncWriteService(repo, 10, time.Millisecond).
+		WithRetry(3, time.Millisecond).
+		WithStatusTransitions(map[string][]string{"pending": {"written"}})
+
+	trackingID, err := svc.Enqueue(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "e1"})
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	svc.flush(context.Background())
+
+	status, _ := svc.GetStatus(trackingID)
+	if status.Status != domain.WriteStatusPending {
+		t.Fatalf("status = %v, want pending (transition to retrying not configured as allowed)", status.Status)
+	}
+	if status.Error != domain.ErrInvalidStatusTransition.Error() {
+		t.Fatalf("status.Error = %q, want %q", status.Error, domain.ErrInvalidStatusTransition.Error())
+	}
+	if svc.QueueDepth() != 0 {
+		t.Fatalf("QueueDepth = %d, want 0 (blocked transition drops the item rather than retrying forever)", svc.QueueDepth())
+	}
+}
+
+func TestAsyncWriteService_PersistentQueuePersistsOnEnqueue(t *testing.T) {
+	pq := &fakePersistentQueue{}
+	svc := NewAsyncWriteService(&fakeRepo{}, 10, time.Millisecond).WithPersistentQueue(pq)
+
+	if _, err := svc.Enqueue(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "e1"}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	n, _ := pq.Len(context.Background())
+	if n != 1 {
+		t.Fatalf("persistent queue len = %d, want 1", n)
+	}
+}
+
+func TestAsyncWriteService_RecoverReloadsPendingEntries(t *testing.T) {
+	pq := &fakePersistentQueue{entries: []queue.Entry{
+		{TrackingID: "tr-1", Log: domain.AuditLog{TenantID: "tenant-a", Event: "e1"}, EnqueuedAt: time.Now()},
+	}}
+	svc := NewAsyncWriteService(&fakeRepo{}, 10, time.Millisecond).WithPersistentQueue(pq)
+
+	if err := svc.Recover(context.Background()); err != nil {
+		t.Fatalf("Recover: %v", err)
+	}
+	if svc.QueueDepth() != 1 {
+		t.Fatalf("QueueDepth after recover = %d, want 1", svc.QueueDepth())
+	}
+
+	status, err := svc.GetStatus("tr-1")
+	if err != nil {
+		t.Fatalf("GetStatus: %v", err)
+	}
+	if status.Status != domain.WriteStatusPending {
+		t.Fatalf("status = %v, want pending", status.Status)
+	}
+}
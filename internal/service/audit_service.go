@@ -0,0 +1,1074 @@
+// Package service implements the business logic of the audit-log service,
+// sitting between the HTTP layer and the storage layer.
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/config"
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+	"github.com/naveenkumar2412/audit-log-service/internal/ingestmode"
+	"github.com/naveenkumar2412/audit-log-service/internal/logging"
+	"github.com/naveenkumar2412/audit-log-service/internal/metrics"
+	"github.com/naveenkumar2412/audit-log-service/internal/tracing"
+)
+
+// Repo is the storage interface the service depends on. It is satisfied by
+// internal/store/postgres.AuditLogRepo.
+type Repo interface {
+	Create(ctx context.Context, log domain.AuditLog) (domain.AuditLog, error)
+	CreateIfAbsent(ctx context.Context, log domain.AuditLog, matchFields []string, window time.Duration) (domain.AuditLog, bool, error)
+	CreateWithIdempotencyKey(ctx context.Context, log domain.AuditLog, window time.Duration) (domain.AuditLog, bool, error)
+	// List returns a filter's matching logs. The second return value is
+	// the filter's total match count (ignoring Limit/Offset), computed
+	// via a single-query window function rather than a separate COUNT(*)
+	// round trip; it is nil unless filter.WithCount is set.
+	List(ctx context.Context, filter domain.Filter) ([]domain.AuditLog, *int64, error)
+	GetByID(ctx context.Context, tenantID, id string, includeDeleted bool) (domain.AuditLog, error)
+	HeadByID(ctx context.Context, tenantID, id string) (domain.AuditLog, error)
+	TimeSeries(ctx context.Context, filter domain.Filter, interval string) ([]domain.TimeBucket, error)
+	DeleteByTenant(ctx context.Context, tenantID string) (int64, error)
+	DeleteByFilter(ctx context.Context, filter domain.Filter) (int64, error)
+	SoftDelete(ctx context.Context, tenantID, id string) error
+	HardDelete(ctx context.Context, tenantID, id string) error
+	Facets(ctx context.Context, filter domain.Filter, field string, topN int) (domain.FacetResult, error)
+	LatestPerResource(ctx context.Context, filter domain.Filter) ([]domain.AuditLog, error)
+	AddTags(ctx context.Context, tenantID, id string, tags []string) (domain.AuditLog, error)
+	RemoveTags(ctx context.Context, tenantID, id string, tags []string) (domain.AuditLog, error)
+	AppendMeta(ctx context.Context, tenantID, id string, patch map[string]any) (domain.AuditLog, error)
+	EventCounts(ctx context.Context, filter domain.Filter) (map[string]int64, error)
+	ResourceCounts(ctx context.Context, filter domain.Filter) (map[string]int64, error)
+	TenantStorageBytes(ctx context.Context, tenantID string) (int64, error)
+	VerifyChain(ctx context.Context, tenantID string) (domain.ChainVerification, error)
+}
+
+// facetFieldAllowlist restricts which fields GetFacets will accept,
+// mirroring the allowlist re-checked in the postgres repo.
+var facetFieldAllowlist = map[string]bool{
+	"tenant_id":   true,
+	"actor":       true,
+	"event":       true,
+	"resource":    true,
+	"resource_id": true,
+}
+
+// defaultFacetTopN and maxFacetTopN bound how many distinct values
+// GetFacets returns when the caller doesn't specify, or asks for too many.
+const (
+	defaultFacetTopN = 20
+	maxFacetTopN     = 200
+)
+
+// intervalStep maps each supported histogram interval to its bucket
+// duration, and doubles as the allowlist validated before the interval is
+// interpolated into the repo's date_trunc query.
+var intervalStep = map[string]time.Duration{
+	"hour": time.Hour,
+	"day":  24 * time.Hour,
+	"week": 7 * 24 * time.Hour,
+}
+
+// maxTimeSeriesBuckets caps the number of buckets returned by GetTimeSeries
+// so a wide date range with a fine-grained interval can't produce an
+// enormous response.
+const maxTimeSeriesBuckets = 1000
+
+// Notifier delivers a newly-created audit log to interested subscribers
+// (webhooks, Slack, etc). Notify is expected to be best-effort: it should
+// log its own failures rather than returning an error that would affect
+// the create request. AuditService calls Notify synchronously (not in its
+// own goroutine), so a Notifier that might block on delivery should return
+// quickly itself and fan out asynchronously — see NotificationPool, which
+// bounds that fan-out to a fixed worker pool instead of spawning an
+// unbounded goroutine per call.
+type Notifier interface {
+	Notify(ctx context.Context, log domain.AuditLog)
+}
+
+// multiNotifier fans Notify out to every one of ns in order, letting
+// AuditService (which only holds a single Notifier) feed more than one
+// sink — e.g. a webhook NotificationPool and a stream.Hub — from the same
+// create path. See Notifiers.
+type multiNotifier []Notifier
+
+func (m multiNotifier) Notify(ctx context.Context, log domain.AuditLog) {
+	for _, n := range m {
+		n.Notify(ctx, log)
+	}
+}
+
+// Notifiers combines ns into a single Notifier that calls each of them in
+// order, for use with WithNotifier when more than one sink needs newly
+// created audit logs.
+func Notifiers(ns ...Notifier) Notifier {
+	return multiNotifier(ns)
+}
+
+// Deduper is the backend for the HTTP-edge request dedup window (see
+// config.DedupConfig). It is satisfied by internal/dedup.RedisStore.
+type Deduper interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// FileSink is the backend for the optional local NDJSON file output (see
+// config.FileSinkConfig). It is satisfied by internal/filesink.Sink.
+type FileSink interface {
+	Write(ctx context.Context, log domain.AuditLog) error
+}
+
+// KafkaPublisher is the backend for the optional Kafka publisher (see
+// config.KafkaConfig). It is satisfied by internal/kafka.Publisher.
+// Publish is expected to be best-effort: a failure is logged and counted
+// (see metrics.KafkaPublishErrorsTotal) rather than returned to the
+// caller in a way that would affect the create request.
+type KafkaPublisher interface {
+	Publish(ctx context.Context, log domain.AuditLog) error
+}
+
+// NATSPublisher is the backend for the optional NATS JetStream publisher
+// (see config.NATSConfig). It is satisfied by internal/nats.Publisher.
+// Like KafkaPublisher, it composes independently of the other one: a
+// deployment can enable Kafka, NATS, both, or neither, since
+// AuditService invokes each that's configured. Publish is expected to
+// be best-effort: a failure is logged and counted (see
+// metrics.NATSPublishErrorsTotal) rather than returned to the caller in
+// a way that would affect the create request.
+type NATSPublisher interface {
+	Publish(ctx context.Context, log domain.AuditLog) error
+}
+
+// Cache is the backend for the read-through GetByID cache (see
+// config.RedisConfig). It is satisfied by internal/cache.RedisCache.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+// AuditService is the single entry point for audit log business logic. It
+// owns pagination defaulting/capping so handlers never need to know about
+// page-size configuration.
+type AuditService struct {
+	repo            Repo
+	cfg             config.Config
+	pii             *piiScanner
+	redact          *redactor
+	notifier        Notifier
+	dedup           Deduper
+	fileSink        FileSink
+	fileSinkPrimary bool
+	kafka           KafkaPublisher
+	nats            NATSPublisher
+	cache           Cache
+	cacheTTL        time.Duration
+}
+
+// NewAuditService returns a service backed by the given repo and config.
+// cfg.PII.Patterns and cfg.Redaction.Patterns are assumed to already be
+// validated (see config.Load), so a compile failure here falls back to
+// an empty, no-op scanner/redactor rather than failing service
+// construction.
+func NewAuditService(repo Repo, cfg config.Config) *AuditService {
+	pii, err := newPIIScanner(cfg.PII)
+	if err != nil {
+		pii = &piiScanner{patterns: map[string]*regexp.Regexp{}}
+	}
+	redact, err := newRedactor(cfg.Redaction)
+	if err != nil {
+		redact = &redactor{}
+	}
+	return &AuditService{repo: repo, cfg: cfg, pii: pii, redact: redact}
+}
+
+// WithNotifier sets the notifier used to fan out newly-created audit logs
+// and returns s for chaining. Not setting one (the default) disables
+// notification entirely.
+func (s *AuditService) WithNotifier(notifier Notifier) *AuditService {
+	s.notifier = notifier
+	return s
+}
+
+// WithDeduper sets the backend used for the request dedup window and
+// returns s for chaining. Not setting one disables dedup regardless of
+// cfg.Dedup.Enabled.
+func (s *AuditService) WithDeduper(dedup Deduper) *AuditService {
+	s.dedup = dedup
+	return s
+}
+
+// WithFileSink sets the backend for the optional local NDJSON file
+// output and returns s for chaining. primary selects the sink's role:
+// true makes it the sole store for CreateAuditLog (Postgres is not
+// written to at all), false makes it a best-effort mirror written
+// alongside Postgres. Not setting one disables the file sink regardless
+// of cfg.FileSink.Enabled.
+func (s *AuditService) WithFileSink(sink FileSink, primary bool) *AuditService {
+	s.fileSink = sink
+	s.fileSinkPrimary = primary
+	return s
+}
+
+// WithKafkaPublisher sets the backend used to publish newly-created audit
+// logs to Kafka and returns s for chaining. Not setting one disables
+// publishing regardless of cfg.Kafka.Enabled.
+func (s *AuditService) WithKafkaPublisher(publisher KafkaPublisher) *AuditService {
+	s.kafka = publisher
+	return s
+}
+
+// WithNATSPublisher sets the backend used to publish newly-created audit
+// logs to NATS JetStream and returns s for chaining. Not setting one
+// disables publishing regardless of cfg.NATS.Enabled.
+func (s *AuditService) WithNATSPublisher(publisher NATSPublisher) *AuditService {
+	s.nats = publisher
+	return s
+}
+
+// WithCache sets the backend for the GetByID read-through cache and
+// returns s for chaining. Not setting one disables caching regardless of
+// cfg.Redis.Addr.
+func (s *AuditService) WithCache(cache Cache, ttl time.Duration) *AuditService {
+	s.cache = cache
+	s.cacheTTL = ttl
+	return s
+}
+
+// cacheKey returns the Cache key GetAuditLog stores a record under.
+func cacheKey(tenantID, id string) string {
+	return "auditlog:" + tenantID + ":" + id
+}
+
+// dedupKey returns the content-hash key used to detect a duplicate
+// create request, hashing only the configured fields. Field names are
+// assumed to already be validated against config's dedup field
+// allowlist (see config.Load).
+func dedupKey(log domain.AuditLog, fields []string) string {
+	parts := make(map[string]any, len(fields))
+	for _, field := range fields {
+		switch field {
+		case "tenant_id":
+			parts[field] = log.TenantID
+		case "actor":
+			parts[field] = log.Actor
+		case "event":
+			parts[field] = log.Event
+		case "resource":
+			parts[field] = log.Resource
+		case "resource_id":
+			parts[field] = log.ResourceID
+		case "data":
+			parts[field] = log.Data
+		}
+	}
+	// encoding/json marshals map[string]any keys in sorted order, so this
+	// is deterministic regardless of the order fields were configured in.
+	b, _ := json.Marshal(parts)
+	sum := sha256.Sum256(b)
+	return "dedup:" + hex.EncodeToString(sum[:])
+}
+
+// prepareCreate runs every check and enrichment step shared by
+// CreateAuditLog and CreateAuditLogConditional before the record reaches
+// the repo: the tenant's event allowlist/denylist, the live-ingestion
+// timestamp-skew check (exempted by ingestmode), meta allow/denylist
+// filtering, PII scanning, business-hours classification, and quota
+// enforcement. It returns log with those enrichments applied, or an error
+// if the record should be rejected outright.
+func (s *AuditService) prepareCreate(ctx context.Context, log domain.AuditLog) (domain.AuditLog, error) {
+	if policy, ok := s.cfg.Ingestion[log.TenantID]; ok && !policy.Allows(log.Event) {
+		return domain.AuditLog{}, fmt.Errorf("%w: %s", domain.ErrEventNotAllowed, log.Event)
+	}
+
+	if log.ClientIP != "" {
+		normalized, err := normalizeClientIP(log.ClientIP)
+		if err != nil {
+			return domain.AuditLog{}, fmt.Errorf("%w: client_ip %q: %s", domain.ErrInvalidArgument, log.ClientIP, err)
+		}
+		log.ClientIP = normalized
+	}
+
+	if !log.CreatedAt.IsZero() && !ingestmode.FromContext(ctx) {
+		now := time.Now()
+		oldest := now.Add(-s.cfg.Timestamp.MaxPastSkew)
+		newest := now.Add(s.cfg.Timestamp.MaxFutureSkew)
+		if log.CreatedAt.Before(oldest) || log.CreatedAt.After(newest) {
+			return domain.AuditLog{}, fmt.Errorf("%w: timestamp %s is outside the acceptable skew window [%s, %s]; retry as a backfill/import request if this is historical data", domain.ErrInvalidArgument, log.CreatedAt.Format(time.RFC3339), oldest.Format(time.RFC3339), newest.Format(time.RFC3339))
+		}
+	}
+
+	if s.cfg.Redaction.Enabled {
+		log.Data = s.redact.redactMap(log.Data)
+	}
+
+	log.Meta = filterMetaAllowlist(log.Meta, s.cfg.MetaAllowlist[log.TenantID])
+	log.Meta = filterMetaDenylist(ctx, log.Meta, s.cfg.MetaDenylist)
+
+	if s.cfg.PII.Enabled {
+		if categories := s.pii.scan(log.Data, log.Meta); len(categories) > 0 {
+			if log.Meta == nil {
+				log.Meta = map[string]any{}
+			}
+			log.Meta["contains_pii"] = true
+			log.Meta["pii_categories"] = categories
+		}
+	}
+
+	if s.cfg.BusinessHours.Enabled {
+		at := log.CreatedAt
+		if at.IsZero() {
+			at = time.Now()
+		}
+		window := s.cfg.BusinessHours.WindowFor(log.TenantID)
+		businessHours, dayOfWeek := classifyBusinessHours(window, at)
+		if log.Meta == nil {
+			log.Meta = map[string]any{}
+		}
+		log.Meta["business_hours"] = businessHours
+		log.Meta["day_of_week"] = dayOfWeek
+	}
+
+	if s.cfg.Quota.Enabled {
+		if quota := s.cfg.Quota.BytesFor(log.TenantID); quota > 0 {
+			usage, err := s.repo.TenantStorageBytes(ctx, log.TenantID)
+			if err != nil {
+				logging.FromContext(ctx).Error("quota: usage lookup failed", "error", err)
+			} else {
+				if usage >= quota {
+					return domain.AuditLog{}, fmt.Errorf("%w: tenant %q is using %d of %d bytes", domain.ErrQuotaExceeded, log.TenantID, usage, quota)
+				}
+				if ratio := s.cfg.Quota.SoftLimitRatio; ratio > 0 && float64(usage) >= float64(quota)*ratio {
+					logging.FromContext(ctx).Warn("quota: tenant approaching storage limit", "tenant_id", log.TenantID, "usage_bytes", usage, "quota_bytes", quota)
+				}
+			}
+		}
+	}
+
+	return log, nil
+}
+
+// CreateAuditLog records a new audit log entry. It is rejected with
+// domain.ErrEventNotAllowed if the tenant's configured event
+// allowlist/denylist disallows log.Event, or with domain.ErrInvalidArgument
+// if log.CreatedAt is set and falls outside the acceptable skew window
+// for live ingestion (see config.TimestampConfig) — unless ctx is marked
+// as a backfill/import request (see ingestmode), which exempts it.
+func (s *AuditService) CreateAuditLog(ctx context.Context, log domain.AuditLog) (domain.AuditLog, error) {
+	ctx, span := tracing.Start(ctx, "AuditService.CreateAuditLog", trace.WithAttributes(attribute.String("tenant_id", log.TenantID)))
+	defer span.End()
+
+	log, err := s.prepareCreate(ctx, log)
+	if err != nil {
+		span.RecordError(err)
+		return domain.AuditLog{}, err
+	}
+
+	var key string
+	if s.cfg.Dedup.Enabled && s.dedup != nil {
+		key = dedupKey(log, s.cfg.Dedup.Fields)
+		if existing, found, err := s.dedup.Get(ctx, key); err != nil {
+			logging.FromContext(ctx).Error("dedup lookup failed", "error", err)
+		} else if found {
+			var original domain.AuditLog
+			if err := json.Unmarshal(existing, &original); err != nil {
+				logging.FromContext(ctx).Error("dedup: unmarshal cached record failed", "error", err)
+			} else {
+				return original, nil
+			}
+		}
+	}
+
+	var created domain.AuditLog
+	if s.fileSink != nil && s.fileSinkPrimary {
+		if log.ID == uuid.Nil {
+			log.ID = uuid.New()
+		}
+		if log.CreatedAt.IsZero() {
+			log.CreatedAt = time.Now()
+		}
+		if err := s.fileSink.Write(ctx, log); err != nil {
+			logging.FromContext(ctx).Error("file sink write failed", "error", err)
+			span.RecordError(err)
+			return domain.AuditLog{}, err
+		}
+		created = log
+	} else {
+		created, err = s.repo.Create(ctx, log)
+		if err != nil {
+			logging.FromContext(ctx).Error("create audit log failed", "error", err)
+			span.RecordError(err)
+			return domain.AuditLog{}, err
+		}
+		if s.fileSink != nil {
+			mirrored := created
+			go func() {
+				if err := s.fileSink.Write(context.WithoutCancel(ctx), mirrored); err != nil {
+					logging.FromContext(ctx).Error("file sink mirror write failed", "error", err)
+				}
+			}()
+		}
+	}
+	logging.FromContext(ctx).Info("audit log created", "id", created.ID, "event", created.Event)
+	metrics.AuditLogsCreatedTotal.WithLabelValues(created.TenantID, created.Event).Inc()
+
+	if key != "" {
+		if body, err := json.Marshal(created); err != nil {
+			logging.FromContext(ctx).Error("dedup: marshal record failed", "error", err)
+		} else if err := s.dedup.Set(context.WithoutCancel(ctx), key, body, s.cfg.Dedup.Window); err != nil {
+			logging.FromContext(ctx).Error("dedup: store failed", "error", err)
+		}
+	}
+
+	if s.notifier != nil {
+		s.notifier.Notify(ctx, created)
+	}
+	if s.kafka != nil {
+		mirrored := created
+		go func() {
+			if err := s.kafka.Publish(context.WithoutCancel(ctx), mirrored); err != nil {
+				logging.FromContext(ctx).Error("kafka publish failed", "error", err)
+				metrics.KafkaPublishErrorsTotal.Inc()
+			}
+		}()
+	}
+	if s.nats != nil {
+		mirrored := created
+		go func() {
+			if err := s.nats.Publish(context.WithoutCancel(ctx), mirrored); err != nil {
+				logging.FromContext(ctx).Error("nats publish failed", "error", err)
+				metrics.NATSPublishErrorsTotal.Inc()
+			}
+		}()
+	}
+	span.SetAttributes(attribute.String("result_id", created.ID.String()))
+	return created, nil
+}
+
+// conditionalMatchFieldAllowlist restricts which domain.AuditLog fields
+// CreateAuditLogConditional's cond.MatchFields can name, mirroring the
+// allowlist re-checked in the postgres repo.
+var conditionalMatchFieldAllowlist = map[string]bool{
+	"actor":       true,
+	"event":       true,
+	"resource":    true,
+	"resource_id": true,
+}
+
+// CreateAuditLogConditional behaves like CreateAuditLog, except it first
+// checks for an existing record for the same tenant matching every field
+// named in cond.MatchFields, created within cond.Window. If one is found
+// it is returned unchanged with created=false instead of inserting a
+// duplicate; otherwise log is inserted and returned with created=true.
+// The check and insert happen atomically in the repo (see
+// postgres.AuditLogRepo.CreateIfAbsent), so concurrent callers racing on
+// the same match key can't both observe "absent" and insert a duplicate.
+// Unlike CreateAuditLog, it does not consult the dedup window, write to
+// the file sink, or publish to Kafka or NATS; of the downstream sinks,
+// only the Notifier is called for a newly created record.
+func (s *AuditService) CreateAuditLogConditional(ctx context.Context, log domain.AuditLog, cond domain.CreateCondition) (domain.AuditLog, bool, error) {
+	if len(cond.MatchFields) == 0 {
+		return domain.AuditLog{}, false, fmt.Errorf("%w: if_absent requires at least one match field", domain.ErrInvalidArgument)
+	}
+	for _, field := range cond.MatchFields {
+		if !conditionalMatchFieldAllowlist[field] {
+			return domain.AuditLog{}, false, fmt.Errorf("%w: unsupported if_absent match field %q", domain.ErrInvalidArgument, field)
+		}
+	}
+	if cond.Window <= 0 {
+		return domain.AuditLog{}, false, fmt.Errorf("%w: if_absent requires a positive window", domain.ErrInvalidArgument)
+	}
+
+	log, err := s.prepareCreate(ctx, log)
+	if err != nil {
+		return domain.AuditLog{}, false, err
+	}
+
+	result, created, err := s.repo.CreateIfAbsent(ctx, log, cond.MatchFields, cond.Window)
+	if err != nil {
+		logging.FromContext(ctx).Error("conditional create audit log failed", "error", err)
+		return domain.AuditLog{}, false, err
+	}
+
+	if !created {
+		return result, false, nil
+	}
+
+	logging.FromContext(ctx).Info("audit log created", "id", result.ID, "event", result.Event)
+	metrics.AuditLogsCreatedTotal.WithLabelValues(result.TenantID, result.Event).Inc()
+	if s.notifier != nil {
+		s.notifier.Notify(ctx, result)
+	}
+	return result, true, nil
+}
+
+// CreateAuditLogIdempotent behaves like CreateAuditLog, except that
+// log.IdempotencyKey, when non-empty, is stored with the record and
+// checked against every other record already stored for the same tenant
+// (see postgres.AuditLogRepo.CreateWithIdempotencyKey): a match within
+// config.IdempotencyConfig.Window is returned unchanged with created=false
+// instead of inserting a duplicate; otherwise log is inserted and returned
+// with created=true. Unlike the Dedup feature, the key is supplied by the
+// client and persisted, so it survives across requests rather than just a
+// short server-side window. Unlike CreateAuditLog, it does not write to
+// the file sink or publish to Kafka or NATS; of the downstream sinks, only
+// the Notifier is called for a newly created record.
+func (s *AuditService) CreateAuditLogIdempotent(ctx context.Context, log domain.AuditLog) (domain.AuditLog, bool, error) {
+	ctx, span := tracing.Start(ctx, "AuditService.CreateAuditLogIdempotent", trace.WithAttributes(attribute.String("tenant_id", log.TenantID)))
+	defer span.End()
+
+	log, err := s.prepareCreate(ctx, log)
+	if err != nil {
+		span.RecordError(err)
+		return domain.AuditLog{}, false, err
+	}
+
+	result, created, err := s.repo.CreateWithIdempotencyKey(ctx, log, s.cfg.Idempotency.Window)
+	if err != nil {
+		logging.FromContext(ctx).Error("idempotent create audit log failed", "error", err)
+		span.RecordError(err)
+		return domain.AuditLog{}, false, err
+	}
+
+	if !created {
+		return result, false, nil
+	}
+
+	logging.FromContext(ctx).Info("audit log created", "id", result.ID, "event", result.Event)
+	metrics.AuditLogsCreatedTotal.WithLabelValues(result.TenantID, result.Event).Inc()
+	if s.notifier != nil {
+		s.notifier.Notify(ctx, result)
+	}
+	return result, true, nil
+}
+
+// maxBatchCreateEntries caps how many entries CreateAuditLogsBatch accepts
+// in one call, so a single request can't tie up the store issuing an
+// unbounded number of per-entry inserts.
+const maxBatchCreateEntries = 500
+
+// CreateAuditLogsBatch creates multiple audit logs from a single request.
+// Every entry is validated (the same checks CreateAuditLog applies — event
+// policy, timestamp skew, quota, etc.) before anything is written, so one
+// bad entry's validation failure is reported without touching the store.
+//
+// In the default mode, entries that pass validation are still created even
+// if others fail: the result's Failed slice reports each rejected entry by
+// its original index, and Created holds the rest in submission order. When
+// allOrNothing is true, any validation failure aborts the whole batch and
+// nothing is created.
+//
+// Entries are inserted one at a time through the same repo.Create path
+// CreateAuditLog uses, not a single multi-row statement: each entry needs
+// the full per-tenant ingestion/quota pipeline prepareCreate runs, and
+// running that inside one SQL transaction would mean either duplicating
+// that logic into the postgres layer or holding a transaction open across
+// it, neither of which this architecture does anywhere else.
+func (s *AuditService) CreateAuditLogsBatch(ctx context.Context, logs []domain.AuditLog, allOrNothing bool) (domain.BatchCreateResult, error) {
+	if len(logs) == 0 {
+		return domain.BatchCreateResult{}, fmt.Errorf("%w: batch must contain at least one entry", domain.ErrInvalidArgument)
+	}
+	if len(logs) > maxBatchCreateEntries {
+		return domain.BatchCreateResult{}, fmt.Errorf("%w: batch of %d entries exceeds the limit of %d", domain.ErrInvalidArgument, len(logs), maxBatchCreateEntries)
+	}
+
+	prepared := make([]domain.AuditLog, len(logs))
+	var failed []domain.BatchEntryError
+	for i, log := range logs {
+		p, err := s.prepareCreate(ctx, log)
+		if err != nil {
+			failed = append(failed, domain.BatchEntryError{Index: i, Error: err.Error()})
+			continue
+		}
+		prepared[i] = p
+	}
+
+	if allOrNothing && len(failed) > 0 {
+		return domain.BatchCreateResult{Failed: failed}, nil
+	}
+
+	created := make([]domain.AuditLog, 0, len(prepared))
+	failedIndex := make(map[int]bool, len(failed))
+	for _, f := range failed {
+		failedIndex[f.Index] = true
+	}
+	for i, log := range prepared {
+		if failedIndex[i] {
+			continue
+		}
+		result, err := s.repo.Create(ctx, log)
+		if err != nil {
+			logging.FromContext(ctx).Error("batch create audit log failed", "index", i, "error", err)
+			failed = append(failed, domain.BatchEntryError{Index: i, Error: err.Error()})
+			continue
+		}
+		logging.FromContext(ctx).Info("audit log created", "id", result.ID, "event", result.Event)
+		metrics.AuditLogsCreatedTotal.WithLabelValues(result.TenantID, result.Event).Inc()
+		if s.notifier != nil {
+			s.notifier.Notify(ctx, result)
+		}
+		created = append(created, result)
+	}
+
+	return domain.BatchCreateResult{Created: created, Failed: failed}, nil
+}
+
+// filterMetaAllowlist drops any key not in allowlist. A nil/empty
+// allowlist allows every key, so tenants without a configured allowlist
+// see no change in behavior.
+func filterMetaAllowlist(meta map[string]any, allowlist []string) map[string]any {
+	if len(allowlist) == 0 || meta == nil {
+		return meta
+	}
+	allowed := make(map[string]bool, len(allowlist))
+	for _, key := range allowlist {
+		allowed[key] = true
+	}
+	filtered := make(map[string]any, len(meta))
+	for k, v := range meta {
+		if allowed[k] {
+			filtered[k] = v
+		}
+	}
+	return filtered
+}
+
+// filterMetaDenylist drops any key in denylist from meta, logging a
+// security warning for each one stripped, since a producer including one
+// is almost always a mistake (e.g. a credential value leaking into meta)
+// rather than intent. Unlike filterMetaAllowlist this is not
+// tenant-scoped: denylisted keys are dropped for every tenant.
+func filterMetaDenylist(ctx context.Context, meta map[string]any, denylist []string) map[string]any {
+	if len(meta) == 0 || len(denylist) == 0 {
+		return meta
+	}
+	for _, key := range denylist {
+		if _, ok := meta[key]; ok {
+			logging.FromContext(ctx).Warn("meta denylist: stripped disallowed key before persisting", "key", key)
+			delete(meta, key)
+		}
+	}
+	return meta
+}
+
+// normalizeClientIP parses raw (which may carry a port, as net.Addr-style
+// strings and some proxies' headers do) and returns its canonical text
+// form, so the same client is never stored under two different strings —
+// e.g. an IPv4-mapped IPv6 address like "::ffff:192.0.2.1" normalizes to
+// "192.0.2.1", matching how a direct IPv4 connection would be stored. It
+// returns an error if raw, once any port is stripped, is not a valid IP.
+func normalizeClientIP(raw string) (string, error) {
+	host := raw
+	if h, _, err := net.SplitHostPort(raw); err == nil {
+		host = h
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return "", fmt.Errorf("not a valid IP address")
+	}
+	return ip.String(), nil
+}
+
+// sortFieldAllowlist maps the public sort_by values ListAuditLogs
+// accepts to the column List orders by. "timestamp" is accepted as an
+// alias for created_at, the field's conceptual name to API callers even
+// though the column itself is created_at. "status" is not offered: this
+// schema has no record status/workflow-state column.
+var sortFieldAllowlist = map[string]string{
+	"timestamp":  "created_at",
+	"created_at": "created_at",
+	"event":      "event",
+	"resource":   "resource",
+}
+
+// resolveSort validates sortBy/sortOrder from a list request and returns
+// the column and direction List should order by. An empty sortBy
+// defaults to created_at; an empty sortOrder defaults to desc.
+func resolveSort(sortBy, sortOrder string) (column, order string, err error) {
+	column = "created_at"
+	if sortBy != "" {
+		col, ok := sortFieldAllowlist[sortBy]
+		if !ok {
+			return "", "", fmt.Errorf("%w: unsupported sort_by %q", domain.ErrInvalidArgument, sortBy)
+		}
+		column = col
+	}
+
+	switch sortOrder {
+	case "":
+		order = "desc"
+	case "asc", "desc":
+		order = sortOrder
+	default:
+		return "", "", fmt.Errorf("%w: sort_order must be asc or desc, got %q", domain.ErrInvalidArgument, sortOrder)
+	}
+
+	return column, order, nil
+}
+
+// ListAuditLogs returns a page of audit logs for the given filter. The
+// filter's Limit is resolved against the "audit_logs" pagination config
+// before querying the store, and SortBy/SortOrder are validated against
+// sortFieldAllowlist.
+func (s *AuditService) ListAuditLogs(ctx context.Context, filter domain.Filter) (domain.Page, error) {
+	ctx, span := tracing.Start(ctx, "AuditService.ListAuditLogs", trace.WithAttributes(attribute.String("tenant_id", filter.TenantID)))
+	defer span.End()
+
+	pagination := s.cfg.PaginationFor("audit_logs")
+	filter.Limit = pagination.Resolve(filter.Limit)
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	column, order, err := resolveSort(filter.SortBy, filter.SortOrder)
+	if err != nil {
+		span.RecordError(err)
+		return domain.Page{}, err
+	}
+	if filter.Cursor != nil && (column != "created_at" || order != "desc") {
+		err := fmt.Errorf("%w: cursor pagination requires the default sort order (created_at desc)", domain.ErrInvalidArgument)
+		span.RecordError(err)
+		return domain.Page{}, err
+	}
+	filter.SortBy, filter.SortOrder = column, order
+
+	logs, total, err := s.repo.List(ctx, filter)
+	if err != nil {
+		span.RecordError(err)
+		return domain.Page{}, err
+	}
+
+	page := domain.Page{Logs: logs, Limit: filter.Limit, Offset: filter.Offset, Total: total}
+	if len(logs) == filter.Limit {
+		last := logs[len(logs)-1]
+		page.NextCursor = domain.EncodeCursor(domain.Cursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+	span.SetAttributes(attribute.Int("result_count", len(logs)))
+	return page, nil
+}
+
+// GetAuditLog fetches a single audit log by ID, scoped to a tenant.
+// includeDeleted, if set, returns a soft-deleted record instead of
+// domain.ErrNotFound; such lookups bypass the cache, since cached entries
+// are only ever populated from non-deleted reads.
+func (s *AuditService) GetAuditLog(ctx context.Context, tenantID, id string, includeDeleted bool) (domain.AuditLog, error) {
+	if s.cache == nil || includeDeleted {
+		return s.repo.GetByID(ctx, tenantID, id, includeDeleted)
+	}
+
+	key := cacheKey(tenantID, id)
+	if cached, found, err := s.cache.Get(ctx, key); err != nil {
+		logging.FromContext(ctx).Error("cache: get failed, falling through to store", "error", err)
+	} else if found {
+		var log domain.AuditLog
+		if err := json.Unmarshal(cached, &log); err != nil {
+			logging.FromContext(ctx).Error("cache: unmarshal cached record failed", "error", err)
+		} else {
+			return log, nil
+		}
+	}
+
+	log, err := s.repo.GetByID(ctx, tenantID, id, false)
+	if err != nil {
+		return domain.AuditLog{}, err
+	}
+
+	if body, err := json.Marshal(log); err != nil {
+		logging.FromContext(ctx).Error("cache: marshal record failed", "error", err)
+	} else if err := s.cache.Set(ctx, key, body, s.cacheTTL); err != nil {
+		logging.FromContext(ctx).Error("cache: set failed", "error", err)
+	}
+	return log, nil
+}
+
+// DeleteAuditLog soft-deletes a single record (sets deleted_at), so it is
+// excluded from ListAuditLogs/GetAuditLog but remains recoverable and
+// intact for tamper-evidence purposes. For true, irreversible removal see
+// HardDeleteAuditLog, which is meant to be exposed only behind an
+// operator-configured auth.RoutePolicy requiring an elevated scope.
+func (s *AuditService) DeleteAuditLog(ctx context.Context, tenantID, id string) error {
+	if err := s.repo.SoftDelete(ctx, tenantID, id); err != nil {
+		logging.FromContext(ctx).Error("soft delete audit log failed", "error", err)
+		return err
+	}
+	s.invalidateCache(ctx, tenantID, id)
+	return nil
+}
+
+// HardDeleteAuditLog permanently removes a single record, bypassing the
+// soft delete. There is no undo; callers are responsible for gating this
+// behind a suitably restrictive auth.RoutePolicy (see
+// httpapi.Handler.hardDeleteAuditLog).
+func (s *AuditService) HardDeleteAuditLog(ctx context.Context, tenantID, id string) error {
+	if err := s.repo.HardDelete(ctx, tenantID, id); err != nil {
+		logging.FromContext(ctx).Error("hard delete audit log failed", "error", err)
+		return err
+	}
+	s.invalidateCache(ctx, tenantID, id)
+	return nil
+}
+
+// GetAuditLogHead reports whether a record exists and, if so, its
+// metadata for freshness checks (ID, Sequence, CreatedAt), without
+// fetching its Data/Meta/Tags. Backs HEAD /api/v1/audit/{id} for
+// monitoring tools and caches that only need existence+freshness.
+func (s *AuditService) GetAuditLogHead(ctx context.Context, tenantID, id string) (domain.AuditLog, error) {
+	return s.repo.HeadByID(ctx, tenantID, id)
+}
+
+// PurgeTenant permanently deletes every audit log for tenantID and
+// returns how many were deleted. Used for tenant offboarding; it is
+// irreversible.
+//
+// This does not invalidate any cached GetByID entries for the tenant:
+// unlike AddTags/RemoveTags, which know exactly which id changed, a purge
+// would need to enumerate every id the cache might hold, which the cache
+// itself doesn't expose (see Cache). A purged tenant's cached records
+// simply expire on their own via cacheTTL, the same TTL that already
+// bounds how long a read-through cache can serve stale data.
+func (s *AuditService) PurgeTenant(ctx context.Context, tenantID string) (int64, error) {
+	deleted, err := s.repo.DeleteByTenant(ctx, tenantID)
+	if err != nil {
+		logging.FromContext(ctx).Error("purge tenant failed", "tenant_id", tenantID, "error", err)
+		return 0, err
+	}
+	logging.FromContext(ctx).Info("tenant purged", "tenant_id", tenantID, "deleted", deleted)
+	metrics.AuditLogsDeletedTotal.WithLabelValues(tenantID).Add(float64(deleted))
+	return deleted, nil
+}
+
+// DeleteAuditLogsByFilter permanently deletes every record matching
+// filter and returns how many were deleted. It is irreversible, so
+// unlike ListAuditLogs this rejects a filter that isn't scoped tightly
+// enough to be a deliberate cleanup rather than an accident: filter.TenantID
+// is required, and at least one further bound (a time range, an
+// event/resource/actor/auth type, a tag, a search term, or an Expr
+// condition) must also be set. A bare tenant_id alone would delete that
+// tenant's entire history — PurgeTenant already exists for that, and
+// requires calling a route named accordingly rather than an empty filter
+// slipping through here unnoticed.
+func (s *AuditService) DeleteAuditLogsByFilter(ctx context.Context, filter domain.Filter) (int64, error) {
+	if filter.TenantID == "" {
+		return 0, fmt.Errorf("%w: tenant_id is required", domain.ErrInvalidArgument)
+	}
+	if !filterHasBound(filter) {
+		return 0, fmt.Errorf("%w: filter must include at least one bound besides tenant_id (e.g. a time range, event, resource, tag, or search term)", domain.ErrInvalidArgument)
+	}
+
+	deleted, err := s.repo.DeleteByFilter(ctx, filter)
+	if err != nil {
+		logging.FromContext(ctx).Error("delete audit logs by filter failed", "tenant_id", filter.TenantID, "error", err)
+		return 0, err
+	}
+	logging.FromContext(ctx).Info("audit logs deleted by filter", "tenant_id", filter.TenantID, "deleted", deleted)
+	metrics.AuditLogsDeletedTotal.WithLabelValues(filter.TenantID).Add(float64(deleted))
+	return deleted, nil
+}
+
+// filterHasBound reports whether filter narrows results by anything
+// besides TenantID/IncludeDeleted, for DeleteAuditLogsByFilter's
+// unbounded-delete guard.
+func filterHasBound(filter domain.Filter) bool {
+	return filter.Actor != "" ||
+		filter.AuthType != "" ||
+		filter.Event != "" ||
+		len(filter.Events) > 0 ||
+		filter.Resource != "" ||
+		len(filter.Resources) > 0 ||
+		filter.ResourceID != "" ||
+		!filter.From.IsZero() ||
+		!filter.To.IsZero() ||
+		filter.ContainsPII != nil ||
+		filter.BusinessHours != nil ||
+		len(filter.Tags) > 0 ||
+		filter.Search != "" ||
+		len(filter.Expr) > 0
+}
+
+// AddTags merges tags into the record's existing tag set and returns the
+// updated record.
+func (s *AuditService) AddTags(ctx context.Context, tenantID, id string, tags []string) (domain.AuditLog, error) {
+	log, err := s.repo.AddTags(ctx, tenantID, id, tags)
+	if err != nil {
+		return domain.AuditLog{}, err
+	}
+	s.invalidateCache(ctx, tenantID, id)
+	return log, nil
+}
+
+// RemoveTags removes tags from the record's existing tag set and returns
+// the updated record.
+func (s *AuditService) RemoveTags(ctx context.Context, tenantID, id string, tags []string) (domain.AuditLog, error) {
+	log, err := s.repo.RemoveTags(ctx, tenantID, id, tags)
+	if err != nil {
+		return domain.AuditLog{}, err
+	}
+	s.invalidateCache(ctx, tenantID, id)
+	return log, nil
+}
+
+// AppendMeta merges patch into the record's existing meta object,
+// leaving data and every key not in patch untouched, and returns the
+// updated record. patch is subject to the same MetaDenylist as a
+// create, so it can't be used to sneak a disallowed key past it.
+func (s *AuditService) AppendMeta(ctx context.Context, tenantID, id string, patch map[string]any) (domain.AuditLog, error) {
+	patch = filterMetaDenylist(ctx, patch, s.cfg.MetaDenylist)
+	log, err := s.repo.AppendMeta(ctx, tenantID, id, patch)
+	if err != nil {
+		return domain.AuditLog{}, err
+	}
+	s.invalidateCache(ctx, tenantID, id)
+	return log, nil
+}
+
+// invalidateCache drops the cached GetByID entry for tenantID/id, if
+// caching is enabled. Best-effort: a failure is logged, not returned,
+// since the caller's own write already succeeded and the stale entry
+// will still expire on its own via cacheTTL.
+func (s *AuditService) invalidateCache(ctx context.Context, tenantID, id string) {
+	if s.cache == nil {
+		return
+	}
+	if err := s.cache.Delete(ctx, cacheKey(tenantID, id)); err != nil {
+		logging.FromContext(ctx).Error("cache: invalidate failed", "error", err)
+	}
+}
+
+// GetStats returns a lightweight activity summary for tenantID: total
+// event volume, a breakdown by event type, and how many of those events
+// look like failures (see config.StatsConfig.ErrorEventSuffixes).
+func (s *AuditService) GetStats(ctx context.Context, tenantID string) (domain.TenantStats, error) {
+	if tenantID == "" {
+		return domain.TenantStats{}, fmt.Errorf("%w: tenant_id is required", domain.ErrInvalidArgument)
+	}
+
+	counts, err := s.repo.EventCounts(ctx, domain.Filter{TenantID: tenantID})
+	if err != nil {
+		return domain.TenantStats{}, err
+	}
+	resourceCounts, err := s.repo.ResourceCounts(ctx, domain.Filter{TenantID: tenantID})
+	if err != nil {
+		return domain.TenantStats{}, err
+	}
+
+	stats := domain.TenantStats{TenantID: tenantID, EventCounts: counts, ResourceCounts: resourceCounts}
+	for event, count := range counts {
+		stats.TotalEvents += count
+		if isErrorEvent(event, s.cfg.Stats.ErrorEventSuffixes) {
+			stats.ErrorEvents += count
+		}
+	}
+	if stats.TotalEvents > 0 {
+		stats.ErrorRate = float64(stats.ErrorEvents) / float64(stats.TotalEvents)
+	}
+
+	if s.cfg.Quota.Enabled {
+		usage, err := s.repo.TenantStorageBytes(ctx, tenantID)
+		if err != nil {
+			return domain.TenantStats{}, err
+		}
+		stats.StorageBytes = usage
+		stats.QuotaBytes = s.cfg.Quota.BytesFor(tenantID)
+	}
+	return stats, nil
+}
+
+func isErrorEvent(event string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(event, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetLatestPerResource returns the single most recent audit log for each
+// distinct resource matching filter, for "current state" dashboards that
+// would otherwise have to fetch and dedupe every event client-side.
+// filter.TenantID should normally be set to scope the view to one tenant.
+func (s *AuditService) GetLatestPerResource(ctx context.Context, filter domain.Filter) ([]domain.AuditLog, error) {
+	return s.repo.LatestPerResource(ctx, filter)
+}
+
+// VerifyChain walks tenantID's tamper-evident hash chain (see
+// AuditLog.Hash/PrevHash) and reports whether it is intact, along with the
+// first broken record if not.
+func (s *AuditService) VerifyChain(ctx context.Context, tenantID string) (domain.ChainVerification, error) {
+	if tenantID == "" {
+		return domain.ChainVerification{}, fmt.Errorf("%w: tenant_id is required", domain.ErrInvalidArgument)
+	}
+	result, err := s.repo.VerifyChain(ctx, tenantID)
+	if err != nil {
+		return domain.ChainVerification{}, err
+	}
+	if !result.Valid {
+		logging.FromContext(ctx).Error("audit log hash chain broken", "tenant_id", tenantID, "broken_at", result.BrokenAt, "reason", result.Reason)
+	}
+	return result, nil
+}
+
+// GetFacets returns the topN most frequent values of field among audit
+// logs matching filter, plus the total number of distinct values, so
+// callers can tell whether the list was truncated. topN <= 0 falls back
+// to defaultFacetTopN and is capped at maxFacetTopN.
+func (s *AuditService) GetFacets(ctx context.Context, filter domain.Filter, field string, topN int) (domain.FacetResult, error) {
+	if !facetFieldAllowlist[field] {
+		return domain.FacetResult{}, fmt.Errorf("%w: field must be one of tenant_id, actor, event, resource, resource_id", domain.ErrInvalidArgument)
+	}
+	if topN <= 0 {
+		topN = defaultFacetTopN
+	}
+	if topN > maxFacetTopN {
+		topN = maxFacetTopN
+	}
+	return s.repo.Facets(ctx, filter, field, topN)
+}
+
+// GetTimeSeries returns a count-per-bucket histogram over filter.From..To
+// at the given interval ("hour", "day" or "week"), with zero-count buckets
+// filled in so the result has no gaps for charting. The range is required
+// (filter.From and filter.To must both be set) so the number of buckets is
+// bounded.
+func (s *AuditService) GetTimeSeries(ctx context.Context, filter domain.Filter, interval string) ([]domain.TimeBucket, error) {
+	step, ok := intervalStep[interval]
+	if !ok {
+		return nil, fmt.Errorf("%w: interval must be one of hour, day, week", domain.ErrInvalidArgument)
+	}
+	if filter.From.IsZero() || filter.To.IsZero() || !filter.To.After(filter.From) {
+		return nil, fmt.Errorf("%w: from and to must both be set with to after from", domain.ErrInvalidArgument)
+	}
+
+	from := filter.From.Truncate(step)
+	to := filter.To.Truncate(step)
+	numBuckets := int(to.Sub(from)/step) + 1
+	if numBuckets > maxTimeSeriesBuckets {
+		return nil, fmt.Errorf("%w: range produces %d buckets at %s interval, exceeds max of %d", domain.ErrInvalidArgument, numBuckets, interval, maxTimeSeriesBuckets)
+	}
+
+	buckets, err := s.repo.TimeSeries(ctx, filter, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[int64]int64, len(buckets))
+	for _, b := range buckets {
+		counts[b.BucketStart.Unix()] = b.Count
+	}
+
+	filled := make([]domain.TimeBucket, 0, numBuckets)
+	for t := from; !t.After(to); t = t.Add(step) {
+		filled = append(filled, domain.TimeBucket{BucketStart: t, Count: counts[t.Unix()]})
+	}
+	return filled, nil
+}
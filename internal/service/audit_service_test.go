@@ -0,0 +1,1489 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/config"
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+	"github.com/naveenkumar2412/audit-log-service/internal/ingestmode"
+	"github.com/naveenkumar2412/audit-log-service/internal/metrics"
+)
+
+type fakeRepo struct {
+	lastFilter   domain.Filter
+	lastField    string
+	lastTopN     int
+	createCalls  int
+	storageBytes int64
+
+	// listResult is what List returns, simulating rows the store would
+	// have matched.
+	listResult []domain.AuditLog
+	// listTotal is what List returns as its total count.
+	listTotal *int64
+
+	// existingMatch, when set, is what CreateIfAbsent returns as a match
+	// instead of inserting, simulating an existing record within the
+	// requested window.
+	existingMatch       *domain.AuditLog
+	lastMatchFields     []string
+	lastMatchWindow     time.Duration
+	createIfAbsentCalls int
+
+	// existingIdempotencyMatch, when set, is what CreateWithIdempotencyKey
+	// returns as a match instead of inserting, simulating an existing
+	// record for the same idempotency key within the requested window.
+	existingIdempotencyMatch      *domain.AuditLog
+	lastIdempotencyWindow         time.Duration
+	createWithIdempotencyKeyCalls int
+
+	// getByIDResult is what GetByID returns, simulating the row the store
+	// would have matched.
+	getByIDResult domain.AuditLog
+	getByIDCalls  int
+
+	softDeleteCalls int
+	softDeleteErr   error
+	hardDeleteCalls int
+	hardDeleteErr   error
+
+	// deleteByFilterResult/deleteByFilterErr are what DeleteByFilter
+	// returns, simulating the store's DELETE outcome.
+	deleteByFilterResult int64
+	deleteByFilterErr    error
+	deleteByFilterCalls  int
+
+	verifyChainResult domain.ChainVerification
+	verifyChainErr    error
+}
+
+func (f *fakeRepo) Create(ctx context.Context, log domain.AuditLog) (domain.AuditLog, error) {
+	f.createCalls++
+	log.ID = uuid.New()
+	return log, nil
+}
+
+func (f *fakeRepo) CreateIfAbsent(ctx context.Context, log domain.AuditLog, matchFields []string, window time.Duration) (domain.AuditLog, bool, error) {
+	f.createIfAbsentCalls++
+	f.lastMatchFields = matchFields
+	f.lastMatchWindow = window
+	if f.existingMatch != nil {
+		return *f.existingMatch, false, nil
+	}
+	log.ID = uuid.New()
+	return log, true, nil
+}
+
+func (f *fakeRepo) CreateWithIdempotencyKey(ctx context.Context, log domain.AuditLog, window time.Duration) (domain.AuditLog, bool, error) {
+	f.createWithIdempotencyKeyCalls++
+	f.lastIdempotencyWindow = window
+	if f.existingIdempotencyMatch != nil {
+		return *f.existingIdempotencyMatch, false, nil
+	}
+	log.ID = uuid.New()
+	return log, true, nil
+}
+
+func (f *fakeRepo) List(ctx context.Context, filter domain.Filter) ([]domain.AuditLog, *int64, error) {
+	f.lastFilter = filter
+	return f.listResult, f.listTotal, nil
+}
+
+func (f *fakeRepo) GetByID(ctx context.Context, tenantID, id string, includeDeleted bool) (domain.AuditLog, error) {
+	f.getByIDCalls++
+	return f.getByIDResult, nil
+}
+
+func (f *fakeRepo) HeadByID(ctx context.Context, tenantID, id string) (domain.AuditLog, error) {
+	return domain.AuditLog{}, nil
+}
+
+func (f *fakeRepo) TimeSeries(ctx context.Context, filter domain.Filter, interval string) ([]domain.TimeBucket, error) {
+	return nil, nil
+}
+
+func (f *fakeRepo) DeleteByTenant(ctx context.Context, tenantID string) (int64, error) {
+	return 0, nil
+}
+
+func (f *fakeRepo) DeleteByFilter(ctx context.Context, filter domain.Filter) (int64, error) {
+	f.deleteByFilterCalls++
+	f.lastFilter = filter
+	return f.deleteByFilterResult, f.deleteByFilterErr
+}
+
+func (f *fakeRepo) SoftDelete(ctx context.Context, tenantID, id string) error {
+	f.softDeleteCalls++
+	if f.softDeleteErr != nil {
+		return f.softDeleteErr
+	}
+	return nil
+}
+
+func (f *fakeRepo) HardDelete(ctx context.Context, tenantID, id string) error {
+	f.hardDeleteCalls++
+	if f.hardDeleteErr != nil {
+		return f.hardDeleteErr
+	}
+	return nil
+}
+
+func (f *fakeRepo) VerifyChain(ctx context.Context, tenantID string) (domain.ChainVerification, error) {
+	return f.verifyChainResult, f.verifyChainErr
+}
+
+func (f *fakeRepo) Facets(ctx context.Context, filter domain.Filter, field string, topN int) (domain.FacetResult, error) {
+	f.lastField = field
+	f.lastTopN = topN
+	return domain.FacetResult{TotalDistinct: 3}, nil
+}
+
+func (f *fakeRepo) LatestPerResource(ctx context.Context, filter domain.Filter) ([]domain.AuditLog, error) {
+	return nil, nil
+}
+
+func (f *fakeRepo) AddTags(ctx context.Context, tenantID, id string, tags []string) (domain.AuditLog, error) {
+	return domain.AuditLog{TenantID: tenantID, Tags: tags}, nil
+}
+
+func (f *fakeRepo) RemoveTags(ctx context.Context, tenantID, id string, tags []string) (domain.AuditLog, error) {
+	return domain.AuditLog{TenantID: tenantID}, nil
+}
+
+func (f *fakeRepo) AppendMeta(ctx context.Context, tenantID, id string, patch map[string]any) (domain.AuditLog, error) {
+	return domain.AuditLog{TenantID: tenantID, Meta: patch}, nil
+}
+
+func (f *fakeRepo) EventCounts(ctx context.Context, filter domain.Filter) (map[string]int64, error) {
+	f.lastFilter = filter
+	return map[string]int64{"user.login": 3, "payment.failed": 1}, nil
+}
+
+func (f *fakeRepo) ResourceCounts(ctx context.Context, filter domain.Filter) (map[string]int64, error) {
+	return map[string]int64{"user": 3, "payment": 1}, nil
+}
+
+func (f *fakeRepo) TenantStorageBytes(ctx context.Context, tenantID string) (int64, error) {
+	return f.storageBytes, nil
+}
+
+func testConfig() config.Config {
+	return config.Config{
+		Pagination: map[string]config.Pagination{
+			"default":    {DefaultLimit: 50, MaxLimit: 1000},
+			"audit_logs": {DefaultLimit: 25, MaxLimit: 100},
+		},
+		Stats:     config.StatsConfig{ErrorEventSuffixes: []string{".failed", ".error"}},
+		Timestamp: config.TimestampConfig{MaxPastSkew: 24 * time.Hour, MaxFutureSkew: 5 * time.Minute},
+	}
+}
+
+func TestListAuditLogs_DefaultsLimit(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewAuditService(repo, testConfig())
+
+	page, err := svc.ListAuditLogs(context.Background(), domain.Filter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.Limit != 25 {
+		t.Errorf("expected default limit 25, got %d", page.Limit)
+	}
+	if repo.lastFilter.Limit != 25 {
+		t.Errorf("expected repo to receive limit 25, got %d", repo.lastFilter.Limit)
+	}
+}
+
+func TestListAuditLogs_CapsLimit(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewAuditService(repo, testConfig())
+
+	page, err := svc.ListAuditLogs(context.Background(), domain.Filter{Limit: 9999})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.Limit != 100 {
+		t.Errorf("expected capped limit 100, got %d", page.Limit)
+	}
+}
+
+func TestListAuditLogs_NegativeOffsetClamped(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewAuditService(repo, testConfig())
+
+	page, err := svc.ListAuditLogs(context.Background(), domain.Filter{Offset: -5})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.Offset != 0 {
+		t.Errorf("expected offset clamped to 0, got %d", page.Offset)
+	}
+}
+
+func TestListAuditLogs_SetsNextCursorOnFullPage(t *testing.T) {
+	last := domain.AuditLog{ID: uuid.New(), CreatedAt: time.Now()}
+	repo := &fakeRepo{listResult: []domain.AuditLog{
+		{ID: uuid.New(), CreatedAt: time.Now().Add(time.Second)},
+		last,
+	}}
+	svc := NewAuditService(repo, testConfig())
+
+	page, err := svc.ListAuditLogs(context.Background(), domain.Filter{Limit: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.NextCursor == "" {
+		t.Fatal("expected NextCursor to be set on a full page")
+	}
+	cursor, err := domain.DecodeCursor(page.NextCursor)
+	if err != nil {
+		t.Fatalf("decode cursor: %v", err)
+	}
+	if cursor.ID != last.ID {
+		t.Errorf("cursor ID = %v, want %v (the last row in the page)", cursor.ID, last.ID)
+	}
+}
+
+func TestListAuditLogs_PassesTotalThroughWhenSet(t *testing.T) {
+	total := int64(42)
+	repo := &fakeRepo{listResult: []domain.AuditLog{{ID: uuid.New()}}, listTotal: &total}
+	svc := NewAuditService(repo, testConfig())
+
+	page, err := svc.ListAuditLogs(context.Background(), domain.Filter{WithCount: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.Total == nil || *page.Total != 42 {
+		t.Errorf("page.Total = %v, want 42", page.Total)
+	}
+}
+
+func TestListAuditLogs_OmitsTotalWhenNotRequested(t *testing.T) {
+	repo := &fakeRepo{listResult: []domain.AuditLog{{ID: uuid.New()}}}
+	svc := NewAuditService(repo, testConfig())
+
+	page, err := svc.ListAuditLogs(context.Background(), domain.Filter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.Total != nil {
+		t.Errorf("page.Total = %v, want nil", *page.Total)
+	}
+}
+
+func TestListAuditLogs_NoNextCursorOnPartialPage(t *testing.T) {
+	repo := &fakeRepo{listResult: []domain.AuditLog{{ID: uuid.New()}}}
+	svc := NewAuditService(repo, testConfig())
+
+	page, err := svc.ListAuditLogs(context.Background(), domain.Filter{Limit: 25})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.NextCursor != "" {
+		t.Errorf("expected no NextCursor on a partial page, got %q", page.NextCursor)
+	}
+}
+
+func TestListAuditLogs_PassesCursorThroughToRepo(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewAuditService(repo, testConfig())
+
+	cursor := &domain.Cursor{CreatedAt: time.Now(), ID: uuid.New()}
+	_, err := svc.ListAuditLogs(context.Background(), domain.Filter{Cursor: cursor})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.lastFilter.Cursor != cursor {
+		t.Errorf("expected repo to receive the cursor, got %+v", repo.lastFilter.Cursor)
+	}
+}
+
+func TestListAuditLogs_RejectsUnsupportedSortBy(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewAuditService(repo, testConfig())
+
+	_, err := svc.ListAuditLogs(context.Background(), domain.Filter{SortBy: "status"})
+	if !errors.Is(err, domain.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, got %v", err)
+	}
+}
+
+func TestListAuditLogs_RejectsUnsupportedSortOrder(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewAuditService(repo, testConfig())
+
+	_, err := svc.ListAuditLogs(context.Background(), domain.Filter{SortOrder: "sideways"})
+	if !errors.Is(err, domain.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, got %v", err)
+	}
+}
+
+func TestListAuditLogs_AcceptsAllowlistedSortBy(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewAuditService(repo, testConfig())
+
+	if _, err := svc.ListAuditLogs(context.Background(), domain.Filter{SortBy: "timestamp", SortOrder: "asc"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.lastFilter.SortBy != "created_at" {
+		t.Errorf("expected sort_by alias \"timestamp\" to resolve to \"created_at\", got %q", repo.lastFilter.SortBy)
+	}
+	if repo.lastFilter.SortOrder != "asc" {
+		t.Errorf("expected sort_order \"asc\" to pass through, got %q", repo.lastFilter.SortOrder)
+	}
+}
+
+func TestListAuditLogs_RejectsCursorWithNonDefaultSort(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewAuditService(repo, testConfig())
+
+	cursor := &domain.Cursor{CreatedAt: time.Now(), ID: uuid.New()}
+	_, err := svc.ListAuditLogs(context.Background(), domain.Filter{Cursor: cursor, SortBy: "event"})
+	if !errors.Is(err, domain.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, got %v", err)
+	}
+}
+
+func TestCreateAuditLog_DenylistRejectsEvent(t *testing.T) {
+	repo := &fakeRepo{}
+	cfg := testConfig()
+	cfg.Ingestion = map[string]config.EventPolicy{
+		"tenant-a": {Denylist: []string{"junk.event"}},
+	}
+	svc := NewAuditService(repo, cfg)
+
+	_, err := svc.CreateAuditLog(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "junk.event"})
+	if !errors.Is(err, domain.ErrEventNotAllowed) {
+		t.Fatalf("expected ErrEventNotAllowed, got %v", err)
+	}
+}
+
+func TestCreateAuditLog_AllowlistAcceptsListedEvent(t *testing.T) {
+	repo := &fakeRepo{}
+	cfg := testConfig()
+	cfg.Ingestion = map[string]config.EventPolicy{
+		"tenant-a": {Allowlist: []string{"user.login"}},
+	}
+	svc := NewAuditService(repo, cfg)
+
+	if _, err := svc.CreateAuditLog(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "user.login"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCreateAuditLog_IncrementsCreatedMetric(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewAuditService(repo, testConfig())
+
+	before := testutil.ToFloat64(metrics.AuditLogsCreatedTotal.WithLabelValues("tenant-metrics", "user.login"))
+
+	if _, err := svc.CreateAuditLog(context.Background(), domain.AuditLog{TenantID: "tenant-metrics", Event: "user.login"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	after := testutil.ToFloat64(metrics.AuditLogsCreatedTotal.WithLabelValues("tenant-metrics", "user.login"))
+	if after != before+1 {
+		t.Errorf("audit_logs_created_total{tenant=tenant-metrics,event=user.login} = %v, want %v", after, before+1)
+	}
+}
+
+func TestCreateAuditLogsBatch_RejectsEmptyBatch(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewAuditService(repo, testConfig())
+
+	_, err := svc.CreateAuditLogsBatch(context.Background(), nil, false)
+	if !errors.Is(err, domain.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, got %v", err)
+	}
+}
+
+func TestCreateAuditLogsBatch_RejectsOversizedBatch(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewAuditService(repo, testConfig())
+
+	logs := make([]domain.AuditLog, maxBatchCreateEntries+1)
+	for i := range logs {
+		logs[i] = domain.AuditLog{TenantID: "tenant-a", Event: "user.login"}
+	}
+
+	_, err := svc.CreateAuditLogsBatch(context.Background(), logs, false)
+	if !errors.Is(err, domain.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, got %v", err)
+	}
+}
+
+func TestCreateAuditLogsBatch_OneBadEntryDoesNotFailTheRest(t *testing.T) {
+	repo := &fakeRepo{}
+	cfg := testConfig()
+	cfg.Ingestion = map[string]config.EventPolicy{
+		"tenant-a": {Denylist: []string{"junk.event"}},
+	}
+	svc := NewAuditService(repo, cfg)
+
+	logs := []domain.AuditLog{
+		{TenantID: "tenant-a", Event: "user.login"},
+		{TenantID: "tenant-a", Event: "junk.event"},
+		{TenantID: "tenant-a", Event: "user.logout"},
+	}
+
+	result, err := svc.CreateAuditLogsBatch(context.Background(), logs, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Created) != 2 {
+		t.Errorf("expected 2 created entries, got %d", len(result.Created))
+	}
+	if len(result.Failed) != 1 || result.Failed[0].Index != 1 {
+		t.Fatalf("expected entry 1 to be reported failed, got %+v", result.Failed)
+	}
+}
+
+func TestCreateAuditLogsBatch_AllOrNothingAbortsOnAnyFailure(t *testing.T) {
+	repo := &fakeRepo{}
+	cfg := testConfig()
+	cfg.Ingestion = map[string]config.EventPolicy{
+		"tenant-a": {Denylist: []string{"junk.event"}},
+	}
+	svc := NewAuditService(repo, cfg)
+
+	logs := []domain.AuditLog{
+		{TenantID: "tenant-a", Event: "user.login"},
+		{TenantID: "tenant-a", Event: "junk.event"},
+	}
+
+	result, err := svc.CreateAuditLogsBatch(context.Background(), logs, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Created) != 0 {
+		t.Errorf("expected nothing created in all-or-nothing mode, got %d", len(result.Created))
+	}
+	if repo.createCalls != 0 {
+		t.Errorf("expected repo.Create to never be called in all-or-nothing mode, got %d calls", repo.createCalls)
+	}
+	if len(result.Failed) != 1 || result.Failed[0].Index != 1 {
+		t.Fatalf("expected entry 1 to be reported failed, got %+v", result.Failed)
+	}
+}
+
+func TestCreateAuditLog_NoPolicyAllowsAnything(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewAuditService(repo, testConfig())
+
+	if _, err := svc.CreateAuditLog(context.Background(), domain.AuditLog{TenantID: "tenant-z", Event: "anything"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCreateAuditLog_NormalizesIPv4ClientIP(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewAuditService(repo, testConfig())
+
+	log, err := svc.CreateAuditLog(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "e1", ClientIP: "192.0.2.1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if log.ClientIP != "192.0.2.1" {
+		t.Errorf("got ClientIP=%q, want 192.0.2.1", log.ClientIP)
+	}
+}
+
+func TestCreateAuditLog_NormalizesIPv6ClientIP(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewAuditService(repo, testConfig())
+
+	log, err := svc.CreateAuditLog(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "e1", ClientIP: "2001:db8::1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if log.ClientIP != "2001:db8::1" {
+		t.Errorf("got ClientIP=%q, want 2001:db8::1", log.ClientIP)
+	}
+}
+
+func TestCreateAuditLog_NormalizesIPv4MappedIPv6ClientIPToDottedForm(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewAuditService(repo, testConfig())
+
+	log, err := svc.CreateAuditLog(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "e1", ClientIP: "::ffff:192.0.2.1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if log.ClientIP != "192.0.2.1" {
+		t.Errorf("got ClientIP=%q, want 192.0.2.1 (IPv4-mapped IPv6 normalized to dotted form)", log.ClientIP)
+	}
+}
+
+func TestCreateAuditLog_NormalizesClientIPWithPort(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewAuditService(repo, testConfig())
+
+	log, err := svc.CreateAuditLog(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "e1", ClientIP: "192.0.2.1:54321"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if log.ClientIP != "192.0.2.1" {
+		t.Errorf("got ClientIP=%q, want 192.0.2.1 (port stripped)", log.ClientIP)
+	}
+}
+
+func TestCreateAuditLog_RejectsMalformedClientIP(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewAuditService(repo, testConfig())
+
+	_, err := svc.CreateAuditLog(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "e1", ClientIP: "not-an-ip"})
+	if !errors.Is(err, domain.ErrInvalidArgument) {
+		t.Errorf("err = %v, want domain.ErrInvalidArgument", err)
+	}
+}
+
+func TestCreateAuditLogConditional_InsertsWhenAbsent(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewAuditService(repo, testConfig())
+
+	log, created, err := svc.CreateAuditLogConditional(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "user.login", Actor: "alice"},
+		domain.CreateCondition{MatchFields: []string{"actor", "event"}, Window: time.Minute})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !created {
+		t.Error("expected created=true when no match exists")
+	}
+	if log.ID == (uuid.UUID{}) {
+		t.Error("expected the record to have an assigned ID")
+	}
+	if repo.createIfAbsentCalls != 1 {
+		t.Errorf("expected 1 CreateIfAbsent call, got %d", repo.createIfAbsentCalls)
+	}
+}
+
+func TestCreateAuditLogConditional_ReturnsExistingMatch(t *testing.T) {
+	existing := domain.AuditLog{ID: uuid.New(), TenantID: "tenant-a", Event: "user.login", Actor: "alice"}
+	repo := &fakeRepo{existingMatch: &existing}
+	svc := NewAuditService(repo, testConfig())
+
+	log, created, err := svc.CreateAuditLogConditional(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "user.login", Actor: "alice"},
+		domain.CreateCondition{MatchFields: []string{"actor", "event"}, Window: time.Minute})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created {
+		t.Error("expected created=false when a match exists")
+	}
+	if log.ID != existing.ID {
+		t.Errorf("expected the existing record to be returned, got %+v", log)
+	}
+}
+
+func TestCreateAuditLogConditional_RejectsUnsupportedMatchField(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewAuditService(repo, testConfig())
+
+	_, _, err := svc.CreateAuditLogConditional(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "user.login"},
+		domain.CreateCondition{MatchFields: []string{"data"}, Window: time.Minute})
+	if !errors.Is(err, domain.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, got %v", err)
+	}
+}
+
+func TestCreateAuditLogConditional_RejectsNonPositiveWindow(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewAuditService(repo, testConfig())
+
+	_, _, err := svc.CreateAuditLogConditional(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "user.login"},
+		domain.CreateCondition{MatchFields: []string{"event"}, Window: 0})
+	if !errors.Is(err, domain.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, got %v", err)
+	}
+}
+
+func TestCreateAuditLogIdempotent_InsertsWhenKeyUnseen(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewAuditService(repo, testConfig())
+
+	log, created, err := svc.CreateAuditLogIdempotent(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "user.login", IdempotencyKey: "req-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !created {
+		t.Error("expected created=true for an unseen idempotency key")
+	}
+	if log.ID == (uuid.UUID{}) {
+		t.Error("expected the record to have an assigned ID")
+	}
+	if repo.createWithIdempotencyKeyCalls != 1 {
+		t.Errorf("expected 1 CreateWithIdempotencyKey call, got %d", repo.createWithIdempotencyKeyCalls)
+	}
+}
+
+func TestCreateAuditLogIdempotent_ReturnsExistingRecordForRepeatedKey(t *testing.T) {
+	existing := domain.AuditLog{ID: uuid.New(), TenantID: "tenant-a", Event: "user.login", IdempotencyKey: "req-1"}
+	repo := &fakeRepo{existingIdempotencyMatch: &existing}
+	svc := NewAuditService(repo, testConfig())
+
+	log, created, err := svc.CreateAuditLogIdempotent(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "user.login", IdempotencyKey: "req-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created {
+		t.Error("expected created=false when a record for the key already exists")
+	}
+	if log.ID != existing.ID {
+		t.Errorf("expected the existing record to be returned, got %+v", log)
+	}
+}
+
+func TestCreateAuditLogIdempotent_PassesConfiguredWindowToRepo(t *testing.T) {
+	repo := &fakeRepo{}
+	cfg := testConfig()
+	cfg.Idempotency.Window = 2 * time.Hour
+	svc := NewAuditService(repo, cfg)
+
+	if _, _, err := svc.CreateAuditLogIdempotent(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "user.login", IdempotencyKey: "req-1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.lastIdempotencyWindow != 2*time.Hour {
+		t.Errorf("expected repo to receive the configured window, got %v", repo.lastIdempotencyWindow)
+	}
+}
+
+type tsFakeRepo struct {
+	buckets []domain.TimeBucket
+}
+
+func (f *tsFakeRepo) Create(ctx context.Context, log domain.AuditLog) (domain.AuditLog, error) {
+	return log, nil
+}
+
+func (f *tsFakeRepo) CreateWithIdempotencyKey(ctx context.Context, log domain.AuditLog, window time.Duration) (domain.AuditLog, bool, error) {
+	return log, true, nil
+}
+
+func (f *tsFakeRepo) CreateIfAbsent(ctx context.Context, log domain.AuditLog, matchFields []string, window time.Duration) (domain.AuditLog, bool, error) {
+	return log, true, nil
+}
+
+func (f *tsFakeRepo) List(ctx context.Context, filter domain.Filter) ([]domain.AuditLog, *int64, error) {
+	return nil, nil, nil
+}
+
+func (f *tsFakeRepo) GetByID(ctx context.Context, tenantID, id string, includeDeleted bool) (domain.AuditLog, error) {
+	return domain.AuditLog{}, nil
+}
+
+func (f *tsFakeRepo) SoftDelete(ctx context.Context, tenantID, id string) error {
+	return nil
+}
+
+func (f *tsFakeRepo) HardDelete(ctx context.Context, tenantID, id string) error {
+	return nil
+}
+
+func (f *tsFakeRepo) VerifyChain(ctx context.Context, tenantID string) (domain.ChainVerification, error) {
+	return domain.ChainVerification{}, nil
+}
+
+func (f *tsFakeRepo) HeadByID(ctx context.Context, tenantID, id string) (domain.AuditLog, error) {
+	return domain.AuditLog{}, nil
+}
+
+func (f *tsFakeRepo) TimeSeries(ctx context.Context, filter domain.Filter, interval string) ([]domain.TimeBucket, error) {
+	return f.buckets, nil
+}
+
+func (f *tsFakeRepo) DeleteByTenant(ctx context.Context, tenantID string) (int64, error) {
+	return 0, nil
+}
+
+func (f *tsFakeRepo) DeleteByFilter(ctx context.Context, filter domain.Filter) (int64, error) {
+	return 0, nil
+}
+
+func (f *tsFakeRepo) Facets(ctx context.Context, filter domain.Filter, field string, topN int) (domain.FacetResult, error) {
+	return domain.FacetResult{}, nil
+}
+
+func (f *tsFakeRepo) LatestPerResource(ctx context.Context, filter domain.Filter) ([]domain.AuditLog, error) {
+	return nil, nil
+}
+
+func (f *tsFakeRepo) AddTags(ctx context.Context, tenantID, id string, tags []string) (domain.AuditLog, error) {
+	return domain.AuditLog{}, nil
+}
+
+func (f *tsFakeRepo) RemoveTags(ctx context.Context, tenantID, id string, tags []string) (domain.AuditLog, error) {
+	return domain.AuditLog{}, nil
+}
+
+func (f *tsFakeRepo) AppendMeta(ctx context.Context, tenantID, id string, patch map[string]any) (domain.AuditLog, error) {
+	return domain.AuditLog{}, nil
+}
+
+func (f *tsFakeRepo) EventCounts(ctx context.Context, filter domain.Filter) (map[string]int64, error) {
+	return nil, nil
+}
+
+func (f *tsFakeRepo) ResourceCounts(ctx context.Context, filter domain.Filter) (map[string]int64, error) {
+	return nil, nil
+}
+
+func (f *tsFakeRepo) TenantStorageBytes(ctx context.Context, tenantID string) (int64, error) {
+	return 0, nil
+}
+
+func TestGetTimeSeries_FillsGaps(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 1, 4, 0, 0, 0, 0, time.UTC)
+	repo := &tsFakeRepo{buckets: []domain.TimeBucket{
+		{BucketStart: from, Count: 3},
+		{BucketStart: to, Count: 7},
+	}}
+	svc := NewAuditService(repo, testConfig())
+
+	buckets, err := svc.GetTimeSeries(context.Background(), domain.Filter{From: from, To: to}, "day")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(buckets) != 4 {
+		t.Fatalf("expected 4 buckets, got %d", len(buckets))
+	}
+	if buckets[0].Count != 3 || buckets[3].Count != 7 {
+		t.Errorf("unexpected counts: %+v", buckets)
+	}
+	if buckets[1].Count != 0 || buckets[2].Count != 0 {
+		t.Errorf("expected gap buckets to be zero-filled: %+v", buckets)
+	}
+}
+
+func TestGetTimeSeries_RejectsUnknownInterval(t *testing.T) {
+	repo := &tsFakeRepo{}
+	svc := NewAuditService(repo, testConfig())
+
+	_, err := svc.GetTimeSeries(context.Background(), domain.Filter{From: time.Now(), To: time.Now().Add(time.Hour)}, "fortnight")
+	if !errors.Is(err, domain.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, got %v", err)
+	}
+}
+
+func TestGetTimeSeries_RejectsMissingRange(t *testing.T) {
+	repo := &tsFakeRepo{}
+	svc := NewAuditService(repo, testConfig())
+
+	_, err := svc.GetTimeSeries(context.Background(), domain.Filter{}, "day")
+	if !errors.Is(err, domain.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, got %v", err)
+	}
+}
+
+func TestCreateAuditLog_MetaAllowlistDropsUnlistedKeys(t *testing.T) {
+	repo := &fakeRepo{}
+	cfg := testConfig()
+	cfg.MetaAllowlist = map[string][]string{"tenant-a": {"ip"}}
+	svc := NewAuditService(repo, cfg)
+
+	log, err := svc.CreateAuditLog(context.Background(), domain.AuditLog{
+		TenantID: "tenant-a",
+		Event:    "login",
+		Meta:     map[string]any{"ip": "1.2.3.4", "ua": "curl"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := log.Meta["ua"]; ok {
+		t.Errorf("expected ua to be dropped, got %+v", log.Meta)
+	}
+	if log.Meta["ip"] != "1.2.3.4" {
+		t.Errorf("expected ip to be kept, got %+v", log.Meta)
+	}
+}
+
+func TestCreateAuditLog_MetaDenylistStripsDisallowedKeys(t *testing.T) {
+	repo := &fakeRepo{}
+	cfg := testConfig()
+	cfg.MetaDenylist = []string{"password"}
+	svc := NewAuditService(repo, cfg)
+
+	log, err := svc.CreateAuditLog(context.Background(), domain.AuditLog{
+		TenantID: "tenant-a",
+		Event:    "login",
+		Meta:     map[string]any{"password": "hunter2", "ip": "1.2.3.4"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := log.Meta["password"]; ok {
+		t.Errorf("expected password to be stripped, got %+v", log.Meta)
+	}
+	if log.Meta["ip"] != "1.2.3.4" {
+		t.Errorf("expected ip to be kept, got %+v", log.Meta)
+	}
+}
+
+func TestGetFacets_DefaultsAndCapsTopN(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewAuditService(repo, testConfig())
+
+	if _, err := svc.GetFacets(context.Background(), domain.Filter{}, "actor", 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.lastTopN != defaultFacetTopN {
+		t.Errorf("lastTopN = %d, want default %d", repo.lastTopN, defaultFacetTopN)
+	}
+
+	if _, err := svc.GetFacets(context.Background(), domain.Filter{}, "actor", 10000); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.lastTopN != maxFacetTopN {
+		t.Errorf("lastTopN = %d, want capped %d", repo.lastTopN, maxFacetTopN)
+	}
+}
+
+func TestGetFacets_RejectsUnknownField(t *testing.T) {
+	svc := NewAuditService(&fakeRepo{}, testConfig())
+
+	if _, err := svc.GetFacets(context.Background(), domain.Filter{}, "data", 0); !errors.Is(err, domain.ErrInvalidArgument) {
+		t.Fatalf("err = %v, want ErrInvalidArgument", err)
+	}
+}
+
+type recordingNotifier struct {
+	notified []domain.AuditLog
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, log domain.AuditLog) {
+	n.notified = append(n.notified, log)
+}
+
+func TestCreateAuditLog_NotifiesOnSuccess(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewAuditService(repo, testConfig())
+	notifier := &recordingNotifier{}
+	svc.WithNotifier(notifier)
+
+	if _, err := svc.CreateAuditLog(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "e"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(notifier.notified) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if len(notifier.notified) != 1 {
+		t.Fatalf("expected notifier to be called once, got %d", len(notifier.notified))
+	}
+}
+
+type fakeDeduper struct {
+	store map[string][]byte
+}
+
+func newFakeDeduper() *fakeDeduper {
+	return &fakeDeduper{store: map[string][]byte{}}
+}
+
+func (f *fakeDeduper) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	v, ok := f.store[key]
+	return v, ok, nil
+}
+
+func (f *fakeDeduper) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	f.store[key] = value
+	return nil
+}
+
+func TestCreateAuditLog_DedupReturnsOriginalOnDuplicate(t *testing.T) {
+	repo := &fakeRepo{}
+	cfg := testConfig()
+	cfg.Dedup = config.DedupConfig{Enabled: true, Window: time.Minute, Fields: []string{"tenant_id", "event"}}
+	svc := NewAuditService(repo, cfg).WithDeduper(newFakeDeduper())
+
+	first, err := svc.CreateAuditLog(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "user.login"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	second, err := svc.CreateAuditLog(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "user.login"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if second.ID != first.ID {
+		t.Errorf("expected duplicate request to return the original record, got a new ID")
+	}
+	if repo.createCalls != 1 {
+		t.Errorf("repo.Create called %d times, want 1 (second request should have been deduped)", repo.createCalls)
+	}
+}
+
+func TestCreateAuditLog_DedupDisabledAllowsDuplicates(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewAuditService(repo, testConfig()).WithDeduper(newFakeDeduper())
+
+	if _, err := svc.CreateAuditLog(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "user.login"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.CreateAuditLog(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "user.login"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if repo.createCalls != 2 {
+		t.Errorf("repo.Create called %d times, want 2 (dedup disabled by default)", repo.createCalls)
+	}
+}
+
+func TestGetStats_ComputesTotalsAndErrorRate(t *testing.T) {
+	svc := NewAuditService(&fakeRepo{}, testConfig())
+
+	stats, err := svc.GetStats(context.Background(), "tenant-a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.TotalEvents != 4 {
+		t.Errorf("TotalEvents = %d, want 4", stats.TotalEvents)
+	}
+	if stats.ErrorEvents != 1 {
+		t.Errorf("ErrorEvents = %d, want 1", stats.ErrorEvents)
+	}
+	if stats.ErrorRate != 0.25 {
+		t.Errorf("ErrorRate = %v, want 0.25", stats.ErrorRate)
+	}
+	wantEventCounts := map[string]int64{"user.login": 3, "payment.failed": 1}
+	if !reflect.DeepEqual(stats.EventCounts, wantEventCounts) {
+		t.Errorf("EventCounts = %v, want %v", stats.EventCounts, wantEventCounts)
+	}
+	wantResourceCounts := map[string]int64{"user": 3, "payment": 1}
+	if !reflect.DeepEqual(stats.ResourceCounts, wantResourceCounts) {
+		t.Errorf("ResourceCounts = %v, want %v", stats.ResourceCounts, wantResourceCounts)
+	}
+}
+
+func TestGetStats_RejectsMissingTenant(t *testing.T) {
+	svc := NewAuditService(&fakeRepo{}, testConfig())
+
+	if _, err := svc.GetStats(context.Background(), ""); !errors.Is(err, domain.ErrInvalidArgument) {
+		t.Fatalf("err = %v, want ErrInvalidArgument", err)
+	}
+}
+
+func TestAddTags_DelegatesToRepo(t *testing.T) {
+	svc := NewAuditService(&fakeRepo{}, testConfig())
+
+	got, err := svc.AddTags(context.Background(), "tenant-a", "log-1", []string{"high-risk"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got.Tags) != 1 || got.Tags[0] != "high-risk" {
+		t.Errorf("got tags %+v, want [high-risk]", got.Tags)
+	}
+}
+
+func TestRemoveTags_DelegatesToRepo(t *testing.T) {
+	svc := NewAuditService(&fakeRepo{}, testConfig())
+
+	if _, err := svc.RemoveTags(context.Background(), "tenant-a", "log-1", []string{"high-risk"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestAppendMeta_DelegatesToRepo(t *testing.T) {
+	svc := NewAuditService(&fakeRepo{}, testConfig())
+
+	got, err := svc.AppendMeta(context.Background(), "tenant-a", "log-1", map[string]any{"correlation_id": "abc"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Meta["correlation_id"] != "abc" {
+		t.Errorf("got meta %+v, want correlation_id=abc", got.Meta)
+	}
+}
+
+func TestAppendMeta_StripsDenylistedKeys(t *testing.T) {
+	cfg := testConfig()
+	cfg.MetaDenylist = []string{"password"}
+	svc := NewAuditService(&fakeRepo{}, cfg)
+
+	got, err := svc.AppendMeta(context.Background(), "tenant-a", "log-1", map[string]any{"password": "secret", "correlation_id": "abc"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := got.Meta["password"]; ok {
+		t.Error("expected denylisted key to be stripped before reaching the repo")
+	}
+	if got.Meta["correlation_id"] != "abc" {
+		t.Errorf("got meta %+v, want correlation_id to survive", got.Meta)
+	}
+}
+
+func TestCreateAuditLog_RejectsTimestampOutsidePastSkew(t *testing.T) {
+	svc := NewAuditService(&fakeRepo{}, testConfig())
+
+	stale := time.Now().Add(-48 * time.Hour)
+	_, err := svc.CreateAuditLog(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "user.login", CreatedAt: stale})
+	if !errors.Is(err, domain.ErrInvalidArgument) {
+		t.Fatalf("err = %v, want ErrInvalidArgument", err)
+	}
+}
+
+func TestCreateAuditLog_RejectsTimestampOutsideFutureSkew(t *testing.T) {
+	svc := NewAuditService(&fakeRepo{}, testConfig())
+
+	future := time.Now().Add(time.Hour)
+	_, err := svc.CreateAuditLog(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "user.login", CreatedAt: future})
+	if !errors.Is(err, domain.ErrInvalidArgument) {
+		t.Fatalf("err = %v, want ErrInvalidArgument", err)
+	}
+}
+
+func TestCreateAuditLog_AllowsTimestampWithinSkewWindow(t *testing.T) {
+	svc := NewAuditService(&fakeRepo{}, testConfig())
+
+	recent := time.Now().Add(-time.Hour)
+	if _, err := svc.CreateAuditLog(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "user.login", CreatedAt: recent}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCreateAuditLog_ImportModeExemptsStaleTimestampFromSkewCheck(t *testing.T) {
+	svc := NewAuditService(&fakeRepo{}, testConfig())
+
+	ctx := ingestmode.WithContext(context.Background(), true)
+	stale := time.Now().Add(-365 * 24 * time.Hour)
+	if _, err := svc.CreateAuditLog(ctx, domain.AuditLog{TenantID: "tenant-a", Event: "user.login", CreatedAt: stale}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCreateAuditLog_ZeroTimestampSkipsSkewCheck(t *testing.T) {
+	svc := NewAuditService(&fakeRepo{}, testConfig())
+
+	if _, err := svc.CreateAuditLog(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "user.login"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+type fakeFileSink struct {
+	mu      sync.Mutex
+	written []domain.AuditLog
+}
+
+func (f *fakeFileSink) Write(ctx context.Context, log domain.AuditLog) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.written = append(f.written, log)
+	return nil
+}
+
+func (f *fakeFileSink) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.written)
+}
+
+func TestCreateAuditLog_MirrorFileSinkWritesAlongsideRepo(t *testing.T) {
+	repo := &fakeRepo{}
+	sink := &fakeFileSink{}
+	svc := NewAuditService(repo, testConfig()).WithFileSink(sink, false)
+
+	if _, err := svc.CreateAuditLog(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "user.login"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.createCalls != 1 {
+		t.Errorf("expected repo.Create to be called once, got %d", repo.createCalls)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for sink.count() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if sink.count() != 1 {
+		t.Fatalf("expected file sink to be written once, got %d", sink.count())
+	}
+}
+
+func TestCreateAuditLog_PrimaryFileSinkSkipsRepo(t *testing.T) {
+	repo := &fakeRepo{}
+	sink := &fakeFileSink{}
+	svc := NewAuditService(repo, testConfig()).WithFileSink(sink, true)
+
+	log, err := svc.CreateAuditLog(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "user.login"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.createCalls != 0 {
+		t.Errorf("expected repo.Create not to be called, got %d calls", repo.createCalls)
+	}
+	if sink.count() != 1 {
+		t.Fatalf("expected file sink to be written once, got %d", sink.count())
+	}
+	if log.ID == (uuid.UUID{}) {
+		t.Error("expected a server-assigned ID even without a repo write")
+	}
+	if log.CreatedAt.IsZero() {
+		t.Error("expected a server-assigned CreatedAt even without a repo write")
+	}
+}
+
+type fakeKafkaPublisher struct {
+	mu        sync.Mutex
+	published []domain.AuditLog
+	err       error
+}
+
+func (f *fakeKafkaPublisher) Publish(ctx context.Context, log domain.AuditLog) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	f.published = append(f.published, log)
+	return nil
+}
+
+func (f *fakeKafkaPublisher) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.published)
+}
+
+func TestCreateAuditLog_PublishesToKafkaOnSuccess(t *testing.T) {
+	repo := &fakeRepo{}
+	publisher := &fakeKafkaPublisher{}
+	svc := NewAuditService(repo, testConfig()).WithKafkaPublisher(publisher)
+
+	if _, err := svc.CreateAuditLog(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "user.login"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for publisher.count() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if publisher.count() != 1 {
+		t.Fatalf("expected publisher to be called once, got %d", publisher.count())
+	}
+}
+
+func TestCreateAuditLog_KafkaPublishFailureDoesNotFailCreate(t *testing.T) {
+	repo := &fakeRepo{}
+	publisher := &fakeKafkaPublisher{err: errors.New("broker unreachable")}
+	svc := NewAuditService(repo, testConfig()).WithKafkaPublisher(publisher)
+
+	if _, err := svc.CreateAuditLog(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "user.login"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+type fakeNATSPublisher struct {
+	mu        sync.Mutex
+	published []domain.AuditLog
+	err       error
+}
+
+func (f *fakeNATSPublisher) Publish(ctx context.Context, log domain.AuditLog) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	f.published = append(f.published, log)
+	return nil
+}
+
+func (f *fakeNATSPublisher) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.published)
+}
+
+func TestCreateAuditLog_PublishesToNATSOnSuccess(t *testing.T) {
+	repo := &fakeRepo{}
+	publisher := &fakeNATSPublisher{}
+	svc := NewAuditService(repo, testConfig()).WithNATSPublisher(publisher)
+
+	if _, err := svc.CreateAuditLog(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "user.login"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for publisher.count() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if publisher.count() != 1 {
+		t.Fatalf("expected publisher to be called once, got %d", publisher.count())
+	}
+}
+
+func TestCreateAuditLog_NATSPublishFailureDoesNotFailCreate(t *testing.T) {
+	repo := &fakeRepo{}
+	publisher := &fakeNATSPublisher{err: errors.New("no responders")}
+	svc := NewAuditService(repo, testConfig()).WithNATSPublisher(publisher)
+
+	if _, err := svc.CreateAuditLog(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "user.login"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+type fakeCache struct {
+	mu          sync.Mutex
+	store       map[string][]byte
+	deleteCalls int
+}
+
+func newFakeCache() *fakeCache {
+	return &fakeCache{store: make(map[string][]byte)}
+}
+
+func (c *fakeCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	val, ok := c.store[key]
+	return val, ok, nil
+}
+
+func (c *fakeCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.store[key] = value
+	return nil
+}
+
+func (c *fakeCache) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.deleteCalls++
+	delete(c.store, key)
+	return nil
+}
+
+func TestGetAuditLog_CachesOnMissAndServesFromCacheAfter(t *testing.T) {
+	id := uuid.New()
+	repo := &fakeRepo{getByIDResult: domain.AuditLog{ID: id, TenantID: "tenant-a", Event: "user.login"}}
+	cache := newFakeCache()
+	svc := NewAuditService(repo, testConfig()).WithCache(cache, time.Minute)
+
+	log, err := svc.GetAuditLog(context.Background(), "tenant-a", id.String(), false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if log.Event != "user.login" {
+		t.Errorf("Event = %q, want user.login", log.Event)
+	}
+	if repo.getByIDCalls != 1 {
+		t.Fatalf("expected repo.GetByID to be called once on miss, got %d calls", repo.getByIDCalls)
+	}
+
+	if _, err := svc.GetAuditLog(context.Background(), "tenant-a", id.String(), false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.getByIDCalls != 1 {
+		t.Errorf("expected repo.GetByID not to be called again on a cache hit, got %d calls", repo.getByIDCalls)
+	}
+}
+
+func TestAddTags_InvalidatesCache(t *testing.T) {
+	id := uuid.New()
+	repo := &fakeRepo{getByIDResult: domain.AuditLog{ID: id, TenantID: "tenant-a"}}
+	cache := newFakeCache()
+	svc := NewAuditService(repo, testConfig()).WithCache(cache, time.Minute)
+
+	if _, err := svc.GetAuditLog(context.Background(), "tenant-a", id.String(), false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := svc.AddTags(context.Background(), "tenant-a", id.String(), []string{"reviewed"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cache.deleteCalls != 1 {
+		t.Errorf("expected AddTags to invalidate the cached entry, got %d deletes", cache.deleteCalls)
+	}
+}
+
+func TestAppendMeta_InvalidatesCache(t *testing.T) {
+	id := uuid.New()
+	repo := &fakeRepo{getByIDResult: domain.AuditLog{ID: id, TenantID: "tenant-a"}}
+	cache := newFakeCache()
+	svc := NewAuditService(repo, testConfig()).WithCache(cache, time.Minute)
+
+	if _, err := svc.GetAuditLog(context.Background(), "tenant-a", id.String(), false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := svc.AppendMeta(context.Background(), "tenant-a", id.String(), map[string]any{"correlation_id": "abc"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cache.deleteCalls != 1 {
+		t.Errorf("expected AppendMeta to invalidate the cached entry, got %d deletes", cache.deleteCalls)
+	}
+}
+
+func TestDeleteAuditLog_SoftDeletesAndInvalidatesCache(t *testing.T) {
+	id := uuid.New()
+	repo := &fakeRepo{getByIDResult: domain.AuditLog{ID: id, TenantID: "tenant-a"}}
+	cache := newFakeCache()
+	svc := NewAuditService(repo, testConfig()).WithCache(cache, time.Minute)
+
+	if _, err := svc.GetAuditLog(context.Background(), "tenant-a", id.String(), false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := svc.DeleteAuditLog(context.Background(), "tenant-a", id.String()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.softDeleteCalls != 1 {
+		t.Errorf("expected DeleteAuditLog to call repo.SoftDelete once, got %d calls", repo.softDeleteCalls)
+	}
+	if repo.hardDeleteCalls != 0 {
+		t.Errorf("expected DeleteAuditLog not to call repo.HardDelete, got %d calls", repo.hardDeleteCalls)
+	}
+	if cache.deleteCalls != 1 {
+		t.Errorf("expected DeleteAuditLog to invalidate the cached entry, got %d deletes", cache.deleteCalls)
+	}
+}
+
+func TestDeleteAuditLog_PropagatesRepoError(t *testing.T) {
+	id := uuid.New()
+	wantErr := domain.ErrNotFound
+	repo := &fakeRepo{softDeleteErr: wantErr}
+	svc := NewAuditService(repo, testConfig())
+
+	if err := svc.DeleteAuditLog(context.Background(), "tenant-a", id.String()); !errors.Is(err, wantErr) {
+		t.Fatalf("expected %v, got %v", wantErr, err)
+	}
+}
+
+func TestHardDeleteAuditLog_PermanentlyRemovesAndInvalidatesCache(t *testing.T) {
+	id := uuid.New()
+	repo := &fakeRepo{getByIDResult: domain.AuditLog{ID: id, TenantID: "tenant-a"}}
+	cache := newFakeCache()
+	svc := NewAuditService(repo, testConfig()).WithCache(cache, time.Minute)
+
+	if _, err := svc.GetAuditLog(context.Background(), "tenant-a", id.String(), false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := svc.HardDeleteAuditLog(context.Background(), "tenant-a", id.String()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.hardDeleteCalls != 1 {
+		t.Errorf("expected HardDeleteAuditLog to call repo.HardDelete once, got %d calls", repo.hardDeleteCalls)
+	}
+	if repo.softDeleteCalls != 0 {
+		t.Errorf("expected HardDeleteAuditLog not to call repo.SoftDelete, got %d calls", repo.softDeleteCalls)
+	}
+	if cache.deleteCalls != 1 {
+		t.Errorf("expected HardDeleteAuditLog to invalidate the cached entry, got %d deletes", cache.deleteCalls)
+	}
+}
+
+func TestHardDeleteAuditLog_PropagatesRepoError(t *testing.T) {
+	id := uuid.New()
+	wantErr := domain.ErrNotFound
+	repo := &fakeRepo{hardDeleteErr: wantErr}
+	svc := NewAuditService(repo, testConfig())
+
+	if err := svc.HardDeleteAuditLog(context.Background(), "tenant-a", id.String()); !errors.Is(err, wantErr) {
+		t.Fatalf("expected %v, got %v", wantErr, err)
+	}
+}
+
+func TestDeleteAuditLogsByFilter_RejectsMissingTenant(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewAuditService(repo, testConfig())
+
+	_, err := svc.DeleteAuditLogsByFilter(context.Background(), domain.Filter{Event: "test.run"})
+	if !errors.Is(err, domain.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, got %v", err)
+	}
+	if repo.deleteByFilterCalls != 0 {
+		t.Errorf("expected no repo call for a rejected filter, got %d", repo.deleteByFilterCalls)
+	}
+}
+
+func TestDeleteAuditLogsByFilter_RejectsTenantIDAlone(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewAuditService(repo, testConfig())
+
+	_, err := svc.DeleteAuditLogsByFilter(context.Background(), domain.Filter{TenantID: "tenant-a"})
+	if !errors.Is(err, domain.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument for an unbounded filter, got %v", err)
+	}
+	if repo.deleteByFilterCalls != 0 {
+		t.Errorf("expected no repo call for a rejected filter, got %d", repo.deleteByFilterCalls)
+	}
+}
+
+func TestDeleteAuditLogsByFilter_DeletesWhenBoundedByTenantAndEvent(t *testing.T) {
+	repo := &fakeRepo{deleteByFilterResult: 7}
+	svc := NewAuditService(repo, testConfig())
+
+	filter := domain.Filter{TenantID: "tenant-a", Event: "test.run"}
+	deleted, err := svc.DeleteAuditLogsByFilter(context.Background(), filter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deleted != 7 {
+		t.Errorf("got %d deleted, want 7", deleted)
+	}
+	if repo.deleteByFilterCalls != 1 {
+		t.Errorf("expected exactly one repo call, got %d", repo.deleteByFilterCalls)
+	}
+	if repo.lastFilter.TenantID != filter.TenantID || repo.lastFilter.Event != filter.Event {
+		t.Errorf("got filter %+v passed to repo, want %+v", repo.lastFilter, filter)
+	}
+}
+
+func TestDeleteAuditLogsByFilter_DeletesWhenBoundedByDateRange(t *testing.T) {
+	repo := &fakeRepo{deleteByFilterResult: 3}
+	svc := NewAuditService(repo, testConfig())
+
+	filter := domain.Filter{TenantID: "tenant-a", To: time.Now()}
+	if _, err := svc.DeleteAuditLogsByFilter(context.Background(), filter); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.deleteByFilterCalls != 1 {
+		t.Errorf("expected exactly one repo call, got %d", repo.deleteByFilterCalls)
+	}
+}
+
+func TestDeleteAuditLogsByFilter_PropagatesRepoError(t *testing.T) {
+	wantErr := errors.New("connection reset")
+	repo := &fakeRepo{deleteByFilterErr: wantErr}
+	svc := NewAuditService(repo, testConfig())
+
+	_, err := svc.DeleteAuditLogsByFilter(context.Background(), domain.Filter{TenantID: "tenant-a", Event: "test.run"})
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("expected %v, got %v", wantErr, err)
+	}
+}
+
+func TestVerifyChain_RejectsMissingTenant(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewAuditService(repo, testConfig())
+
+	_, err := svc.VerifyChain(context.Background(), "")
+	if !errors.Is(err, domain.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, got %v", err)
+	}
+}
+
+func TestVerifyChain_ReturnsRepoResult(t *testing.T) {
+	brokenAt := uuid.New()
+	repo := &fakeRepo{verifyChainResult: domain.ChainVerification{Valid: false, Checked: 3, BrokenAt: &brokenAt, Reason: "hash does not match the record's content"}}
+	svc := NewAuditService(repo, testConfig())
+
+	result, err := svc.VerifyChain(context.Background(), "tenant-a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Valid || result.Checked != 3 || result.BrokenAt == nil || *result.BrokenAt != brokenAt {
+		t.Errorf("expected the repo's result to pass through unchanged, got %+v", result)
+	}
+}
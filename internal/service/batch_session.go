@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+)
+
+// batchSessionTTL bounds how long an idle batch session stays resumable
+// before it's treated as abandoned.
+const batchSessionTTL = 30 * time.Minute
+
+type batchSession struct {
+	tenantID  string
+	logs      []domain.AuditLog
+	expiresAt time.Time
+	committed bool
+}
+
+// BatchIngestService supports chunked, resumable ingestion: a client opens
+// a session, uploads chunks of audit logs over however many requests it
+// needs (retrying a dropped chunk by resending it), then commits, which
+// writes everything it accumulated (see Commit). Sessions live only in this process's memory, so
+// they don't survive a restart — acceptable for bridging a single flaky
+// upload, not a durability guarantee.
+type BatchIngestService struct {
+	repo Repo
+
+	mu       sync.Mutex
+	sessions map[string]*batchSession
+}
+
+// NewBatchIngestService returns a service backed by repo for the final
+// commit write.
+func NewBatchIngestService(repo Repo) *BatchIngestService {
+	return &BatchIngestService{repo: repo, sessions: make(map[string]*batchSession)}
+}
+
+// OpenSession starts a new batch session for a tenant and returns its
+// token.
+func (s *BatchIngestService) OpenSession(tenantID string) (string, error) {
+	if tenantID == "" {
+		return "", fmt.Errorf("%w: tenant_id is required", domain.ErrInvalidArgument)
+	}
+
+	token := uuid.NewString()
+	s.mu.Lock()
+	s.sessions[token] = &batchSession{tenantID: tenantID, expiresAt: time.Now().Add(batchSessionTTL)}
+	s.mu.Unlock()
+	return token, nil
+}
+
+// AppendChunk adds logs to an open session, extending its expiry. Each
+// log's TenantID is set to the session's tenant, replacing whatever it
+// carried.
+func (s *BatchIngestService) AppendChunk(token string, logs []domain.AuditLog) (int, error) {
+	sess, err := s.getOpenSession(token)
+	if err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, log := range logs {
+		log.TenantID = sess.tenantID
+		sess.logs = append(sess.logs, log)
+	}
+	sess.expiresAt = time.Now().Add(batchSessionTTL)
+	return len(sess.logs), nil
+}
+
+// Commit writes every log accumulated in the session and marks it
+// committed; a committed session can't accept further chunks or be
+// committed twice. The returned logs carry the IDs assigned by the
+// store, so callers can build a Location for each created record.
+// Logs are written one at a time, not in one transaction: if a write
+// fails, Commit returns the error, the logs written before it stay in the
+// store, and the session stays open, so committing it again writes those
+// logs a second time.
+func (s *BatchIngestService) Commit(ctx context.Context, token string) ([]domain.AuditLog, error) {
+	sess, err := s.getOpenSession(token)
+	if err != nil {
+		return nil, err
+	}
+
+	created := make([]domain.AuditLog, 0, len(sess.logs))
+	for _, log := range sess.logs {
+		log, err := s.repo.Create(ctx, log)
+		if err != nil {
+			return nil, err
+		}
+		created = append(created, log)
+	}
+
+	s.mu.Lock()
+	sess.committed = true
+	s.mu.Unlock()
+	return created, nil
+}
+
+func (s *BatchIngestService) getOpenSession(token string) (*batchSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.sessions[token]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown batch session", domain.ErrNotFound)
+	}
+	if sess.committed {
+		return nil, fmt.Errorf("%w: batch session already committed", domain.ErrInvalidArgument)
+	}
+	if time.Now().After(sess.expiresAt) {
+		delete(s.sessions, token)
+		return nil, fmt.Errorf("%w: batch session expired", domain.ErrNotFound)
+	}
+	return sess, nil
+}
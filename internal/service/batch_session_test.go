@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+)
+
+func TestBatchIngestService_OpenAppendCommit(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewBatchIngestService(repo)
+
+	token, err := svc.OpenSession("tenant-a")
+	if err != nil {
+		t.Fatalf("OpenSession: %v", err)
+	}
+
+	total, err := svc.AppendChunk(token, []domain.AuditLog{{Event: "e1"}, {Event: "e2"}})
+	if err != nil {
+		t.Fatalf("AppendChunk: %v", err)
+	}
+	if total != 2 {
+		t.Fatalf("total = %d, want 2", total)
+	}
+
+	created, err := svc.Commit(context.Background(), token)
+	if err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if len(created) != 2 {
+		t.Fatalf("created = %d, want 2", len(created))
+	}
+	for _, log := range created {
+		if log.ID == uuid.Nil {
+			t.Errorf("expected created log to have an ID, got %+v", log)
+		}
+	}
+
+	if _, err := svc.AppendChunk(token, []domain.AuditLog{{Event: "e3"}}); !errors.Is(err, domain.ErrInvalidArgument) {
+		t.Fatalf("AppendChunk after commit: err = %v, want ErrInvalidArgument", err)
+	}
+}
+
+func TestBatchIngestService_OpenSessionRequiresTenant(t *testing.T) {
+	svc := NewBatchIngestService(&fakeRepo{})
+
+	if _, err := svc.OpenSession(""); !errors.Is(err, domain.ErrInvalidArgument) {
+		t.Fatalf("err = %v, want ErrInvalidArgument", err)
+	}
+}
+
+func TestBatchIngestService_UnknownToken(t *testing.T) {
+	svc := NewBatchIngestService(&fakeRepo{})
+
+	if _, err := svc.AppendChunk("missing", nil); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+}
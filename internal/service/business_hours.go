@@ -0,0 +1,53 @@
+package service
+
+import (
+	"time"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/config"
+)
+
+// defaultBusinessDays is used whenever a BusinessHoursWindow.Days is empty.
+var defaultBusinessDays = map[time.Weekday]bool{
+	time.Monday:    true,
+	time.Tuesday:   true,
+	time.Wednesday: true,
+	time.Thursday:  true,
+	time.Friday:    true,
+}
+
+// classifyBusinessHours reports whether at falls within window's business
+// hours and which day of week it occurred on. It never errors: an unknown
+// timezone (which should have been caught at config load time) falls back
+// to UTC rather than blocking ingestion.
+func classifyBusinessHours(window config.BusinessHoursWindow, at time.Time) (businessHours bool, dayOfWeek string) {
+	loc, err := time.LoadLocation(window.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	local := at.In(loc)
+	dayOfWeek = local.Weekday().String()
+
+	days := businessDays(window.Days)
+	if !days[local.Weekday()] {
+		return false, dayOfWeek
+	}
+	hour := local.Hour()
+	return hour >= window.StartHour && hour < window.EndHour, dayOfWeek
+}
+
+// businessDays converts names to a weekday set, defaulting to Monday-Friday
+// when names is empty.
+func businessDays(names []string) map[time.Weekday]bool {
+	if len(names) == 0 {
+		return defaultBusinessDays
+	}
+	days := make(map[time.Weekday]bool, len(names))
+	for _, name := range names {
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			if d.String() == name {
+				days[d] = true
+			}
+		}
+	}
+	return days
+}
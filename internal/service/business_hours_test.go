@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/config"
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+	"github.com/naveenkumar2412/audit-log-service/internal/ingestmode"
+)
+
+func TestClassifyBusinessHours_WithinWindow(t *testing.T) {
+	window := config.BusinessHoursWindow{Timezone: "UTC", StartHour: 9, EndHour: 17}
+	// 2026-08-10 is a Monday.
+	businessHours, dayOfWeek := classifyBusinessHours(window, time.Date(2026, 8, 10, 12, 0, 0, 0, time.UTC))
+	if !businessHours {
+		t.Error("expected business_hours=true")
+	}
+	if dayOfWeek != "Monday" {
+		t.Errorf("dayOfWeek = %q, want Monday", dayOfWeek)
+	}
+}
+
+func TestClassifyBusinessHours_OutsideHourRange(t *testing.T) {
+	window := config.BusinessHoursWindow{Timezone: "UTC", StartHour: 9, EndHour: 17}
+	businessHours, _ := classifyBusinessHours(window, time.Date(2026, 8, 10, 20, 0, 0, 0, time.UTC))
+	if businessHours {
+		t.Error("expected business_hours=false outside hour range")
+	}
+}
+
+func TestClassifyBusinessHours_DefaultsToMondayFriday(t *testing.T) {
+	window := config.BusinessHoursWindow{Timezone: "UTC", StartHour: 9, EndHour: 17}
+	// 2026-08-15 is a Saturday.
+	businessHours, dayOfWeek := classifyBusinessHours(window, time.Date(2026, 8, 15, 12, 0, 0, 0, time.UTC))
+	if businessHours {
+		t.Error("expected business_hours=false on a Saturday with the default Mon-Fri window")
+	}
+	if dayOfWeek != "Saturday" {
+		t.Errorf("dayOfWeek = %q, want Saturday", dayOfWeek)
+	}
+}
+
+func TestClassifyBusinessHours_CustomDays(t *testing.T) {
+	window := config.BusinessHoursWindow{Timezone: "UTC", StartHour: 9, EndHour: 17, Days: []string{"Saturday", "Sunday"}}
+	// 2026-08-15 is a Saturday.
+	businessHours, _ := classifyBusinessHours(window, time.Date(2026, 8, 15, 12, 0, 0, 0, time.UTC))
+	if !businessHours {
+		t.Error("expected business_hours=true on a configured business day")
+	}
+}
+
+func TestClassifyBusinessHours_UnknownTimezoneFallsBackToUTC(t *testing.T) {
+	window := config.BusinessHoursWindow{Timezone: "not/a-zone", StartHour: 9, EndHour: 17}
+	businessHours, dayOfWeek := classifyBusinessHours(window, time.Date(2026, 8, 10, 12, 0, 0, 0, time.UTC))
+	if !businessHours {
+		t.Error("expected fallback to UTC to still classify correctly")
+	}
+	if dayOfWeek != "Monday" {
+		t.Errorf("dayOfWeek = %q, want Monday", dayOfWeek)
+	}
+}
+
+func TestCreateAuditLog_TagsBusinessHours(t *testing.T) {
+	cfg := testConfig()
+	cfg.BusinessHours = config.BusinessHoursConfig{
+		Enabled: true,
+		Default: config.BusinessHoursWindow{Timezone: "UTC", StartHour: 9, EndHour: 17},
+	}
+	svc := NewAuditService(&fakeRepo{}, cfg)
+
+	ctx := ingestmode.WithContext(context.Background(), true)
+	// 2026-08-10 is a Monday.
+	created, err := svc.CreateAuditLog(ctx, domain.AuditLog{TenantID: "tenant-a", Event: "user.login", CreatedAt: time.Date(2026, 8, 10, 12, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.Meta["business_hours"] != true {
+		t.Errorf("expected business_hours=true, got %v", created.Meta["business_hours"])
+	}
+	if created.Meta["day_of_week"] != "Monday" {
+		t.Errorf("expected day_of_week=Monday, got %v", created.Meta["day_of_week"])
+	}
+}
+
+func TestCreateAuditLog_BusinessHoursDisabledSkipsTagging(t *testing.T) {
+	svc := NewAuditService(&fakeRepo{}, testConfig())
+
+	created, err := svc.CreateAuditLog(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "user.login"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := created.Meta["business_hours"]; ok {
+		t.Error("expected no business_hours tag when disabled")
+	}
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+)
+
+// exprFieldAllowlist restricts which audit_logs columns an expression
+// filter can reference — the same columns the built-in filter fields
+// already expose, so the free-form filter can't read anything the
+// structured one couldn't.
+var exprFieldAllowlist = map[string]bool{
+	"tenant_id":   true,
+	"actor":       true,
+	"event":       true,
+	"resource":    true,
+	"resource_id": true,
+	"created_at":  true,
+	"sequence":    true,
+}
+
+// exprOps is ordered longest-operator-first so "!=" and ">=" are matched
+// before their single-character prefixes "=" and ">".
+var exprOps = []domain.ExprOp{domain.ExprNeq, domain.ExprGte, domain.ExprLte, domain.ExprEq, domain.ExprGt, domain.ExprLt, domain.ExprLike}
+
+// ParseExpression parses a semicolon-separated list of "field<op>value"
+// conditions, e.g. "actor=alice;event!=login.failed". It rejects any
+// field not in exprFieldAllowlist so the result is always safe to
+// interpolate as a column name once validated — values are still passed
+// to the store as query parameters, never concatenated into SQL.
+func ParseExpression(expr string) ([]domain.ExprCond, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, nil
+	}
+
+	var conds []domain.ExprCond
+	for _, clause := range strings.Split(expr, ";") {
+		clause = strings.TrimSpace(clause)
+		if clause == "" {
+			continue
+		}
+
+		cond, err := parseClause(clause)
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, cond)
+	}
+	return conds, nil
+}
+
+func parseClause(clause string) (domain.ExprCond, error) {
+	for _, op := range exprOps {
+		idx := strings.Index(clause, string(op))
+		if idx <= 0 {
+			continue
+		}
+		field := strings.TrimSpace(clause[:idx])
+		value := strings.TrimSpace(clause[idx+len(op):])
+		if !exprFieldAllowlist[field] {
+			return domain.ExprCond{}, fmt.Errorf("%w: unknown filter field %q", domain.ErrInvalidArgument, field)
+		}
+		return domain.ExprCond{Field: field, Op: op, Value: value}, nil
+	}
+	return domain.ExprCond{}, fmt.Errorf("%w: could not parse filter clause %q", domain.ErrInvalidArgument, clause)
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+)
+
+func TestParseExpression_MultipleClauses(t *testing.T) {
+	conds, err := ParseExpression("actor=alice;event!=login.failed")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(conds) != 2 {
+		t.Fatalf("expected 2 conditions, got %d", len(conds))
+	}
+	if conds[0] != (domain.ExprCond{Field: "actor", Op: domain.ExprEq, Value: "alice"}) {
+		t.Errorf("got %+v", conds[0])
+	}
+	if conds[1] != (domain.ExprCond{Field: "event", Op: domain.ExprNeq, Value: "login.failed"}) {
+		t.Errorf("got %+v", conds[1])
+	}
+}
+
+func TestParseExpression_RejectsUnknownField(t *testing.T) {
+	_, err := ParseExpression("password=secret")
+	if !errors.Is(err, domain.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, got %v", err)
+	}
+}
+
+func TestParseExpression_AllowsSequenceGapDetection(t *testing.T) {
+	conds, err := ParseExpression("sequence>100")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(conds) != 1 || conds[0] != (domain.ExprCond{Field: "sequence", Op: domain.ExprGt, Value: "100"}) {
+		t.Errorf("got %+v", conds)
+	}
+}
+
+func TestParseExpression_Empty(t *testing.T) {
+	conds, err := ParseExpression("")
+	if err != nil || conds != nil {
+		t.Errorf("expected nil, nil, got %v, %v", conds, err)
+	}
+}
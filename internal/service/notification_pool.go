@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+	"github.com/naveenkumar2412/audit-log-service/internal/logging"
+	"github.com/naveenkumar2412/audit-log-service/internal/metrics"
+)
+
+type notificationJob struct {
+	ctx context.Context
+	log domain.AuditLog
+}
+
+// NotificationPool bounds notification fan-out to a fixed number of
+// worker goroutines pulling from a buffered channel, replacing the
+// unbounded go func() per create that AuditService used before (see
+// config.NotificationConfig), which could exhaust memory under load. It
+// satisfies Notifier itself, so AuditService submits to it exactly as it
+// would a plain Notifier.
+type NotificationPool struct {
+	notifier      Notifier
+	jobs          chan notificationJob
+	workers       int
+	submitTimeout time.Duration
+	done          chan struct{}
+}
+
+// NewNotificationPool returns a pool that delivers through notifier using
+// workers goroutines pulling from a channel buffered to queueSize.
+// submitTimeout is how long Notify blocks once the queue is full before
+// giving up and dropping the notification (incrementing
+// metrics.NotificationsDroppedTotal); <= 0 drops immediately instead of
+// blocking at all. Call Run to start the workers.
+func NewNotificationPool(notifier Notifier, workers, queueSize int, submitTimeout time.Duration) *NotificationPool {
+	return &NotificationPool{
+		notifier:      notifier,
+		jobs:          make(chan notificationJob, queueSize),
+		workers:       workers,
+		submitTimeout: submitTimeout,
+		done:          make(chan struct{}),
+	}
+}
+
+// Notify enqueues log for delivery by one of the pool's workers. It
+// satisfies the Notifier interface, so it never blocks the caller on the
+// actual delivery, only (briefly, per submitTimeout) on the queue having
+// room.
+func (p *NotificationPool) Notify(ctx context.Context, log domain.AuditLog) {
+	job := notificationJob{ctx: context.WithoutCancel(ctx), log: log}
+
+	select {
+	case p.jobs <- job:
+		return
+	default:
+	}
+
+	if p.submitTimeout <= 0 {
+		p.drop(ctx, log)
+		return
+	}
+
+	timer := time.NewTimer(p.submitTimeout)
+	defer timer.Stop()
+	select {
+	case p.jobs <- job:
+	case <-timer.C:
+		p.drop(ctx, log)
+	}
+}
+
+func (p *NotificationPool) drop(ctx context.Context, log domain.AuditLog) {
+	metrics.NotificationsDroppedTotal.Inc()
+	logging.FromContext(ctx).Error("notification pool queue full, dropping notification",
+		"tenant_id", log.TenantID, "event", log.Event)
+}
+
+// Run starts the pool's workers and blocks until ctx is canceled. On
+// cancellation, each worker drains whatever is already buffered in the
+// queue before returning, so a notification Notify already accepted is
+// not abandoned by shutdown. Once every worker has finished draining,
+// Run closes the channel Shutdown waits on.
+func (p *NotificationPool) Run(ctx context.Context) {
+	defer close(p.done)
+
+	var wg sync.WaitGroup
+	wg.Add(p.workers)
+	for i := 0; i < p.workers; i++ {
+		go func() {
+			defer wg.Done()
+			p.work(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+// Shutdown blocks until Run has finished draining every notification
+// already accepted by Notify, or ctx's deadline elapses first, whichever
+// comes first. The caller must cancel Run's own context first (e.g. as
+// part of the same shutdown sequence) — Shutdown only waits for drain to
+// finish, it does not itself trigger it.
+func (p *NotificationPool) Shutdown(ctx context.Context) error {
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *NotificationPool) work(ctx context.Context) {
+	for {
+		select {
+		case job := <-p.jobs:
+			p.notifier.Notify(job.ctx, job.log)
+		case <-ctx.Done():
+			p.drain()
+			return
+		}
+	}
+}
+
+func (p *NotificationPool) drain() {
+	for {
+		select {
+		case job := <-p.jobs:
+			p.notifier.Notify(job.ctx, job.log)
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+	"github.com/naveenkumar2412/audit-log-service/internal/metrics"
+)
+
+type countingNotifier struct {
+	mu       sync.Mutex
+	count    int32
+	received map[string]bool
+}
+
+func (n *countingNotifier) Notify(ctx context.Context, log domain.AuditLog) {
+	time.Sleep(time.Millisecond)
+	atomic.AddInt32(&n.count, 1)
+	n.mu.Lock()
+	if n.received == nil {
+		n.received = make(map[string]bool)
+	}
+	n.received[log.Event] = true
+	n.mu.Unlock()
+}
+
+func TestNotificationPool_ProcessesMoreJobsThanWorkers(t *testing.T) {
+	notifier := &countingNotifier{}
+	pool := NewNotificationPool(notifier, 3, 100, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go pool.Run(ctx)
+
+	const jobs = 20
+	for i := 0; i < jobs; i++ {
+		pool.Notify(context.Background(), domain.AuditLog{Event: "e"})
+	}
+
+	deadline := time.After(2 * time.Second)
+	for atomic.LoadInt32(&notifier.count) < jobs {
+		select {
+		case <-deadline:
+			t.Fatalf("got %d processed, want %d", atomic.LoadInt32(&notifier.count), jobs)
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
+
+func TestNotificationPool_DropsAndCountsWhenQueueFullWithoutBlocking(t *testing.T) {
+	block := make(chan struct{})
+	blockingNotifier := &blockingCountingNotifier{block: block}
+	pool := NewNotificationPool(blockingNotifier, 1, 1, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go pool.Run(ctx)
+
+	// The first Notify is picked up by the single worker and blocks there;
+	// the second fills the queue's one slot; the third finds both full and
+	// must be dropped immediately since submitTimeout is 0.
+	droppedBefore := testutil.ToFloat64(metrics.NotificationsDroppedTotal)
+	pool.Notify(context.Background(), domain.AuditLog{Event: "1"})
+	time.Sleep(20 * time.Millisecond)
+	pool.Notify(context.Background(), domain.AuditLog{Event: "2"})
+	pool.Notify(context.Background(), domain.AuditLog{Event: "3"})
+	if dropped := testutil.ToFloat64(metrics.NotificationsDroppedTotal) - droppedBefore; dropped != 1 {
+		t.Errorf("notifications_dropped_total grew by %v, want 1", dropped)
+	}
+
+	close(block)
+
+	deadline := time.After(2 * time.Second)
+	for atomic.LoadInt32(&blockingNotifier.count) < 2 {
+		select {
+		case <-deadline:
+			t.Fatalf("got %d processed, want 2 (one dropped)", atomic.LoadInt32(&blockingNotifier.count))
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
+
+type blockingCountingNotifier struct {
+	block chan struct{}
+	count int32
+}
+
+func (n *blockingCountingNotifier) Notify(ctx context.Context, log domain.AuditLog) {
+	if log.Event == "1" {
+		<-n.block
+	}
+	atomic.AddInt32(&n.count, 1)
+}
+
+func TestNotificationPool_DrainsQueueOnShutdown(t *testing.T) {
+	notifier := &countingNotifier{}
+	pool := NewNotificationPool(notifier, 1, 10, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	for i := 0; i < 5; i++ {
+		pool.Notify(context.Background(), domain.AuditLog{Event: "e"})
+	}
+
+	done := make(chan struct{})
+	go func() {
+		pool.Run(ctx)
+		close(done)
+	}()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected Run to return after context cancellation")
+	}
+
+	if got := atomic.LoadInt32(&notifier.count); got != 5 {
+		t.Errorf("got %d drained, want 5", got)
+	}
+}
+
+func TestNotificationPool_ShutdownWaitsForCreatedLogsNotificationToFlush(t *testing.T) {
+	notifier := &countingNotifier{}
+	pool := NewNotificationPool(notifier, 1, 10, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go pool.Run(ctx)
+
+	repo := &fakeRepo{}
+	svc := NewAuditService(repo, testConfig()).WithNotifier(pool)
+	if _, err := svc.CreateAuditLog(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "user.login"}); err != nil {
+		t.Fatalf("CreateAuditLog: %v", err)
+	}
+
+	// Simulate the SIGTERM shutdown path: stop accepting new work, then
+	// wait for the pool to finish draining what was already accepted.
+	cancel()
+	if err := pool.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	if atomic.LoadInt32(&notifier.count) != 1 {
+		t.Errorf("got %d notifications sent, want 1", notifier.count)
+	}
+	if !notifier.received["user.login"] {
+		t.Error("expected the created log's notification to have been sent before Shutdown returned")
+	}
+}
+
+func TestNotificationPool_ShutdownTimesOutIfDrainHangs(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	notifier := &blockingCountingNotifier{block: block}
+	pool := NewNotificationPool(notifier, 1, 10, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go pool.Run(ctx)
+
+	pool.Notify(context.Background(), domain.AuditLog{Event: "1"})
+	time.Sleep(20 * time.Millisecond)
+
+	timeoutCtx, timeoutCancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer timeoutCancel()
+	if err := pool.Shutdown(timeoutCtx); err == nil {
+		t.Fatal("expected Shutdown to time out while a worker is still blocked")
+	}
+}
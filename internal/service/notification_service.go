@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+)
+
+// NotificationRepo is the storage interface NotificationService depends
+// on. It is satisfied by internal/store/postgres.FailedNotificationRepo.
+type NotificationRepo interface {
+	ListByTenant(ctx context.Context, tenantID string) ([]domain.FailedNotification, error)
+}
+
+// NotificationService exposes the dead-letter queue of notification
+// deliveries that failed every attempt (see notify.Dispatcher.WithDeadLetter
+// and notify.NotificationRetryWorker).
+type NotificationService struct {
+	repo NotificationRepo
+}
+
+// NewNotificationService returns a service backed by the given repo.
+func NewNotificationService(repo NotificationRepo) *NotificationService {
+	return &NotificationService{repo: repo}
+}
+
+// ListFailedNotifications returns a tenant's current backlog of failed
+// notification deliveries.
+func (s *NotificationService) ListFailedNotifications(ctx context.Context, tenantID string) ([]domain.FailedNotification, error) {
+	return s.repo.ListByTenant(ctx, tenantID)
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+)
+
+type fakeNotificationRepo struct {
+	tenantID string
+	list     []domain.FailedNotification
+}
+
+func (f *fakeNotificationRepo) ListByTenant(ctx context.Context, tenantID string) ([]domain.FailedNotification, error) {
+	f.tenantID = tenantID
+	return f.list, nil
+}
+
+func TestListFailedNotifications_PassesThroughToRepo(t *testing.T) {
+	repo := &fakeNotificationRepo{list: []domain.FailedNotification{{Channel: "https://example.com/hook"}}}
+	svc := NewNotificationService(repo)
+
+	got, err := svc.ListFailedNotifications(context.Background(), "tenant-a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.tenantID != "tenant-a" {
+		t.Errorf("got tenantID=%q, want tenant-a", repo.tenantID)
+	}
+	if len(got) != 1 || got[0].Channel != "https://example.com/hook" {
+		t.Errorf("got %+v", got)
+	}
+}
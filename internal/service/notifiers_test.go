@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+)
+
+func TestNotifiers_CallsEveryNotifier(t *testing.T) {
+	a, b := &recordingNotifier{}, &recordingNotifier{}
+	combined := Notifiers(a, b)
+
+	log := domain.AuditLog{Event: "login"}
+	combined.Notify(context.Background(), log)
+
+	if len(a.notified) != 1 || a.notified[0].Event != "login" {
+		t.Errorf("first notifier got %+v", a.notified)
+	}
+	if len(b.notified) != 1 || b.notified[0].Event != "login" {
+		t.Errorf("second notifier got %+v", b.notified)
+	}
+}
+
+func TestNotifiers_EmptyIsANoOp(t *testing.T) {
+	combined := Notifiers()
+	combined.Notify(context.Background(), domain.AuditLog{Event: "login"})
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/config"
+)
+
+// defaultPIIPatterns is used whenever config.PIIConfig.Patterns is empty,
+// covering the most common categories compliance teams ask for.
+var defaultPIIPatterns = map[string]string{
+	"email":       `[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}`,
+	"phone":       `\+?\d[\d\-. ]{8,}\d`,
+	"credit_card": `\b(?:\d[ -]?){13,16}\b`,
+}
+
+// piiScanner flags records whose Data/Meta fields contain values matching
+// one or more configured PII patterns. It never rejects a record — only
+// annotates it — so a bad regex or false positive can't block ingestion.
+type piiScanner struct {
+	categories []string
+	patterns   map[string]*regexp.Regexp
+}
+
+// newPIIScanner compiles cfg's patterns (or the built-in defaults if none
+// are configured). It returns an error if a configured pattern doesn't
+// compile, since that's a configuration mistake the operator should fix
+// rather than silently ignore.
+func newPIIScanner(cfg config.PIIConfig) (*piiScanner, error) {
+	raw := cfg.Patterns
+	if len(raw) == 0 {
+		raw = defaultPIIPatterns
+	}
+
+	patterns := make(map[string]*regexp.Regexp, len(raw))
+	categories := make([]string, 0, len(raw))
+	for category, expr := range raw {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("service: pii pattern %q: %w", category, err)
+		}
+		patterns[category] = re
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	return &piiScanner{categories: categories, patterns: patterns}, nil
+}
+
+// scan inspects every string value in data and meta, returning the sorted
+// list of categories that matched. A nil/empty return means no PII was
+// detected.
+func (s *piiScanner) scan(data, meta map[string]any) []string {
+	var found []string
+	for _, category := range s.categories {
+		re := s.patterns[category]
+		if containsMatch(data, re) || containsMatch(meta, re) {
+			found = append(found, category)
+		}
+	}
+	return found
+}
+
+func containsMatch(m map[string]any, re *regexp.Regexp) bool {
+	for _, v := range m {
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/config"
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+)
+
+func TestPIIScanner_DetectsDefaultCategories(t *testing.T) {
+	scanner, err := newPIIScanner(config.PIIConfig{})
+	if err != nil {
+		t.Fatalf("newPIIScanner: %v", err)
+	}
+
+	categories := scanner.scan(map[string]any{"email": "jane@example.com"}, nil)
+	if len(categories) != 1 || categories[0] != "email" {
+		t.Errorf("categories = %v, want [email]", categories)
+	}
+}
+
+func TestPIIScanner_NoMatchReturnsEmpty(t *testing.T) {
+	scanner, err := newPIIScanner(config.PIIConfig{})
+	if err != nil {
+		t.Fatalf("newPIIScanner: %v", err)
+	}
+
+	categories := scanner.scan(map[string]any{"note": "nothing sensitive here"}, nil)
+	if len(categories) != 0 {
+		t.Errorf("categories = %v, want none", categories)
+	}
+}
+
+func TestCreateAuditLog_FlagsPIIWhenEnabled(t *testing.T) {
+	repo := &fakeRepo{}
+	cfg := testConfig()
+	cfg.PII = config.PIIConfig{Enabled: true}
+	svc := NewAuditService(repo, cfg)
+
+	log, err := svc.CreateAuditLog(context.Background(), domain.AuditLog{
+		TenantID: "tenant-a",
+		Event:    "user.login",
+		Data:     map[string]any{"contact": "jane@example.com"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if log.Meta["contains_pii"] != true {
+		t.Errorf("expected contains_pii to be set, got %+v", log.Meta)
+	}
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/config"
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+)
+
+func TestCreateAuditLog_RejectsWriteOverQuota(t *testing.T) {
+	repo := &fakeRepo{storageBytes: 1000}
+	cfg := testConfig()
+	cfg.Quota = config.QuotaConfig{Enabled: true, DefaultBytes: 1000}
+	svc := NewAuditService(repo, cfg)
+
+	_, err := svc.CreateAuditLog(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "user.login"})
+	if !errors.Is(err, domain.ErrQuotaExceeded) {
+		t.Fatalf("err = %v, want ErrQuotaExceeded", err)
+	}
+}
+
+func TestCreateAuditLog_AllowsWriteUnderQuota(t *testing.T) {
+	repo := &fakeRepo{storageBytes: 500}
+	cfg := testConfig()
+	cfg.Quota = config.QuotaConfig{Enabled: true, DefaultBytes: 1000}
+	svc := NewAuditService(repo, cfg)
+
+	if _, err := svc.CreateAuditLog(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "user.login"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCreateAuditLog_UnlimitedQuotaSkipsUsageLookup(t *testing.T) {
+	cfg := testConfig()
+	cfg.Quota = config.QuotaConfig{Enabled: true}
+	svc := NewAuditService(&fakeRepo{storageBytes: 1 << 40}, cfg)
+
+	if _, err := svc.CreateAuditLog(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "user.login"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestGetStats_IncludesUsageWhenQuotaEnabled(t *testing.T) {
+	repo := &fakeRepo{storageBytes: 42}
+	cfg := testConfig()
+	cfg.Quota = config.QuotaConfig{Enabled: true, DefaultBytes: 1000}
+	svc := NewAuditService(repo, cfg)
+
+	stats, err := svc.GetStats(context.Background(), "tenant-a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.StorageBytes != 42 || stats.QuotaBytes != 1000 {
+		t.Errorf("got storage_bytes=%d quota_bytes=%d, want 42/1000", stats.StorageBytes, stats.QuotaBytes)
+	}
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/config"
+)
+
+// redactedValue replaces any value matched by a redactor.
+const redactedValue = "***"
+
+// redactor masks values in Data before storage, unlike piiScanner which
+// only flags matches for visibility. It walks nested objects and arrays
+// so a sensitive value buried a few levels deep is still caught.
+type redactor struct {
+	keys     map[string]bool
+	patterns []*regexp.Regexp
+}
+
+// newRedactor compiles cfg's patterns and lowercases its keys for
+// case-insensitive matching. It returns an error if a configured pattern
+// doesn't compile, since that's a configuration mistake the operator
+// should fix rather than silently ignore.
+func newRedactor(cfg config.RedactionConfig) (*redactor, error) {
+	keys := make(map[string]bool, len(cfg.Keys))
+	for _, k := range cfg.Keys {
+		keys[strings.ToLower(strings.TrimSpace(k))] = true
+	}
+
+	patterns := make([]*regexp.Regexp, 0, len(cfg.Patterns))
+	for _, expr := range cfg.Patterns {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("service: redaction pattern %q: %w", expr, err)
+		}
+		patterns = append(patterns, re)
+	}
+	return &redactor{keys: keys, patterns: patterns}, nil
+}
+
+// redactMap returns a copy of m with every value whose key matches r.keys
+// (case-insensitive) or whose string content matches one of r.patterns
+// replaced with redactedValue, recursing into nested objects and arrays.
+// m itself is left untouched.
+func (r *redactor) redactMap(m map[string]any) map[string]any {
+	if len(m) == 0 {
+		return m
+	}
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		if r.keys[strings.ToLower(k)] {
+			out[k] = redactedValue
+			continue
+		}
+		out[k] = r.redactValue(v)
+	}
+	return out
+}
+
+func (r *redactor) redactValue(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		return r.redactMap(val)
+	case []any:
+		out := make([]any, len(val))
+		for i, child := range val {
+			out[i] = r.redactValue(child)
+		}
+		return out
+	case string:
+		for _, re := range r.patterns {
+			if re.MatchString(val) {
+				return redactedValue
+			}
+		}
+		return val
+	default:
+		return val
+	}
+}
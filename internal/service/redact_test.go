@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/config"
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+)
+
+func TestRedactor_MasksMatchingKeyAtTopLevel(t *testing.T) {
+	r, err := newRedactor(config.RedactionConfig{Keys: []string{"password"}})
+	if err != nil {
+		t.Fatalf("newRedactor: %v", err)
+	}
+
+	got := r.redactMap(map[string]any{"password": "hunter2", "actor": "alice"})
+	if got["password"] != redactedValue {
+		t.Errorf("password = %v, want %q", got["password"], redactedValue)
+	}
+	if got["actor"] != "alice" {
+		t.Errorf("actor = %v, want unchanged", got["actor"])
+	}
+}
+
+func TestRedactor_KeyMatchIsCaseInsensitive(t *testing.T) {
+	r, err := newRedactor(config.RedactionConfig{Keys: []string{"SSN"}})
+	if err != nil {
+		t.Fatalf("newRedactor: %v", err)
+	}
+
+	got := r.redactMap(map[string]any{"ssn": "123-45-6789"})
+	if got["ssn"] != redactedValue {
+		t.Errorf("ssn = %v, want %q", got["ssn"], redactedValue)
+	}
+}
+
+func TestRedactor_MasksMatchingKeyInNestedObject(t *testing.T) {
+	r, err := newRedactor(config.RedactionConfig{Keys: []string{"token"}})
+	if err != nil {
+		t.Fatalf("newRedactor: %v", err)
+	}
+
+	got := r.redactMap(map[string]any{
+		"auth": map[string]any{"token": "abc123", "scheme": "bearer"},
+	})
+	auth, ok := got["auth"].(map[string]any)
+	if !ok {
+		t.Fatalf("auth = %v, want map[string]any", got["auth"])
+	}
+	if auth["token"] != redactedValue {
+		t.Errorf("auth.token = %v, want %q", auth["token"], redactedValue)
+	}
+	if auth["scheme"] != "bearer" {
+		t.Errorf("auth.scheme = %v, want unchanged", auth["scheme"])
+	}
+}
+
+func TestRedactor_MasksMatchingKeyInsideArrayOfObjects(t *testing.T) {
+	r, err := newRedactor(config.RedactionConfig{Keys: []string{"card_number"}})
+	if err != nil {
+		t.Fatalf("newRedactor: %v", err)
+	}
+
+	got := r.redactMap(map[string]any{
+		"cards": []any{
+			map[string]any{"card_number": "4111111111111111", "brand": "visa"},
+			map[string]any{"card_number": "5500000000000004", "brand": "mastercard"},
+		},
+	})
+	cards, ok := got["cards"].([]any)
+	if !ok || len(cards) != 2 {
+		t.Fatalf("cards = %v, want a 2-element slice", got["cards"])
+	}
+	for i, c := range cards {
+		card := c.(map[string]any)
+		if card["card_number"] != redactedValue {
+			t.Errorf("cards[%d].card_number = %v, want %q", i, card["card_number"], redactedValue)
+		}
+	}
+}
+
+func TestRedactor_MasksValueMatchingPattern(t *testing.T) {
+	r, err := newRedactor(config.RedactionConfig{Patterns: []string{`\d{3}-\d{2}-\d{4}`}})
+	if err != nil {
+		t.Fatalf("newRedactor: %v", err)
+	}
+
+	got := r.redactMap(map[string]any{"note": "ssn is 123-45-6789"})
+	if got["note"] != redactedValue {
+		t.Errorf("note = %v, want %q", got["note"], redactedValue)
+	}
+}
+
+func TestRedactor_PatternMatchAppliesInsideNestedArray(t *testing.T) {
+	r, err := newRedactor(config.RedactionConfig{Patterns: []string{`\d{3}-\d{2}-\d{4}`}})
+	if err != nil {
+		t.Fatalf("newRedactor: %v", err)
+	}
+
+	got := r.redactMap(map[string]any{
+		"notes": []any{"clean note", "123-45-6789 leaked here"},
+	})
+	notes, ok := got["notes"].([]any)
+	if !ok || len(notes) != 2 {
+		t.Fatalf("notes = %v, want a 2-element slice", got["notes"])
+	}
+	if notes[0] != "clean note" {
+		t.Errorf("notes[0] = %v, want unchanged", notes[0])
+	}
+	if notes[1] != redactedValue {
+		t.Errorf("notes[1] = %v, want %q", notes[1], redactedValue)
+	}
+}
+
+func TestRedactor_LeavesNonMatchingValuesUntouched(t *testing.T) {
+	r, err := newRedactor(config.RedactionConfig{Keys: []string{"password"}})
+	if err != nil {
+		t.Fatalf("newRedactor: %v", err)
+	}
+
+	got := r.redactMap(map[string]any{"count": float64(3), "active": true, "tags": []any{"a", "b"}})
+	if got["count"] != float64(3) || got["active"] != true {
+		t.Errorf("unrelated scalars were changed: %+v", got)
+	}
+	tags, ok := got["tags"].([]any)
+	if !ok || tags[0] != "a" || tags[1] != "b" {
+		t.Errorf("tags = %v, want unchanged", got["tags"])
+	}
+}
+
+func TestRedactor_RejectsInvalidPattern(t *testing.T) {
+	if _, err := newRedactor(config.RedactionConfig{Patterns: []string{"("}}); err == nil {
+		t.Fatal("expected error for invalid regex pattern")
+	}
+}
+
+func TestCreateAuditLog_RedactsDataWhenEnabled(t *testing.T) {
+	repo := &fakeRepo{}
+	cfg := testConfig()
+	cfg.Redaction = config.RedactionConfig{Enabled: true, Keys: []string{"password"}}
+	svc := NewAuditService(repo, cfg)
+
+	log, err := svc.CreateAuditLog(context.Background(), domain.AuditLog{
+		TenantID: "tenant-a",
+		Event:    "user.login",
+		Data: map[string]any{
+			"password": "hunter2",
+			"profile":  map[string]any{"password": "hunter2", "name": "alice"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if log.Data["password"] != redactedValue {
+		t.Errorf("top-level password = %v, want %q", log.Data["password"], redactedValue)
+	}
+	profile := log.Data["profile"].(map[string]any)
+	if profile["password"] != redactedValue {
+		t.Errorf("nested password = %v, want %q", profile["password"], redactedValue)
+	}
+	if profile["name"] != "alice" {
+		t.Errorf("nested name = %v, want unchanged", profile["name"])
+	}
+}
+
+func TestCreateAuditLog_LeavesDataUntouchedWhenRedactionDisabled(t *testing.T) {
+	repo := &fakeRepo{}
+	cfg := testConfig()
+	svc := NewAuditService(repo, cfg)
+
+	log, err := svc.CreateAuditLog(context.Background(), domain.AuditLog{
+		TenantID: "tenant-a",
+		Event:    "user.login",
+		Data:     map[string]any{"password": "hunter2"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if log.Data["password"] != "hunter2" {
+		t.Errorf("password = %v, want unchanged when redaction is disabled", log.Data["password"])
+	}
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+)
+
+// WebhookRepo is the storage interface WebhookService depends on. It is
+// satisfied by internal/store/postgres.WebhookRepo.
+type WebhookRepo interface {
+	Create(ctx context.Context, sub domain.WebhookSubscription) (domain.WebhookSubscription, error)
+	ListByTenant(ctx context.Context, tenantID string) ([]domain.WebhookSubscription, error)
+	Delete(ctx context.Context, tenantID string, id uuid.UUID) error
+}
+
+// WebhookService manages per-tenant webhook subscriptions.
+type WebhookService struct {
+	repo WebhookRepo
+}
+
+// NewWebhookService returns a service backed by the given repo.
+func NewWebhookService(repo WebhookRepo) *WebhookService {
+	return &WebhookService{repo: repo}
+}
+
+// Subscribe registers a new webhook subscription for a tenant. sub.Enabled
+// is persisted as given — callers that want "enabled unless the caller
+// said otherwise" defaulting (e.g. the HTTP layer, whose request body
+// might omit an "enabled" field entirely) apply that default themselves
+// before calling Subscribe, since a plain bool can't distinguish "not
+// provided" from "false".
+func (s *WebhookService) Subscribe(ctx context.Context, sub domain.WebhookSubscription) (domain.WebhookSubscription, error) {
+	if sub.TenantID == "" || sub.URL == "" {
+		return domain.WebhookSubscription{}, fmt.Errorf("%w: tenant_id and url are required", domain.ErrInvalidArgument)
+	}
+	if sub.BatchMaxSize < 0 || sub.BatchLingerSeconds < 0 {
+		return domain.WebhookSubscription{}, fmt.Errorf("%w: batch_max_size and batch_linger_seconds must not be negative", domain.ErrInvalidArgument)
+	}
+	return s.repo.Create(ctx, sub)
+}
+
+// ListSubscriptions returns every subscription registered for a tenant.
+func (s *WebhookService) ListSubscriptions(ctx context.Context, tenantID string) ([]domain.WebhookSubscription, error) {
+	return s.repo.ListByTenant(ctx, tenantID)
+}
+
+// Unsubscribe removes a tenant's subscription.
+func (s *WebhookService) Unsubscribe(ctx context.Context, tenantID string, id uuid.UUID) error {
+	return s.repo.Delete(ctx, tenantID, id)
+}
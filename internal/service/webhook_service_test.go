@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+)
+
+type fakeWebhookRepo struct {
+	created domain.WebhookSubscription
+}
+
+func (f *fakeWebhookRepo) Create(ctx context.Context, sub domain.WebhookSubscription) (domain.WebhookSubscription, error) {
+	f.created = sub
+	return sub, nil
+}
+
+func (f *fakeWebhookRepo) ListByTenant(ctx context.Context, tenantID string) ([]domain.WebhookSubscription, error) {
+	return nil, nil
+}
+
+func (f *fakeWebhookRepo) Delete(ctx context.Context, tenantID string, id uuid.UUID) error {
+	return nil
+}
+
+func TestSubscribe_RequiresTenantAndURL(t *testing.T) {
+	svc := NewWebhookService(&fakeWebhookRepo{})
+
+	_, err := svc.Subscribe(context.Background(), domain.WebhookSubscription{})
+	if !errors.Is(err, domain.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, got %v", err)
+	}
+}
+
+func TestSubscribe_RejectsNegativeBatchSettings(t *testing.T) {
+	svc := NewWebhookService(&fakeWebhookRepo{})
+
+	_, err := svc.Subscribe(context.Background(), domain.WebhookSubscription{TenantID: "t1", URL: "https://example.com/hook", BatchMaxSize: -1})
+	if !errors.Is(err, domain.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, got %v", err)
+	}
+}
+
+func TestSubscribe_PassesThroughValidSubscription(t *testing.T) {
+	repo := &fakeWebhookRepo{}
+	svc := NewWebhookService(repo)
+
+	sub, err := svc.Subscribe(context.Background(), domain.WebhookSubscription{TenantID: "t1", URL: "https://example.com/hook"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sub.TenantID != "t1" || sub.URL != "https://example.com/hook" {
+		t.Errorf("got %+v", sub)
+	}
+}
+
+func TestSubscribe_PassesThroughSecretAndEnabled(t *testing.T) {
+	repo := &fakeWebhookRepo{}
+	svc := NewWebhookService(repo)
+
+	sub, err := svc.Subscribe(context.Background(), domain.WebhookSubscription{
+		TenantID: "t1",
+		URL:      "https://example.com/hook",
+		Secret:   "tenant-secret",
+		Enabled:  true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sub.Secret != "tenant-secret" || !sub.Enabled {
+		t.Errorf("got %+v, want Secret=tenant-secret Enabled=true", sub)
+	}
+}
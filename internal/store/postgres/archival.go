@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// ArchiveCompleted moves every audit log whose meta[statusKey] ==
+// statusValue and whose created_at is older than `before` into
+// audit_logs_archive, removing it from the hot audit_logs table. Both
+// steps run in a single transaction so a crash can't leave a row in
+// neither or both tables. It returns the number of rows archived.
+func (r *AuditLogRepo) ArchiveCompleted(ctx context.Context, before time.Time, statusKey, statusValue string) (int64, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("postgres: archive completed: begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	const insertQ = `
+		INSERT INTO audit_logs_archive
+		SELECT * FROM audit_logs
+		WHERE created_at < $1 AND meta->>$2 = $3`
+	if _, err := tx.Exec(ctx, insertQ, before, statusKey, statusValue); err != nil {
+		return 0, fmt.Errorf("postgres: archive completed: copy: %w", err)
+	}
+
+	const deleteQ = `
+		DELETE FROM audit_logs
+		WHERE created_at < $1 AND meta->>$2 = $3`
+	tag, err := tx.Exec(ctx, deleteQ, before, statusKey, statusValue)
+	if err != nil {
+		return 0, fmt.Errorf("postgres: archive completed: delete: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("postgres: archive completed: commit: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// ArchivalWorker periodically archives completed records older than the
+// configured age. Run it in its own goroutine; it stops when ctx is
+// canceled.
+type ArchivalWorker struct {
+	repo        *AuditLogRepo
+	after       time.Duration
+	statusKey   string
+	statusValue string
+}
+
+// NewArchivalWorker returns a worker that archives records whose meta
+// marks them completed once they're older than `after`.
+func NewArchivalWorker(repo *AuditLogRepo, after time.Duration, statusKey, statusValue string) *ArchivalWorker {
+	return &ArchivalWorker{repo: repo, after: after, statusKey: statusKey, statusValue: statusValue}
+}
+
+// Run sweeps for eligible records every interval until ctx is canceled.
+func (w *ArchivalWorker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cutoff := time.Now().Add(-w.after)
+			n, err := w.repo.ArchiveCompleted(ctx, cutoff, w.statusKey, w.statusValue)
+			if err != nil {
+				log.Printf("archival: sweep failed: %v", err)
+				continue
+			}
+			if n > 0 {
+				log.Printf("archival: archived %d completed records older than %s", n, w.after)
+			}
+		}
+	}
+}
@@ -0,0 +1,1630 @@
+// Package postgres implements the audit log storage interfaces on top of
+// PostgreSQL using pgx.
+package postgres
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/crypto"
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+	"github.com/naveenkumar2412/audit-log-service/internal/logging"
+	"github.com/naveenkumar2412/audit-log-service/internal/tracing"
+)
+
+// exprFieldAllowlist is checked again here even though service.ParseExpression
+// already validates it, so this package stays safe to call directly
+// (e.g. from a future internal tool) without relying on the caller.
+var exprFieldAllowlist = map[string]bool{
+	"tenant_id":   true,
+	"actor":       true,
+	"event":       true,
+	"resource":    true,
+	"resource_id": true,
+	"created_at":  true,
+	"sequence":    true,
+}
+
+// sortColumnAllowlist is checked again here even though service.resolveSort
+// already validates filter.SortBy, so this package stays safe to call
+// directly without relying on the caller. Only columns present here can
+// ever be interpolated into an ORDER BY clause.
+var sortColumnAllowlist = map[string]bool{
+	"created_at": true,
+	"event":      true,
+	"resource":   true,
+}
+
+// exprOpSQL maps a domain.ExprOp to its literal SQL operator. Only
+// operators present here can ever be interpolated into a query.
+var exprOpSQL = map[domain.ExprOp]string{
+	domain.ExprEq:   "=",
+	domain.ExprNeq:  "!=",
+	domain.ExprGt:   ">",
+	domain.ExprLt:   "<",
+	domain.ExprGte:  ">=",
+	domain.ExprLte:  "<=",
+	domain.ExprLike: "LIKE",
+}
+
+// AuditLogRepo provides CRUD and listing access to the audit_logs table.
+type AuditLogRepo struct {
+	pool *pgxpool.Pool
+
+	// sequenceEnabled gates the per-tenant sequence assignment in Create
+	// (see WithSequenceEnabled and config.SequenceConfig). Off by default
+	// since it costs an extra round trip per write.
+	sequenceEnabled bool
+
+	// encryptor, when set (see WithEncryptor), seals the data column
+	// before insert and opens it again on read, transparently to every
+	// caller. Left nil, data is stored exactly as before.
+	encryptor crypto.Encryptor
+
+	// queryTimeout bounds every query this repo runs (see
+	// WithQueryTimeout and config.DBConfig.QueryTimeout), so a hung
+	// query can't pin a pool connection indefinitely. Zero disables it,
+	// leaving cancellation entirely up to the caller's own ctx.
+	queryTimeout time.Duration
+}
+
+// NewAuditLogRepo returns a repo backed by the given connection pool.
+func NewAuditLogRepo(pool *pgxpool.Pool) *AuditLogRepo {
+	return &AuditLogRepo{pool: pool}
+}
+
+// WithQueryTimeout makes every subsequent query this repo runs bound to
+// timeout, unless the caller's ctx already carries an earlier deadline
+// (e.g. an HTTP request's own timeout), in which case that deadline is
+// left alone. Not calling this (the default) leaves queries unbounded
+// except by whatever deadline the caller supplies.
+func (r *AuditLogRepo) WithQueryTimeout(timeout time.Duration) *AuditLogRepo {
+	r.queryTimeout = timeout
+	return r
+}
+
+// withTimeout returns ctx bounded by r.queryTimeout from now, unless ctx
+// already has a deadline (the caller's own takes precedence) or
+// r.queryTimeout is zero (disabled). The returned cancel func must always
+// be called, even when it's a no-op, so callers can defer it
+// unconditionally.
+func (r *AuditLogRepo) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.queryTimeout <= 0 {
+		return ctx, func() {}
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, r.queryTimeout)
+}
+
+// WithSequenceEnabled turns on per-tenant sequence number assignment on
+// Create. Assumes audit_logs has a nullable `sequence bigint` column and a
+// `tenant_sequences (tenant_id text primary key, next_value bigint not
+// null default 1)` table backing nextSequence.
+func (r *AuditLogRepo) WithSequenceEnabled(enabled bool) *AuditLogRepo {
+	r.sequenceEnabled = enabled
+	return r
+}
+
+// WithEncryptor turns on field-level encryption of the data column: insert
+// seals it under enc, and every read path opens it again before returning
+// to the caller. Not setting one (the default) stores data exactly as
+// before. Encrypting data makes it opaque to Postgres — see
+// config.EncryptionConfig's doc comment for what that breaks.
+func (r *AuditLogRepo) WithEncryptor(enc crypto.Encryptor) *AuditLogRepo {
+	r.encryptor = enc
+	return r
+}
+
+// encodeData marshals log.Data to JSON and, if an encryptor is
+// configured, seals it and re-wraps the ciphertext as a JSON string so it
+// still fits the jsonb column. A plaintext data value is always a JSON
+// object, so an encrypted one is unambiguous to decodeData on the way
+// back out.
+func (r *AuditLogRepo) encodeData(data map[string]any) ([]byte, error) {
+	plaintext, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: marshal data: %w", err)
+	}
+	if r.encryptor == nil {
+		return plaintext, nil
+	}
+	sealed, err := r.encryptor.Encrypt(plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: encrypt data: %w", err)
+	}
+	return json.Marshal(base64.StdEncoding.EncodeToString(sealed))
+}
+
+// decodeData reverses encodeData. raw holding a JSON object means it was
+// never encrypted (or encryption is disabled); raw holding a JSON string
+// means it's a base64-wrapped sealed blob that must be decrypted before
+// it can be unmarshaled into the caller's map.
+func (r *AuditLogRepo) decodeData(raw []byte) (map[string]any, error) {
+	var asString string
+	if err := json.Unmarshal(raw, &asString); err == nil {
+		if r.encryptor == nil {
+			return nil, fmt.Errorf("postgres: data column holds an encrypted blob but no encryptor is configured")
+		}
+		sealed, err := base64.StdEncoding.DecodeString(asString)
+		if err != nil {
+			return nil, fmt.Errorf("postgres: decode encrypted data: %w", err)
+		}
+		plaintext, err := r.encryptor.Decrypt(sealed)
+		if err != nil {
+			return nil, fmt.Errorf("postgres: decrypt data: %w", err)
+		}
+		raw = plaintext
+	}
+
+	var data map[string]any
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("postgres: unmarshal data: %w", err)
+	}
+	return data, nil
+}
+
+// Create inserts a new audit log record and returns it with server-assigned
+// fields (ID, CreatedAt, Sequence if enabled, and Hash/PrevHash) populated.
+// Assumes audit_logs.tags is a text[] column with a GIN index (e.g.
+// `CREATE INDEX ON audit_logs USING GIN (tags)`) so the @>/&& tag filters
+// in List stay index-backed as the table grows. The hash chain (see
+// chainHash) additionally assumes two non-nullable text columns:
+//
+//	ALTER TABLE audit_logs ADD COLUMN hash text NOT NULL DEFAULT '';
+//	ALTER TABLE audit_logs ADD COLUMN prev_hash text NOT NULL DEFAULT '';
+//
+// The hash chain computation (reading the tenant's latest hash, then
+// inserting with PrevHash set to it) runs inside a transaction serialized
+// by a per-tenant advisory lock (see tenantLockKey), so two concurrent
+// creates for the same tenant can't both read the same PrevHash and insert
+// two records claiming the same position in the chain.
+func (r *AuditLogRepo) Create(ctx context.Context, log domain.AuditLog) (domain.AuditLog, error) {
+	ctx, span := tracing.Start(ctx, "postgres.AuditLogRepo.Create", trace.WithAttributes(attribute.String("tenant_id", log.TenantID)))
+	defer span.End()
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		span.RecordError(err)
+		return domain.AuditLog{}, fmt.Errorf("postgres: create: begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", tenantLockKey(log.TenantID)); err != nil {
+		span.RecordError(err)
+		return domain.AuditLog{}, fmt.Errorf("postgres: create: chain lock: %w", err)
+	}
+
+	if r.sequenceEnabled {
+		seq, err := r.nextSequence(ctx, tx, log.TenantID)
+		if err != nil {
+			span.RecordError(err)
+			return domain.AuditLog{}, err
+		}
+		log.Sequence = seq
+	}
+
+	prevHash, err := r.latestHash(ctx, tx, log.TenantID)
+	if err != nil {
+		span.RecordError(err)
+		return domain.AuditLog{}, err
+	}
+	log.PrevHash = prevHash
+	log.Hash, err = chainHash(log)
+	if err != nil {
+		span.RecordError(err)
+		return domain.AuditLog{}, err
+	}
+
+	created, err := r.insert(ctx, tx, log)
+	if err != nil {
+		span.RecordError(err)
+		return domain.AuditLog{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		span.RecordError(err)
+		return domain.AuditLog{}, fmt.Errorf("postgres: create: commit: %w", err)
+	}
+	return created, nil
+}
+
+// idempotencyKeyConstraint is the name of the partial unique index backing
+// CreateWithIdempotencyKey. Assumes audit_logs has a nullable
+// `idempotency_key text` column and:
+//
+//	CREATE UNIQUE INDEX idempotency_key_unique_idx ON audit_logs (tenant_id, idempotency_key) WHERE idempotency_key IS NOT NULL;
+const idempotencyKeyConstraint = "idempotency_key_unique_idx"
+
+// CreateWithIdempotencyKey behaves like Create, except that if a record for
+// the same tenant and log.IdempotencyKey already exists (via
+// idempotencyKeyConstraint) and was created within the last window, that
+// record is returned unchanged instead of inserting a duplicate, with
+// created reporting false. A match older than window is treated as
+// expired: its key is freed and log is inserted as a genuine new record.
+// window <= 0 means matches never expire.
+func (r *AuditLogRepo) CreateWithIdempotencyKey(ctx context.Context, log domain.AuditLog, window time.Duration) (domain.AuditLog, bool, error) {
+	ctx, span := tracing.Start(ctx, "postgres.AuditLogRepo.CreateWithIdempotencyKey", trace.WithAttributes(attribute.String("tenant_id", log.TenantID)))
+	defer span.End()
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	created, err := r.Create(ctx, log)
+	if err == nil {
+		return created, true, nil
+	}
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != "23505" || pgErr.ConstraintName != idempotencyKeyConstraint {
+		span.RecordError(err)
+		return domain.AuditLog{}, false, err
+	}
+
+	existing, existingErr := r.byTenantAndIdempotencyKey(ctx, log.TenantID, log.IdempotencyKey)
+	if existingErr != nil {
+		span.RecordError(existingErr)
+		return domain.AuditLog{}, false, existingErr
+	}
+
+	if window <= 0 || time.Since(existing.CreatedAt) < window {
+		return existing, false, nil
+	}
+
+	// The matching record is older than window, so its key is no longer
+	// eligible to dedupe against: free it and retry the insert once. A
+	// fresh race on the same key between the UPDATE and the retry is left
+	// to surface as a second unique-violation rather than looping, since
+	// that would require the same caller to retry with the same key
+	// within the instant this takes.
+	const freeStaleKeyQ = `UPDATE audit_logs SET idempotency_key = NULL WHERE id = $1`
+	if _, err := r.pool.Exec(ctx, freeStaleKeyQ, existing.ID); err != nil {
+		span.RecordError(err)
+		return domain.AuditLog{}, false, fmt.Errorf("postgres: free stale idempotency key: %w", err)
+	}
+
+	created, err = r.Create(ctx, log)
+	if err != nil {
+		span.RecordError(err)
+		return domain.AuditLog{}, false, err
+	}
+	return created, true, nil
+}
+
+// byTenantAndIdempotencyKey returns the record matched by
+// idempotencyKeyConstraint for tenantID and key.
+func (r *AuditLogRepo) byTenantAndIdempotencyKey(ctx context.Context, tenantID, key string) (domain.AuditLog, error) {
+	const q = `
+		SELECT id, tenant_id, actor, event, resource, resource_id, data, meta, tags, sequence, auth_type, client_ip, created_at, deleted_at, hash, prev_hash, idempotency_key
+		FROM audit_logs
+		WHERE tenant_id = $1 AND idempotency_key = $2`
+	return r.scanAuditLog(r.pool.QueryRow(ctx, q, tenantID, key))
+}
+
+// queryRower is satisfied by both *pgxpool.Pool and pgx.Tx, letting insert
+// run either directly against the pool (Create) or inside an open
+// transaction (CreateIfAbsent).
+type queryRower interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// insert runs the INSERT underlying Create and CreateIfAbsent against q.
+// The caller is responsible for assigning log.Sequence beforehand if
+// sequencing is enabled.
+func (r *AuditLogRepo) insert(ctx context.Context, q queryRower, log domain.AuditLog) (domain.AuditLog, error) {
+	data, err := r.encodeData(log.Data)
+	if err != nil {
+		return domain.AuditLog{}, err
+	}
+	meta, err := json.Marshal(log.Meta)
+	if err != nil {
+		return domain.AuditLog{}, fmt.Errorf("postgres: marshal meta: %w", err)
+	}
+
+	// createdAt is left nil for live ingestion, letting the column default
+	// (now()) apply; the service layer only populates log.CreatedAt for
+	// backfill/import requests that survived the timestamp-skew check.
+	var createdAt *time.Time
+	if !log.CreatedAt.IsZero() {
+		createdAt = &log.CreatedAt
+	}
+
+	const insertQ = `
+		INSERT INTO audit_logs (tenant_id, actor, event, resource, resource_id, data, meta, tags, sequence, auth_type, client_ip, created_at, hash, prev_hash, idempotency_key)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, COALESCE($12, now()), $13, $14, $15)
+		RETURNING id, created_at`
+
+	var sequence *int64
+	if log.Sequence != 0 {
+		sequence = &log.Sequence
+	}
+
+	var idempotencyKey *string
+	if log.IdempotencyKey != "" {
+		idempotencyKey = &log.IdempotencyKey
+	}
+
+	var clientIP *string
+	if log.ClientIP != "" {
+		clientIP = &log.ClientIP
+	}
+
+	row := q.QueryRow(ctx, insertQ, log.TenantID, log.Actor, log.Event, log.Resource, log.ResourceID, data, meta, log.Tags, sequence, log.AuthType, clientIP, createdAt, log.Hash, log.PrevHash, idempotencyKey)
+	if err := row.Scan(&log.ID, &log.CreatedAt); err != nil {
+		err = fmt.Errorf("postgres: insert audit log: %w", err)
+		logging.FromContext(ctx).Error("insert audit log failed", "error", err)
+		return domain.AuditLog{}, err
+	}
+	return log, nil
+}
+
+// conditionalMatchFieldAllowlist restricts which columns CreateIfAbsent's
+// matchFields can name, since they are interpolated into the query as
+// column names rather than passed as pgx placeholders. Re-checked here
+// even though the service layer already validates it, matching
+// exprFieldAllowlist's defense-in-depth.
+var conditionalMatchFieldAllowlist = map[string]bool{
+	"actor":       true,
+	"event":       true,
+	"resource":    true,
+	"resource_id": true,
+}
+
+// matchFieldValue returns log's value for one of
+// conditionalMatchFieldAllowlist's fields.
+func matchFieldValue(log domain.AuditLog, field string) string {
+	switch field {
+	case "actor":
+		return log.Actor
+	case "event":
+		return log.Event
+	case "resource":
+		return log.Resource
+	case "resource_id":
+		return log.ResourceID
+	default:
+		return ""
+	}
+}
+
+// tenantLockKey derives a single advisory-lock key from tenantID alone,
+// used to serialize the hash chain's read-latest-then-insert sequence in
+// Create and CreateIfAbsent (see chainHash), separately from
+// conditionalLockKey's narrower match-key locking.
+func tenantLockKey(tenantID string) int64 {
+	h := sha256.Sum256([]byte("chain:" + tenantID))
+	return int64(binary.BigEndian.Uint64(h[:8]))
+}
+
+// latestHash returns the Hash of the most recently inserted record for
+// tenantID (by created_at, id, matching List's tie-break), or "" if the
+// tenant has no records yet.
+func (r *AuditLogRepo) latestHash(ctx context.Context, q queryRower, tenantID string) (string, error) {
+	const latestHashQ = `SELECT hash FROM audit_logs WHERE tenant_id = $1 ORDER BY created_at DESC, id DESC LIMIT 1`
+
+	var hash string
+	err := q.QueryRow(ctx, latestHashQ, tenantID).Scan(&hash)
+	if err == pgx.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("postgres: latest hash: %w", err)
+	}
+	return hash, nil
+}
+
+// chainHash computes log's tamper-evident hash: sha256 of its canonical
+// content fields together with log.PrevHash, so altering any historical
+// record's content, or the chain's links, changes this and every hash
+// after it (see VerifyChain). Deliberately excludes server-assigned
+// fields (ID, CreatedAt, Sequence) so it can be computed once before
+// insert rather than requiring a second round trip after.
+func chainHash(log domain.AuditLog) (string, error) {
+	data, err := json.Marshal(log.Data)
+	if err != nil {
+		return "", fmt.Errorf("postgres: chain hash: marshal data: %w", err)
+	}
+	meta, err := json.Marshal(log.Meta)
+	if err != nil {
+		return "", fmt.Errorf("postgres: chain hash: marshal meta: %w", err)
+	}
+	tags := append([]string{}, log.Tags...)
+	sort.Strings(tags)
+
+	h := sha256.New()
+	for _, part := range []string{log.TenantID, log.Actor, log.Event, log.Resource, log.ResourceID, string(data), string(meta), strings.Join(tags, ","), log.AuthType, log.PrevHash} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// conditionalLockKey derives a single advisory-lock key from log's tenant
+// and its values for matchFields, so CreateIfAbsent only serializes
+// callers racing on the exact same match key against each other.
+func conditionalLockKey(log domain.AuditLog, matchFields []string) int64 {
+	h := sha256.New()
+	h.Write([]byte(log.TenantID))
+	for _, field := range matchFields {
+		h.Write([]byte("\x00" + field + "=" + matchFieldValue(log, field)))
+	}
+	sum := h.Sum(nil)
+	return int64(binary.BigEndian.Uint64(sum[:8]))
+}
+
+// CreateIfAbsent inserts log unless a record for the same tenant already
+// matches every field named in matchFields and was created within the
+// last window, in which case that record is returned unchanged instead.
+// The lookup and insert run inside one transaction, serialized by a
+// postgres advisory lock keyed on the match values (see
+// conditionalLockKey), so two concurrent callers racing on the same match
+// key can't both observe "absent" and insert a duplicate. It returns
+// (record, true, nil) when log was inserted, or (record, false, nil) when
+// an existing match was returned instead.
+func (r *AuditLogRepo) CreateIfAbsent(ctx context.Context, log domain.AuditLog, matchFields []string, window time.Duration) (domain.AuditLog, bool, error) {
+	ctx, span := tracing.Start(ctx, "postgres.AuditLogRepo.CreateIfAbsent", trace.WithAttributes(attribute.String("tenant_id", log.TenantID)))
+	defer span.End()
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	for _, field := range matchFields {
+		if !conditionalMatchFieldAllowlist[field] {
+			return domain.AuditLog{}, false, fmt.Errorf("postgres: create if absent: unsupported match field %q", field)
+		}
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return domain.AuditLog{}, false, fmt.Errorf("postgres: create if absent: begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", conditionalLockKey(log, matchFields)); err != nil {
+		return domain.AuditLog{}, false, fmt.Errorf("postgres: create if absent: lock: %w", err)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(`SELECT id, tenant_id, actor, event, resource, resource_id, data, meta, tags, COALESCE(sequence, 0), auth_type, client_ip, created_at, deleted_at, hash, prev_hash, idempotency_key
+		FROM audit_logs WHERE deleted_at IS NULL AND tenant_id = $1 AND created_at >= $2`)
+	args := []any{log.TenantID, time.Now().Add(-window)}
+	for _, field := range matchFields {
+		args = append(args, matchFieldValue(log, field))
+		fmt.Fprintf(&sb, " AND %s = $%d", field, len(args))
+	}
+	sb.WriteString(" ORDER BY created_at DESC LIMIT 1")
+
+	existing, err := r.scanAuditLog(tx.QueryRow(ctx, sb.String(), args...))
+	if err == nil {
+		if err := tx.Commit(ctx); err != nil {
+			return domain.AuditLog{}, false, fmt.Errorf("postgres: create if absent: commit: %w", err)
+		}
+		return existing, false, nil
+	}
+	if err != pgx.ErrNoRows {
+		return domain.AuditLog{}, false, fmt.Errorf("postgres: create if absent: match lookup: %w", err)
+	}
+
+	if r.sequenceEnabled {
+		seq, err := r.nextSequence(ctx, tx, log.TenantID)
+		if err != nil {
+			return domain.AuditLog{}, false, err
+		}
+		log.Sequence = seq
+	}
+
+	// Chain hashing is serialized on the tenant as a whole (see
+	// tenantLockKey), in addition to the narrower match-key lock already
+	// held above, since two CreateIfAbsent calls with different match
+	// keys for the same tenant can still race on the chain's tail.
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", tenantLockKey(log.TenantID)); err != nil {
+		return domain.AuditLog{}, false, fmt.Errorf("postgres: create if absent: chain lock: %w", err)
+	}
+	prevHash, err := r.latestHash(ctx, tx, log.TenantID)
+	if err != nil {
+		return domain.AuditLog{}, false, err
+	}
+	log.PrevHash = prevHash
+	log.Hash, err = chainHash(log)
+	if err != nil {
+		return domain.AuditLog{}, false, err
+	}
+
+	created, err := r.insert(ctx, tx, log)
+	if err != nil {
+		return domain.AuditLog{}, false, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return domain.AuditLog{}, false, fmt.Errorf("postgres: create if absent: commit: %w", err)
+	}
+	return created, true, nil
+}
+
+// nextSequence atomically assigns and returns the next sequence number for
+// tenantID. The INSERT .. ON CONFLICT DO UPDATE is a single statement, so
+// Postgres's row-level locking serializes concurrent callers for the same
+// tenant without the repo needing an explicit transaction.
+func (r *AuditLogRepo) nextSequence(ctx context.Context, q queryRower, tenantID string) (int64, error) {
+	const nextSeqQ = `
+		INSERT INTO tenant_sequences (tenant_id, next_value)
+		VALUES ($1, 2)
+		ON CONFLICT (tenant_id) DO UPDATE SET next_value = tenant_sequences.next_value + 1
+		RETURNING next_value - 1`
+
+	var seq int64
+	if err := q.QueryRow(ctx, nextSeqQ, tenantID).Scan(&seq); err != nil {
+		return 0, fmt.Errorf("postgres: next sequence for tenant %q: %w", tenantID, err)
+	}
+	return seq, nil
+}
+
+// searchTSVectorExpr is the expression filter.Search's full-text condition
+// matches against. Kept in one place so the GIN index assumed below stays
+// in sync with the query.
+const searchTSVectorExpr = `to_tsvector('english', resource || ' ' || event || ' ' || coalesce(data::text, '') || ' ' || coalesce(meta::text, ''))`
+
+// minFullTextSearchLen is the shortest filter.Search term that gets a
+// full-text plainto_tsquery match; shorter terms fall back to ILIKE, since
+// tsquery ranks very short terms poorly (e.g. stemming/stopwords can drop
+// them entirely).
+const minFullTextSearchLen = 3
+
+// List returns a page of audit logs matching the filter, ordered newest
+// first. filter.Limit and filter.Offset are assumed to already be resolved
+// by the service layer. filter.Search assumes a GIN index backing
+// searchTSVectorExpr so the full-text path stays index-backed as the
+// table grows:
+//
+//	CREATE INDEX ON audit_logs USING GIN ((to_tsvector('english', resource || ' ' || event || ' ' || coalesce(data::text, '') || ' ' || coalesce(meta::text, ''))));
+func (r *AuditLogRepo) List(ctx context.Context, filter domain.Filter) ([]domain.AuditLog, *int64, error) {
+	ctx, span := tracing.Start(ctx, "postgres.AuditLogRepo.List", trace.WithAttributes(attribute.String("tenant_id", filter.TenantID)))
+	defer span.End()
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	query, args, err := buildListQuery(filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		span.RecordError(err)
+		return nil, nil, fmt.Errorf("postgres: list audit logs: %w", err)
+	}
+	defer rows.Close()
+
+	var logs []domain.AuditLog
+	var total *int64
+	for rows.Next() {
+		if filter.WithCount {
+			log, count, err := r.scanAuditLogWithCount(rows)
+			if err != nil {
+				span.RecordError(err)
+				return nil, nil, err
+			}
+			logs = append(logs, log)
+			total = &count
+			continue
+		}
+		log, err := r.scanAuditLog(rows)
+		if err != nil {
+			span.RecordError(err)
+			return nil, nil, err
+		}
+		logs = append(logs, log)
+	}
+	if err := rows.Err(); err != nil {
+		span.RecordError(err)
+		return nil, nil, fmt.Errorf("postgres: list audit logs: %w", err)
+	}
+	if filter.WithCount && total == nil {
+		// COUNT(*) OVER() emits no rows at all when the table/filter has
+		// zero matches, unlike a separate "SELECT COUNT(*)" query — so an
+		// empty result still means a total of 0, not "not computed".
+		zero := int64(0)
+		total = &zero
+	}
+	span.SetAttributes(attribute.Int("result_count", len(logs)))
+	return logs, total, nil
+}
+
+// ExplainList returns the query plan Postgres would use for List(ctx,
+// filter), via EXPLAIN. It exists for ops debugging index usage and for
+// the index-scan assertion in audit_log_repo_explain_test.go.
+func (r *AuditLogRepo) ExplainList(ctx context.Context, filter domain.Filter) (string, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	query, args, err := buildListQuery(filter)
+	if err != nil {
+		return "", err
+	}
+
+	rows, err := r.pool.Query(ctx, "EXPLAIN "+query, args...)
+	if err != nil {
+		return "", fmt.Errorf("postgres: explain list audit logs: %w", err)
+	}
+	defer rows.Close()
+
+	var lines []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return "", fmt.Errorf("postgres: explain list audit logs: %w", err)
+		}
+		lines = append(lines, line)
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("postgres: explain list audit logs: %w", err)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// buildFilterWhere builds the WHERE conditions shared by every query that
+// filters audit_logs by a domain.Filter: List/ExplainList's row-returning
+// query and DeleteByFilter's DELETE. It excludes pagination (Cursor,
+// Limit, Offset) and sorting, which only apply to a row-returning query,
+// so both callers filter identically and a change here can't drift
+// between them. The returned string always starts with "1=1", so
+// callers can append further " AND ..." conditions of their own.
+func buildFilterWhere(filter domain.Filter) (string, []any, error) {
+	var sb strings.Builder
+	sb.WriteString("1=1")
+
+	var args []any
+	addCond := func(cond string, val any) {
+		args = append(args, val)
+		fmt.Fprintf(&sb, " AND %s $%d", cond, len(args))
+	}
+	// addCondIn emits "column = ANY($n)" for multi-value filters (see
+	// filter.Events/Resources), letting callers match several values in
+	// one query instead of OR-ing several addCond calls together.
+	addCondIn := func(column string, values []string) {
+		args = append(args, values)
+		fmt.Fprintf(&sb, " AND %s = ANY($%d)", column, len(args))
+	}
+
+	if !filter.IncludeDeleted {
+		sb.WriteString(" AND deleted_at IS NULL")
+	}
+	if filter.TenantID != "" {
+		addCond("tenant_id =", filter.TenantID)
+	}
+	if filter.Actor != "" {
+		addCond("actor =", filter.Actor)
+	}
+	if filter.AuthType != "" {
+		addCond("auth_type =", filter.AuthType)
+	}
+	if len(filter.Events) > 0 {
+		addCondIn("event", filter.Events)
+	} else if filter.Event != "" {
+		addCond("event =", filter.Event)
+	}
+	if len(filter.Resources) > 0 {
+		addCondIn("resource", filter.Resources)
+	} else if filter.Resource != "" {
+		addCond("resource =", filter.Resource)
+	}
+	if filter.ResourceID != "" {
+		addCond("resource_id =", filter.ResourceID)
+	}
+	if !filter.From.IsZero() {
+		addCond("created_at >=", filter.From)
+	}
+	if !filter.To.IsZero() {
+		addCond("created_at <=", filter.To)
+	}
+	if filter.ContainsPII != nil {
+		addCond("(meta->>'contains_pii')::boolean IS NOT DISTINCT FROM", *filter.ContainsPII)
+	}
+	if filter.BusinessHours != nil {
+		addCond("(meta->>'business_hours')::boolean IS NOT DISTINCT FROM", *filter.BusinessHours)
+	}
+	if len(filter.Tags) > 0 {
+		if filter.TagsMatchAll {
+			addCond("tags @>", filter.Tags)
+		} else {
+			addCond("tags &&", filter.Tags)
+		}
+	}
+	if filter.Search != "" {
+		args = append(args, filter.Search)
+		n := len(args)
+		if len(filter.Search) >= minFullTextSearchLen {
+			fmt.Fprintf(&sb, " AND %s @@ plainto_tsquery('english', $%d)", searchTSVectorExpr, n)
+		} else {
+			fmt.Fprintf(&sb, " AND (resource ILIKE '%%' || $%d || '%%' OR event ILIKE '%%' || $%d || '%%' OR data::text ILIKE '%%' || $%d || '%%' OR meta::text ILIKE '%%' || $%d || '%%')", n, n, n, n)
+		}
+	}
+
+	for _, cond := range filter.Expr {
+		sqlOp, ok := exprOpSQL[cond.Op]
+		if !ok || !exprFieldAllowlist[cond.Field] {
+			return "", nil, fmt.Errorf("postgres: invalid filter condition %+v", cond)
+		}
+		addCond(cond.Field+" "+sqlOp, cond.Value)
+	}
+
+	return sb.String(), args, nil
+}
+
+// buildListQuery builds the SQL and args List (and ExplainList) would
+// run for filter. The leading WHERE condition, tenant_id, is also the
+// leading column of audit_logs_tenant_id_created_at_idx and
+// audit_logs_tenant_event_created_at_idx (see migrations/), so a
+// tenant-scoped query can use either as an index scan; the order the
+// remaining conditions are appended in doesn't affect the planner's
+// choice, only which columns end up with a condition at all.
+func buildListQuery(filter domain.Filter) (string, []any, error) {
+	dataColumn, metaColumn := "data", "meta"
+	if filter.SkipData {
+		dataColumn = "NULL::jsonb"
+	}
+	if filter.SkipMeta {
+		metaColumn = "NULL::jsonb"
+	}
+	countColumn := ""
+	if filter.WithCount {
+		// COUNT(*) OVER() computes the filter's total match count in the
+		// same pass as the page of rows, instead of a second "SELECT
+		// COUNT(*) ... WHERE ..." round trip with its own WHERE clause to
+		// keep in sync.
+		countColumn = ", COUNT(*) OVER()"
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "SELECT id, tenant_id, actor, event, resource, resource_id, %s, %s, tags, COALESCE(sequence, 0), auth_type, client_ip, created_at, deleted_at, hash, prev_hash, idempotency_key%s FROM audit_logs WHERE ", dataColumn, metaColumn, countColumn)
+
+	where, args, err := buildFilterWhere(filter)
+	if err != nil {
+		return "", nil, err
+	}
+	sb.WriteString(where)
+
+	if filter.Cursor != nil {
+		args = append(args, filter.Cursor.CreatedAt, filter.Cursor.ID)
+		fmt.Fprintf(&sb, " AND (created_at, id) < ($%d, $%d)", len(args)-1, len(args))
+	}
+
+	sortColumn := filter.SortBy
+	if sortColumn == "" {
+		sortColumn = "created_at"
+	}
+	sortOrder := filter.SortOrder
+	if sortOrder == "" {
+		sortOrder = "desc"
+	}
+	if !sortColumnAllowlist[sortColumn] {
+		return "", nil, fmt.Errorf("postgres: list audit logs: unsupported sort_by %q", sortColumn)
+	}
+	if sortOrder != "asc" && sortOrder != "desc" {
+		return "", nil, fmt.Errorf("postgres: list audit logs: unsupported sort_order %q", sortOrder)
+	}
+
+	// Sorting by the chosen column alone is non-deterministic when
+	// several rows share a value (common with batch inserts, or any
+	// sort_by other than created_at): id in the same direction breaks
+	// ties so pages stay stable instead of skipping or duplicating rows.
+	// filter.Cursor's keyset condition above assumes the default
+	// created_at/desc ordering; the service layer rejects any other
+	// combination before it reaches here.
+	fmt.Fprintf(&sb, " ORDER BY %s %s, id %s", sortColumn, strings.ToUpper(sortOrder), strings.ToUpper(sortOrder))
+	args = append(args, filter.Limit)
+	fmt.Fprintf(&sb, " LIMIT $%d", len(args))
+	if filter.Cursor == nil {
+		args = append(args, filter.Offset)
+		fmt.Fprintf(&sb, " OFFSET $%d", len(args))
+	}
+
+	return sb.String(), args, nil
+}
+
+// TimeSeries returns event counts bucketed by interval for the given
+// filter. Buckets with zero events are omitted; the service layer fills
+// the gaps. interval is interpolated directly into the query, so callers
+// MUST validate it against a fixed allowlist (see service.validIntervals)
+// before calling this — it is not parameterizable via pgx placeholders.
+func (r *AuditLogRepo) TimeSeries(ctx context.Context, filter domain.Filter, interval string) ([]domain.TimeBucket, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `SELECT date_trunc('%s', created_at) AS bucket, count(*) FROM audit_logs WHERE 1=1`, interval)
+
+	var args []any
+	addCond := func(cond string, val any) {
+		args = append(args, val)
+		fmt.Fprintf(&sb, " AND %s $%d", cond, len(args))
+	}
+
+	if filter.TenantID != "" {
+		addCond("tenant_id =", filter.TenantID)
+	}
+	if filter.Actor != "" {
+		addCond("actor =", filter.Actor)
+	}
+	if filter.AuthType != "" {
+		addCond("auth_type =", filter.AuthType)
+	}
+	if filter.Event != "" {
+		addCond("event =", filter.Event)
+	}
+	if filter.Resource != "" {
+		addCond("resource =", filter.Resource)
+	}
+	if !filter.From.IsZero() {
+		addCond("created_at >=", filter.From)
+	}
+	if !filter.To.IsZero() {
+		addCond("created_at <=", filter.To)
+	}
+
+	sb.WriteString(" GROUP BY bucket ORDER BY bucket")
+
+	rows, err := r.pool.Query(ctx, sb.String(), args...)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: time series: %w", err)
+	}
+	defer rows.Close()
+
+	var buckets []domain.TimeBucket
+	for rows.Next() {
+		var b domain.TimeBucket
+		if err := rows.Scan(&b.BucketStart, &b.Count); err != nil {
+			return nil, fmt.Errorf("postgres: scan time bucket: %w", err)
+		}
+		buckets = append(buckets, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgres: time series: %w", err)
+	}
+	return buckets, nil
+}
+
+// DeleteByTenant permanently removes every audit log belonging to
+// tenantID and returns the number of rows deleted. It is used for tenant
+// offboarding; there is no undo.
+func (r *AuditLogRepo) DeleteByTenant(ctx context.Context, tenantID string) (int64, error) {
+	ctx, span := tracing.Start(ctx, "postgres.AuditLogRepo.DeleteByTenant", trace.WithAttributes(attribute.String("tenant_id", tenantID)))
+	defer span.End()
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	tag, err := r.pool.Exec(ctx, `DELETE FROM audit_logs WHERE tenant_id = $1`, tenantID)
+	if err != nil {
+		span.RecordError(err)
+		return 0, fmt.Errorf("postgres: delete by tenant: %w", err)
+	}
+	deleted := tag.RowsAffected()
+	span.SetAttributes(attribute.Int64("result_count", deleted))
+	return deleted, nil
+}
+
+// DeleteByFilter permanently removes every record matching filter and
+// returns the number of rows deleted. It shares its WHERE conditions with
+// List (see buildFilterWhere), so "what would this delete" can always be
+// previewed first with a List/count=true call using the same filter. The
+// service layer is responsible for rejecting an unbounded filter before
+// this is ever called; this method itself runs whatever filter it's
+// given, without a LIMIT or batching like DeleteOlderThan uses.
+func (r *AuditLogRepo) DeleteByFilter(ctx context.Context, filter domain.Filter) (int64, error) {
+	ctx, span := tracing.Start(ctx, "postgres.AuditLogRepo.DeleteByFilter", trace.WithAttributes(attribute.String("tenant_id", filter.TenantID)))
+	defer span.End()
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	where, args, err := buildFilterWhere(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	tag, err := r.pool.Exec(ctx, "DELETE FROM audit_logs WHERE "+where, args...)
+	if err != nil {
+		span.RecordError(err)
+		return 0, fmt.Errorf("postgres: delete by filter: %w", err)
+	}
+	deleted := tag.RowsAffected()
+	span.SetAttributes(attribute.Int64("result_count", deleted))
+	return deleted, nil
+}
+
+// retentionDeleteBatchSize caps how many rows a single DELETE in
+// DeleteOlderThan removes, so purging a large backlog doesn't hold a
+// table-wide lock for the whole operation.
+const retentionDeleteBatchSize = 1000
+
+// DeleteOlderThan permanently removes every record created before cutoff,
+// scoped to tenantID if set or across all tenants if empty (matching
+// domain.Filter's "empty means no constraint" convention), and returns
+// the total number of rows deleted. Deletes run in batches of
+// retentionDeleteBatchSize rather than a single statement, so a large
+// backlog is purged without holding a lock on the whole table at once.
+func (r *AuditLogRepo) DeleteOlderThan(ctx context.Context, tenantID string, cutoff time.Time) (int64, error) {
+	ctx, span := tracing.Start(ctx, "postgres.AuditLogRepo.DeleteOlderThan", trace.WithAttributes(attribute.String("tenant_id", tenantID)))
+	defer span.End()
+
+	var sb strings.Builder
+	sb.WriteString(`DELETE FROM audit_logs WHERE id IN (SELECT id FROM audit_logs WHERE created_at < $1`)
+	args := []any{cutoff}
+	if tenantID != "" {
+		args = append(args, tenantID)
+		fmt.Fprintf(&sb, " AND tenant_id = $%d", len(args))
+	}
+	args = append(args, retentionDeleteBatchSize)
+	fmt.Fprintf(&sb, " LIMIT $%d)", len(args))
+	deleteQ := sb.String()
+
+	var total int64
+	for {
+		// The timeout is applied per batch, not once for the whole loop,
+		// so purging a large backlog isn't bounded by a single deadline
+		// sized for one DELETE.
+		batchCtx, cancel := r.withTimeout(ctx)
+		tag, err := r.pool.Exec(batchCtx, deleteQ, args...)
+		cancel()
+		if err != nil {
+			span.RecordError(err)
+			return total, fmt.Errorf("postgres: delete older than: %w", err)
+		}
+		n := tag.RowsAffected()
+		total += n
+		if n < retentionDeleteBatchSize {
+			break
+		}
+	}
+	span.SetAttributes(attribute.Int64("result_count", total))
+	return total, nil
+}
+
+// ListOlderThan returns up to limit records created before cutoff,
+// ordered by created_at, scoped to tenantID if set or across all tenants
+// if empty (matching domain.Filter's "empty means no constraint"
+// convention). Used by RetentionWorker to fetch a batch to archive (see
+// archive.Archiver) before removing it with DeleteByIDs, rather than
+// deleting it outright via DeleteOlderThan.
+func (r *AuditLogRepo) ListOlderThan(ctx context.Context, tenantID string, cutoff time.Time, limit int) ([]domain.AuditLog, error) {
+	ctx, span := tracing.Start(ctx, "postgres.AuditLogRepo.ListOlderThan", trace.WithAttributes(attribute.String("tenant_id", tenantID)))
+	defer span.End()
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var sb strings.Builder
+	sb.WriteString(`SELECT id, tenant_id, actor, event, resource, resource_id, data, meta, tags, sequence, auth_type, client_ip, created_at, deleted_at, hash, prev_hash, idempotency_key FROM audit_logs WHERE created_at < $1`)
+	args := []any{cutoff}
+	if tenantID != "" {
+		args = append(args, tenantID)
+		fmt.Fprintf(&sb, " AND tenant_id = $%d", len(args))
+	}
+	args = append(args, limit)
+	fmt.Fprintf(&sb, " ORDER BY created_at LIMIT $%d", len(args))
+
+	rows, err := r.pool.Query(ctx, sb.String(), args...)
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("postgres: list older than: %w", err)
+	}
+	defer rows.Close()
+
+	var logs []domain.AuditLog
+	for rows.Next() {
+		log, err := r.scanAuditLog(rows)
+		if err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+		logs = append(logs, log)
+	}
+	if err := rows.Err(); err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("postgres: list older than: %w", err)
+	}
+	return logs, nil
+}
+
+// DeleteByIDs permanently removes exactly the given records and returns
+// the number of rows deleted. Used by RetentionWorker to remove a batch
+// once ListOlderThan's rows have been durably archived elsewhere, rather
+// than re-deriving the batch via DeleteOlderThan and risking it not
+// matching exactly what was archived.
+func (r *AuditLogRepo) DeleteByIDs(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	ctx, span := tracing.Start(ctx, "postgres.AuditLogRepo.DeleteByIDs")
+	defer span.End()
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	tag, err := r.pool.Exec(ctx, `DELETE FROM audit_logs WHERE id = ANY($1)`, ids)
+	if err != nil {
+		span.RecordError(err)
+		return 0, fmt.Errorf("postgres: delete by ids: %w", err)
+	}
+	deleted := tag.RowsAffected()
+	span.SetAttributes(attribute.Int64("result_count", deleted))
+	return deleted, nil
+}
+
+// SoftDelete sets deleted_at = now() on the record, without removing the
+// row, so it stops appearing in List/GetByID (unless IncludeDeleted is
+// set) while remaining recoverable and intact for tamper-evidence
+// purposes. Returns domain.ErrNotFound if no matching, not-already-deleted
+// record exists. Assumes audit_logs has a nullable `deleted_at
+// timestamptz` column:
+//
+//	ALTER TABLE audit_logs ADD COLUMN deleted_at timestamptz;
+//	CREATE INDEX ON audit_logs (tenant_id) WHERE deleted_at IS NULL;
+func (r *AuditLogRepo) SoftDelete(ctx context.Context, tenantID, id string) error {
+	ctx, span := tracing.Start(ctx, "postgres.AuditLogRepo.SoftDelete", trace.WithAttributes(attribute.String("tenant_id", tenantID)))
+	defer span.End()
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	tag, err := r.pool.Exec(ctx, `UPDATE audit_logs SET deleted_at = now() WHERE tenant_id = $1 AND id = $2 AND deleted_at IS NULL`, tenantID, id)
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("postgres: soft delete: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// HardDelete permanently removes a single record, bypassing the soft
+// delete. It is meant to be called only from a path gated by an
+// operator-configured auth.RoutePolicy (see httpapi.Handler.hardDeleteAuditLog),
+// since unlike SoftDelete there is no undo. Returns domain.ErrNotFound if
+// no matching record exists.
+func (r *AuditLogRepo) HardDelete(ctx context.Context, tenantID, id string) error {
+	ctx, span := tracing.Start(ctx, "postgres.AuditLogRepo.HardDelete", trace.WithAttributes(attribute.String("tenant_id", tenantID)))
+	defer span.End()
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	tag, err := r.pool.Exec(ctx, `DELETE FROM audit_logs WHERE tenant_id = $1 AND id = $2`, tenantID, id)
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("postgres: hard delete: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// VerifyChain walks tenantID's hash chain in insertion order (oldest
+// first) and reports whether it is intact: for each record it checks that
+// PrevHash matches the preceding record's Hash, then recomputes the
+// record's own Hash from its stored fields (see chainHash) and compares
+// it against what's stored. It stops and reports the first broken link,
+// since every hash after a tampered record will also fail to verify.
+// Soft-deleted records are included, since the row (and its place in the
+// chain) still exists; only a HardDelete breaks the chain.
+func (r *AuditLogRepo) VerifyChain(ctx context.Context, tenantID string) (domain.ChainVerification, error) {
+	ctx, span := tracing.Start(ctx, "postgres.AuditLogRepo.VerifyChain", trace.WithAttributes(attribute.String("tenant_id", tenantID)))
+	defer span.End()
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	const q = `
+		SELECT id, tenant_id, actor, event, resource, resource_id, data, meta, tags, COALESCE(sequence, 0), auth_type, client_ip, created_at, deleted_at, hash, prev_hash, idempotency_key
+		FROM audit_logs WHERE tenant_id = $1 ORDER BY created_at ASC, id ASC`
+
+	rows, err := r.pool.Query(ctx, q, tenantID)
+	if err != nil {
+		span.RecordError(err)
+		return domain.ChainVerification{}, fmt.Errorf("postgres: verify chain: %w", err)
+	}
+	defer rows.Close()
+
+	result := domain.ChainVerification{Valid: true}
+	wantPrev := ""
+	for rows.Next() {
+		log, err := r.scanAuditLog(rows)
+		if err != nil {
+			span.RecordError(err)
+			return domain.ChainVerification{}, err
+		}
+		result.Checked++
+
+		if log.PrevHash != wantPrev {
+			result.Valid = false
+			result.BrokenAt = &log.ID
+			result.Reason = "prev_hash does not match the preceding record's hash"
+			break
+		}
+		want, err := chainHash(log)
+		if err != nil {
+			return domain.ChainVerification{}, err
+		}
+		if log.Hash != want {
+			result.Valid = false
+			result.BrokenAt = &log.ID
+			result.Reason = "hash does not match the record's content"
+			break
+		}
+		wantPrev = log.Hash
+	}
+	if err := rows.Err(); err != nil {
+		span.RecordError(err)
+		return domain.ChainVerification{}, fmt.Errorf("postgres: verify chain: %w", err)
+	}
+	if !result.Valid {
+		span.SetAttributes(attribute.Bool("chain_valid", false))
+	}
+	return result, nil
+}
+
+// LatestPerResource returns the single most recent audit log for each
+// distinct resource matching filter, using DISTINCT ON (resource) so the
+// dedup happens in the database rather than by pulling every event.
+// filter.Limit/Offset are ignored; this always returns one row per
+// resource.
+func (r *AuditLogRepo) LatestPerResource(ctx context.Context, filter domain.Filter) ([]domain.AuditLog, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var sb strings.Builder
+	sb.WriteString(`SELECT DISTINCT ON (resource) id, tenant_id, actor, event, resource, resource_id, data, meta, tags, COALESCE(sequence, 0), created_at FROM audit_logs WHERE 1=1`)
+
+	var args []any
+	addCond := func(cond string, val any) {
+		args = append(args, val)
+		fmt.Fprintf(&sb, " AND %s $%d", cond, len(args))
+	}
+
+	if filter.TenantID != "" {
+		addCond("tenant_id =", filter.TenantID)
+	}
+	if filter.Actor != "" {
+		addCond("actor =", filter.Actor)
+	}
+	if filter.AuthType != "" {
+		addCond("auth_type =", filter.AuthType)
+	}
+	if filter.Event != "" {
+		addCond("event =", filter.Event)
+	}
+	if filter.ResourceID != "" {
+		addCond("resource_id =", filter.ResourceID)
+	}
+	if !filter.From.IsZero() {
+		addCond("created_at >=", filter.From)
+	}
+	if !filter.To.IsZero() {
+		addCond("created_at <=", filter.To)
+	}
+
+	sb.WriteString(" ORDER BY resource, created_at DESC")
+
+	rows, err := r.pool.Query(ctx, sb.String(), args...)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: latest per resource: %w", err)
+	}
+	defer rows.Close()
+
+	var logs []domain.AuditLog
+	for rows.Next() {
+		log, err := r.scanAuditLog(rows)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, log)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgres: latest per resource: %w", err)
+	}
+	return logs, nil
+}
+
+// facetFieldAllowlist restricts which columns can be used in Facets,
+// since the field name is interpolated into the query (it can't be a
+// pgx placeholder because it names a column, not a value).
+var facetFieldAllowlist = map[string]bool{
+	"tenant_id":   true,
+	"actor":       true,
+	"event":       true,
+	"resource":    true,
+	"resource_id": true,
+}
+
+// Facets returns the topN most frequent values of field among audit logs
+// matching filter, along with the total number of distinct values. field
+// MUST be validated against facetFieldAllowlist before calling this.
+func (r *AuditLogRepo) Facets(ctx context.Context, filter domain.Filter, field string, topN int) (domain.FacetResult, error) {
+	if !facetFieldAllowlist[field] {
+		return domain.FacetResult{}, fmt.Errorf("postgres: facets: invalid field %q", field)
+	}
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var whereSB strings.Builder
+	whereSB.WriteString(" WHERE 1=1")
+	var args []any
+	addCond := func(cond string, val any) {
+		args = append(args, val)
+		fmt.Fprintf(&whereSB, " AND %s $%d", cond, len(args))
+	}
+
+	if filter.TenantID != "" {
+		addCond("tenant_id =", filter.TenantID)
+	}
+	if filter.Actor != "" {
+		addCond("actor =", filter.Actor)
+	}
+	if filter.AuthType != "" {
+		addCond("auth_type =", filter.AuthType)
+	}
+	if filter.Event != "" {
+		addCond("event =", filter.Event)
+	}
+	if filter.Resource != "" {
+		addCond("resource =", filter.Resource)
+	}
+	if !filter.From.IsZero() {
+		addCond("created_at >=", filter.From)
+	}
+	if !filter.To.IsZero() {
+		addCond("created_at <=", filter.To)
+	}
+	where := whereSB.String()
+
+	var total int64
+	countQ := fmt.Sprintf(`SELECT count(DISTINCT %s) FROM audit_logs%s`, field, where)
+	if err := r.pool.QueryRow(ctx, countQ, args...).Scan(&total); err != nil {
+		return domain.FacetResult{}, fmt.Errorf("postgres: facets: count distinct: %w", err)
+	}
+
+	topArgs := append(append([]any{}, args...), topN)
+	topQ := fmt.Sprintf(`SELECT %s, count(*) FROM audit_logs%s GROUP BY %s ORDER BY count(*) DESC LIMIT $%d`, field, where, field, len(topArgs))
+	rows, err := r.pool.Query(ctx, topQ, topArgs...)
+	if err != nil {
+		return domain.FacetResult{}, fmt.Errorf("postgres: facets: top values: %w", err)
+	}
+	defer rows.Close()
+
+	var values []domain.FacetValue
+	for rows.Next() {
+		var v domain.FacetValue
+		if err := rows.Scan(&v.Value, &v.Count); err != nil {
+			return domain.FacetResult{}, fmt.Errorf("postgres: facets: scan value: %w", err)
+		}
+		values = append(values, v)
+	}
+	if err := rows.Err(); err != nil {
+		return domain.FacetResult{}, fmt.Errorf("postgres: facets: %w", err)
+	}
+	return domain.FacetResult{Values: values, TotalDistinct: total}, nil
+}
+
+// EventCounts returns a count of matching audit logs grouped by event
+// name, for tenant-level stats aggregation (see service.GetStats).
+func (r *AuditLogRepo) EventCounts(ctx context.Context, filter domain.Filter) (map[string]int64, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var sb strings.Builder
+	sb.WriteString(`SELECT event, count(*) FROM audit_logs WHERE 1=1`)
+
+	var args []any
+	addCond := func(cond string, val any) {
+		args = append(args, val)
+		fmt.Fprintf(&sb, " AND %s $%d", cond, len(args))
+	}
+
+	if filter.TenantID != "" {
+		addCond("tenant_id =", filter.TenantID)
+	}
+	if filter.Actor != "" {
+		addCond("actor =", filter.Actor)
+	}
+	if filter.AuthType != "" {
+		addCond("auth_type =", filter.AuthType)
+	}
+	if filter.Resource != "" {
+		addCond("resource =", filter.Resource)
+	}
+	if !filter.From.IsZero() {
+		addCond("created_at >=", filter.From)
+	}
+	if !filter.To.IsZero() {
+		addCond("created_at <=", filter.To)
+	}
+
+	sb.WriteString(" GROUP BY event")
+
+	rows, err := r.pool.Query(ctx, sb.String(), args...)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: event counts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int64)
+	for rows.Next() {
+		var event string
+		var count int64
+		if err := rows.Scan(&event, &count); err != nil {
+			return nil, fmt.Errorf("postgres: scan event count: %w", err)
+		}
+		counts[event] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgres: event counts: %w", err)
+	}
+	return counts, nil
+}
+
+// ResourceCounts returns a count of matching audit logs grouped by
+// resource, for tenant-level stats aggregation (see service.GetStats).
+func (r *AuditLogRepo) ResourceCounts(ctx context.Context, filter domain.Filter) (map[string]int64, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var sb strings.Builder
+	sb.WriteString(`SELECT resource, count(*) FROM audit_logs WHERE 1=1`)
+
+	var args []any
+	addCond := func(cond string, val any) {
+		args = append(args, val)
+		fmt.Fprintf(&sb, " AND %s $%d", cond, len(args))
+	}
+
+	if filter.TenantID != "" {
+		addCond("tenant_id =", filter.TenantID)
+	}
+	if filter.Actor != "" {
+		addCond("actor =", filter.Actor)
+	}
+	if filter.AuthType != "" {
+		addCond("auth_type =", filter.AuthType)
+	}
+	if filter.Event != "" {
+		addCond("event =", filter.Event)
+	}
+	if !filter.From.IsZero() {
+		addCond("created_at >=", filter.From)
+	}
+	if !filter.To.IsZero() {
+		addCond("created_at <=", filter.To)
+	}
+
+	sb.WriteString(" GROUP BY resource")
+
+	rows, err := r.pool.Query(ctx, sb.String(), args...)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: resource counts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int64)
+	for rows.Next() {
+		var resource string
+		var count int64
+		if err := rows.Scan(&resource, &count); err != nil {
+			return nil, fmt.Errorf("postgres: scan resource count: %w", err)
+		}
+		counts[resource] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgres: resource counts: %w", err)
+	}
+	return counts, nil
+}
+
+// TenantStorageBytes returns tenantID's total stored bytes, computed with
+// pg_column_size over every row rather than a running counter, since this
+// repo has no migration mechanism to maintain one.
+func (r *AuditLogRepo) TenantStorageBytes(ctx context.Context, tenantID string) (int64, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	const q = `SELECT coalesce(sum(pg_column_size(audit_logs.*)), 0) FROM audit_logs WHERE tenant_id = $1`
+
+	var bytes int64
+	if err := r.pool.QueryRow(ctx, q, tenantID).Scan(&bytes); err != nil {
+		return 0, fmt.Errorf("postgres: tenant storage bytes: %w", err)
+	}
+	return bytes, nil
+}
+
+// GetByID fetches a single audit log by its ID, returning
+// domain.ErrNotFound if it does not exist.
+func (r *AuditLogRepo) GetByID(ctx context.Context, tenantID, id string, includeDeleted bool) (domain.AuditLog, error) {
+	ctx, span := tracing.Start(ctx, "postgres.AuditLogRepo.GetByID", trace.WithAttributes(attribute.String("tenant_id", tenantID)))
+	defer span.End()
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	q := `
+		SELECT id, tenant_id, actor, event, resource, resource_id, data, meta, tags, COALESCE(sequence, 0), auth_type, client_ip, created_at, deleted_at, hash, prev_hash, idempotency_key
+		FROM audit_logs WHERE tenant_id = $1 AND id = $2`
+	if !includeDeleted {
+		q += " AND deleted_at IS NULL"
+	}
+
+	row := r.pool.QueryRow(ctx, q, tenantID, id)
+	log, err := r.scanAuditLog(row)
+	if err == pgx.ErrNoRows {
+		return domain.AuditLog{}, domain.ErrNotFound
+	}
+	if err != nil {
+		span.RecordError(err)
+	}
+	return log, err
+}
+
+// HeadByID fetches only a record's existence/metadata fields (id,
+// sequence, created_at), skipping data/meta/tags, so callers checking
+// existence or freshness (e.g. HEAD /api/v1/audit/{id}) don't pay for
+// transferring large blobs. Returns domain.ErrNotFound if no record
+// matches.
+func (r *AuditLogRepo) HeadByID(ctx context.Context, tenantID, id string) (domain.AuditLog, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	const q = `SELECT id, COALESCE(sequence, 0), created_at FROM audit_logs WHERE tenant_id = $1 AND id = $2`
+
+	var log domain.AuditLog
+	if err := r.pool.QueryRow(ctx, q, tenantID, id).Scan(&log.ID, &log.Sequence, &log.CreatedAt); err != nil {
+		if err == pgx.ErrNoRows {
+			return domain.AuditLog{}, domain.ErrNotFound
+		}
+		return domain.AuditLog{}, err
+	}
+	return log, nil
+}
+
+// AddTags merges newTags into the record's existing tags (deduplicating)
+// and returns the updated record. It returns domain.ErrNotFound if no
+// record matches tenantID and id.
+func (r *AuditLogRepo) AddTags(ctx context.Context, tenantID, id string, newTags []string) (domain.AuditLog, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	const q = `
+		UPDATE audit_logs
+		SET tags = ARRAY(SELECT DISTINCT unnest(tags || $3::text[]))
+		WHERE tenant_id = $1 AND id = $2
+		RETURNING id, tenant_id, actor, event, resource, resource_id, data, meta, tags, COALESCE(sequence, 0), auth_type, client_ip, created_at, deleted_at, hash, prev_hash, idempotency_key`
+
+	row := r.pool.QueryRow(ctx, q, tenantID, id, newTags)
+	log, err := r.scanAuditLog(row)
+	if err == pgx.ErrNoRows {
+		return domain.AuditLog{}, domain.ErrNotFound
+	}
+	return log, err
+}
+
+// RemoveTags removes goneTags from the record's existing tags and returns
+// the updated record. It returns domain.ErrNotFound if no record matches
+// tenantID and id.
+func (r *AuditLogRepo) RemoveTags(ctx context.Context, tenantID, id string, goneTags []string) (domain.AuditLog, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	const q = `
+		UPDATE audit_logs
+		SET tags = COALESCE(ARRAY(SELECT unnest(tags) EXCEPT SELECT unnest($3::text[])), '{}')
+		WHERE tenant_id = $1 AND id = $2
+		RETURNING id, tenant_id, actor, event, resource, resource_id, data, meta, tags, COALESCE(sequence, 0), auth_type, client_ip, created_at, deleted_at, hash, prev_hash, idempotency_key`
+
+	row := r.pool.QueryRow(ctx, q, tenantID, id, goneTags)
+	log, err := r.scanAuditLog(row)
+	if err == pgx.ErrNoRows {
+		return domain.AuditLog{}, domain.ErrNotFound
+	}
+	return log, err
+}
+
+// AppendMeta merges patch into the record's existing meta JSON object
+// (patch's keys win on conflict) and returns the updated record, leaving
+// data and every other column untouched. It returns domain.ErrNotFound
+// if no record matches tenantID and id.
+func (r *AuditLogRepo) AppendMeta(ctx context.Context, tenantID, id string, patch map[string]any) (domain.AuditLog, error) {
+	patchJSON, err := json.Marshal(patch)
+	if err != nil {
+		return domain.AuditLog{}, fmt.Errorf("postgres: marshal meta patch: %w", err)
+	}
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	const q = `
+		UPDATE audit_logs
+		SET meta = meta || $3::jsonb
+		WHERE tenant_id = $1 AND id = $2
+		RETURNING id, tenant_id, actor, event, resource, resource_id, data, meta, tags, COALESCE(sequence, 0), auth_type, client_ip, created_at, deleted_at, hash, prev_hash, idempotency_key`
+
+	row := r.pool.QueryRow(ctx, q, tenantID, id, patchJSON)
+	log, err := r.scanAuditLog(row)
+	if err == pgx.ErrNoRows {
+		return domain.AuditLog{}, domain.ErrNotFound
+	}
+	return log, err
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func (r *AuditLogRepo) scanAuditLog(row scanner) (domain.AuditLog, error) {
+	var log domain.AuditLog
+	var data, meta []byte
+	var idempotencyKey, clientIP *string
+	if err := row.Scan(&log.ID, &log.TenantID, &log.Actor, &log.Event, &log.Resource, &log.ResourceID, &data, &meta, &log.Tags, &log.Sequence, &log.AuthType, &clientIP, &log.CreatedAt, &log.DeletedAt, &log.Hash, &log.PrevHash, &idempotencyKey); err != nil {
+		if err == pgx.ErrNoRows {
+			return domain.AuditLog{}, err
+		}
+		return domain.AuditLog{}, fmt.Errorf("postgres: scan audit log: %w", err)
+	}
+	if idempotencyKey != nil {
+		log.IdempotencyKey = *idempotencyKey
+	}
+	if clientIP != nil {
+		log.ClientIP = *clientIP
+	}
+	if len(data) > 0 {
+		decoded, err := r.decodeData(data)
+		if err != nil {
+			return domain.AuditLog{}, err
+		}
+		log.Data = decoded
+	}
+	if len(meta) > 0 {
+		if err := json.Unmarshal(meta, &log.Meta); err != nil {
+			return domain.AuditLog{}, fmt.Errorf("postgres: unmarshal meta: %w", err)
+		}
+	}
+	return log, nil
+}
+
+// scanAuditLogWithCount is scanAuditLog plus a trailing COUNT(*) OVER()
+// column, for List's filter.WithCount mode (see buildListQuery).
+func (r *AuditLogRepo) scanAuditLogWithCount(row scanner) (domain.AuditLog, int64, error) {
+	var log domain.AuditLog
+	var data, meta []byte
+	var idempotencyKey, clientIP *string
+	var count int64
+	if err := row.Scan(&log.ID, &log.TenantID, &log.Actor, &log.Event, &log.Resource, &log.ResourceID, &data, &meta, &log.Tags, &log.Sequence, &log.AuthType, &clientIP, &log.CreatedAt, &log.DeletedAt, &log.Hash, &log.PrevHash, &idempotencyKey, &count); err != nil {
+		if err == pgx.ErrNoRows {
+			return domain.AuditLog{}, 0, err
+		}
+		return domain.AuditLog{}, 0, fmt.Errorf("postgres: scan audit log: %w", err)
+	}
+	if idempotencyKey != nil {
+		log.IdempotencyKey = *idempotencyKey
+	}
+	if clientIP != nil {
+		log.ClientIP = *clientIP
+	}
+	if len(data) > 0 {
+		decoded, err := r.decodeData(data)
+		if err != nil {
+			return domain.AuditLog{}, 0, err
+		}
+		log.Data = decoded
+	}
+	if len(meta) > 0 {
+		if err := json.Unmarshal(meta, &log.Meta); err != nil {
+			return domain.AuditLog{}, 0, fmt.Errorf("postgres: unmarshal meta: %w", err)
+		}
+	}
+	return log, count, nil
+}
@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+	"github.com/naveenkumar2412/audit-log-service/internal/migrate"
+)
+
+// BenchmarkList compares the default mode (no total count) against
+// filter.WithCount's single-query COUNT(*) OVER() mode, to confirm the
+// latter doesn't regress the common case and costs relatively little
+// extra over a query that was already scanning every matching row.
+// Skipped unless TEST_DATABASE_URL points at a throwaway database.
+func BenchmarkList(b *testing.B) {
+	dbURL := os.Getenv("TEST_DATABASE_URL")
+	if dbURL == "" {
+		b.Skip("TEST_DATABASE_URL not set; skipping List benchmark")
+	}
+
+	if err := migrate.Up(dbURL); err != nil {
+		b.Fatalf("migrate.Up: %v", err)
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dbURL)
+	if err != nil {
+		b.Fatalf("pgxpool.New: %v", err)
+	}
+	defer pool.Close()
+
+	repo := NewAuditLogRepo(pool)
+	baseFilter := domain.Filter{TenantID: "bench-tenant", Limit: 50}
+
+	b.Run("WithoutCount", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, _, err := repo.List(ctx, baseFilter); err != nil {
+				b.Fatalf("List: %v", err)
+			}
+		}
+	})
+
+	b.Run("WithCount", func(b *testing.B) {
+		filter := baseFilter
+		filter.WithCount = true
+		for i := 0; i < b.N; i++ {
+			if _, _, err := repo.List(ctx, filter); err != nil {
+				b.Fatalf("List: %v", err)
+			}
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TestWithTimeout_CancelsSlowQuery confirms that a repo configured with
+// WithQueryTimeout cancels a query that runs longer than the timeout, and
+// that the error returned wraps context.DeadlineExceeded rather than
+// hanging. pg_sleep stands in for a genuinely slow query. Skipped unless
+// TEST_DATABASE_URL points at a throwaway database.
+func TestWithTimeout_CancelsSlowQuery(t *testing.T) {
+	dbURL := os.Getenv("TEST_DATABASE_URL")
+	if dbURL == "" {
+		t.Skip("TEST_DATABASE_URL not set; skipping query timeout test")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dbURL)
+	if err != nil {
+		t.Fatalf("pgxpool.New: %v", err)
+	}
+	defer pool.Close()
+
+	repo := NewAuditLogRepo(pool).WithQueryTimeout(50 * time.Millisecond)
+
+	queryCtx, cancel := repo.withTimeout(ctx)
+	defer cancel()
+
+	_, err = pool.Exec(queryCtx, "SELECT pg_sleep(1)")
+	if err == nil {
+		t.Fatal("expected pg_sleep(1) to be cancelled by the 50ms query timeout, got nil error")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected error to wrap context.DeadlineExceeded, got %v", err)
+	}
+}
+
+// TestWithTimeout_LeavesCallerDeadlineAlone confirms that withTimeout does
+// not shorten a deadline the caller's own ctx already carries.
+func TestWithTimeout_LeavesCallerDeadlineAlone(t *testing.T) {
+	repo := (&AuditLogRepo{}).WithQueryTimeout(time.Millisecond)
+
+	callerCtx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	gotCtx, gotCancel := repo.withTimeout(callerCtx)
+	defer gotCancel()
+
+	wantDeadline, _ := callerCtx.Deadline()
+	gotDeadline, ok := gotCtx.Deadline()
+	if !ok || !gotDeadline.Equal(wantDeadline) {
+		t.Errorf("expected withTimeout to leave the caller's own deadline untouched, got %v (ok=%v), want %v", gotDeadline, ok, wantDeadline)
+	}
+}
+
+// TestWithTimeout_DisabledByDefault confirms a repo with no query timeout
+// configured passes ctx through unchanged.
+func TestWithTimeout_DisabledByDefault(t *testing.T) {
+	repo := &AuditLogRepo{}
+
+	ctx := context.Background()
+	gotCtx, cancel := repo.withTimeout(ctx)
+	defer cancel()
+
+	if _, ok := gotCtx.Deadline(); ok {
+		t.Error("expected no deadline when no query timeout is configured")
+	}
+}
@@ -0,0 +1,133 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+)
+
+// FailedNotificationRepo provides CRUD access to the failed_notifications
+// table, the dead-letter queue for notification deliveries that failed
+// every attempt (see notify.Dispatcher.WithDeadLetter).
+//
+// Assumes a failed_notifications table: id uuid primary key default
+// gen_random_uuid(), audit_log_id uuid not null, tenant_id text not null,
+// channel text not null, error text not null, attempts int not null
+// default 1, next_retry_at timestamptz not null, created_at timestamptz
+// not null default now().
+type FailedNotificationRepo struct {
+	pool *pgxpool.Pool
+}
+
+// NewFailedNotificationRepo returns a repo backed by the given connection
+// pool.
+func NewFailedNotificationRepo(pool *pgxpool.Pool) *FailedNotificationRepo {
+	return &FailedNotificationRepo{pool: pool}
+}
+
+// Enqueue persists a newly failed delivery. fn.Attempts and
+// fn.NextRetryAt are taken as given rather than defaulted here, since the
+// caller (notify.Dispatcher) already knows how many attempts the sender
+// itself made and what backoff to apply.
+func (r *FailedNotificationRepo) Enqueue(ctx context.Context, fn domain.FailedNotification) error {
+	const q = `
+		INSERT INTO failed_notifications (audit_log_id, tenant_id, channel, error, attempts, next_retry_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at`
+
+	row := r.pool.QueryRow(ctx, q, fn.AuditLogID, fn.TenantID, fn.Channel, fn.Error, fn.Attempts, fn.NextRetryAt)
+	if err := row.Scan(&fn.ID, &fn.CreatedAt); err != nil {
+		return fmt.Errorf("postgres: enqueue failed notification: %w", err)
+	}
+	return nil
+}
+
+// DueForRetry returns up to limit failed notifications whose next_retry_at
+// has passed, oldest-due first, for NotificationRetryWorker's sweep.
+func (r *FailedNotificationRepo) DueForRetry(ctx context.Context, limit int) ([]domain.FailedNotification, error) {
+	const q = `
+		SELECT id, audit_log_id, tenant_id, channel, error, attempts, next_retry_at, created_at
+		FROM failed_notifications
+		WHERE next_retry_at <= now()
+		ORDER BY next_retry_at ASC
+		LIMIT $1`
+
+	rows, err := r.pool.Query(ctx, q, limit)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: list due failed notifications: %w", err)
+	}
+	defer rows.Close()
+
+	var due []domain.FailedNotification
+	for rows.Next() {
+		var fn domain.FailedNotification
+		if err := rows.Scan(&fn.ID, &fn.AuditLogID, &fn.TenantID, &fn.Channel, &fn.Error, &fn.Attempts, &fn.NextRetryAt, &fn.CreatedAt); err != nil {
+			return nil, fmt.Errorf("postgres: scan failed notification: %w", err)
+		}
+		due = append(due, fn)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgres: list due failed notifications: %w", err)
+	}
+	return due, nil
+}
+
+// MarkSucceeded removes a failed notification once a retry succeeds.
+func (r *FailedNotificationRepo) MarkSucceeded(ctx context.Context, id uuid.UUID) error {
+	if _, err := r.pool.Exec(ctx, `DELETE FROM failed_notifications WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("postgres: delete failed notification: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed records another failed retry attempt, incrementing Attempts
+// and rescheduling for nextRetryAt.
+func (r *FailedNotificationRepo) MarkFailed(ctx context.Context, id uuid.UUID, errMsg string, nextRetryAt time.Time) error {
+	const q = `
+		UPDATE failed_notifications
+		SET attempts = attempts + 1, error = $2, next_retry_at = $3
+		WHERE id = $1`
+
+	tag, err := r.pool.Exec(ctx, q, id, errMsg, nextRetryAt)
+	if err != nil {
+		return fmt.Errorf("postgres: reschedule failed notification: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// ListByTenant returns a tenant's failed-notification backlog, most
+// recently created first.
+func (r *FailedNotificationRepo) ListByTenant(ctx context.Context, tenantID string) ([]domain.FailedNotification, error) {
+	const q = `
+		SELECT id, audit_log_id, tenant_id, channel, error, attempts, next_retry_at, created_at
+		FROM failed_notifications
+		WHERE tenant_id = $1
+		ORDER BY created_at DESC`
+
+	rows, err := r.pool.Query(ctx, q, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: list failed notifications: %w", err)
+	}
+	defer rows.Close()
+
+	var list []domain.FailedNotification
+	for rows.Next() {
+		var fn domain.FailedNotification
+		if err := rows.Scan(&fn.ID, &fn.AuditLogID, &fn.TenantID, &fn.Channel, &fn.Error, &fn.Attempts, &fn.NextRetryAt, &fn.CreatedAt); err != nil {
+			return nil, fmt.Errorf("postgres: scan failed notification: %w", err)
+		}
+		list = append(list, fn)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgres: list failed notifications: %w", err)
+	}
+	return list, nil
+}
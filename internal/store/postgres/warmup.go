@@ -0,0 +1,32 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Warmup eagerly establishes minConns connections against pool, logging
+// progress as it goes. It exists so operators can pay the cost of
+// establishing connections at startup instead of on the first burst of
+// traffic after a deploy.
+func Warmup(ctx context.Context, pool *pgxpool.Pool, minConns int32) error {
+	conns := make([]*pgxpool.Conn, 0, minConns)
+	defer func() {
+		for _, c := range conns {
+			c.Release()
+		}
+	}()
+
+	for i := int32(1); i <= minConns; i++ {
+		conn, err := pool.Acquire(ctx)
+		if err != nil {
+			return fmt.Errorf("postgres: warmup connection %d/%d: %w", i, minConns, err)
+		}
+		conns = append(conns, conn)
+		log.Printf("postgres: warmup %d/%d connections established", i, minConns)
+	}
+	return nil
+}
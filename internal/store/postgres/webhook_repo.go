@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+)
+
+// WebhookRepo provides CRUD access to the webhook_subscriptions table.
+type WebhookRepo struct {
+	pool *pgxpool.Pool
+}
+
+// NewWebhookRepo returns a repo backed by the given connection pool.
+func NewWebhookRepo(pool *pgxpool.Pool) *WebhookRepo {
+	return &WebhookRepo{pool: pool}
+}
+
+// Create inserts a new webhook subscription and returns it with
+// server-assigned fields populated. Assumes webhook_subscriptions has
+// batch_max_size and batch_linger_seconds integer columns (both
+// defaulting to 0, meaning batching disabled) and secret/enabled columns
+// alongside the original tenant_id/url/events.
+func (r *WebhookRepo) Create(ctx context.Context, sub domain.WebhookSubscription) (domain.WebhookSubscription, error) {
+	const q = `
+		INSERT INTO webhook_subscriptions (tenant_id, url, events, batch_max_size, batch_linger_seconds, secret, enabled)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at`
+
+	row := r.pool.QueryRow(ctx, q, sub.TenantID, sub.URL, sub.Events, sub.BatchMaxSize, sub.BatchLingerSeconds, sub.Secret, sub.Enabled)
+	if err := row.Scan(&sub.ID, &sub.CreatedAt); err != nil {
+		return domain.WebhookSubscription{}, fmt.Errorf("postgres: insert webhook subscription: %w", err)
+	}
+	return sub, nil
+}
+
+// ListByTenant returns every subscription registered for tenantID.
+func (r *WebhookRepo) ListByTenant(ctx context.Context, tenantID string) ([]domain.WebhookSubscription, error) {
+	const q = `
+		SELECT id, tenant_id, url, events, batch_max_size, batch_linger_seconds, secret, enabled, created_at
+		FROM webhook_subscriptions WHERE tenant_id = $1
+		ORDER BY created_at DESC`
+
+	rows, err := r.pool.Query(ctx, q, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: list webhook subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var subs []domain.WebhookSubscription
+	for rows.Next() {
+		var sub domain.WebhookSubscription
+		if err := rows.Scan(&sub.ID, &sub.TenantID, &sub.URL, &sub.Events, &sub.BatchMaxSize, &sub.BatchLingerSeconds, &sub.Secret, &sub.Enabled, &sub.CreatedAt); err != nil {
+			return nil, fmt.Errorf("postgres: scan webhook subscription: %w", err)
+		}
+		subs = append(subs, sub)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgres: list webhook subscriptions: %w", err)
+	}
+	return subs, nil
+}
+
+// Delete removes a subscription, scoped to tenantID so one tenant cannot
+// delete another's subscription by guessing an ID.
+func (r *WebhookRepo) Delete(ctx context.Context, tenantID string, id uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM webhook_subscriptions WHERE tenant_id = $1 AND id = $2`, tenantID, id)
+	if err != nil {
+		return fmt.Errorf("postgres: delete webhook subscription: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+// Package stream implements an in-process publish/subscribe hub that
+// fans newly-created audit logs out to live subscribers. It backs the
+// SSE endpoint exposed by httpapi.StreamHandler (GET
+// /api/v1/audit/stream): Hub satisfies service.Notifier, so
+// AuditService.CreateAuditLog publishes to it the same way it would any
+// webhook notifier (see service.Notifiers to fan out to both).
+//
+// Delivery is in-process and best-effort only: a subscriber connected to
+// a different replica of the service never sees events published on this
+// one. Fanning out across replicas (e.g. via Redis pub/sub) is a
+// follow-up, not handled here.
+package stream
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+	"github.com/naveenkumar2412/audit-log-service/internal/metrics"
+)
+
+// subscriberBufferSize bounds how many undelivered events a slow
+// subscriber can accumulate before Notify starts dropping for it (see
+// Subscription.Dropped).
+const subscriberBufferSize = 64
+
+// Filter selects which published audit logs a Subscription receives.
+// TenantID is required — Hub never sends a subscriber another tenant's
+// events, regardless of what the caller's credential would otherwise be
+// allowed to see, so tenant isolation does not depend on the caller
+// remembering to set it. Event and Resource, left empty, match anything.
+type Filter struct {
+	TenantID string
+	Event    string
+	Resource string
+}
+
+func (f Filter) matches(log domain.AuditLog) bool {
+	if f.TenantID == "" || f.TenantID != log.TenantID {
+		return false
+	}
+	if f.Event != "" && f.Event != log.Event {
+		return false
+	}
+	if f.Resource != "" && f.Resource != log.Resource {
+		return false
+	}
+	return true
+}
+
+// Subscription is a live registration returned by Hub.Subscribe. Events
+// delivers newly-published audit logs matching Filter; Close unregisters
+// it and must be called once the subscriber is done (e.g. on client
+// disconnect) to free the Hub's reference to it.
+type Subscription struct {
+	Events chan domain.AuditLog
+
+	dropped atomic.Int64
+
+	hub    *Hub
+	filter Filter
+}
+
+// Dropped returns how many events have been dropped for this
+// subscription since the last call to Dropped, resetting the count to
+// zero. A subscriber should check it whenever it reads Events and
+// surface a non-zero result to the client (e.g. as a distinct SSE event)
+// so a gap in the stream is never silent.
+func (s *Subscription) Dropped() int64 {
+	return s.dropped.Swap(0)
+}
+
+// Close unregisters the subscription. Safe to call more than once.
+func (s *Subscription) Close() {
+	s.hub.unsubscribe(s)
+}
+
+// Hub fans published audit logs out to every live Subscription whose
+// Filter matches. All methods are safe for concurrent use.
+type Hub struct {
+	mu   sync.Mutex
+	subs map[*Subscription]struct{}
+}
+
+// NewHub returns an empty Hub.
+func NewHub() *Hub {
+	return &Hub{subs: map[*Subscription]struct{}{}}
+}
+
+// Subscribe registers a new Subscription matching filter. Callers must
+// call Subscription.Close once they stop reading from Events, typically
+// via defer right after Subscribe.
+func (h *Hub) Subscribe(filter Filter) *Subscription {
+	sub := &Subscription{
+		Events: make(chan domain.AuditLog, subscriberBufferSize),
+		hub:    h,
+		filter: filter,
+	}
+	h.mu.Lock()
+	h.subs[sub] = struct{}{}
+	metrics.StreamSubscribersActive.Set(float64(len(h.subs)))
+	h.mu.Unlock()
+	return sub
+}
+
+func (h *Hub) unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	delete(h.subs, sub)
+	metrics.StreamSubscribersActive.Set(float64(len(h.subs)))
+	h.mu.Unlock()
+}
+
+// Notify implements service.Notifier: it fans log out to every
+// Subscription whose Filter matches, dropping it — incrementing that
+// subscription's Dropped count plus metrics.StreamEventsDroppedTotal —
+// for any subscriber whose buffer is already full, rather than blocking
+// the caller on a slow consumer.
+func (h *Hub) Notify(ctx context.Context, log domain.AuditLog) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subs {
+		if !sub.filter.matches(log) {
+			continue
+		}
+		select {
+		case sub.Events <- log:
+		default:
+			sub.dropped.Add(1)
+			metrics.StreamEventsDroppedTotal.Inc()
+		}
+	}
+}
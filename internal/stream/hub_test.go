@@ -0,0 +1,105 @@
+package stream
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/domain"
+)
+
+func recv(t *testing.T, sub *Subscription) domain.AuditLog {
+	t.Helper()
+	select {
+	case log := <-sub.Events:
+		return log
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+		return domain.AuditLog{}
+	}
+}
+
+func TestHub_DeliversToMatchingSubscription(t *testing.T) {
+	h := NewHub()
+	sub := h.Subscribe(Filter{TenantID: "tenant-a"})
+	defer sub.Close()
+
+	h.Notify(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "login"})
+
+	if got := recv(t, sub); got.Event != "login" {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestHub_FiltersOutOtherTenants(t *testing.T) {
+	h := NewHub()
+	sub := h.Subscribe(Filter{TenantID: "tenant-a"})
+	defer sub.Close()
+
+	h.Notify(context.Background(), domain.AuditLog{TenantID: "tenant-b", Event: "login"})
+
+	select {
+	case got := <-sub.Events:
+		t.Fatalf("expected no event, got %+v", got)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestHub_FiltersOutNonMatchingEvent(t *testing.T) {
+	h := NewHub()
+	sub := h.Subscribe(Filter{TenantID: "tenant-a", Event: "logout"})
+	defer sub.Close()
+
+	h.Notify(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "login"})
+
+	select {
+	case got := <-sub.Events:
+		t.Fatalf("expected no event, got %+v", got)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestHub_RequiresTenantIDOnFilter(t *testing.T) {
+	h := NewHub()
+	sub := h.Subscribe(Filter{})
+	defer sub.Close()
+
+	h.Notify(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "login"})
+
+	select {
+	case got := <-sub.Events:
+		t.Fatalf("expected no event for an unset tenant filter, got %+v", got)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestHub_ClosedSubscriptionStopsReceiving(t *testing.T) {
+	h := NewHub()
+	sub := h.Subscribe(Filter{TenantID: "tenant-a"})
+	sub.Close()
+
+	h.Notify(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "login"})
+
+	select {
+	case got := <-sub.Events:
+		t.Fatalf("expected no event after Close, got %+v", got)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestHub_DropsAndCountsWhenSubscriberBufferIsFull(t *testing.T) {
+	h := NewHub()
+	sub := h.Subscribe(Filter{TenantID: "tenant-a"})
+	defer sub.Close()
+
+	for i := 0; i < subscriberBufferSize+5; i++ {
+		h.Notify(context.Background(), domain.AuditLog{TenantID: "tenant-a", Event: "login"})
+	}
+
+	if dropped := sub.Dropped(); dropped != 5 {
+		t.Errorf("got %d dropped, want 5", dropped)
+	}
+	if dropped := sub.Dropped(); dropped != 0 {
+		t.Errorf("Dropped should reset to 0 after being read, got %d", dropped)
+	}
+}
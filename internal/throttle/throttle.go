@@ -0,0 +1,88 @@
+// Package throttle implements per-key notification rate limiting (see
+// notify.Dispatcher's Throttler), backed by Redis.
+package throttle
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/resilience"
+)
+
+// RedisThrottle gates how often a given key may fire by opening a window
+// in Redis on the first call and suppressing every call until it expires.
+// Throttling protects alert volume, not correctness, so it fails open:
+// once breaker reports the circuit open, Allow skips Redis entirely and
+// behaves as if no window were active, rather than blocking notification
+// on a down dependency.
+type RedisThrottle struct {
+	client  *redis.Client
+	breaker *resilience.Breaker
+}
+
+// NewRedisThrottle returns a throttle backed by a Redis client connected
+// to addr, retrying per retry and tripping breaker on repeated failures
+// (see resilience.Breaker). breaker may be nil to disable tripping.
+func NewRedisThrottle(addr string, retry resilience.RetryOptions, breaker *resilience.Breaker) *RedisThrottle {
+	return &RedisThrottle{
+		client: redis.NewClient(&redis.Options{
+			Addr:            addr,
+			MaxRetries:      retry.MaxRetries,
+			MinRetryBackoff: retry.MinRetryBackoff,
+			MaxRetryBackoff: retry.MaxRetryBackoff,
+		}),
+		breaker: breaker,
+	}
+}
+
+// Allow reports whether the caller may act on key now. It opens a new
+// window (sendNow=true) the first time key is seen, or once the previous
+// window has expired; any other call within an open window is suppressed
+// (sendNow=false) and counted.
+//
+// When a new window opens, rollupSuppressedCount reports how many calls
+// were suppressed during the window that just closed, so the caller can
+// attach a "suppressed N similar alerts" summary to the notification that
+// reopens it. There is no background process to deliver that summary the
+// instant a window closes; it is only ever reported lazily, to whichever
+// call next reopens the window. If no further call ever arrives, the
+// rollup for the final window is never delivered.
+func (t *RedisThrottle) Allow(ctx context.Context, key string, window time.Duration) (sendNow bool, rollupSuppressedCount int, err error) {
+	if !t.breaker.Allow() {
+		return true, 0, nil
+	}
+
+	activeKey := "throttle:active:" + key
+	suppressedKey := "throttle:suppressed:" + key
+
+	opened, err := t.client.SetNX(ctx, activeKey, 1, window).Result()
+	if err != nil {
+		t.breaker.RecordFailure()
+		return true, 0, fmt.Errorf("throttle: open window for %s: %w", key, err)
+	}
+	t.breaker.RecordSuccess()
+
+	if !opened {
+		if err := t.client.Incr(ctx, suppressedKey).Err(); err != nil {
+			t.breaker.RecordFailure()
+			return false, 0, fmt.Errorf("throttle: count suppressed call for %s: %w", key, err)
+		}
+		t.client.Expire(ctx, suppressedKey, window*2)
+		t.breaker.RecordSuccess()
+		return false, 0, nil
+	}
+
+	count, err := t.client.GetDel(ctx, suppressedKey).Int()
+	if err == redis.Nil {
+		return true, 0, nil
+	}
+	if err != nil {
+		// The window opened successfully; losing the rollup count isn't
+		// worth failing (or retrying) the call over.
+		return true, 0, nil
+	}
+	return true, count, nil
+}
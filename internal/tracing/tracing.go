@@ -0,0 +1,140 @@
+// Package tracing wires up OpenTelemetry distributed tracing: a resource
+// describing this service, an OTLP/gRPC exporter when enabled, and the
+// middleware/helpers call sites use to create spans. When tracing is
+// disabled, Init installs the SDK's no-op tracer provider so every
+// instrumented call site still compiles and runs, just without recording
+// anything.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/naveenkumar2412/audit-log-service/internal/config"
+)
+
+const instrumentationName = "github.com/naveenkumar2412/audit-log-service"
+
+// StartupTimeout bounds how long Init waits to establish the initial OTLP
+// connection before giving up, so a misconfigured or unreachable collector
+// doesn't hang service startup indefinitely.
+const StartupTimeout = 5 * time.Second
+
+// Init configures the global tracer provider and propagator from cfg and
+// returns a shutdown function that flushes and closes the exporter; callers
+// should defer it. When cfg.Enabled is false, the default (no-op) tracer
+// provider is left in place and shutdown is a no-op.
+func Init(ctx context.Context, cfg config.TracingConfig) (shutdown func(context.Context) error, err error) {
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	connectCtx, cancel := context.WithTimeout(ctx, StartupTimeout)
+	defer cancel()
+
+	exporter, err := otlptracegrpc.New(connectCtx,
+		otlptracegrpc.WithEndpoint(cfg.OTLPEndpoint),
+		otlptracegrpc.WithInsecure(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("tracing: create OTLP exporter: %w", err)
+	}
+
+	res, err := resource.New(ctx, resource.WithAttributes(semconv.ServiceName("audit-log-service")))
+	if err != nil {
+		return nil, fmt.Errorf("tracing: build resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	return provider.Shutdown, nil
+}
+
+// Tracer returns this service's tracer. It's backed by the no-op provider
+// until Init installs a real one, so call sites don't need to special-case
+// tracing being disabled.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Start is a thin wrapper around Tracer().Start, so call sites in the
+// service and store layers don't need to import both "otel" and
+// "otel/trace" just to open a child span.
+func Start(ctx context.Context, spanName string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, spanName, opts...)
+}
+
+// LinkFromContext returns a link to ctx's current span, for attaching to a
+// new root span started in a detached goroutine (see StartDetached) whose
+// own context.WithoutCancel no longer carries a parent-child relationship
+// worth preserving, but whose trace is still worth correlating back to the
+// request that triggered it.
+func LinkFromContext(ctx context.Context) trace.Link {
+	return trace.Link{SpanContext: trace.SpanContextFromContext(ctx)}
+}
+
+// StartDetached starts a new root span named spanName, linked to ctx's
+// current span, while preserving ctx's other values (e.g. its attached
+// logger). Use it for spans opened in a goroutine dispatched with
+// context.WithoutCancel (e.g. notify.Dispatcher.Notify), where a real
+// parent-child relationship would tie a long-running background span to a
+// request span that may have already ended by the time it starts.
+func StartDetached(ctx context.Context, spanName string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	link := LinkFromContext(ctx)
+	detached := trace.ContextWithSpanContext(ctx, trace.SpanContext{})
+	return Start(detached, spanName, append(opts, trace.WithLinks(link))...)
+}
+
+// Middleware starts a span for every request named after chi's matched
+// route pattern (not the raw URL, to avoid exploding span-name cardinality
+// on path params like {id}), and records the method and status code as
+// attributes. It must be registered after chi has matched a route (i.e.
+// via r.Use, not wrapping an individual handler).
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := Start(r.Context(), "http.request", trace.WithAttributes(
+			attribute.String("http.method", r.Method),
+		))
+		defer span.End()
+
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(sw, r.WithContext(ctx))
+
+		path := chi.RouteContext(r.Context()).RoutePattern()
+		if path == "" {
+			path = "unmatched"
+		}
+		span.SetName(fmt.Sprintf("%s %s", r.Method, path))
+		span.SetAttributes(
+			attribute.String("http.route", path),
+			attribute.Int("http.status_code", sw.status),
+		)
+	})
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
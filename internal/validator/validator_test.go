@@ -0,0 +1,119 @@
+package validator
+
+import "testing"
+
+type testRequest struct {
+	TenantID string `json:"tenant_id" validate:"required,tenant_id"`
+	Event    string `json:"event" validate:"required,event_name"`
+	Resource string `json:"resource" validate:"omitempty,resource_name"`
+	Actor    string `json:"actor" validate:"omitempty,actor_name"`
+}
+
+func TestStruct_AcceptsValidRequest(t *testing.T) {
+	v := New()
+	req := testRequest{TenantID: "tenant-a", Event: "user.login", Resource: "invoices/2024-01", Actor: "alice@example.com"}
+	if err := v.Struct(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestStruct_RejectsBadTenantID(t *testing.T) {
+	v := New()
+	req := testRequest{TenantID: "Tenant A!", Event: "user.login"}
+
+	err := v.Struct(req)
+	if err == nil {
+		t.Fatal("expected an error for a malformed tenant_id")
+	}
+	want := `tenant_id must be a lowercase identifier (letters, digits, '-', '_')`
+	if err.Error() != want {
+		t.Errorf("got %q, want %q", err.Error(), want)
+	}
+}
+
+func TestStruct_RejectsBadEventName(t *testing.T) {
+	v := New()
+	req := testRequest{TenantID: "tenant-a", Event: "User Login"}
+
+	err := v.Struct(req)
+	if err == nil {
+		t.Fatal("expected an error for a malformed event name")
+	}
+	want := `event must be dot-separated lowercase segments, e.g. "user.login"`
+	if err.Error() != want {
+		t.Errorf("got %q, want %q", err.Error(), want)
+	}
+}
+
+func TestStruct_RejectsMissingRequiredField(t *testing.T) {
+	v := New()
+	req := testRequest{Event: "user.login"}
+
+	err := v.Struct(req)
+	if err == nil {
+		t.Fatal("expected an error for a missing tenant_id")
+	}
+	want := `tenant_id is required`
+	if err.Error() != want {
+		t.Errorf("got %q, want %q", err.Error(), want)
+	}
+}
+
+func TestStruct_CombinesMultipleFieldErrors(t *testing.T) {
+	v := New()
+	req := testRequest{TenantID: "Bad Tenant", Event: "Bad Event"}
+
+	err := v.Struct(req)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	want := `tenant_id must be a lowercase identifier (letters, digits, '-', '_'); event must be dot-separated lowercase segments, e.g. "user.login"`
+	if err.Error() != want {
+		t.Errorf("got %q, want %q", err.Error(), want)
+	}
+}
+
+func TestStruct_OptionalFieldAllowsEmpty(t *testing.T) {
+	v := New()
+	req := testRequest{TenantID: "tenant-a", Event: "user.login"}
+	if err := v.Struct(req); err != nil {
+		t.Fatalf("unexpected error for empty optional fields: %v", err)
+	}
+}
+
+func TestStruct_RejectsBadResourceName(t *testing.T) {
+	v := New()
+	req := testRequest{TenantID: "tenant-a", Event: "user.login", Resource: "Not A Resource!"}
+
+	err := v.Struct(req)
+	if err == nil {
+		t.Fatal("expected an error for a malformed resource name")
+	}
+}
+
+// TestStruct_DoesNotMangleSQLKeywordSubstrings guards against ever
+// reintroducing a sanitizer that strips substrings like "select"/
+// "update"/"create" from input (see the package doc): a legitimate event
+// name or tenant ID containing one of those words as a substring must
+// validate, and survive Struct's validation, completely unchanged.
+func TestStruct_DoesNotMangleSQLKeywordSubstrings(t *testing.T) {
+	v := New()
+	req := testRequest{TenantID: "selectco", Event: "user.account_created", Resource: "updated_invoices"}
+
+	if err := v.Struct(req); err != nil {
+		t.Fatalf("unexpected error for legitimate values containing SQL keyword substrings: %v", err)
+	}
+	if req.TenantID != "selectco" || req.Event != "user.account_created" || req.Resource != "updated_invoices" {
+		t.Errorf("values were mutated by validation: %+v", req)
+	}
+}
+
+func TestStruct_RejectsBadActorName(t *testing.T) {
+	v := New()
+	req := testRequest{TenantID: "tenant-a", Event: "user.login", Actor: "not a valid actor!"}
+
+	err := v.Struct(req)
+	if err == nil {
+		t.Fatal("expected an error for a malformed actor name")
+	}
+}
@@ -0,0 +1,9 @@
+// Package migrations embeds the SQL migration files in this directory so
+// they ship inside the compiled binary (see internal/migrate, which
+// applies them) instead of needing to be deployed alongside it.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS